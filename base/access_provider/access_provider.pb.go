@@ -137,8 +137,10 @@ type AccessSyncFromTarget struct {
 	LockDeleteByName              []string `protobuf:"bytes,27,rep,name=lock_delete_by_name,json=lockDeleteByName,proto3" json:"lock_delete_by_name,omitempty"`
 	LockDeleteByTag               []string `protobuf:"bytes,28,rep,name=lock_delete_by_tag,json=lockDeleteByTag,proto3" json:"lock_delete_by_tag,omitempty"`
 	LockDeleteWhenIncomplete      bool     `protobuf:"varint,29,opt,name=lock_delete_when_incomplete,json=lockDeleteWhenIncomplete,proto3" json:"lock_delete_when_incomplete,omitempty"`
-	unknownFields                 protoimpl.UnknownFields
-	sizeCache                     protoimpl.SizeCache
+	// lock_rules are evaluated in addition to the lock flags above.
+	LockRules     []*LockRule `protobuf:"bytes,30,rep,name=lock_rules,json=lockRules,proto3" json:"lock_rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AccessSyncFromTarget) Reset() {
@@ -374,6 +376,138 @@ func (x *AccessSyncFromTarget) GetLockDeleteWhenIncomplete() bool {
 	return false
 }
 
+func (x *AccessSyncFromTarget) GetLockRules() []*LockRule {
+	if x != nil {
+		return x.LockRules
+	}
+	return nil
+}
+
+// LockRule locks parts of the access providers that match all of its conditions when they are imported into Raito.
+// The names, types, tags, data objects, actions and owners are regular expressions that need to match completely. Conditions that are not set match all access providers.
+type LockRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// name identifies the rule in error messages
+	Name  string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Names []string `protobuf:"bytes,2,rep,name=names,proto3" json:"names,omitempty"`
+	Types []string `protobuf:"bytes,3,rep,name=types,proto3" json:"types,omitempty"`
+	// tags are matched against 'key:value' for each tag of the access provider
+	Tags []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	// what_data_objects are matched against the full names of the data objects in the what of the access provider
+	WhatDataObjects []string `protobuf:"bytes,5,rep,name=what_data_objects,json=whatDataObjects,proto3" json:"what_data_objects,omitempty"`
+	// actions are matched case-insensitively against the action of the access provider (e.g. grant, mask, filtered)
+	Actions []string `protobuf:"bytes,6,rep,name=actions,proto3" json:"actions,omitempty"`
+	// incomplete_only only matches access providers that are marked as incomplete by the connector
+	IncompleteOnly bool     `protobuf:"varint,7,opt,name=incomplete_only,json=incompleteOnly,proto3" json:"incomplete_only,omitempty"`
+	Owners         []string `protobuf:"bytes,8,rep,name=owners,proto3" json:"owners,omitempty"`
+	// locks contains the kinds of locks to set: who, inheritance, what, name, delete, owners or fully.
+	Locks []string `protobuf:"bytes,9,rep,name=locks,proto3" json:"locks,omitempty"`
+	// reason is shown in Raito to explain why the access provider is locked
+	Reason        string `protobuf:"bytes,10,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockRule) Reset() {
+	*x = LockRule{}
+	mi := &file_access_provider_access_provider_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockRule) ProtoMessage() {}
+
+func (x *LockRule) ProtoReflect() protoreflect.Message {
+	mi := &file_access_provider_access_provider_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockRule.ProtoReflect.Descriptor instead.
+func (*LockRule) Descriptor() ([]byte, []int) {
+	return file_access_provider_access_provider_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LockRule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *LockRule) GetNames() []string {
+	if x != nil {
+		return x.Names
+	}
+	return nil
+}
+
+func (x *LockRule) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *LockRule) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *LockRule) GetWhatDataObjects() []string {
+	if x != nil {
+		return x.WhatDataObjects
+	}
+	return nil
+}
+
+func (x *LockRule) GetActions() []string {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+func (x *LockRule) GetIncompleteOnly() bool {
+	if x != nil {
+		return x.IncompleteOnly
+	}
+	return false
+}
+
+func (x *LockRule) GetOwners() []string {
+	if x != nil {
+		return x.Owners
+	}
+	return nil
+}
+
+func (x *LockRule) GetLocks() []string {
+	if x != nil {
+		return x.Locks
+	}
+	return nil
+}
+
+func (x *LockRule) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 // AccessSyncResult represents the result from the data access sync process.
 // A potential error is also modeled in here so specific errors remain intact when passed over RPC.
 type AccessSyncResult struct {
@@ -387,7 +521,7 @@ type AccessSyncResult struct {
 
 func (x *AccessSyncResult) Reset() {
 	*x = AccessSyncResult{}
-	mi := &file_access_provider_access_provider_proto_msgTypes[2]
+	mi := &file_access_provider_access_provider_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -399,7 +533,7 @@ func (x *AccessSyncResult) String() string {
 func (*AccessSyncResult) ProtoMessage() {}
 
 func (x *AccessSyncResult) ProtoReflect() protoreflect.Message {
-	mi := &file_access_provider_access_provider_proto_msgTypes[2]
+	mi := &file_access_provider_access_provider_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -412,7 +546,7 @@ func (x *AccessSyncResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AccessSyncResult.ProtoReflect.Descriptor instead.
 func (*AccessSyncResult) Descriptor() ([]byte, []int) {
-	return file_access_provider_access_provider_proto_rawDescGZIP(), []int{2}
+	return file_access_provider_access_provider_proto_rawDescGZIP(), []int{3}
 }
 
 // Deprecated: Marked as deprecated in access_provider/access_provider.proto.
@@ -441,7 +575,7 @@ type AccessSyncConfig struct {
 
 func (x *AccessSyncConfig) Reset() {
 	*x = AccessSyncConfig{}
-	mi := &file_access_provider_access_provider_proto_msgTypes[3]
+	mi := &file_access_provider_access_provider_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -453,7 +587,7 @@ func (x *AccessSyncConfig) String() string {
 func (*AccessSyncConfig) ProtoMessage() {}
 
 func (x *AccessSyncConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_access_provider_access_provider_proto_msgTypes[3]
+	mi := &file_access_provider_access_provider_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -466,7 +600,7 @@ func (x *AccessSyncConfig) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AccessSyncConfig.ProtoReflect.Descriptor instead.
 func (*AccessSyncConfig) Descriptor() ([]byte, []int) {
-	return file_access_provider_access_provider_proto_rawDescGZIP(), []int{3}
+	return file_access_provider_access_provider_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *AccessSyncConfig) GetSupportPartialSync() bool {
@@ -488,8 +622,7 @@ const file_access_provider_access_provider_proto_rawDesc = "" +
 	"sourceFile\x120\n" +
 	"\x14feedback_target_file\x18\x03 \x01(\tR\x12feedbackTargetFile\x12\x16\n" +
 	"\x06prefix\x18\x04 \x01(\tR\x06prefix\x12\x12\n" +
-	"\x04test\x18\x05 \x01(\tR\x04test\"\xe8\n" +
-	"\n" +
+	"\x04test\x18\x05 \x01(\tR\x04test\"\xa2\v\n" +
 	"\x14AccessSyncFromTarget\x125\n" +
 	"\n" +
 	"config_map\x18\x01 \x01(\v2\x16.util.config.ConfigMapR\tconfigMap\x12\x1f\n" +
@@ -523,7 +656,21 @@ const file_access_provider_access_provider_proto_rawDesc = "" +
 	"\x1alock_names_when_incomplete\x18\x1a \x01(\bR\x17lockNamesWhenIncomplete\x12-\n" +
 	"\x13lock_delete_by_name\x18\x1b \x03(\tR\x10lockDeleteByName\x12+\n" +
 	"\x12lock_delete_by_tag\x18\x1c \x03(\tR\x0flockDeleteByTag\x12=\n" +
-	"\x1block_delete_when_incomplete\x18\x1d \x01(\bR\x18lockDeleteWhenIncomplete\"y\n" +
+	"\x1block_delete_when_incomplete\x18\x1d \x01(\bR\x18lockDeleteWhenIncomplete\x128\n" +
+	"\n" +
+	"lock_rules\x18\x1e \x03(\v2\x19.access_provider.LockRuleR\tlockRules\"\x93\x02\n" +
+	"\bLockRule\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05names\x18\x02 \x03(\tR\x05names\x12\x14\n" +
+	"\x05types\x18\x03 \x03(\tR\x05types\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12*\n" +
+	"\x11what_data_objects\x18\x05 \x03(\tR\x0fwhatDataObjects\x12\x18\n" +
+	"\aactions\x18\x06 \x03(\tR\aactions\x12'\n" +
+	"\x0fincomplete_only\x18\a \x01(\bR\x0eincompleteOnly\x12\x16\n" +
+	"\x06owners\x18\b \x03(\tR\x06owners\x12\x14\n" +
+	"\x05locks\x18\t \x03(\tR\x05locks\x12\x16\n" +
+	"\x06reason\x18\n" +
+	" \x01(\tR\x06reason\"y\n" +
 	"\x10AccessSyncResult\x121\n" +
 	"\x05error\x18\x01 \x01(\v2\x17.util.error.ErrorResultB\x02\x18\x01R\x05error\x122\n" +
 	"\x15access_provider_count\x18\x02 \x01(\x05R\x13accessProviderCount\"P\n" +
@@ -549,34 +696,36 @@ func file_access_provider_access_provider_proto_rawDescGZIP() []byte {
 	return file_access_provider_access_provider_proto_rawDescData
 }
 
-var file_access_provider_access_provider_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_access_provider_access_provider_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_access_provider_access_provider_proto_goTypes = []any{
 	(*AccessSyncToTarget)(nil),          // 0: access_provider.AccessSyncToTarget
 	(*AccessSyncFromTarget)(nil),        // 1: access_provider.AccessSyncFromTarget
-	(*AccessSyncResult)(nil),            // 2: access_provider.AccessSyncResult
-	(*AccessSyncConfig)(nil),            // 3: access_provider.AccessSyncConfig
-	(*config.ConfigMap)(nil),            // 4: util.config.ConfigMap
-	(*error1.ErrorResult)(nil),          // 5: util.error.ErrorResult
-	(*emptypb.Empty)(nil),               // 6: google.protobuf.Empty
-	(*version.CliBuildInformation)(nil), // 7: util.version.CliBuildInformation
+	(*LockRule)(nil),                    // 2: access_provider.LockRule
+	(*AccessSyncResult)(nil),            // 3: access_provider.AccessSyncResult
+	(*AccessSyncConfig)(nil),            // 4: access_provider.AccessSyncConfig
+	(*config.ConfigMap)(nil),            // 5: util.config.ConfigMap
+	(*error1.ErrorResult)(nil),          // 6: util.error.ErrorResult
+	(*emptypb.Empty)(nil),               // 7: google.protobuf.Empty
+	(*version.CliBuildInformation)(nil), // 8: util.version.CliBuildInformation
 }
 var file_access_provider_access_provider_proto_depIdxs = []int32{
-	4, // 0: access_provider.AccessSyncToTarget.config_map:type_name -> util.config.ConfigMap
-	4, // 1: access_provider.AccessSyncFromTarget.config_map:type_name -> util.config.ConfigMap
-	5, // 2: access_provider.AccessSyncResult.error:type_name -> util.error.ErrorResult
-	6, // 3: access_provider.AccessProviderSyncService.CliVersionInformation:input_type -> google.protobuf.Empty
-	1, // 4: access_provider.AccessProviderSyncService.SyncFromTarget:input_type -> access_provider.AccessSyncFromTarget
-	0, // 5: access_provider.AccessProviderSyncService.SyncToTarget:input_type -> access_provider.AccessSyncToTarget
-	6, // 6: access_provider.AccessProviderSyncService.SyncConfig:input_type -> google.protobuf.Empty
-	7, // 7: access_provider.AccessProviderSyncService.CliVersionInformation:output_type -> util.version.CliBuildInformation
-	2, // 8: access_provider.AccessProviderSyncService.SyncFromTarget:output_type -> access_provider.AccessSyncResult
-	2, // 9: access_provider.AccessProviderSyncService.SyncToTarget:output_type -> access_provider.AccessSyncResult
-	3, // 10: access_provider.AccessProviderSyncService.SyncConfig:output_type -> access_provider.AccessSyncConfig
-	7, // [7:11] is the sub-list for method output_type
-	3, // [3:7] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	5, // 0: access_provider.AccessSyncToTarget.config_map:type_name -> util.config.ConfigMap
+	5, // 1: access_provider.AccessSyncFromTarget.config_map:type_name -> util.config.ConfigMap
+	2, // 2: access_provider.AccessSyncFromTarget.lock_rules:type_name -> access_provider.LockRule
+	6, // 3: access_provider.AccessSyncResult.error:type_name -> util.error.ErrorResult
+	7, // 4: access_provider.AccessProviderSyncService.CliVersionInformation:input_type -> google.protobuf.Empty
+	1, // 5: access_provider.AccessProviderSyncService.SyncFromTarget:input_type -> access_provider.AccessSyncFromTarget
+	0, // 6: access_provider.AccessProviderSyncService.SyncToTarget:input_type -> access_provider.AccessSyncToTarget
+	7, // 7: access_provider.AccessProviderSyncService.SyncConfig:input_type -> google.protobuf.Empty
+	8, // 8: access_provider.AccessProviderSyncService.CliVersionInformation:output_type -> util.version.CliBuildInformation
+	3, // 9: access_provider.AccessProviderSyncService.SyncFromTarget:output_type -> access_provider.AccessSyncResult
+	3, // 10: access_provider.AccessProviderSyncService.SyncToTarget:output_type -> access_provider.AccessSyncResult
+	4, // 11: access_provider.AccessProviderSyncService.SyncConfig:output_type -> access_provider.AccessSyncConfig
+	8, // [8:12] is the sub-list for method output_type
+	4, // [4:8] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_access_provider_access_provider_proto_init() }
@@ -590,7 +739,7 @@ func file_access_provider_access_provider_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_access_provider_access_provider_proto_rawDesc), len(file_access_provider_access_provider_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
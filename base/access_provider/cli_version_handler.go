@@ -3,3 +3,7 @@ package access_provider
 import "github.com/Masterminds/semver/v3"
 
 var MinimalCliVersion = semver.MustParse("0.47.0-dev1")
+
+// LockRulesMinimalCliVersion is the first version of the CLI that passes the lock rules to the access syncer.
+// Connectors built with an older version silently ignore them.
+var LockRulesMinimalCliVersion = semver.MustParse("0.59.0-0")
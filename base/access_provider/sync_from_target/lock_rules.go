@@ -0,0 +1,289 @@
+package sync_from_target
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/smithy-go/ptr"
+
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/constants"
+)
+
+// The kinds of locks a lock rule can set on an access provider.
+const (
+	LockWho         = "who"
+	LockInheritance = "inheritance"
+	LockWhat        = "what"
+	LockName        = "name"
+	LockDelete      = "delete"
+	LockOwners      = "owners"
+	LockFully       = "fully"
+)
+
+// lockRule is the compiled version of an access_provider.LockRule.
+// All conditions that are set need to match for the rule to apply. Within one condition, matching any of the patterns is sufficient.
+type lockRule struct {
+	names          []*regexp.Regexp
+	types          []*regexp.Regexp
+	tags           []*regexp.Regexp
+	whatObjects    []*regexp.Regexp
+	actions        []*regexp.Regexp
+	owners         []*regexp.Regexp
+	incompleteOnly bool
+
+	locks  []string
+	reason string
+}
+
+type lockRules []*lockRule
+
+// compileLockRules compiles the lock rules in the config together with the rules that are equivalent to the (older) lock flags.
+func compileLockRules(config *access_provider.AccessSyncFromTarget) (lockRules, error) {
+	rules := legacyLockRules(config)
+	rules = append(rules, config.LockRules...)
+
+	result := make(lockRules, 0, len(rules))
+
+	for i, rule := range rules {
+		compiled, err := compileLockRule(rule)
+		if err != nil {
+			name := rule.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+
+			return nil, fmt.Errorf("lock rule %s: %w", name, err)
+		}
+
+		result = append(result, compiled)
+	}
+
+	return result, nil
+}
+
+func compileLockRule(rule *access_provider.LockRule) (*lockRule, error) {
+	if len(rule.Locks) == 0 {
+		return nil, fmt.Errorf("no locks defined")
+	}
+
+	for _, lock := range rule.Locks {
+		switch lock {
+		case LockWho, LockInheritance, LockWhat, LockName, LockDelete, LockOwners, LockFully:
+		default:
+			return nil, fmt.Errorf("unknown lock %q", lock)
+		}
+	}
+
+	result := &lockRule{
+		incompleteOnly: rule.IncompleteOnly,
+		locks:          rule.Locks,
+		reason:         rule.Reason,
+	}
+
+	conditions := []struct {
+		field    string
+		patterns []string
+		target   *[]*regexp.Regexp
+		flags    string
+	}{
+		{field: "names", patterns: rule.Names, target: &result.names},
+		{field: "types", patterns: rule.Types, target: &result.types},
+		{field: "tags", patterns: rule.Tags, target: &result.tags},
+		{field: "what-data-objects", patterns: rule.WhatDataObjects, target: &result.whatObjects},
+		{field: "actions", patterns: rule.Actions, target: &result.actions, flags: "(?i)"},
+		{field: "owners", patterns: rule.Owners, target: &result.owners},
+	}
+
+	for _, condition := range conditions {
+		for _, pattern := range condition.patterns {
+			re, err := regexp.Compile(condition.flags + "^(?:" + pattern + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", condition.field, err)
+			}
+
+			*condition.target = append(*condition.target, re)
+		}
+	}
+
+	return result, nil
+}
+
+// legacyLockRules translates the lock flags into the equivalent lock rules.
+func legacyLockRules(config *access_provider.AccessSyncFromTarget) []*access_provider.LockRule {
+	var rules []*access_provider.LockRule
+
+	addRules := func(lock string, all bool, byName []string, byTag []string, whenIncomplete bool) {
+		if all {
+			rules = append(rules, &access_provider.LockRule{Name: "lock-all-" + lock, Locks: []string{lock}})
+		}
+
+		if len(byName) > 0 {
+			rules = append(rules, &access_provider.LockRule{Name: "lock-" + lock + "-by-name", Names: byName, Locks: []string{lock}})
+		}
+
+		if len(byTag) > 0 {
+			rules = append(rules, &access_provider.LockRule{Name: "lock-" + lock + "-by-tag", Tags: byTag, Locks: []string{lock}})
+		}
+
+		if whenIncomplete {
+			rules = append(rules, &access_provider.LockRule{Name: "lock-" + lock + "-when-incomplete", IncompleteOnly: true, Locks: []string{lock}})
+		}
+	}
+
+	addRules(LockWho, config.LockAllWho, config.LockWhoByName, config.LockWhoByTag, config.LockWhoWhenIncomplete)
+	addRules(LockInheritance, config.LockAllInheritance, config.LockInheritanceByName, config.LockInheritanceByTag, config.LockInheritanceWhenIncomplete)
+	addRules(LockWhat, config.LockAllWhat, config.LockWhatByName, config.LockWhatByTag, config.LockWhatWhenIncomplete)
+	addRules(LockName, config.LockAllNames, config.LockNamesByName, config.LockNamesByTag, config.LockNamesWhenIncomplete)
+	addRules(LockDelete, config.LockAllDelete, config.LockDeleteByName, config.LockDeleteByTag, config.LockDeleteWhenIncomplete)
+	addRules(LockOwners, config.LockAllOwners, nil, nil, false)
+
+	// The legacy make-not-internalizable parameter is still supported for now, but will be removed in the future
+	if len(config.MakeNotInternalizable) > 0 {
+		rules = append(rules, &access_provider.LockRule{Name: "make-not-internalizable", Names: config.MakeNotInternalizable, Locks: []string{LockFully}})
+	}
+
+	addRules(LockFully, config.FullyLockAll, config.FullyLockByName, config.FullyLockByTag, config.FullyLockWhenIncomplete)
+
+	return rules
+}
+
+// apply sets the locks of all matching rules on the access provider.
+func (r lockRules) apply(ap *AccessProvider) {
+	for _, rule := range r {
+		if rule.matches(ap) {
+			rule.lock(ap)
+		}
+	}
+}
+
+func (r *lockRule) matches(ap *AccessProvider) bool {
+	if r.incompleteOnly && (ap.Incomplete == nil || !*ap.Incomplete) {
+		return false
+	}
+
+	if len(r.names) > 0 && !matchesAny(r.names, ap.Name) {
+		return false
+	}
+
+	if len(r.types) > 0 && (ap.Type == nil || !matchesAny(r.types, *ap.Type)) {
+		return false
+	}
+
+	if len(r.actions) > 0 && !matchesAny(r.actions, ap.Action.String()) {
+		return false
+	}
+
+	if len(r.tags) > 0 {
+		tags := make([]string, 0, len(ap.Tags))
+		for _, tag := range ap.Tags {
+			tags = append(tags, fmt.Sprintf("%s:%s", tag.Key, tag.Value))
+		}
+
+		if !matchesAny(r.tags, tags...) {
+			return false
+		}
+	}
+
+	if len(r.whatObjects) > 0 && !matchesAny(r.whatObjects, whatDataObjects(ap)...) {
+		return false
+	}
+
+	if len(r.owners) > 0 && !matchesAny(r.owners, owners(ap)...) {
+		return false
+	}
+
+	return true
+}
+
+func (r *lockRule) lock(ap *AccessProvider) {
+	var reason *string
+	if r.reason != "" {
+		reason = ptr.String(r.reason)
+	}
+
+	setLock := func(locked **bool, lockedReason **string) {
+		*locked = ptr.Bool(true)
+
+		// The first rule that provides a reason wins
+		if *lockedReason == nil {
+			*lockedReason = reason
+		}
+	}
+
+	for _, lock := range r.locks {
+		switch lock {
+		case LockWho:
+			setLock(&ap.WhoLocked, &ap.WhoLockedReason)
+		case LockInheritance:
+			setLock(&ap.InheritanceLocked, &ap.InheritanceLockedReason)
+		case LockWhat:
+			setLock(&ap.WhatLocked, &ap.WhatLockedReason)
+		case LockName:
+			setLock(&ap.NameLocked, &ap.NameLockedReason)
+		case LockDelete:
+			setLock(&ap.DeleteLocked, &ap.DeleteLockedReason)
+		case LockOwners:
+			setLock(&ap.OwnersLocked, &ap.OwnersLockedReason)
+		case LockFully:
+			ap.NotInternalizable = true
+		}
+	}
+}
+
+func matchesAny(patterns []*regexp.Regexp, values ...string) bool {
+	for _, value := range values {
+		for _, pattern := range patterns {
+			if pattern.MatchString(value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func whatDataObjects(ap *AccessProvider) []string {
+	var result []string
+
+	addWhat := func(what []WhatItem) {
+		for _, item := range what {
+			if item.DataObject != nil {
+				result = append(result, item.DataObject.FullName)
+			}
+		}
+	}
+
+	addWhat(ap.What)
+
+	for _, access := range ap.Access {
+		if access != nil {
+			addWhat(access.What)
+		}
+	}
+
+	return result
+}
+
+func owners(ap *AccessProvider) []string {
+	var result []string
+
+	if ap.Owners != nil {
+		result = append(result, ap.Owners.Users...)
+	}
+
+	for _, tag := range ap.Tags {
+		if tag.Key != constants.RaitoOwnerTagKey {
+			continue
+		}
+
+		for _, owner := range strings.Split(tag.Value, ",") {
+			if owner = strings.TrimSpace(owner); owner != "" {
+				result = append(result, owner)
+			}
+		}
+	}
+
+	return result
+}
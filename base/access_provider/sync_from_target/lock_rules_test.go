@@ -0,0 +1,173 @@
+package sync_from_target
+
+import (
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/access_provider/types"
+	"github.com/raito-io/cli/base/constants"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/tag"
+)
+
+func TestLockRule_Matches(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    *access_provider.LockRule
+		ap      *AccessProvider
+		matches bool
+	}{
+		{
+			name:    "no conditions",
+			rule:    &access_provider.LockRule{},
+			ap:      &AccessProvider{},
+			matches: true,
+		},
+		{
+			name:    "by name",
+			rule:    &access_provider.LockRule{Names: []string{"myname1"}},
+			ap:      &AccessProvider{Name: "myname1"},
+			matches: true,
+		},
+		{
+			name:    "by name - regex",
+			rule:    &access_provider.LockRule{Names: []string{"my.+", "another.+"}},
+			ap:      &AccessProvider{Name: "myname1"},
+			matches: true,
+		},
+		{
+			name:    "by name - must match completely",
+			rule:    &access_provider.LockRule{Names: []string{"name|other"}},
+			ap:      &AccessProvider{Name: "myname"},
+			matches: false,
+		},
+		{
+			name:    "by tag - regex",
+			rule:    &access_provider.LockRule{Tags: []string{"tag1:.+"}},
+			ap:      &AccessProvider{Tags: []*tag.Tag{{Key: "tag2", Value: "val1"}, {Key: "tag1", Value: "val1"}}},
+			matches: true,
+		},
+		{
+			name:    "by tag - no hit",
+			rule:    &access_provider.LockRule{Tags: []string{"tag1:.+"}},
+			ap:      &AccessProvider{Tags: []*tag.Tag{{Key: "tag2", Value: "val1"}}},
+			matches: false,
+		},
+		{
+			name:    "by type",
+			rule:    &access_provider.LockRule{Types: []string{"role"}},
+			ap:      &AccessProvider{Type: ptr.String("role")},
+			matches: true,
+		},
+		{
+			name:    "by type - no type",
+			rule:    &access_provider.LockRule{Types: []string{"role"}},
+			ap:      &AccessProvider{},
+			matches: false,
+		},
+		{
+			name:    "by action",
+			rule:    &access_provider.LockRule{Actions: []string{"Mask|Filtered"}},
+			ap:      &AccessProvider{Action: types.Filtered},
+			matches: true,
+		},
+		{
+			name:    "by action - no hit",
+			rule:    &access_provider.LockRule{Actions: []string{"Mask|Filtered"}},
+			ap:      &AccessProvider{Action: types.Grant},
+			matches: false,
+		},
+		{
+			name: "by what data object",
+			rule: &access_provider.LockRule{WhatDataObjects: []string{`PROD\..+`}},
+			ap: &AccessProvider{What: []WhatItem{
+				{DataObject: &data_source.DataObjectReference{FullName: "DEV.SCHEMA"}},
+				{DataObject: &data_source.DataObjectReference{FullName: "PROD.SCHEMA"}},
+			}},
+			matches: true,
+		},
+		{
+			name: "by what data object - deprecated access",
+			rule: &access_provider.LockRule{WhatDataObjects: []string{`PROD\..+`}},
+			ap: &AccessProvider{Access: []*Access{
+				{What: []WhatItem{{DataObject: &data_source.DataObjectReference{FullName: "PROD.SCHEMA"}}}},
+			}},
+			matches: true,
+		},
+		{
+			name:    "by owner",
+			rule:    &access_provider.LockRule{Owners: []string{"alice@.+"}},
+			ap:      &AccessProvider{Owners: &OwnersInput{Users: []string{"alice@raito.io"}}},
+			matches: true,
+		},
+		{
+			name:    "by owner - owner tag",
+			rule:    &access_provider.LockRule{Owners: []string{"bob@.+"}},
+			ap:      &AccessProvider{Tags: []*tag.Tag{{Key: constants.RaitoOwnerTagKey, Value: "alice@raito.io, bob@raito.io"}}},
+			matches: true,
+		},
+		{
+			name:    "incomplete only",
+			rule:    &access_provider.LockRule{IncompleteOnly: true},
+			ap:      &AccessProvider{Incomplete: ptr.Bool(false)},
+			matches: false,
+		},
+		{
+			name:    "all conditions must match",
+			rule:    &access_provider.LockRule{Names: []string{"my.+"}, Types: []string{"role"}, IncompleteOnly: true},
+			ap:      &AccessProvider{Name: "myname", Type: ptr.String("role")},
+			matches: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Locks = []string{LockWho}
+
+			rule, err := compileLockRule(tt.rule)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.matches, rule.matches(tt.ap))
+		})
+	}
+}
+
+func TestLockRules_FirstReasonWins(t *testing.T) {
+	rules, err := compileLockRules(&access_provider.AccessSyncFromTarget{
+		LockAllWho: true,
+		LockRules: []*access_provider.LockRule{
+			{Names: []string{"blah"}, Locks: []string{LockWho, LockFully}, Reason: "first"},
+			{Locks: []string{LockWho, LockOwners}, Reason: "second"},
+		},
+	})
+	require.NoError(t, err)
+
+	ap := AccessProvider{Name: "blah"}
+	rules.apply(&ap)
+
+	assert.True(t, ap.NotInternalizable)
+	assert.Equal(t, ptr.Bool(true), ap.WhoLocked)
+	assert.Equal(t, ptr.String("first"), ap.WhoLockedReason)
+	assert.Equal(t, ptr.Bool(true), ap.OwnersLocked)
+	assert.Equal(t, ptr.String("second"), ap.OwnersLockedReason)
+	assert.Nil(t, ap.WhatLocked)
+}
+
+func TestCompileLockRules_Invalid(t *testing.T) {
+	_, err := compileLockRules(&access_provider.AccessSyncFromTarget{
+		LockRules: []*access_provider.LockRule{{Name: "broken", Names: []string{"(("}, Locks: []string{LockWho}}},
+	})
+	assert.ErrorContains(t, err, "lock rule broken: parsing names")
+
+	_, err = compileLockRules(&access_provider.AccessSyncFromTarget{
+		LockRules: []*access_provider.LockRule{{Locks: []string{"everything"}}},
+	})
+	assert.ErrorContains(t, err, `lock rule #1: unknown lock "everything"`)
+
+	_, err = compileLockRules(&access_provider.AccessSyncFromTarget{LockWhoByName: []string{"(("}})
+	assert.ErrorContains(t, err, "lock rule lock-who-by-name: parsing names")
+}
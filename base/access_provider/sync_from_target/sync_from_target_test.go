@@ -137,105 +137,6 @@ func TestAccessProviderFileCreator(t *testing.T) {
 	assert.Nil(t, apsr[1].Type)
 }
 
-func TestShouldLock(t *testing.T) {
-	tests := []struct {
-		name         string
-		lockAll      bool
-		nameLocks    []string
-		tagLocks     []string
-		onIncomplete bool
-		ap           *AccessProvider
-		shouldLock   bool
-	}{
-		{
-			name:       "lock all",
-			lockAll:    true,
-			ap:         &AccessProvider{},
-			shouldLock: true,
-		},
-		{
-			name:       "no lock",
-			lockAll:    false,
-			ap:         &AccessProvider{},
-			shouldLock: false,
-		},
-		{
-			name:      "lock by name",
-			lockAll:   false,
-			nameLocks: []string{"myname1"},
-			ap: &AccessProvider{
-				Name: "myname1",
-			},
-			shouldLock: true,
-		},
-		{
-			name:      "lock by name - regex",
-			lockAll:   false,
-			nameLocks: []string{"my.+", "another.+"},
-			ap: &AccessProvider{
-				Name: "myname1",
-			},
-			shouldLock: true,
-		},
-		{
-			name:      "lock by tag",
-			lockAll:   false,
-			nameLocks: []string{"my.+", "another.+"},
-			tagLocks:  []string{"tag1:val1"},
-			ap: &AccessProvider{
-				Name: "blahname",
-				Tags: []*tag.Tag{
-					{
-						Key:   "tag1",
-						Value: "val1",
-					},
-				},
-			},
-			shouldLock: true,
-		},
-		{
-			name:      "lock by tag - regex",
-			lockAll:   false,
-			nameLocks: []string{"my.+", "another.+"},
-			tagLocks:  []string{"tag1:.+"},
-			ap: &AccessProvider{
-				Name: "blahname",
-				Tags: []*tag.Tag{
-					{
-						Key:   "tag1",
-						Value: "val1",
-					},
-				},
-			},
-			shouldLock: true,
-		},
-		{
-			name:      "lock by tag - regex - no hit",
-			lockAll:   false,
-			nameLocks: []string{"my.+", "another.+"},
-			tagLocks:  []string{"tag1:.+"},
-			ap: &AccessProvider{
-				Name: "blahname",
-				Tags: []*tag.Tag{
-					{
-						Key:   "tag2",
-						Value: "val1",
-					},
-				},
-			},
-			shouldLock: false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			lock, err := shouldLock("lock-name", tt.lockAll, tt.nameLocks, tt.tagLocks, tt.onIncomplete, tt.ap)
-			assert.NoError(t, err)
-			assert.Equal(t, tt.shouldLock, lock)
-		})
-	}
-}
-
 func TestCheckLocking(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -373,18 +274,44 @@ func TestCheckLocking(t *testing.T) {
 			ap:       &AccessProvider{Incomplete: ptr.Bool(true)},
 			resultAp: &AccessProvider{NameLocked: ptr.Bool(true)},
 		},
+
+		{
+			name:     "lock owners",
+			config:   &access_provider.AccessSyncFromTarget{LockAllOwners: true},
+			ap:       &AccessProvider{},
+			resultAp: &AccessProvider{OwnersLocked: ptr.Bool(true)},
+		},
+		{
+			name:     "make not internalizable",
+			config:   &access_provider.AccessSyncFromTarget{MakeNotInternalizable: []string{"bl.+"}},
+			ap:       &AccessProvider{Name: "blah"},
+			resultAp: &AccessProvider{NotInternalizable: true},
+		},
+		{
+			name: "lock rule",
+			config: &access_provider.AccessSyncFromTarget{LockRules: []*access_provider.LockRule{
+				{Names: []string{"bl.+"}, Locks: []string{LockWho, LockDelete}, Reason: "managed elsewhere"},
+			}},
+			ap:       &AccessProvider{Name: "blah"},
+			resultAp: &AccessProvider{WhoLocked: ptr.Bool(true), WhoLockedReason: ptr.String("managed elsewhere"), DeleteLocked: ptr.Bool(true), DeleteLockedReason: ptr.String("managed elsewhere")},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			checkAp := *tt.ap
-			err := checkLocking(&checkAp, tt.config)
+			rules, err := compileLockRules(tt.config)
 			assert.NoError(t, err)
+
+			rules.apply(&checkAp)
 			assert.Equal(t, tt.resultAp.NotInternalizable, checkAp.NotInternalizable)
 			assert.Equal(t, tt.resultAp.WhoLocked, checkAp.WhoLocked)
 			assert.Equal(t, tt.resultAp.WhatLocked, checkAp.WhatLocked)
 			assert.Equal(t, tt.resultAp.DeleteLocked, checkAp.DeleteLocked)
 			assert.Equal(t, tt.resultAp.NameLocked, checkAp.NameLocked)
+			assert.Equal(t, tt.resultAp.OwnersLocked, checkAp.OwnersLocked)
+			assert.Equal(t, tt.resultAp.WhoLockedReason, checkAp.WhoLockedReason)
+			assert.Equal(t, tt.resultAp.DeleteLockedReason, checkAp.DeleteLockedReason)
 		})
 	}
 }
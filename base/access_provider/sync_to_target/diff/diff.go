@@ -0,0 +1,320 @@
+// Package diff compares the desired state of an access provider with the current state of the corresponding role in the data source
+// and returns the changes that are needed to bring the role in the desired state.
+package diff
+
+import (
+	"sort"
+
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/data_source"
+)
+
+// CurrentState is the state of a role as it currently exists in the data source. It is provided by the connector.
+type CurrentState struct {
+	// Name is the name of the role in the data source
+	Name string
+
+	Users       []string
+	Groups      []string
+	InheritFrom []string
+	Recipients  []string
+
+	// What contains the permissions that are currently granted to the role on each data object
+	What []sync_to_target.WhatItem
+}
+
+// Rename describes that a role needs to be renamed in the data source, e.g. because the naming hint of the access provider changed.
+type Rename struct {
+	From string
+	To   string
+}
+
+// PermissionChange contains the permissions to grant or revoke on a data object.
+type PermissionChange struct {
+	DataObject  data_source.DataObjectReference
+	Permissions []string
+}
+
+// ChangeSet contains the changes to apply on a role in the data source to bring it in the desired state of the access provider.
+// All lists are sorted, so the changes are deterministic.
+type ChangeSet struct {
+	// Create is true if the role doesn't exist yet in the data source
+	Create bool
+
+	// Delete is true if the access provider is deleted and the role still exists in the data source
+	Delete bool
+
+	// Rename is set if the role exists in the data source under another name than the desired name
+	Rename *Rename
+
+	UsersToAdd    []string
+	UsersToRemove []string
+
+	GroupsToAdd    []string
+	GroupsToRemove []string
+
+	InheritFromToAdd    []string
+	InheritFromToRemove []string
+
+	RecipientsToAdd    []string
+	RecipientsToRemove []string
+
+	PermissionsToGrant  []PermissionChange
+	PermissionsToRevoke []PermissionChange
+
+	// state is the who state of the role once all changes are applied
+	state sync_to_target.AccessProviderWhoFeedbackState
+
+	// deleted is true if the access provider is deleted, regardless of whether the role still exists
+	deleted bool
+}
+
+// IsEmpty returns true if the role is already in the desired state.
+func (c *ChangeSet) IsEmpty() bool {
+	return !c.Create && !c.Delete && c.Rename == nil &&
+		len(c.UsersToAdd) == 0 && len(c.UsersToRemove) == 0 &&
+		len(c.GroupsToAdd) == 0 && len(c.GroupsToRemove) == 0 &&
+		len(c.InheritFromToAdd) == 0 && len(c.InheritFromToRemove) == 0 &&
+		len(c.RecipientsToAdd) == 0 && len(c.RecipientsToRemove) == 0 &&
+		len(c.PermissionsToGrant) == 0 && len(c.PermissionsToRevoke) == 0
+}
+
+// FeedbackState returns the state of the role once all changes are applied successfully, to be used in the feedback of the access provider.
+// Returns nil for a deleted access provider.
+func (c *ChangeSet) FeedbackState() *sync_to_target.AccessProviderFeedbackState {
+	if c.deleted {
+		return nil
+	}
+
+	return &sync_to_target.AccessProviderFeedbackState{Who: c.state}
+}
+
+type options struct {
+	inheritedRoleNames  map[string]string
+	onlyExplicitRemoval bool
+}
+
+// WithInheritedRoleNames maps the entries of InheritFrom (the actual names of the inherited access providers) to the names of their roles in the data source.
+// This is needed when the inherited access providers are renamed in the same sync. Entries that are not in the map are used as is.
+func WithInheritedRoleNames(names map[string]string) func(*options) {
+	return func(o *options) {
+		o.inheritedRoleNames = names
+	}
+}
+
+// WithOnlyExplicitRemoval only removes the users, groups, inherited roles, recipients and permissions that are explicitly removed from the access provider (DeletedWho and DeleteWhat).
+// This is useful for data sources in which the roles can also contain members or permissions that are not managed by Raito.
+// By default, everything in the current state that is not in the desired state is removed.
+func WithOnlyExplicitRemoval() func(*options) {
+	return func(o *options) {
+		o.onlyExplicitRemoval = true
+	}
+}
+
+// Calculate returns the changes to apply on the role in the data source to bring it in the desired state of the access provider.
+// The desiredName is the name the role should have in the data source (e.g. generated from the naming hint). The current state is nil if the role doesn't exist in the data source.
+func Calculate(desired *sync_to_target.AccessProvider, desiredName string, current *CurrentState, opts ...func(*options)) *ChangeSet {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	changes := &ChangeSet{deleted: desired.Delete}
+
+	if desired.Delete {
+		changes.Delete = current != nil
+
+		return changes
+	}
+
+	if current == nil {
+		changes.Create = true
+		current = &CurrentState{Name: desiredName}
+	} else if current.Name != desiredName {
+		changes.Rename = &Rename{From: current.Name, To: desiredName}
+	}
+
+	deletedWho := sync_to_target.WhoItem{}
+	if desired.DeletedWho != nil {
+		deletedWho = *desired.DeletedWho
+	}
+
+	inheritFrom := make([]string, 0, len(desired.Who.InheritFrom))
+	for _, name := range desired.Who.InheritFrom {
+		inheritFrom = append(inheritFrom, o.inheritedRoleName(name))
+	}
+
+	deletedInheritFrom := make([]string, 0, len(deletedWho.InheritFrom))
+	for _, name := range deletedWho.InheritFrom {
+		deletedInheritFrom = append(deletedInheritFrom, o.inheritedRoleName(name))
+	}
+
+	changes.UsersToAdd, changes.UsersToRemove, changes.state.Users = o.diffSets(desired.Who.Users, deletedWho.Users, current.Users)
+	changes.GroupsToAdd, changes.GroupsToRemove, changes.state.Groups = o.diffSets(desired.Who.Groups, deletedWho.Groups, current.Groups)
+	changes.InheritFromToAdd, changes.InheritFromToRemove, changes.state.InheritFrom = o.diffSets(inheritFrom, deletedInheritFrom, current.InheritFrom)
+	changes.RecipientsToAdd, changes.RecipientsToRemove, changes.state.Recipients = o.diffSets(desired.Who.Recipients, deletedWho.Recipients, current.Recipients)
+
+	changes.PermissionsToGrant, changes.PermissionsToRevoke = o.diffPermissions(desired.What, desired.DeleteWhat, current.What)
+
+	return changes
+}
+
+func (o *options) inheritedRoleName(name string) string {
+	if mapped, found := o.inheritedRoleNames[name]; found {
+		return mapped
+	}
+
+	return name
+}
+
+// diffSets returns the elements to add and to remove, and the resulting set once the changes are applied.
+func (o *options) diffSets(desired []string, deleted []string, current []string) ([]string, []string, []string) {
+	desiredSet := toSet(desired)
+	currentSet := toSet(current)
+
+	removable := currentSet
+	if o.onlyExplicitRemoval {
+		removable = toSet(deleted)
+	}
+
+	var toAdd, toRemove []string
+
+	result := make(map[string]struct{}, len(currentSet)+len(desiredSet))
+
+	for e := range desiredSet {
+		result[e] = struct{}{}
+
+		if _, found := currentSet[e]; !found {
+			toAdd = append(toAdd, e)
+		}
+	}
+
+	for e := range currentSet {
+		if _, found := desiredSet[e]; found {
+			continue
+		}
+
+		if _, found := removable[e]; found {
+			toRemove = append(toRemove, e)
+		} else {
+			result[e] = struct{}{}
+		}
+	}
+
+	return sorted(toAdd), sorted(toRemove), sortedKeys(result)
+}
+
+// diffPermissions returns the permissions to grant and to revoke per data object.
+func (o *options) diffPermissions(desired []sync_to_target.WhatItem, deleted []sync_to_target.WhatItem, current []sync_to_target.WhatItem) ([]PermissionChange, []PermissionChange) {
+	desiredPermissions := permissionsPerDataObject(desired)
+	currentPermissions := permissionsPerDataObject(current)
+
+	removablePermissions := currentPermissions
+	if o.onlyExplicitRemoval {
+		removablePermissions = permissionsPerDataObject(deleted)
+	}
+
+	var toGrant, toRevoke []PermissionChange
+
+	for dataObject, permissions := range desiredPermissions {
+		if missing := difference(permissions, currentPermissions[dataObject]); len(missing) > 0 {
+			toGrant = append(toGrant, PermissionChange{DataObject: dataObject, Permissions: missing})
+		}
+	}
+
+	for dataObject, permissions := range currentPermissions {
+		superfluous := intersection(difference(permissions, desiredPermissions[dataObject]), removablePermissions[dataObject])
+
+		if len(superfluous) > 0 {
+			toRevoke = append(toRevoke, PermissionChange{DataObject: dataObject, Permissions: superfluous})
+		}
+	}
+
+	return sortedChanges(toGrant), sortedChanges(toRevoke)
+}
+
+func permissionsPerDataObject(whatItems []sync_to_target.WhatItem) map[data_source.DataObjectReference]map[string]struct{} {
+	result := make(map[data_source.DataObjectReference]map[string]struct{})
+
+	for _, what := range whatItems {
+		if what.DataObject == nil {
+			continue
+		}
+
+		permissions, found := result[*what.DataObject]
+		if !found {
+			permissions = make(map[string]struct{})
+			result[*what.DataObject] = permissions
+		}
+
+		for _, permission := range what.Permissions {
+			permissions[permission] = struct{}{}
+		}
+	}
+
+	return result
+}
+
+// difference returns the sorted elements of a that are not in b.
+func difference(a map[string]struct{}, b map[string]struct{}) []string {
+	var result []string
+
+	for e := range a {
+		if _, found := b[e]; !found {
+			result = append(result, e)
+		}
+	}
+
+	return sorted(result)
+}
+
+// intersection returns the elements of the list that are in the set.
+func intersection(list []string, set map[string]struct{}) []string {
+	var result []string
+
+	for _, e := range list {
+		if _, found := set[e]; found {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+func toSet(list []string) map[string]struct{} {
+	result := make(map[string]struct{}, len(list))
+
+	for _, e := range list {
+		result[e] = struct{}{}
+	}
+
+	return result
+}
+
+func sorted(list []string) []string {
+	sort.Strings(list)
+
+	return list
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	result := make([]string, 0, len(set))
+
+	for e := range set {
+		result = append(result, e)
+	}
+
+	return sorted(result)
+}
+
+func sortedChanges(changes []PermissionChange) []PermissionChange {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].DataObject.FullName == changes[j].DataObject.FullName {
+			return changes[i].DataObject.Type < changes[j].DataObject.Type
+		}
+
+		return changes[i].DataObject.FullName < changes[j].DataObject.FullName
+	})
+
+	return changes
+}
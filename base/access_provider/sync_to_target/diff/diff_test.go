@@ -0,0 +1,171 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/data_source"
+)
+
+func table(name string) *data_source.DataObjectReference {
+	return &data_source.DataObjectReference{FullName: name, Type: "table"}
+}
+
+func TestCalculate(t *testing.T) {
+	desired := &sync_to_target.AccessProvider{
+		Id: "AP1",
+		Who: sync_to_target.WhoItem{
+			Users:       []string{"alice", "bob"},
+			Groups:      []string{"engineering"},
+			InheritFrom: []string{"OLD_ROLE", "OTHER_ROLE"},
+		},
+		DeletedWho: &sync_to_target.WhoItem{Users: []string{"carol"}},
+		What: []sync_to_target.WhatItem{
+			{DataObject: table("db.t1"), Permissions: []string{"SELECT", "INSERT"}},
+			{DataObject: table("db.t2"), Permissions: []string{"SELECT"}},
+		},
+		DeleteWhat: []sync_to_target.WhatItem{
+			{DataObject: table("db.t3"), Permissions: []string{"SELECT"}},
+		},
+	}
+
+	current := &CurrentState{
+		Name:        "ROLE_1",
+		Users:       []string{"alice", "carol", "dave"},
+		InheritFrom: []string{"OTHER_ROLE"},
+		What: []sync_to_target.WhatItem{
+			{DataObject: table("db.t1"), Permissions: []string{"SELECT", "DELETE"}},
+			{DataObject: table("db.t3"), Permissions: []string{"SELECT"}},
+			{DataObject: table("db.t4"), Permissions: []string{"SELECT"}},
+		},
+	}
+
+	type args struct {
+		desired     *sync_to_target.AccessProvider
+		desiredName string
+		current     *CurrentState
+		opts        []func(*options)
+	}
+
+	tests := []struct {
+		name          string
+		args          args
+		want          *ChangeSet
+		wantFeedback  *sync_to_target.AccessProviderFeedbackState
+		wantUnchanged bool
+	}{
+		{
+			name: "new role",
+			args: args{desired: desired, desiredName: "ROLE_1"},
+			want: &ChangeSet{
+				Create:           true,
+				UsersToAdd:       []string{"alice", "bob"},
+				GroupsToAdd:      []string{"engineering"},
+				InheritFromToAdd: []string{"OLD_ROLE", "OTHER_ROLE"},
+				PermissionsToGrant: []PermissionChange{
+					{DataObject: *table("db.t1"), Permissions: []string{"INSERT", "SELECT"}},
+					{DataObject: *table("db.t2"), Permissions: []string{"SELECT"}},
+				},
+			},
+			wantFeedback: &sync_to_target.AccessProviderFeedbackState{Who: sync_to_target.AccessProviderWhoFeedbackState{
+				Users:       []string{"alice", "bob"},
+				Groups:      []string{"engineering"},
+				InheritFrom: []string{"OLD_ROLE", "OTHER_ROLE"},
+				Recipients:  []string{},
+			}},
+		},
+		{
+			name: "existing role with renamed inherited role",
+			args: args{desired: desired, desiredName: "ROLE_1_NEW", current: current, opts: []func(*options){WithInheritedRoleNames(map[string]string{"OLD_ROLE": "NEW_ROLE"})}},
+			want: &ChangeSet{
+				Rename:           &Rename{From: "ROLE_1", To: "ROLE_1_NEW"},
+				UsersToAdd:       []string{"bob"},
+				UsersToRemove:    []string{"carol", "dave"},
+				GroupsToAdd:      []string{"engineering"},
+				InheritFromToAdd: []string{"NEW_ROLE"},
+				PermissionsToGrant: []PermissionChange{
+					{DataObject: *table("db.t1"), Permissions: []string{"INSERT"}},
+					{DataObject: *table("db.t2"), Permissions: []string{"SELECT"}},
+				},
+				PermissionsToRevoke: []PermissionChange{
+					{DataObject: *table("db.t1"), Permissions: []string{"DELETE"}},
+					{DataObject: *table("db.t3"), Permissions: []string{"SELECT"}},
+					{DataObject: *table("db.t4"), Permissions: []string{"SELECT"}},
+				},
+			},
+			wantFeedback: &sync_to_target.AccessProviderFeedbackState{Who: sync_to_target.AccessProviderWhoFeedbackState{
+				Users:       []string{"alice", "bob"},
+				Groups:      []string{"engineering"},
+				InheritFrom: []string{"NEW_ROLE", "OTHER_ROLE"},
+				Recipients:  []string{},
+			}},
+		},
+		{
+			name: "only explicit removal keeps unmanaged members and permissions",
+			args: args{desired: desired, desiredName: "ROLE_1", current: current, opts: []func(*options){WithOnlyExplicitRemoval()}},
+			want: &ChangeSet{
+				UsersToAdd:       []string{"bob"},
+				UsersToRemove:    []string{"carol"},
+				GroupsToAdd:      []string{"engineering"},
+				InheritFromToAdd: []string{"OLD_ROLE"},
+				PermissionsToGrant: []PermissionChange{
+					{DataObject: *table("db.t1"), Permissions: []string{"INSERT"}},
+					{DataObject: *table("db.t2"), Permissions: []string{"SELECT"}},
+				},
+				PermissionsToRevoke: []PermissionChange{
+					{DataObject: *table("db.t3"), Permissions: []string{"SELECT"}},
+				},
+			},
+			wantFeedback: &sync_to_target.AccessProviderFeedbackState{Who: sync_to_target.AccessProviderWhoFeedbackState{
+				Users:       []string{"alice", "bob", "dave"},
+				Groups:      []string{"engineering"},
+				InheritFrom: []string{"OLD_ROLE", "OTHER_ROLE"},
+				Recipients:  []string{},
+			}},
+		},
+		{
+			name: "deleted access provider",
+			args: args{desired: &sync_to_target.AccessProvider{Id: "AP1", Delete: true}, desiredName: "ROLE_1", current: current},
+			want: &ChangeSet{Delete: true},
+		},
+		{
+			name:          "deleted access provider without role",
+			args:          args{desired: &sync_to_target.AccessProvider{Id: "AP1", Delete: true}, desiredName: "ROLE_1"},
+			want:          &ChangeSet{},
+			wantUnchanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Calculate(tt.args.desired, tt.args.desiredName, tt.args.current, tt.args.opts...)
+
+			assert.Equal(t, tt.wantFeedback, got.FeedbackState())
+			assert.Equal(t, tt.wantUnchanged, got.IsEmpty())
+
+			// The resulting state is verified through the feedback state
+			got.state = sync_to_target.AccessProviderWhoFeedbackState{}
+			got.deleted = false
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCalculate_Unchanged(t *testing.T) {
+	desired := &sync_to_target.AccessProvider{
+		Id:   "AP1",
+		Who:  sync_to_target.WhoItem{Users: []string{"alice"}},
+		What: []sync_to_target.WhatItem{{DataObject: table("db.t1"), Permissions: []string{"SELECT"}}},
+	}
+
+	current := &CurrentState{
+		Name:  "ROLE_1",
+		Users: []string{"alice"},
+		What:  []sync_to_target.WhatItem{{DataObject: table("db.t1"), Permissions: []string{"SELECT"}}},
+	}
+
+	assert.True(t, Calculate(desired, "ROLE_1", current).IsEmpty())
+}
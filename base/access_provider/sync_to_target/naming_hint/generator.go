@@ -11,16 +11,13 @@ import (
 	"github.com/raito-io/cli/base/access_provider/sync_to_target"
 )
 
-type UniqueGenerator interface {
-
-	// Generate create unique and consistent name that can be used in the target data source to create access elements.
-	// The argument ap is an access provider pointer. Each next ap that is provided to the class should have an ascending ID to guarantee a deterministic ID generation
-	// The output is a map that will point AccessIDs to the unique generate name
-	Generate(ap *sync_to_target.AccessProvider) (string, error)
-}
+// UniqueGenerator generates unique and consistent names that can be used in the target data source to create access elements.
+//
+// Deprecated: use NamingStrategy instead.
+type UniqueGenerator = NamingStrategy
 
 // uniqueNameGenerator implements a Generate method which generates unique names that can be used to create access elements.
-// If defined, a prefix is used when generating the names.
+// If defined, a prefix or template (see NamingStrategyConfig) is used when generating the names.
 // If the naming hint of an accessProvider is specified, the naming hint will be reformed to a name only consisting valid characters.
 // After that, a validation is executed to check if the valid name should be post-fixed with a unique ID.
 // The post-fixes start with two splitCharacters and end with a 4 character hexadecimal number. Note that this is the only place that 2 splitCharacters can be used after each other.
@@ -38,6 +35,7 @@ type UniqueGenerator interface {
 type uniqueNameGenerator struct {
 	logger         hclog.Logger
 	prefix         string
+	template       *nameTemplate
 	constraints    *NamingConstraints
 	splitCharacter rune
 	translator     Translator
@@ -45,7 +43,13 @@ type uniqueNameGenerator struct {
 }
 
 // NewUniqueNameGenerator will create an implementation of the UniqueGenerator interface. The UniqueGenerator will ensure the constraints provided in the first argument
+//
+// Deprecated: use NewNamingStrategy instead, which also supports templates and hash based uniqueness.
 func NewUniqueNameGenerator(logger hclog.Logger, prefix string, constraints *NamingConstraints) (UniqueGenerator, error) {
+	return newUniqueNameGenerator(logger, prefix, nil, constraints)
+}
+
+func newUniqueNameGenerator(logger hclog.Logger, prefix string, template *nameTemplate, constraints *NamingConstraints) (*uniqueNameGenerator, error) {
 	if constraints.SplitCharacter() == 0 {
 		return nil, errors.New("no support for UniqueGenerator if no split character is defined")
 	}
@@ -66,6 +70,7 @@ func NewUniqueNameGenerator(logger hclog.Logger, prefix string, constraints *Nam
 	return &uniqueNameGenerator{
 		logger:         logger,
 		prefix:         prefix,
+		template:       template,
 		constraints:    constraints,
 		translator:     translator,
 		splitCharacter: constraints.SplitCharacter(),
@@ -77,22 +82,11 @@ func (g *uniqueNameGenerator) Generate(ap *sync_to_target.AccessProvider) (strin
 	// Reserve 6 character for post fix ID
 	maxLength := g.constraints.MaxLength - 6
 
-	var nameHinting string
-	if ap.NamingHint != "" {
-		nameHinting = ap.NamingHint
-	} else {
-		nameHinting = ap.Name
-	}
-
-	name, err := g.translator.Translate(g.prefix + nameHinting)
+	name, err := g.template.baseName(g.prefix, ap, g.translator, maxLength)
 	if err != nil {
 		return "", err
 	}
 
-	if uint(len(name)) > maxLength {
-		name = name[:maxLength]
-	}
-
 	if ap.ActualName != nil && *ap.ActualName == ap.NamingHint {
 		// This case is when external access provider is imported. We try to avoid renaming if possible.
 		if _, found := g.existingNames[*ap.ActualName]; !found {
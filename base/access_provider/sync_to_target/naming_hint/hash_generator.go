@@ -0,0 +1,126 @@
+package naming_hint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+)
+
+// hashNameGenerator generates names that are post-fixed with a hash of the access provider ID.
+// Unlike the uniqueNameGenerator, the generated names do not depend on the other access providers or the order in which they are handled.
+// The post-fix starts with two split characters (if the constraints allow a split character) followed by the hash.
+// When numbers are not allowed, the hash is encoded with the letters 'a' to 'p' instead of hexadecimal characters.
+// Example:
+//
+//	constraints: Uppercase, Numbers, '_', maxLength: 32
+//	- AP{id: "ap1", namingHint: "analysts"} => "ANALYSTS__1B91B5"
+//	- AP{id: "ap2", namingHint: "analysts"} => "ANALYSTS__009394"
+type hashNameGenerator struct {
+	logger      hclog.Logger
+	prefix      string
+	template    *nameTemplate
+	constraints *NamingConstraints
+	translator  Translator
+	separator   string
+	hashLength  uint
+
+	// existingNames maps the generated names to the ID of the access provider they were generated for
+	existingNames map[string]string
+}
+
+func newHashNameGenerator(logger hclog.Logger, prefix string, template *nameTemplate, hashLength uint, constraints *NamingConstraints) (*hashNameGenerator, error) {
+	if hashLength == 0 {
+		hashLength = defaultHashLength
+	}
+
+	if hashLength > sha256.Size*2 {
+		return nil, fmt.Errorf("the hash length can be at most %d", sha256.Size*2)
+	}
+
+	separator := ""
+	if splitCharacter := constraints.SplitCharacter(); splitCharacter != 0 {
+		separator = fmt.Sprintf("%[1]c%[1]c", splitCharacter)
+	}
+
+	if constraints.MaxLength < hashLength+uint(len(separator))+2 {
+		return nil, fmt.Errorf("no support if maximum characters is less than %d", hashLength+uint(len(separator))+2)
+	}
+
+	translator, err := NewNameHintTranslator(constraints)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hashNameGenerator{
+		logger:        logger,
+		prefix:        prefix,
+		template:      template,
+		constraints:   constraints,
+		translator:    translator,
+		separator:     separator,
+		hashLength:    hashLength,
+		existingNames: make(map[string]string),
+	}, nil
+}
+
+func (g *hashNameGenerator) Generate(ap *sync_to_target.AccessProvider) (string, error) {
+	if ap.ActualName != nil && *ap.ActualName == ap.NamingHint {
+		// This case is when external access provider is imported. We try to avoid renaming if possible.
+		if _, found := g.existingNames[*ap.ActualName]; !found {
+			g.existingNames[*ap.ActualName] = ap.Id
+
+			return *ap.ActualName, nil
+		}
+	}
+
+	// Only fall back to a longer hash when the shorter one collides with the name of another access provider
+	for hashLength := g.hashLength; hashLength <= sha256.Size*2; hashLength += 2 {
+		if hashLength+uint(len(g.separator))+1 > g.constraints.MaxLength {
+			break
+		}
+
+		name, err := g.template.baseName(g.prefix, ap, g.translator, g.constraints.MaxLength-hashLength-uint(len(g.separator)))
+		if err != nil {
+			return "", err
+		}
+
+		name = fmt.Sprintf("%s%s%s", name, g.separator, g.hash(ap.Id, hashLength))
+
+		if apId, found := g.existingNames[name]; !found || apId == ap.Id {
+			g.existingNames[name] = ap.Id
+
+			g.logger.Info(fmt.Sprintf("Generate unique name for ap %q: %+v", ap.Name, name))
+
+			return name, nil
+		}
+	}
+
+	return "", errors.New("unable to generate a unique name for access provider " + ap.Id)
+}
+
+func (g *hashNameGenerator) hash(id string, length uint) string {
+	sum := sha256.Sum256([]byte(id))
+	hash := hex.EncodeToString(sum[:])[:length]
+
+	if !g.constraints.Numbers {
+		hash = strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return 'a' + (r - '0')
+			}
+
+			return 'k' + (r - 'a')
+		}, hash)
+	}
+
+	if !g.constraints.LowerCaseLetters {
+		hash = strings.ToUpper(hash)
+	}
+
+	return hash
+}
@@ -0,0 +1,242 @@
+package naming_hint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/util/config"
+)
+
+// The target parameters that can be used to configure the naming strategy (see NamingStrategyConfigFromParameters).
+const (
+	NamingPrefixParameter     = "naming-prefix"
+	NamingTemplateParameter   = "naming-template"
+	NamingUniquenessParameter = "naming-uniqueness"
+	NamingHashLengthParameter = "naming-hash-length"
+)
+
+// The placeholders that can be used in a naming template.
+const (
+	PlaceholderPrefix = "{prefix}"
+	PlaceholderType   = "{type}"
+	PlaceholderHint   = "{hint}"
+	PlaceholderOwner  = "{owner}"
+	PlaceholderParent = "{parent}"
+)
+
+// UniquenessMode defines how a naming strategy makes sure the generated names are unique.
+type UniquenessMode string
+
+const (
+	// UniquenessCounter only post-fixes a name with a counter (e.g. NAME__1) when the name is already in use.
+	// The resulting names depend on the order in which the access providers are handled.
+	UniquenessCounter UniquenessMode = "counter"
+	// UniquenessHash always post-fixes a name with a hash of the access provider ID (e.g. NAME__3FA2C1).
+	// The resulting names only depend on the access provider itself, so they are the same across data sources.
+	UniquenessHash UniquenessMode = "hash"
+)
+
+const defaultHashLength = 6
+
+var placeholderRegex = regexp.MustCompile(`\{[^{}]*}`)
+
+// NamingStrategy generates the names of the access elements (roles, policies, ...) that are created in the data source for access providers.
+type NamingStrategy interface {
+	// Generate creates a name for the given access provider that respects the naming constraints of the data source.
+	Generate(ap *sync_to_target.AccessProvider) (string, error)
+}
+
+// NamingStrategyConfig contains the configuration of a NamingStrategy.
+type NamingStrategyConfig struct {
+	// Prefix is used for the {prefix} placeholder. Without a template, it is put in front of the naming hint.
+	Prefix string
+
+	// Template defines the format of the generated names (e.g. "PROD_{parent}_{hint}_ROLE").
+	// The supported placeholders are {prefix}, {type}, {hint}, {owner} and {parent}.
+	// If empty, the prefix followed by the naming hint is used.
+	Template string
+
+	// Uniqueness defines how names are made unique. Defaults to UniquenessCounter.
+	Uniqueness UniquenessMode
+
+	// HashLength is the number of characters of the hash post-fix when UniquenessHash is used. Defaults to 6.
+	HashLength uint
+}
+
+// NamingStrategyConfigFromParameters reads the naming strategy configuration from the target parameters.
+// The given prefix is used when no prefix is configured in the parameters.
+func NamingStrategyConfigFromParameters(prefix string, configMap *config.ConfigMap) NamingStrategyConfig {
+	if configMap == nil {
+		return NamingStrategyConfig{Prefix: prefix}
+	}
+
+	return NamingStrategyConfig{
+		Prefix:     configMap.GetStringWithDefault(NamingPrefixParameter, prefix),
+		Template:   configMap.GetString(NamingTemplateParameter),
+		Uniqueness: UniquenessMode(strings.ToLower(configMap.GetString(NamingUniquenessParameter))),
+		HashLength: uint(max(configMap.GetInt(NamingHashLengthParameter), 0)), //nolint:gosec
+	}
+}
+
+// NewNamingStrategy creates the NamingStrategy described by the given configuration. The generated names will respect the given constraints.
+func NewNamingStrategy(logger hclog.Logger, constraints *NamingConstraints, strategyConfig NamingStrategyConfig) (NamingStrategy, error) {
+	template, err := parseNameTemplate(strategyConfig.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strategyConfig.Uniqueness {
+	case UniquenessCounter, "":
+		return newUniqueNameGenerator(logger, strategyConfig.Prefix, template, constraints)
+	case UniquenessHash:
+		return newHashNameGenerator(logger, strategyConfig.Prefix, template, strategyConfig.HashLength, constraints)
+	default:
+		return nil, fmt.Errorf("unknown naming uniqueness %q (expected %q or %q)", strategyConfig.Uniqueness, UniquenessCounter, UniquenessHash)
+	}
+}
+
+// nameTemplate renders the base name of an access provider, before it is made unique.
+// A nil nameTemplate renders the prefix followed by the naming hint.
+type nameTemplate struct {
+	template string
+}
+
+func parseNameTemplate(template string) (*nameTemplate, error) {
+	if template == "" {
+		return nil, nil
+	}
+
+	for _, placeholder := range placeholderRegex.FindAllString(template, -1) {
+		switch placeholder {
+		case PlaceholderPrefix, PlaceholderType, PlaceholderHint, PlaceholderOwner, PlaceholderParent:
+		default:
+			return nil, fmt.Errorf("unknown placeholder %s in naming template %q", placeholder, template)
+		}
+	}
+
+	return &nameTemplate{template: template}, nil
+}
+
+// baseName renders and translates the name of the access provider, making sure it is at most maxLength characters long.
+func (t *nameTemplate) baseName(prefix string, ap *sync_to_target.AccessProvider, translator Translator, maxLength uint) (string, error) {
+	hint := namingHint(ap)
+
+	if t == nil {
+		// Kept as is, so the names of existing access providers don't change
+		name, err := translator.Translate(prefix + hint)
+		if err != nil {
+			return "", err
+		}
+
+		if uint(len(name)) > maxLength {
+			name = name[:maxLength]
+		}
+
+		return name, nil
+	}
+
+	name, err := translator.Translate(t.render(prefix, hint, ap))
+	if err != nil {
+		return "", err
+	}
+
+	if uint(len(name)) <= maxLength {
+		return name, nil
+	}
+
+	// Shorten the naming hint first, so the fixed parts of the template are kept
+	translatedHint, err := translator.Translate(hint)
+	if err != nil {
+		return "", err
+	}
+
+	overflow := uint(len(name)) - maxLength
+	if overflow < uint(len(translatedHint)) {
+		name, err = translator.Translate(t.render(prefix, translatedHint[:uint(len(translatedHint))-overflow], ap))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if uint(len(name)) > maxLength {
+		name = name[:maxLength]
+	}
+
+	return name, nil
+}
+
+func (t *nameTemplate) render(prefix string, hint string, ap *sync_to_target.AccessProvider) string {
+	var apType string
+	if ap.Type != nil {
+		apType = *ap.Type
+	}
+
+	return strings.NewReplacer(
+		PlaceholderPrefix, prefix,
+		PlaceholderType, apType,
+		PlaceholderHint, hint,
+		PlaceholderOwner, owner(ap),
+		PlaceholderParent, dataObjectParent(ap),
+	).Replace(t.template)
+}
+
+func namingHint(ap *sync_to_target.AccessProvider) string {
+	if ap.NamingHint != "" {
+		return ap.NamingHint
+	}
+
+	return ap.Name
+}
+
+// owner returns the account name, email or group name of the first owner of the access provider.
+func owner(ap *sync_to_target.AccessProvider) string {
+	for _, o := range ap.Owners {
+		switch {
+		case o.AccountName != nil && *o.AccountName != "":
+			return *o.AccountName
+		case o.Email != nil && *o.Email != "":
+			return *o.Email
+		case o.GroupName != nil && *o.GroupName != "":
+			return *o.GroupName
+		}
+	}
+
+	return ""
+}
+
+// dataObjectParent returns the closest common parent of the data objects in the what of the access provider.
+// For example, the parent of "DB.SCHEMA.TABLE1" and "DB.SCHEMA.TABLE2" is "DB.SCHEMA".
+func dataObjectParent(ap *sync_to_target.AccessProvider) string {
+	var parent []string
+
+	first := true
+
+	for _, what := range ap.What {
+		if what.DataObject == nil {
+			continue
+		}
+
+		path := strings.Split(what.DataObject.FullName, ".")
+		path = path[:len(path)-1]
+
+		if first {
+			parent = path
+			first = false
+
+			continue
+		}
+
+		common := 0
+		for common < len(parent) && common < len(path) && parent[common] == path[common] {
+			common++
+		}
+
+		parent = parent[:common]
+	}
+
+	return strings.Join(parent, ".")
+}
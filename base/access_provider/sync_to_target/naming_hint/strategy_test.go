@@ -0,0 +1,138 @@
+package naming_hint
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/util/config"
+)
+
+var snowflakeConstraints = NamingConstraints{
+	UpperCaseLetters:  true,
+	LowerCaseLetters:  false,
+	Numbers:           true,
+	MaxLength:         32,
+	SpecialCharacters: "_$",
+}
+
+func TestNamingStrategy_Template(t *testing.T) {
+	strategy, err := NewNamingStrategy(logger, &snowflakeConstraints, NamingStrategyConfig{
+		Prefix:   "prod",
+		Template: "{prefix}_{parent}_{hint}_ROLE",
+	})
+	require.NoError(t, err)
+
+	ap := &sync_to_target.AccessProvider{
+		Id:         "ap1",
+		NamingHint: "salesAnalysts",
+		What: []sync_to_target.WhatItem{
+			{DataObject: &data_source.DataObjectReference{FullName: "SALES.PUBLIC.ORDERS"}},
+			{DataObject: &data_source.DataObjectReference{FullName: "SALES.PUBLIC.CUSTOMERS"}},
+		},
+	}
+
+	name, err := strategy.Generate(ap)
+	require.NoError(t, err)
+	assert.Equal(t, "PROD_SALES_PUBLIC_SAL_ROLE", name)
+
+	name, err = strategy.Generate(&sync_to_target.AccessProvider{Id: "ap2", NamingHint: "salesAnalysts", What: ap.What})
+	require.NoError(t, err)
+	assert.Equal(t, "PROD_SALES_PUBLIC_SAL_ROLE__0", name)
+}
+
+func TestNamingStrategy_TemplatePlaceholders(t *testing.T) {
+	strategy, err := NewNamingStrategy(logger, &snowflakeConstraints, NamingStrategyConfig{Template: "{type}_{owner}_{hint}"})
+	require.NoError(t, err)
+
+	name, err := strategy.Generate(&sync_to_target.AccessProvider{
+		Id:     "ap1",
+		Name:   "Finance",
+		Type:   ptr.String("role"),
+		Owners: []sync_to_target.Owner{{GroupName: ptr.String("team-a")}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ROLE_TEAM_A_FINANCE", name)
+}
+
+func TestNamingStrategy_Hash(t *testing.T) {
+	strategy, err := NewNamingStrategy(logger, &snowflakeConstraints, NamingStrategyConfig{Uniqueness: UniquenessHash})
+	require.NoError(t, err)
+
+	name1, err := strategy.Generate(&sync_to_target.AccessProvider{Id: "ap1", NamingHint: "analysts"})
+	require.NoError(t, err)
+
+	name2, err := strategy.Generate(&sync_to_target.AccessProvider{Id: "ap2", NamingHint: "analysts"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "ANALYSTS__1B91B5", name1)
+	assert.Equal(t, "ANALYSTS__009394", name2)
+	assert.NotEqual(t, name1, name2)
+
+	// The names don't depend on the order in which the access providers are handled
+	otherStrategy, err := NewNamingStrategy(logger, &snowflakeConstraints, NamingStrategyConfig{Uniqueness: UniquenessHash})
+	require.NoError(t, err)
+
+	name, err := otherStrategy.Generate(&sync_to_target.AccessProvider{Id: "ap2", NamingHint: "analysts"})
+	require.NoError(t, err)
+	assert.Equal(t, name2, name)
+
+	name, err = otherStrategy.Generate(&sync_to_target.AccessProvider{Id: "ap1", NamingHint: "analysts"})
+	require.NoError(t, err)
+	assert.Equal(t, name1, name)
+}
+
+func TestNamingStrategy_Hash_ImportedAccessProvider(t *testing.T) {
+	strategy, err := NewNamingStrategy(logger, &snowflakeConstraints, NamingStrategyConfig{Uniqueness: UniquenessHash})
+	require.NoError(t, err)
+
+	name, err := strategy.Generate(&sync_to_target.AccessProvider{Id: "ap1", NamingHint: "EXISTING_ROLE", ActualName: ptr.String("EXISTING_ROLE")})
+	require.NoError(t, err)
+	assert.Equal(t, "EXISTING_ROLE", name)
+}
+
+func TestNamingStrategy_Hash_NoNumbersOrSplitCharacter(t *testing.T) {
+	constraints := NamingConstraints{
+		LowerCaseLetters: true,
+		UpperCaseLetters: true,
+		MaxLength:        16,
+	}
+
+	_, err := NewUniqueNameGenerator(logger, "", &constraints)
+	require.Error(t, err)
+
+	strategy, err := NewNamingStrategy(logger, &constraints, NamingStrategyConfig{Uniqueness: UniquenessHash, HashLength: 4})
+	require.NoError(t, err)
+
+	name, err := strategy.Generate(&sync_to_target.AccessProvider{Id: "ap1", NamingHint: "the data analysts"})
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile("^theDataAnaly[a-p]{4}$"), name)
+}
+
+func TestNewNamingStrategy_Invalid(t *testing.T) {
+	_, err := NewNamingStrategy(logger, &snowflakeConstraints, NamingStrategyConfig{Template: "{env}_{hint}"})
+	assert.ErrorContains(t, err, "unknown placeholder {env}")
+
+	_, err = NewNamingStrategy(logger, &snowflakeConstraints, NamingStrategyConfig{Uniqueness: "random"})
+	assert.ErrorContains(t, err, `unknown naming uniqueness "random"`)
+
+	_, err = NewNamingStrategy(logger, &snowflakeConstraints, NamingStrategyConfig{Uniqueness: UniquenessHash, HashLength: 40})
+	assert.ErrorContains(t, err, "no support if maximum characters is less than 44")
+}
+
+func TestNamingStrategyConfigFromParameters(t *testing.T) {
+	assert.Equal(t, NamingStrategyConfig{Prefix: "RAITO_"}, NamingStrategyConfigFromParameters("RAITO_", nil))
+
+	strategyConfig := NamingStrategyConfigFromParameters("RAITO_", &config.ConfigMap{Parameters: map[string]string{
+		NamingTemplateParameter:   "DEV_{hint}_ROLE",
+		NamingUniquenessParameter: "Hash",
+		NamingHashLengthParameter: "8",
+	}})
+
+	assert.Equal(t, NamingStrategyConfig{Prefix: "RAITO_", Template: "DEV_{hint}_ROLE", Uniqueness: UniquenessHash, HashLength: 8}, strategyConfig)
+}
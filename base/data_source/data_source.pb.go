@@ -34,8 +34,18 @@ type DataSourceSyncConfig struct {
 	DataSourceId       string                 `protobuf:"bytes,3,opt,name=data_source_id,json=dataSourceId,proto3" json:"data_source_id,omitempty"`
 	DataObjectParent   string                 `protobuf:"bytes,4,opt,name=data_object_parent,json=dataObjectParent,proto3" json:"data_object_parent,omitempty"`
 	DataObjectExcludes []string               `protobuf:"bytes,5,rep,name=data_object_excludes,json=dataObjectExcludes,proto3" json:"data_object_excludes,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	// incremental indicates that the plugin only needs to export the data objects that changed since the last successful sync (and the deleted ones).
+	Incremental bool `protobuf:"varint,6,opt,name=incremental,proto3" json:"incremental,omitempty"`
+	// watermark is the opaque cursor returned by the plugin at the end of the last successful sync.
+	Watermark string `protobuf:"bytes,7,opt,name=watermark,proto3" json:"watermark,omitempty"`
+	// last_successful_sync is the start time (as unix timestamp in seconds) of the last successful sync.
+	LastSuccessfulSync int64 `protobuf:"varint,8,opt,name=last_successful_sync,json=lastSuccessfulSync,proto3" json:"last_successful_sync,omitempty"`
+	// deleted_objects_file is the file in which the external ids of the deleted data objects are written during an incremental sync.
+	DeletedObjectsFile string `protobuf:"bytes,9,opt,name=deleted_objects_file,json=deletedObjectsFile,proto3" json:"deleted_objects_file,omitempty"`
+	// checkpoint_dir is the directory in which the plugin can store checkpoints, so a retried sync can resume from the last checkpoint. Checkpoints are disabled if empty.
+	CheckpointDir string `protobuf:"bytes,10,opt,name=checkpoint_dir,json=checkpointDir,proto3" json:"checkpoint_dir,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DataSourceSyncConfig) Reset() {
@@ -103,13 +113,55 @@ func (x *DataSourceSyncConfig) GetDataObjectExcludes() []string {
 	return nil
 }
 
+func (x *DataSourceSyncConfig) GetIncremental() bool {
+	if x != nil {
+		return x.Incremental
+	}
+	return false
+}
+
+func (x *DataSourceSyncConfig) GetWatermark() string {
+	if x != nil {
+		return x.Watermark
+	}
+	return ""
+}
+
+func (x *DataSourceSyncConfig) GetLastSuccessfulSync() int64 {
+	if x != nil {
+		return x.LastSuccessfulSync
+	}
+	return 0
+}
+
+func (x *DataSourceSyncConfig) GetDeletedObjectsFile() string {
+	if x != nil {
+		return x.DeletedObjectsFile
+	}
+	return ""
+}
+
+func (x *DataSourceSyncConfig) GetCheckpointDir() string {
+	if x != nil {
+		return x.CheckpointDir
+	}
+	return ""
+}
+
 // DataSourceSyncResult represents the result from the data source sync process.
 // A potential error is also modeled in here so specific errors remain intact when passed over RPC.
 type DataSourceSyncResult struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Deprecated: Marked as deprecated in data_source/data_source.proto.
-	Error         *error1.ErrorResult `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	DataObjects   int32               `protobuf:"varint,2,opt,name=data_objects,json=dataObjects,proto3" json:"data_objects,omitempty"`
+	Error       *error1.ErrorResult `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	DataObjects int32               `protobuf:"varint,2,opt,name=data_objects,json=dataObjects,proto3" json:"data_objects,omitempty"`
+	// incremental indicates whether the plugin exported the data source incrementally.
+	Incremental bool `protobuf:"varint,3,opt,name=incremental,proto3" json:"incremental,omitempty"`
+	// watermark is the opaque cursor to pass to the next incremental sync.
+	Watermark          string `protobuf:"bytes,4,opt,name=watermark,proto3" json:"watermark,omitempty"`
+	DeletedDataObjects int32  `protobuf:"varint,5,opt,name=deleted_data_objects,json=deletedDataObjects,proto3" json:"deleted_data_objects,omitempty"`
+	// part_files contains the files in which the data objects are stored when checkpoints were used. The CLI combines them into the target file.
+	PartFiles     []string `protobuf:"bytes,6,rep,name=part_files,json=partFiles,proto3" json:"part_files,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -159,6 +211,34 @@ func (x *DataSourceSyncResult) GetDataObjects() int32 {
 	return 0
 }
 
+func (x *DataSourceSyncResult) GetIncremental() bool {
+	if x != nil {
+		return x.Incremental
+	}
+	return false
+}
+
+func (x *DataSourceSyncResult) GetWatermark() string {
+	if x != nil {
+		return x.Watermark
+	}
+	return ""
+}
+
+func (x *DataSourceSyncResult) GetDeletedDataObjects() int32 {
+	if x != nil {
+		return x.DeletedDataObjects
+	}
+	return 0
+}
+
+func (x *DataSourceSyncResult) GetPartFiles() []string {
+	if x != nil {
+		return x.PartFiles
+	}
+	return nil
+}
+
 // NoLinting ToSupport GQL schema (temporarily)
 type MetaData struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -1119,7 +1199,7 @@ var File_data_source_data_source_proto protoreflect.FileDescriptor
 
 const file_data_source_data_source_proto_rawDesc = "" +
 	"\n" +
-	"\x1ddata_source/data_source.proto\x12\vdata_source\x1a\x1bgoogle/protobuf/empty.proto\x1a\x18util/config/config.proto\x1a\x16util/error/error.proto\x1a\x1autil/version/version.proto\"\xf4\x01\n" +
+	"\x1ddata_source/data_source.proto\x12\vdata_source\x1a\x1bgoogle/protobuf/empty.proto\x1a\x18util/config/config.proto\x1a\x16util/error/error.proto\x1a\x1autil/version/version.proto\"\xbf\x03\n" +
 	"\x14DataSourceSyncConfig\x125\n" +
 	"\n" +
 	"config_map\x18\x01 \x01(\v2\x16.util.config.ConfigMapR\tconfigMap\x12\x1f\n" +
@@ -1127,10 +1207,21 @@ const file_data_source_data_source_proto_rawDesc = "" +
 	"targetFile\x12$\n" +
 	"\x0edata_source_id\x18\x03 \x01(\tR\fdataSourceId\x12,\n" +
 	"\x12data_object_parent\x18\x04 \x01(\tR\x10dataObjectParent\x120\n" +
-	"\x14data_object_excludes\x18\x05 \x03(\tR\x12dataObjectExcludes\"l\n" +
+	"\x14data_object_excludes\x18\x05 \x03(\tR\x12dataObjectExcludes\x12 \n" +
+	"\vincremental\x18\x06 \x01(\bR\vincremental\x12\x1c\n" +
+	"\twatermark\x18\a \x01(\tR\twatermark\x120\n" +
+	"\x14last_successful_sync\x18\b \x01(\x03R\x12lastSuccessfulSync\x120\n" +
+	"\x14deleted_objects_file\x18\t \x01(\tR\x12deletedObjectsFile\x12%\n" +
+	"\x0echeckpoint_dir\x18\n" +
+	" \x01(\tR\rcheckpointDir\"\xfd\x01\n" +
 	"\x14DataSourceSyncResult\x121\n" +
 	"\x05error\x18\x01 \x01(\v2\x17.util.error.ErrorResultB\x02\x18\x01R\x05error\x12!\n" +
-	"\fdata_objects\x18\x02 \x01(\x05R\vdataObjects\"\xc2\x04\n" +
+	"\fdata_objects\x18\x02 \x01(\x05R\vdataObjects\x12 \n" +
+	"\vincremental\x18\x03 \x01(\bR\vincremental\x12\x1c\n" +
+	"\twatermark\x18\x04 \x01(\tR\twatermark\x120\n" +
+	"\x14deleted_data_objects\x18\x05 \x01(\x05R\x12deletedDataObjects\x12\x1d\n" +
+	"\n" +
+	"part_files\x18\x06 \x03(\tR\tpartFiles\"\xc2\x04\n" +
 	"\bMetaData\x12E\n" +
 	"\x0fdataObjectTypes\x18\x01 \x03(\v2\x1b.data_source.DataObjectTypeR\x0fdataObjectTypes\x12,\n" +
 	"\x11supportedFeatures\x18\x02 \x03(\tR\x11supportedFeatures\x12\x12\n" +
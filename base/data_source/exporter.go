@@ -4,6 +4,9 @@
 // The returned DataSourceFileCreator can then be used (using the AddDataObjects function)
 // to write DataObjects to the file.
 // Make sure to call the Close function on the creator at the end (tip: use defer).
+//
+// When the CLI requests an incremental sync (see DataSourceSyncConfig.Incremental), only the changed data objects
+// need to be added. Deleted data objects are reported using the DeleteDataObjects function of the IncrementalDataSourceFileCreator.
 package data_source
 
 import (
@@ -16,6 +19,7 @@ import (
 )
 
 //go:generate go run github.com/vektra/mockery/v2 --name=DataSourceFileCreator --with-expecter
+//go:generate go run github.com/vektra/mockery/v2 --name=IncrementalDataSourceFileCreator --with-expecter
 
 // DataObject represents a data object in the format that is suitable to be imported into a Raito data source.
 type DataObject struct {
@@ -55,14 +59,37 @@ type DataSourceFileCreator interface {
 	GetDataSourceDetails() *DataSourceDetails
 }
 
+// IncrementalDataSourceFileCreator extends the DataSourceFileCreator with the functions needed for incremental syncs.
+// The DataSourceFileCreator returned by NewDataSourceFileCreator implements it, so it can be accessed using a type assertion.
+type IncrementalDataSourceFileCreator interface {
+	DataSourceFileCreator
+
+	// DeleteDataObjects marks the data objects with the given external ids as deleted. Only supported during an incremental sync.
+	DeleteDataObjects(externalIds ...string) error
+	GetDeletedDataObjectCount() int
+
+	// SetWatermark sets the cursor that will be passed back (in DataSourceSyncConfig.Watermark) in the next incremental sync.
+	SetWatermark(watermark string)
+	GetWatermark() string
+}
+
 type dataSourceFileCreator struct {
 	config *DataSourceSyncConfig
 
 	targetFile        *os.File
 	dataObjectCount   int
 	dataSourceDetails DataSourceDetails
+
+	deletedObjectsFile     *os.File
+	deletedDataObjectCount int
+	watermark              string
+
+	// skipDataSourceObject is set for the parts of an import file that don't contain the data source itself
+	skipDataSourceObject bool
 }
 
+var _ IncrementalDataSourceFileCreator = (*dataSourceFileCreator)(nil)
+
 // NewDataSourceFileCreator creates a new DataSourceFileCreator based on the configuration coming from
 // the Raito CLI.
 func NewDataSourceFileCreator(config *DataSourceSyncConfig) (DataSourceFileCreator, error) {
@@ -84,12 +111,30 @@ func NewDataSourceFileCreator(config *DataSourceSyncConfig) (DataSourceFileCreat
 	return &dsI, nil
 }
 
+// NewDataSourceFileCreatorForPart creates a new DataSourceFileCreator for a part of the import file, for when the data objects are written to multiple files
+// that are combined by the Raito CLI afterwards. Only the first part (index 0) contains the data object representing the data source.
+func NewDataSourceFileCreatorForPart(config *DataSourceSyncConfig, part int) (DataSourceFileCreator, error) {
+	fileCreator, err := NewDataSourceFileCreator(config)
+	if err != nil {
+		return nil, err
+	}
+
+	fileCreator.(*dataSourceFileCreator).skipDataSourceObject = part > 0
+
+	return fileCreator, nil
+}
+
 // Close finalizes the import file and close it so it can be correctly read by the Raito CLI.
 // This method must be called when all data objects have been added and before control is given back
 // to the CLI. It's advised to call this using 'defer'.
 func (d *dataSourceFileCreator) Close() {
 	d.targetFile.WriteString("\n]") //nolint:errcheck
 	d.targetFile.Close()
+
+	if d.deletedObjectsFile != nil {
+		d.deletedObjectsFile.WriteString("\n]") //nolint:errcheck
+		d.deletedObjectsFile.Close()
+	}
 }
 
 func (d *dataSourceFileCreator) GetDataSourceDetails() *DataSourceDetails {
@@ -112,7 +157,7 @@ func (d *dataSourceFileCreator) AddDataObjects(dataObjects ...*DataObject) error
 	}
 
 	// validate whether the first DataObject represents the Data Source, if not generate one
-	if d.dataObjectCount == 0 && d.config.DataObjectParent == "" {
+	if d.dataObjectCount == 0 && d.config.DataObjectParent == "" && !d.skipDataSourceObject {
 		if !strings.EqualFold(dataObjects[0].Type, "datasource") {
 			dataObjects = d.prependDataSourceDataObject(dataObjects)
 		}
@@ -169,6 +214,67 @@ func (d *dataSourceFileCreator) GetDataObjectCount() int {
 	return d.dataObjectCount
 }
 
+// DeleteDataObjects adds the external ids of the deleted data objects to the deletions file.
+// It returns an error when the sync is not incremental, as a full sync implicitly deletes all data objects that were not added.
+func (d *dataSourceFileCreator) DeleteDataObjects(externalIds ...string) error {
+	if len(externalIds) == 0 {
+		return nil
+	}
+
+	if !d.config.Incremental || d.config.DeletedObjectsFile == "" {
+		return fmt.Errorf("deleting data objects is only supported during an incremental sync")
+	}
+
+	if d.deletedObjectsFile == nil {
+		f, err := os.Create(d.config.DeletedObjectsFile)
+		if err != nil {
+			return fmt.Errorf("error creating file for deleted data objects: %w", err)
+		}
+
+		d.deletedObjectsFile = f
+
+		_, err = d.deletedObjectsFile.WriteString("[")
+		if err != nil {
+			return fmt.Errorf("error while writing to file %q: %w", d.deletedObjectsFile.Name(), err)
+		}
+	}
+
+	for _, externalId := range externalIds {
+		if d.deletedDataObjectCount > 0 {
+			d.deletedObjectsFile.WriteString(",") //nolint:errcheck
+		}
+
+		idBuf, err := json.Marshal(externalId)
+		if err != nil {
+			return fmt.Errorf("error while serializing deleted data object %q", externalId)
+		}
+
+		d.deletedObjectsFile.WriteString("\n") //nolint:errcheck
+		_, err = d.deletedObjectsFile.Write(idBuf)
+
+		if err != nil {
+			return fmt.Errorf("error while writing to file %q", d.deletedObjectsFile.Name())
+		}
+
+		d.deletedDataObjectCount++
+	}
+
+	return nil
+}
+
+// GetDeletedDataObjectCount returns the number of data objects that has been marked as deleted.
+func (d *dataSourceFileCreator) GetDeletedDataObjectCount() int {
+	return d.deletedDataObjectCount
+}
+
+func (d *dataSourceFileCreator) SetWatermark(watermark string) {
+	d.watermark = watermark
+}
+
+func (d *dataSourceFileCreator) GetWatermark() string {
+	return d.watermark
+}
+
 func (d *dataSourceFileCreator) createTargetFile() error {
 	f, err := os.Create(d.config.TargetFile)
 	if err != nil {
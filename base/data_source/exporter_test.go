@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func init() {
@@ -167,6 +168,86 @@ func TestDataSourceFileCreatorPartial(t *testing.T) {
 	assert.Equal(t, 3, dsfc.GetDataObjectCount())
 }
 
+func TestDataSourceFileCreatorForPart(t *testing.T) {
+	dir := t.TempDir()
+
+	for part, expectedCount := range []int{2, 1} {
+		config := DataSourceSyncConfig{
+			TargetFile:   dir + "/part-" + strconv.Itoa(part) + ".json",
+			DataSourceId: "myDataSource",
+		}
+		dsfc, err := NewDataSourceFileCreatorForPart(&config, part)
+		assert.Nil(t, err)
+
+		err = dsfc.AddDataObjects(&DataObject{ExternalId: "eid" + strconv.Itoa(part), Name: "DO", Type: "table"})
+		assert.Nil(t, err)
+
+		dsfc.Close()
+
+		// Only the first part contains the data source
+		assert.Equal(t, expectedCount, dsfc.GetDataObjectCount())
+	}
+}
+
+func TestDataSourceFileCreatorIncremental(t *testing.T) {
+	dir := t.TempDir()
+	config := DataSourceSyncConfig{
+		TargetFile:         dir + "/data-source.json",
+		DeletedObjectsFile: dir + "/deleted.json",
+		DataSourceId:       "myDataSource",
+		Incremental:        true,
+		Watermark:          "cursor-1",
+	}
+	fileCreator, err := NewDataSourceFileCreator(&config)
+	assert.Nil(t, err)
+
+	dsfc, ok := fileCreator.(IncrementalDataSourceFileCreator)
+	require.True(t, ok)
+
+	err = dsfc.AddDataObjects(&DataObject{ExternalId: "eid1", Name: "DO1", Type: "table"})
+	assert.Nil(t, err)
+
+	err = dsfc.DeleteDataObjects("eid2", `eid"3`)
+	assert.Nil(t, err)
+	err = dsfc.DeleteDataObjects("eid4")
+	assert.Nil(t, err)
+
+	dsfc.SetWatermark("cursor-2")
+	dsfc.Close()
+
+	assert.Equal(t, 3, dsfc.GetDeletedDataObjectCount())
+	assert.Equal(t, "cursor-2", dsfc.GetWatermark())
+
+	bytes, err := os.ReadFile(config.DeletedObjectsFile)
+	assert.Nil(t, err)
+
+	var deleted []string
+	err = json.Unmarshal(bytes, &deleted)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"eid2", `eid"3`, "eid4"}, deleted)
+}
+
+func TestDataSourceFileCreatorDeleteNotIncremental(t *testing.T) {
+	dir := t.TempDir()
+	config := DataSourceSyncConfig{
+		TargetFile:         dir + "/data-source.json",
+		DeletedObjectsFile: dir + "/deleted.json",
+		DataSourceId:       "myDataSource",
+	}
+	fileCreator, err := NewDataSourceFileCreator(&config)
+	assert.Nil(t, err)
+
+	defer fileCreator.Close()
+
+	dsfc, ok := fileCreator.(IncrementalDataSourceFileCreator)
+	require.True(t, ok)
+
+	err = dsfc.DeleteDataObjects("eid1")
+	assert.Error(t, err)
+	assert.Equal(t, 0, dsfc.GetDeletedDataObjectCount())
+	assert.NoFileExists(t, config.DeletedObjectsFile)
+}
+
 func TestDataSourceDetails(t *testing.T) {
 	tempFile, _ := os.Create("tempfile-" + strconv.Itoa(rand.Int()) + ".json")
 	defer os.Remove(tempFile.Name())
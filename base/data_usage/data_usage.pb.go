@@ -32,8 +32,10 @@ type DataUsageSyncConfig struct {
 	ConfigMap       *config.ConfigMap      `protobuf:"bytes,1,opt,name=config_map,json=configMap,proto3" json:"config_map,omitempty"`
 	TargetFile      string                 `protobuf:"bytes,2,opt,name=target_file,json=targetFile,proto3" json:"target_file,omitempty"`
 	MaxBytesPerFile uint64                 `protobuf:"varint,3,opt,name=max_bytes_per_file,json=maxBytesPerFile,proto3" json:"max_bytes_per_file,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// checkpoint_dir is the directory in which the plugin can store checkpoints, so a retried sync can resume from the last checkpoint. Checkpoints are disabled if empty.
+	CheckpointDir string `protobuf:"bytes,4,opt,name=checkpoint_dir,json=checkpointDir,proto3" json:"checkpoint_dir,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DataUsageSyncConfig) Reset() {
@@ -87,14 +89,23 @@ func (x *DataUsageSyncConfig) GetMaxBytesPerFile() uint64 {
 	return 0
 }
 
+func (x *DataUsageSyncConfig) GetCheckpointDir() string {
+	if x != nil {
+		return x.CheckpointDir
+	}
+	return ""
+}
+
 // DataUsageSyncResult represents the result from the data usage sync process.
 // A potential error is also modeled in here so specific errors remain intact when passed over RPC.
 type DataUsageSyncResult struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Deprecated: Marked as deprecated in data_usage/data_usage.proto.
-	Error         *error1.ErrorResult `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	Statements    int32               `protobuf:"varint,2,opt,name=statements,proto3" json:"statements,omitempty"`
-	TargetFiles   []string            `protobuf:"bytes,3,rep,name=target_files,json=targetFiles,proto3" json:"target_files,omitempty"`
+	Error       *error1.ErrorResult `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Statements  int32               `protobuf:"varint,2,opt,name=statements,proto3" json:"statements,omitempty"`
+	TargetFiles []string            `protobuf:"bytes,3,rep,name=target_files,json=targetFiles,proto3" json:"target_files,omitempty"`
+	// part_files contains the files in which the statements are stored when checkpoints were used. The CLI combines them into the import files.
+	PartFiles     []string `protobuf:"bytes,4,rep,name=part_files,json=partFiles,proto3" json:"part_files,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -151,24 +162,34 @@ func (x *DataUsageSyncResult) GetTargetFiles() []string {
 	return nil
 }
 
+func (x *DataUsageSyncResult) GetPartFiles() []string {
+	if x != nil {
+		return x.PartFiles
+	}
+	return nil
+}
+
 var File_data_usage_data_usage_proto protoreflect.FileDescriptor
 
 const file_data_usage_data_usage_proto_rawDesc = "" +
 	"\n" +
 	"\x1bdata_usage/data_usage.proto\x12\n" +
-	"data_usage\x1a\x1bgoogle/protobuf/empty.proto\x1a\x18util/config/config.proto\x1a\x16util/error/error.proto\x1a\x1autil/version/version.proto\"\x9a\x01\n" +
+	"data_usage\x1a\x1bgoogle/protobuf/empty.proto\x1a\x18util/config/config.proto\x1a\x16util/error/error.proto\x1a\x1autil/version/version.proto\"\xc1\x01\n" +
 	"\x13DataUsageSyncConfig\x125\n" +
 	"\n" +
 	"config_map\x18\x01 \x01(\v2\x16.util.config.ConfigMapR\tconfigMap\x12\x1f\n" +
 	"\vtarget_file\x18\x02 \x01(\tR\n" +
 	"targetFile\x12+\n" +
-	"\x12max_bytes_per_file\x18\x03 \x01(\x04R\x0fmaxBytesPerFile\"\x8b\x01\n" +
+	"\x12max_bytes_per_file\x18\x03 \x01(\x04R\x0fmaxBytesPerFile\x12%\n" +
+	"\x0echeckpoint_dir\x18\x04 \x01(\tR\rcheckpointDir\"\xaa\x01\n" +
 	"\x13DataUsageSyncResult\x121\n" +
 	"\x05error\x18\x01 \x01(\v2\x17.util.error.ErrorResultB\x02\x18\x01R\x05error\x12\x1e\n" +
 	"\n" +
 	"statements\x18\x02 \x01(\x05R\n" +
 	"statements\x12!\n" +
-	"\ftarget_files\x18\x03 \x03(\tR\vtargetFiles2\xbd\x01\n" +
+	"\ftarget_files\x18\x03 \x03(\tR\vtargetFiles\x12\x1d\n" +
+	"\n" +
+	"part_files\x18\x04 \x03(\tR\tpartFiles2\xbd\x01\n" +
 	"\x14DataUsageSyncService\x12R\n" +
 	"\x15CliVersionInformation\x12\x16.google.protobuf.Empty\x1a!.util.version.CliBuildInformation\x12Q\n" +
 	"\rSyncDataUsage\x12\x1f.data_usage.DataUsageSyncConfig\x1a\x1f.data_usage.DataUsageSyncResultB\x8d\x01\n" +
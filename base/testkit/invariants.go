@@ -0,0 +1,286 @@
+package testkit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/raito-io/cli/base/access_provider/sync_from_target"
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/data_usage"
+	"github.com/raito-io/cli/base/identity_store"
+)
+
+// declaredTypes indexes the data object types declared in the meta data of a data source by name.
+// Nil if the meta data is unknown (e.g. for a plugin without data source syncer), in which case every type is accepted.
+type declaredTypes map[string]*data_source.DataObjectType
+
+func newDeclaredTypes(metaData *data_source.MetaData) declaredTypes {
+	if metaData == nil {
+		return nil
+	}
+
+	types := declaredTypes{}
+
+	for _, doType := range metaData.DataObjectTypes {
+		types[doType.Name] = doType
+	}
+
+	return types
+}
+
+func (d declaredTypes) isDeclared(doType string) bool {
+	if d == nil {
+		return true
+	}
+
+	// The data source object is generated by the data source file creator, so it doesn't need to be declared
+	if strings.EqualFold(doType, data_source.Datasource) {
+		return true
+	}
+
+	_, found := d[doType]
+
+	return found
+}
+
+func (d declaredTypes) isPermissionDeclared(doType string, permission string) bool {
+	if d == nil {
+		return true
+	}
+
+	declared, found := d[doType]
+	if !found {
+		return false
+	}
+
+	for _, p := range declared.Permissions {
+		if strings.EqualFold(p.Permission, permission) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckMetaData checks that the data object types declared in the meta data of a data source are unique.
+func CheckMetaData(metaData *data_source.MetaData) []error {
+	var violations []error
+
+	if metaData == nil {
+		return append(violations, errors.New("no meta data returned by the data source syncer"))
+	}
+
+	names := map[string]struct{}{}
+
+	for _, doType := range metaData.DataObjectTypes {
+		if _, found := names[doType.Name]; found {
+			violations = append(violations, fmt.Errorf("data object type %q is declared more than once in the meta data", doType.Name))
+		}
+
+		names[doType.Name] = struct{}{}
+	}
+
+	return violations
+}
+
+// CheckDataObjects checks that the exported data objects have a unique external ID, a resolvable parent and a type declared in the meta data.
+// The parent of the data objects is resolvable if it is exported as well or if it is the given data object parent of a partial sync.
+func CheckDataObjects(dataObjects []data_source.DataObject, metaData *data_source.MetaData, dataObjectParent string) []error {
+	var violations []error
+
+	types := newDeclaredTypes(metaData)
+	externalIds := make(map[string]struct{}, len(dataObjects))
+
+	for i := range dataObjects {
+		do := &dataObjects[i]
+
+		if do.ExternalId == "" {
+			violations = append(violations, fmt.Errorf("data object %q has no external ID", do.FullName))
+		} else if _, found := externalIds[do.ExternalId]; found {
+			violations = append(violations, fmt.Errorf("data object external ID %q is exported more than once", do.ExternalId))
+		}
+
+		externalIds[do.ExternalId] = struct{}{}
+
+		if !types.isDeclared(do.Type) {
+			violations = append(violations, fmt.Errorf("data object %q has type %q, which is not declared in the meta data", do.ExternalId, do.Type))
+		}
+	}
+
+	for i := range dataObjects {
+		do := &dataObjects[i]
+
+		if do.ParentExternalId == "" || do.ParentExternalId == dataObjectParent {
+			continue
+		}
+
+		if _, found := externalIds[do.ParentExternalId]; !found {
+			violations = append(violations, fmt.Errorf("data object %q refers to parent %q, which is not exported", do.ExternalId, do.ParentExternalId))
+		}
+	}
+
+	return violations
+}
+
+// CheckIdentities checks that the exported users and groups have a unique external ID and that the groups they refer to are exported.
+func CheckIdentities(users []identity_store.User, groups []identity_store.Group) []error {
+	var violations []error
+
+	groupIds := make(map[string]struct{}, len(groups))
+
+	for i := range groups {
+		if _, found := groupIds[groups[i].ExternalId]; found {
+			violations = append(violations, fmt.Errorf("group external ID %q is exported more than once", groups[i].ExternalId))
+		}
+
+		groupIds[groups[i].ExternalId] = struct{}{}
+	}
+
+	for i := range groups {
+		for _, parent := range groups[i].ParentGroupExternalIds {
+			if _, found := groupIds[parent]; !found {
+				violations = append(violations, fmt.Errorf("group %q refers to parent group %q, which is not exported", groups[i].ExternalId, parent))
+			}
+		}
+	}
+
+	userIds := make(map[string]struct{}, len(users))
+
+	for i := range users {
+		if _, found := userIds[users[i].ExternalId]; found {
+			violations = append(violations, fmt.Errorf("user external ID %q is exported more than once", users[i].ExternalId))
+		}
+
+		userIds[users[i].ExternalId] = struct{}{}
+
+		for _, group := range users[i].GroupExternalIds {
+			if _, found := groupIds[group]; !found {
+				violations = append(violations, fmt.Errorf("user %q refers to group %q, which is not exported", users[i].ExternalId, group))
+			}
+		}
+	}
+
+	return violations
+}
+
+// CheckAccessProviders checks that the access providers imported from the data source have a unique external ID
+// and that the data objects they give access to have a declared type and declared permissions.
+func CheckAccessProviders(accessProviders []sync_from_target.AccessProvider, metaData *data_source.MetaData) []error {
+	var violations []error
+
+	types := newDeclaredTypes(metaData)
+	externalIds := make(map[string]struct{}, len(accessProviders))
+
+	for i := range accessProviders {
+		ap := &accessProviders[i]
+
+		if _, found := externalIds[ap.ExternalId]; found {
+			violations = append(violations, fmt.Errorf("access provider external ID %q is exported more than once", ap.ExternalId))
+		}
+
+		externalIds[ap.ExternalId] = struct{}{}
+
+		for _, what := range ap.What {
+			if what.DataObject == nil {
+				violations = append(violations, fmt.Errorf("access provider %q has a what item without data object", ap.ExternalId))
+
+				continue
+			}
+
+			if !types.isDeclared(what.DataObject.Type) {
+				violations = append(violations, fmt.Errorf("access provider %q gives access to %q of type %q, which is not declared in the meta data", ap.ExternalId, what.DataObject.FullName, what.DataObject.Type))
+
+				continue
+			}
+
+			for _, permission := range what.Permissions {
+				if !types.isPermissionDeclared(what.DataObject.Type, permission) {
+					violations = append(violations, fmt.Errorf("access provider %q grants permission %q on %q, which is not declared on data object type %q", ap.ExternalId, permission, what.DataObject.FullName, what.DataObject.Type))
+				}
+			}
+		}
+	}
+
+	return violations
+}
+
+// AccessProviderFeedback is the feedback of a single access provider, as written in the feedback file.
+type AccessProviderFeedback struct {
+	ExternalId string           `json:"externalId"`
+	Access     []AccessFeedback `json:"access"`
+}
+
+// AccessFeedback is the feedback of the access (e.g. role or policy) an access provider is implemented with in the data source.
+type AccessFeedback struct {
+	AccessId   string   `json:"accessId"`
+	ActualName string   `json:"actualName"`
+	ExternalId *string  `json:"externalId"`
+	Type       *string  `json:"type"`
+	Errors     []string `json:"errors"`
+	Warnings   []string `json:"warnings"`
+}
+
+// CheckFeedback checks that feedback is given exactly once for every access provider that was exported to the data source, and only for those.
+func CheckFeedback(accessProviders *sync_to_target.AccessProviderImport, feedback []AccessProviderFeedback) []error {
+	var violations []error
+
+	exported := map[string]struct{}{}
+
+	if accessProviders != nil {
+		for _, ap := range accessProviders.AccessProviders {
+			exported[ap.Id] = struct{}{}
+		}
+	}
+
+	received := make(map[string]struct{}, len(feedback))
+
+	for i := range feedback {
+		id := feedback[i].ExternalId
+
+		if _, found := received[id]; found {
+			violations = append(violations, fmt.Errorf("feedback for access provider %q is given more than once", id))
+		}
+
+		received[id] = struct{}{}
+
+		if _, found := exported[id]; !found {
+			violations = append(violations, fmt.Errorf("feedback is given for access provider %q, which was not exported", id))
+		}
+	}
+
+	for id := range exported {
+		if _, found := received[id]; !found {
+			violations = append(violations, fmt.Errorf("no feedback given for exported access provider %q", id))
+		}
+	}
+
+	return violations
+}
+
+// CheckStatements checks that the usage statements have a unique external ID and only refer to data objects of a declared type.
+func CheckStatements(statements []data_usage.Statement, metaData *data_source.MetaData) []error {
+	var violations []error
+
+	types := newDeclaredTypes(metaData)
+	externalIds := make(map[string]struct{}, len(statements))
+
+	for i := range statements {
+		statement := &statements[i]
+
+		if _, found := externalIds[statement.ExternalId]; found {
+			violations = append(violations, fmt.Errorf("statement external ID %q is exported more than once", statement.ExternalId))
+		}
+
+		externalIds[statement.ExternalId] = struct{}{}
+
+		for _, item := range statement.AccessedDataObjects {
+			if !types.isDeclared(item.DataObject.Type) {
+				violations = append(violations, fmt.Errorf("statement %q accesses %q of type %q, which is not declared in the meta data", statement.ExternalId, item.DataObject.FullName, item.DataObject.Type))
+			}
+		}
+	}
+
+	return violations
+}
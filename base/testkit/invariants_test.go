@@ -0,0 +1,147 @@
+package testkit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/raito-io/cli/base/access_provider/sync_from_target"
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/data_usage"
+	"github.com/raito-io/cli/base/identity_store"
+)
+
+var testMetaData = &data_source.MetaData{
+	DataObjectTypes: []*data_source.DataObjectType{
+		{Name: "schema", Type: "schema", Permissions: []*data_source.DataObjectTypePermission{{Permission: "USAGE"}}},
+		{Name: "table", Type: "table", Permissions: []*data_source.DataObjectTypePermission{{Permission: "SELECT"}}},
+	},
+}
+
+func TestCheckMetaData(t *testing.T) {
+	assert.Empty(t, CheckMetaData(testMetaData))
+	assert.Len(t, CheckMetaData(nil), 1)
+
+	duplicate := &data_source.MetaData{
+		DataObjectTypes: []*data_source.DataObjectType{{Name: "table"}, {Name: "view"}, {Name: "table"}},
+	}
+
+	violations := CheckMetaData(duplicate)
+	if assert.Len(t, violations, 1) {
+		assert.Contains(t, violations[0].Error(), `"table" is declared more than once`)
+	}
+}
+
+func TestCheckDataObjects(t *testing.T) {
+	valid := []data_source.DataObject{
+		{ExternalId: "ds", Type: "datasource"},
+		{ExternalId: "schema1", Type: "schema", ParentExternalId: "ds"},
+		{ExternalId: "schema1.table1", Type: "table", ParentExternalId: "schema1"},
+	}
+
+	assert.Empty(t, CheckDataObjects(valid, testMetaData, ""))
+
+	invalid := []data_source.DataObject{
+		{ExternalId: "schema1", Type: "schema"},
+		{ExternalId: "schema1", Type: "schema"},
+		{ExternalId: "schema1.table1", Type: "table", ParentExternalId: "schema2"},
+		{ExternalId: "schema1.view1", Type: "view", ParentExternalId: "schema1"},
+		{FullName: "nameless", Type: "table", ParentExternalId: "schema1"},
+	}
+
+	violations := CheckDataObjects(invalid, testMetaData, "")
+	assert.Len(t, violations, 4)
+	assert.Contains(t, violations[0].Error(), `"schema1" is exported more than once`)
+	assert.Contains(t, violations[1].Error(), `type "view", which is not declared`)
+	assert.Contains(t, violations[2].Error(), `"nameless" has no external ID`)
+	assert.Contains(t, violations[3].Error(), `parent "schema2", which is not exported`)
+}
+
+func TestCheckDataObjects_PartialSync(t *testing.T) {
+	dataObjects := []data_source.DataObject{
+		{ExternalId: "schema1.table1", Type: "table", ParentExternalId: "schema1"},
+	}
+
+	assert.Empty(t, CheckDataObjects(dataObjects, testMetaData, "schema1"))
+	assert.Len(t, CheckDataObjects(dataObjects, testMetaData, ""), 1)
+}
+
+func TestCheckIdentities(t *testing.T) {
+	groups := []identity_store.Group{
+		{ExternalId: "group1"},
+		{ExternalId: "group2", ParentGroupExternalIds: []string{"group1"}},
+	}
+	users := []identity_store.User{
+		{ExternalId: "user1", GroupExternalIds: []string{"group2"}},
+	}
+
+	assert.Empty(t, CheckIdentities(users, groups))
+
+	groups = append(groups, identity_store.Group{ExternalId: "group1", ParentGroupExternalIds: []string{"group3"}})
+	users = append(users, identity_store.User{ExternalId: "user1", GroupExternalIds: []string{"group4"}})
+
+	violations := CheckIdentities(users, groups)
+	assert.Len(t, violations, 4)
+	assert.Contains(t, violations[0].Error(), `group external ID "group1" is exported more than once`)
+	assert.Contains(t, violations[1].Error(), `parent group "group3"`)
+	assert.Contains(t, violations[2].Error(), `user external ID "user1" is exported more than once`)
+	assert.Contains(t, violations[3].Error(), `group "group4", which is not exported`)
+}
+
+func TestCheckAccessProviders(t *testing.T) {
+	table := &data_source.DataObjectReference{FullName: "schema1.table1", Type: "table"}
+
+	valid := []sync_from_target.AccessProvider{
+		{ExternalId: "role1", What: []sync_from_target.WhatItem{{DataObject: table, Permissions: []string{"SELECT"}}}},
+	}
+
+	assert.Empty(t, CheckAccessProviders(valid, testMetaData))
+
+	invalid := []sync_from_target.AccessProvider{
+		{ExternalId: "role1", What: []sync_from_target.WhatItem{{DataObject: table, Permissions: []string{"DELETE"}}}},
+		{ExternalId: "role1", What: []sync_from_target.WhatItem{{DataObject: &data_source.DataObjectReference{FullName: "view1", Type: "view"}}}},
+		{ExternalId: "role2", What: []sync_from_target.WhatItem{{Permissions: []string{"SELECT"}}}},
+	}
+
+	violations := CheckAccessProviders(invalid, testMetaData)
+	assert.Len(t, violations, 4)
+	assert.Contains(t, violations[0].Error(), `permission "DELETE"`)
+	assert.Contains(t, violations[1].Error(), `"role1" is exported more than once`)
+	assert.Contains(t, violations[2].Error(), `type "view", which is not declared`)
+	assert.Contains(t, violations[3].Error(), "without data object")
+}
+
+func TestCheckAccessProviders_UnknownMetaData(t *testing.T) {
+	accessProviders := []sync_from_target.AccessProvider{
+		{ExternalId: "role1", What: []sync_from_target.WhatItem{{DataObject: &data_source.DataObjectReference{FullName: "view1", Type: "view"}, Permissions: []string{"SELECT"}}}},
+	}
+
+	assert.Empty(t, CheckAccessProviders(accessProviders, nil))
+}
+
+func TestCheckFeedback(t *testing.T) {
+	exported := &sync_to_target.AccessProviderImport{
+		AccessProviders: []*sync_to_target.AccessProvider{{Id: "ap1"}, {Id: "ap2"}},
+	}
+
+	assert.Empty(t, CheckFeedback(exported, []AccessProviderFeedback{{ExternalId: "ap1"}, {ExternalId: "ap2"}}))
+
+	violations := CheckFeedback(exported, []AccessProviderFeedback{{ExternalId: "ap1"}, {ExternalId: "ap1"}, {ExternalId: "ap3"}})
+	assert.Len(t, violations, 3)
+	assert.Contains(t, violations[0].Error(), `"ap1" is given more than once`)
+	assert.Contains(t, violations[1].Error(), `"ap3", which was not exported`)
+	assert.Contains(t, violations[2].Error(), `no feedback given for exported access provider "ap2"`)
+}
+
+func TestCheckStatements(t *testing.T) {
+	statements := []data_usage.Statement{
+		{ExternalId: "s1", AccessedDataObjects: []data_usage.UsageDataObjectItem{{DataObject: data_usage.UsageDataObjectReference{FullName: "schema1.table1", Type: "table"}}}},
+		{ExternalId: "s1", AccessedDataObjects: []data_usage.UsageDataObjectItem{{DataObject: data_usage.UsageDataObjectReference{FullName: "view1", Type: "view"}}}},
+	}
+
+	violations := CheckStatements(statements, testMetaData)
+	assert.Len(t, violations, 2)
+	assert.Contains(t, violations[0].Error(), `"s1" is exported more than once`)
+	assert.Contains(t, violations[1].Error(), `type "view", which is not declared`)
+}
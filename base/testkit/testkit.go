@@ -0,0 +1,328 @@
+// Package testkit provides a conformance test kit for Raito CLI plugins.
+// It drives the syncers implemented by a plugin with a fixture configuration, captures the files they produce
+// and checks the invariants the Raito CLI and Raito Cloud rely on: valid JSON, unique external IDs, resolvable references,
+// data object types and permissions declared in the meta data, feedback for every exported access provider, etc.
+//
+// Use New to call the syncers in-process, or NewGRPC to call them through the plugin protocol, exactly like the Raito CLI does:
+//
+//	func TestConformance(t *testing.T) {
+//		kit := testkit.NewGRPC(t, wrappers.DataSourceSync(&DataSourceSyncer{}), wrappers.DataAccessSync(&AccessSyncer{}))
+//		kit.Run(context.Background(), &testkit.Fixture{ConfigMap: &config.ConfigMap{Parameters: params}})
+//	}
+package testkit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-plugin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/access_provider/sync_from_target"
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/data_usage"
+	"github.com/raito-io/cli/base/identity_store"
+	"github.com/raito-io/cli/base/util/config"
+)
+
+// DefaultDataSourceId is the data source ID passed to the data source syncer if the fixture doesn't specify one.
+const DefaultDataSourceId = "testkit-data-source"
+
+// Fixture is the configuration the syncers of the plugin are driven with.
+type Fixture struct {
+	// ConfigMap contains the parameters passed to all syncers.
+	ConfigMap *config.ConfigMap
+
+	DataSourceId       string
+	DataObjectParent   string
+	DataObjectExcludes []string
+
+	// AccessProviders are exported to the data source during the access provider sync to target. That sync is skipped if nil.
+	AccessProviders *sync_to_target.AccessProviderImport
+	Prefix          string
+
+	MaxBytesPerFile uint64
+}
+
+// Result contains everything the syncers of the plugin produced.
+type Result struct {
+	MetaData        *data_source.MetaData
+	DataObjects     []data_source.DataObject
+	Users           []identity_store.User
+	Groups          []identity_store.Group
+	AccessProviders []sync_from_target.AccessProvider
+	Feedback        []AccessProviderFeedback
+	Statements      []data_usage.Statement
+}
+
+// Kit drives the syncers of a plugin and reports every violated invariant as an error of the test.
+type Kit struct {
+	t testing.TB
+
+	dataSourceSyncer    data_source.DataSourceSyncer
+	identityStoreSyncer identity_store.IdentityStoreSyncer
+	accessSyncer        access_provider.AccessSyncer
+	dataUsageSyncer     data_usage.DataUsageSyncer
+}
+
+// New creates a kit that calls the syncers implemented by the given plugin implementations in-process.
+func New(t testing.TB, pluginImpls ...interface{}) *Kit {
+	t.Helper()
+
+	kit := &Kit{t: t}
+
+	for _, impl := range pluginImpls {
+		if p, ok := impl.(data_source.DataSourceSyncer); ok {
+			kit.dataSourceSyncer = p
+		}
+
+		if p, ok := impl.(identity_store.IdentityStoreSyncer); ok {
+			kit.identityStoreSyncer = p
+		}
+
+		if p, ok := impl.(access_provider.AccessSyncer); ok {
+			kit.accessSyncer = p
+		}
+
+		if p, ok := impl.(data_usage.DataUsageSyncer); ok {
+			kit.dataUsageSyncer = p
+		}
+	}
+
+	if kit.dataSourceSyncer == nil && kit.identityStoreSyncer == nil && kit.accessSyncer == nil && kit.dataUsageSyncer == nil {
+		t.Fatal("no syncer implementations found")
+	}
+
+	return kit
+}
+
+// NewGRPC creates a kit that calls the syncers implemented by the given plugin implementations through the plugin protocol,
+// so everything the syncers receive and return is serialized like it is when the plugin is used by the Raito CLI.
+func NewGRPC(t testing.TB, pluginImpls ...interface{}) *Kit {
+	t.Helper()
+
+	local := New(t, pluginImpls...)
+
+	pluginMap := map[string]plugin.Plugin{}
+
+	if local.dataSourceSyncer != nil {
+		pluginMap[data_source.DataSourceSyncerName] = &data_source.DataSourceSyncerPlugin{Impl: local.dataSourceSyncer}
+	}
+
+	if local.identityStoreSyncer != nil {
+		pluginMap[identity_store.IdentityStoreSyncerName] = &identity_store.IdentityStoreSyncerPlugin{Impl: local.identityStoreSyncer}
+	}
+
+	if local.accessSyncer != nil {
+		pluginMap[access_provider.AccessSyncerName] = &access_provider.AccessSyncerPlugin{Impl: local.accessSyncer}
+	}
+
+	if local.dataUsageSyncer != nil {
+		pluginMap[data_usage.DataUsageSyncerName] = &data_usage.DataUsageSyncerPlugin{Impl: local.dataUsageSyncer}
+	}
+
+	client, server := plugin.TestPluginGRPCConn(t, false, pluginMap)
+
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	kit := &Kit{t: t}
+
+	for name := range pluginMap {
+		raw, err := client.Dispense(name)
+		require.NoError(t, err, "dispense plugin %q", name)
+
+		switch p := raw.(type) {
+		case data_source.DataSourceSyncer:
+			kit.dataSourceSyncer = p
+		case identity_store.IdentityStoreSyncer:
+			kit.identityStoreSyncer = p
+		case access_provider.AccessSyncer:
+			kit.accessSyncer = p
+		case data_usage.DataUsageSyncer:
+			kit.dataUsageSyncer = p
+		}
+	}
+
+	return kit
+}
+
+// Run executes all syncers implemented by the plugin with the given fixture and checks the invariants on the produced files.
+// Violations are reported as errors of the test. A failing sync stops the test.
+func (k *Kit) Run(ctx context.Context, fixture *Fixture) *Result {
+	k.t.Helper()
+
+	dir := k.t.TempDir()
+	result := &Result{}
+
+	if fixture.ConfigMap == nil {
+		fixture.ConfigMap = &config.ConfigMap{}
+	}
+
+	if k.dataSourceSyncer != nil {
+		k.syncDataSource(ctx, fixture, dir, result)
+	}
+
+	if k.identityStoreSyncer != nil {
+		k.syncIdentityStore(ctx, fixture, dir, result)
+	}
+
+	if k.accessSyncer != nil {
+		k.syncAccessFromTarget(ctx, fixture, dir, result)
+
+		if fixture.AccessProviders != nil {
+			k.syncAccessToTarget(ctx, fixture, dir, result)
+		}
+	}
+
+	if k.dataUsageSyncer != nil {
+		k.syncDataUsage(ctx, fixture, dir, result)
+	}
+
+	return result
+}
+
+func (k *Kit) syncDataSource(ctx context.Context, fixture *Fixture, dir string, result *Result) {
+	k.t.Helper()
+
+	dataSourceId := fixture.DataSourceId
+	if dataSourceId == "" {
+		dataSourceId = DefaultDataSourceId
+	}
+
+	metaData, err := k.dataSourceSyncer.GetDataSourceMetaData(ctx, fixture.ConfigMap)
+	require.NoError(k.t, err, "get data source meta data")
+
+	result.MetaData = metaData
+	k.report("data source meta data", CheckMetaData(metaData))
+
+	syncConfig := &data_source.DataSourceSyncConfig{
+		ConfigMap:          fixture.ConfigMap,
+		TargetFile:         filepath.Join(dir, "data-source.json"),
+		DataSourceId:       dataSourceId,
+		DataObjectParent:   fixture.DataObjectParent,
+		DataObjectExcludes: fixture.DataObjectExcludes,
+	}
+
+	_, err = k.dataSourceSyncer.SyncDataSource(ctx, syncConfig)
+	require.NoError(k.t, err, "data source sync")
+
+	result.DataObjects = readJSONFile[data_source.DataObject](k.t, syncConfig.TargetFile)
+	k.report("data source sync", CheckDataObjects(result.DataObjects, metaData, fixture.DataObjectParent))
+}
+
+func (k *Kit) syncIdentityStore(ctx context.Context, fixture *Fixture, dir string, result *Result) {
+	k.t.Helper()
+
+	syncConfig := &identity_store.IdentityStoreSyncConfig{
+		ConfigMap: fixture.ConfigMap,
+		UserFile:  filepath.Join(dir, "users.json"),
+		GroupFile: filepath.Join(dir, "groups.json"),
+	}
+
+	_, err := k.identityStoreSyncer.SyncIdentityStore(ctx, syncConfig)
+	require.NoError(k.t, err, "identity store sync")
+
+	result.Users = readJSONFile[identity_store.User](k.t, syncConfig.UserFile)
+	result.Groups = readJSONFile[identity_store.Group](k.t, syncConfig.GroupFile)
+	k.report("identity store sync", CheckIdentities(result.Users, result.Groups))
+}
+
+func (k *Kit) syncAccessFromTarget(ctx context.Context, fixture *Fixture, dir string, result *Result) {
+	k.t.Helper()
+
+	syncConfig := &access_provider.AccessSyncFromTarget{
+		ConfigMap:  fixture.ConfigMap,
+		TargetFile: filepath.Join(dir, "access-from-target.json"),
+		Prefix:     fixture.Prefix,
+	}
+
+	_, err := k.accessSyncer.SyncFromTarget(ctx, syncConfig)
+	require.NoError(k.t, err, "access provider sync from target")
+
+	result.AccessProviders = readJSONFile[sync_from_target.AccessProvider](k.t, syncConfig.TargetFile)
+	k.report("access provider sync from target", CheckAccessProviders(result.AccessProviders, result.MetaData))
+}
+
+func (k *Kit) syncAccessToTarget(ctx context.Context, fixture *Fixture, dir string, result *Result) {
+	k.t.Helper()
+
+	syncConfig := &access_provider.AccessSyncToTarget{
+		ConfigMap:          fixture.ConfigMap,
+		SourceFile:         filepath.Join(dir, "access-to-target.json"),
+		FeedbackTargetFile: filepath.Join(dir, "access-feedback.json"),
+		Prefix:             fixture.Prefix,
+	}
+
+	data, err := json.Marshal(fixture.AccessProviders)
+	require.NoError(k.t, err, "serialize access providers of fixture")
+	require.NoError(k.t, os.WriteFile(syncConfig.SourceFile, data, 0600), "write access providers of fixture")
+
+	_, err = k.accessSyncer.SyncToTarget(ctx, syncConfig)
+	require.NoError(k.t, err, "access provider sync to target")
+
+	result.Feedback = readJSONFile[AccessProviderFeedback](k.t, syncConfig.FeedbackTargetFile)
+	k.report("access provider sync to target", CheckFeedback(fixture.AccessProviders, result.Feedback))
+}
+
+func (k *Kit) syncDataUsage(ctx context.Context, fixture *Fixture, dir string, result *Result) {
+	k.t.Helper()
+
+	syncConfig := &data_usage.DataUsageSyncConfig{
+		ConfigMap:       fixture.ConfigMap,
+		TargetFile:      filepath.Join(dir, "data-usage.json"),
+		MaxBytesPerFile: fixture.MaxBytesPerFile,
+	}
+
+	syncResult, err := k.dataUsageSyncer.SyncDataUsage(ctx, syncConfig)
+	require.NoError(k.t, err, "data usage sync")
+
+	targetFiles := syncResult.GetTargetFiles()
+	if len(targetFiles) == 0 {
+		targetFiles = []string{syncConfig.TargetFile}
+	}
+
+	for _, targetFile := range targetFiles {
+		result.Statements = append(result.Statements, readJSONFile[data_usage.Statement](k.t, targetFile)...)
+	}
+
+	k.report("data usage sync", CheckStatements(result.Statements, result.MetaData))
+}
+
+func (k *Kit) report(step string, violations []error) {
+	k.t.Helper()
+
+	for _, violation := range violations {
+		k.t.Errorf("%s: %s", step, violation.Error())
+	}
+}
+
+// readJSONFile reads a file produced by a syncer. A file that isn't a valid JSON array is reported as a violation.
+func readJSONFile[T any](t testing.TB, path string) []T {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "read file %q", path)
+
+	var items []T
+
+	if !json.Valid(data) {
+		t.Errorf("file %q is not valid JSON", filepath.Base(path))
+
+		return items
+	}
+
+	err = json.Unmarshal(data, &items)
+	if err != nil {
+		t.Errorf("file %q is not a valid JSON array: %s", filepath.Base(path), err.Error())
+	}
+
+	return items
+}
@@ -0,0 +1,136 @@
+package testkit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/base/access_provider/sync_from_target"
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/data_usage"
+	"github.com/raito-io/cli/base/identity_store"
+	"github.com/raito-io/cli/base/util/config"
+	"github.com/raito-io/cli/base/wrappers"
+)
+
+// testConnector is a minimal, well-behaving connector implementing all syncers.
+type testConnector struct{}
+
+func (c *testConnector) GetDataSourceMetaData(_ context.Context, _ *config.ConfigMap) (*data_source.MetaData, error) {
+	return &data_source.MetaData{
+		Type: "test",
+		DataObjectTypes: []*data_source.DataObjectType{
+			{Name: data_source.Datasource, Type: data_source.Datasource},
+			{Name: "schema", Type: "schema", Permissions: []*data_source.DataObjectTypePermission{{Permission: "USAGE"}}},
+			{Name: "table", Type: "table", Permissions: []*data_source.DataObjectTypePermission{{Permission: "SELECT"}, {Permission: "INSERT"}}},
+		},
+	}, nil
+}
+
+func (c *testConnector) SyncDataSource(_ context.Context, handler wrappers.DataSourceObjectHandler, _ *data_source.DataSourceSyncConfig) error {
+	return handler.AddDataObjects(
+		&data_source.DataObject{ExternalId: "schema1", Name: "schema1", FullName: "schema1", Type: "schema"},
+		&data_source.DataObject{ExternalId: "schema1.table1", Name: "table1", FullName: "schema1.table1", Type: "table", ParentExternalId: "schema1"},
+	)
+}
+
+func (c *testConnector) GetIdentityStoreMetaData(_ context.Context, _ *config.ConfigMap) (*identity_store.MetaData, error) {
+	return &identity_store.MetaData{Type: "test"}, nil
+}
+
+func (c *testConnector) SyncIdentityStore(_ context.Context, handler wrappers.IdentityStoreIdentityHandler, _ *config.ConfigMap) error {
+	err := handler.AddGroups(&identity_store.Group{ExternalId: "group1", Name: "group1"})
+	if err != nil {
+		return err
+	}
+
+	return handler.AddUsers(&identity_store.User{ExternalId: "user1", Name: "user1", GroupExternalIds: []string{"group1"}})
+}
+
+func (c *testConnector) SyncAccessProvidersFromTarget(_ context.Context, handler wrappers.AccessProviderHandler, _ *config.ConfigMap) error {
+	return handler.AddAccessProviders(&sync_from_target.AccessProvider{
+		ExternalId: "role1",
+		Name:       "role1",
+		ActualName: "role1",
+		Who:        &sync_from_target.WhoItem{Users: []string{"user1"}},
+		What: []sync_from_target.WhatItem{
+			{DataObject: &data_source.DataObjectReference{FullName: "schema1.table1", Type: "table"}, Permissions: []string{"SELECT"}},
+		},
+	})
+}
+
+func (c *testConnector) SyncAccessProviderToTarget(_ context.Context, accessProviders *sync_to_target.AccessProviderImport, handler wrappers.AccessProviderFeedbackHandler, _ *config.ConfigMap) error {
+	for _, ap := range accessProviders.AccessProviders {
+		err := handler.AddAccessProviderFeedback(sync_to_target.AccessProviderSyncFeedback{AccessProvider: ap.Id, ActualName: ap.Name})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *testConnector) SyncDataUsage(_ context.Context, handler wrappers.DataUsageStatementHandler, _ *config.ConfigMap) error {
+	return handler.AddStatements([]data_usage.Statement{
+		{
+			ExternalId: "statement1",
+			AccessedDataObjects: []data_usage.UsageDataObjectItem{
+				{GlobalPermission: data_usage.Read, DataObject: data_usage.UsageDataObjectReference{FullName: "schema1.table1", Type: "table"}},
+			},
+			User:    "user1",
+			Success: true,
+		},
+	})
+}
+
+func testConnectorImpls() []interface{} {
+	connector := &testConnector{}
+
+	return []interface{}{
+		wrappers.DataSourceSync(connector),
+		wrappers.IdentityStoreSync(connector),
+		wrappers.DataAccessSync(connector),
+		wrappers.DataUsageSync(connector),
+	}
+}
+
+func testFixture() *Fixture {
+	return &Fixture{
+		ConfigMap: &config.ConfigMap{Parameters: map[string]string{"key": "value"}},
+		AccessProviders: &sync_to_target.AccessProviderImport{
+			AccessProviders: []*sync_to_target.AccessProvider{
+				{Id: "ap1", Name: "ap1"},
+				{Id: "ap2", Name: "ap2"},
+			},
+		},
+	}
+}
+
+func TestKit_Run(t *testing.T) {
+	result := New(t, testConnectorImpls()...).Run(context.Background(), testFixture())
+
+	require.NotNil(t, result.MetaData)
+	assert.Len(t, result.DataObjects, 3)
+	assert.Equal(t, DefaultDataSourceId, result.DataObjects[0].ExternalId)
+	assert.Len(t, result.Users, 1)
+	assert.Len(t, result.Groups, 1)
+	assert.Len(t, result.AccessProviders, 1)
+	assert.Len(t, result.Feedback, 2)
+	assert.Len(t, result.Statements, 1)
+}
+
+func TestKit_RunGRPC(t *testing.T) {
+	result := NewGRPC(t, testConnectorImpls()...).Run(context.Background(), testFixture())
+
+	require.NotNil(t, result.MetaData)
+	assert.Len(t, result.MetaData.DataObjectTypes, 3)
+	assert.Len(t, result.DataObjects, 3)
+	assert.Len(t, result.Users, 1)
+	assert.Len(t, result.Groups, 1)
+	assert.Len(t, result.AccessProviders, 1)
+	assert.Len(t, result.Feedback, 2)
+	assert.Len(t, result.Statements, 1)
+}
@@ -0,0 +1,154 @@
+package wrappers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Checkpointer allows long-running syncs to persist their progress.
+// The DataSourceObjectHandler and DataUsageStatementHandler passed by the wrappers implement it, so a syncer can use it through a type assertion:
+//
+//	if checkpointer, ok := handler.(wrappers.Checkpointer); ok {
+//		cursor = checkpointer.CheckpointCursor()
+//	}
+//
+// When a sync fails and is retried by the CLI, the syncer can resume from the last checkpoint instead of starting over.
+// All data that was added before the last checkpoint is kept and must not be added again.
+type Checkpointer interface {
+	// CheckpointCursor returns the cursor of the last checkpoint of a previous attempt of the sync, or an empty string if the sync starts from scratch.
+	CheckpointCursor() string
+
+	// Checkpoint flushes all data that was added so far and persists the given cursor, which can be any string that allows the syncer to continue from this point.
+	// Checkpoint does nothing if the CLI didn't enable checkpoints for the sync.
+	Checkpoint(cursor string) error
+}
+
+const checkpointFile = "checkpoint.json"
+
+// checkpointPart is a part of the import files that was flushed at a checkpoint.
+type checkpointPart struct {
+	Files []string `json:"files"`
+	Count int      `json:"count"`
+	Bytes uint64   `json:"bytes,omitempty"`
+}
+
+type checkpointState struct {
+	Cursor    string           `json:"cursor"`
+	Watermark string           `json:"watermark,omitempty"`
+	Parts     []checkpointPart `json:"parts"`
+}
+
+// checkpointStore keeps the checkpoints of a sync in the directory provided by the CLI.
+type checkpointStore struct {
+	dir   string
+	state checkpointState
+
+	// pending contains the flushed parts of which the checkpoint couldn't be stored. They are part of the result, but are not reused by a retried sync.
+	pending []checkpointPart
+}
+
+func loadCheckpointStore(dir string) (*checkpointStore, error) {
+	store := &checkpointStore{dir: dir}
+
+	data, err := os.ReadFile(filepath.Join(dir, checkpointFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return store, nil
+		}
+
+		return nil, fmt.Errorf("read checkpoint: %w", err)
+	}
+
+	err = json.Unmarshal(data, &store.state)
+	if err != nil {
+		return nil, fmt.Errorf("parse checkpoint: %w", err)
+	}
+
+	return store, nil
+}
+
+// nextPart returns the index of the next part and the file to write it to.
+func (s *checkpointStore) nextPart() (int, string) {
+	index := len(s.state.Parts) + len(s.pending)
+
+	return index, filepath.Join(s.dir, fmt.Sprintf("part-%d.json", index))
+}
+
+// commit persists the cursor together with the part that was flushed. Empty parts are removed.
+func (s *checkpointStore) commit(cursor string, watermark string, part checkpointPart) error {
+	state := checkpointState{
+		Cursor:    cursor,
+		Watermark: watermark,
+		Parts:     s.state.Parts,
+	}
+
+	if part.Count > 0 {
+		state.Parts = append(state.Parts[:len(state.Parts):len(state.Parts)], part)
+	} else {
+		for _, f := range part.Files {
+			_ = os.Remove(f)
+		}
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("serialize checkpoint: %w", err)
+	}
+
+	path := filepath.Join(s.dir, checkpointFile)
+	tmpPath := path + ".tmp"
+
+	err = os.WriteFile(tmpPath, data, 0600)
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+
+	if err != nil {
+		if part.Count > 0 {
+			s.pending = append(s.pending, part)
+		}
+
+		return fmt.Errorf("store checkpoint: %w", err)
+	}
+
+	s.state = state
+
+	return nil
+}
+
+func (s *checkpointStore) parts() []checkpointPart {
+	return append(s.state.Parts[:len(s.state.Parts):len(s.state.Parts)], s.pending...)
+}
+
+func (s *checkpointStore) files() []string {
+	var files []string
+
+	for _, part := range s.parts() {
+		files = append(files, part.Files...)
+	}
+
+	return files
+}
+
+func (s *checkpointStore) count() int {
+	count := 0
+
+	for _, part := range s.parts() {
+		count += part.Count
+	}
+
+	return count
+}
+
+func (s *checkpointStore) bytes() uint64 {
+	var bytes uint64
+
+	for _, part := range s.parts() {
+		bytes += part.Bytes
+	}
+
+	return bytes
+}
@@ -2,10 +2,13 @@ package wrappers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime/debug"
 	"time"
 
+	"google.golang.org/protobuf/proto"
+
 	"github.com/raito-io/cli/base/data_source"
 	"github.com/raito-io/cli/base/util/config"
 	error2 "github.com/raito-io/cli/internal/error"
@@ -19,12 +22,31 @@ type DataSourceObjectHandler interface {
 	SetDataSourceDescription(desc string)
 }
 
+// IncrementalDataSourceObjectHandler is implemented by the DataSourceObjectHandler that is passed to the DataSourceSyncer.
+// An IncrementalDataSourceSyncer uses a type assertion to access it, to report the deleted data objects and the watermark for the next sync.
+//
+//go:generate go run github.com/vektra/mockery/v2 --name=IncrementalDataSourceObjectHandler --with-expecter
+type IncrementalDataSourceObjectHandler interface {
+	DataSourceObjectHandler
+
+	DeleteDataObjects(externalIds ...string) error
+	SetWatermark(watermark string)
+}
+
 //go:generate go run github.com/vektra/mockery/v2 --name=DataSourceSyncer --with-expecter --inpackage
 type DataSourceSyncer interface {
 	SyncDataSource(ctx context.Context, dataSourceHandler DataSourceObjectHandler, config *data_source.DataSourceSyncConfig) error
 	GetDataSourceMetaData(ctx context.Context, configParams *config.ConfigMap) (*data_source.MetaData, error)
 }
 
+// IncrementalDataSourceSyncer can optionally be implemented by a DataSourceSyncer to indicate that it supports incremental syncs.
+// When the CLI requests an incremental sync (and the syncer supports it), the syncer only needs to add the data objects that changed
+// since the sync indicated by the watermark (or last successful sync time) in the config and delete the data objects that were removed.
+// Syncers that do not implement this interface always receive a config with Incremental set to false.
+type IncrementalDataSourceSyncer interface {
+	SupportsIncrementalSync() bool
+}
+
 type DataSourceSyncFactoryFn func(ctx context.Context, configParams *config.ConfigMap) (DataSourceSyncer, func(), error)
 
 func DataSourceSync(syncer DataSourceSyncer) data_source.DataSourceSyncer {
@@ -33,8 +55,9 @@ func DataSourceSync(syncer DataSourceSyncer) data_source.DataSourceSyncer {
 
 func DataSourceSyncFactory(syncer DataSourceSyncFactoryFn) data_source.DataSourceSyncer {
 	return &dataSourceSyncFunction{
-		syncer:             NewSyncFactory(syncer),
-		fileCreatorFactory: data_source.NewDataSourceFileCreator,
+		syncer:                 NewSyncFactory(syncer),
+		fileCreatorFactory:     data_source.NewDataSourceFileCreator,
+		partFileCreatorFactory: data_source.NewDataSourceFileCreatorForPart,
 	}
 }
 
@@ -43,6 +66,9 @@ type dataSourceSyncFunction struct {
 
 	syncer             SyncFactory[config.ConfigMap, DataSourceSyncer]
 	fileCreatorFactory func(config *data_source.DataSourceSyncConfig) (data_source.DataSourceFileCreator, error)
+
+	// partFileCreatorFactory is used instead of the fileCreatorFactory when checkpoints are enabled
+	partFileCreatorFactory func(config *data_source.DataSourceSyncConfig, part int) (data_source.DataSourceFileCreator, error)
 }
 
 func (s *dataSourceSyncFunction) SyncDataSource(ctx context.Context, config *data_source.DataSourceSyncConfig) (_ *data_source.DataSourceSyncResult, err error) {
@@ -59,33 +85,223 @@ func (s *dataSourceSyncFunction) SyncDataSource(ctx context.Context, config *dat
 	}()
 
 	logger.Info("Starting data source synchronisation")
+
+	syncer, err := s.syncer.Create(ctx, config.ConfigMap)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Incremental {
+		if incrementalSyncer, ok := syncer.(IncrementalDataSourceSyncer); !ok || !incrementalSyncer.SupportsIncrementalSync() {
+			logger.Info("Incremental sync is not supported. Falling back to a full sync")
+
+			config.Incremental = false
+		}
+	}
+
 	logger.Debug("Creating file for storing data source")
 
-	fileCreator, err := s.fileCreatorFactory(config)
+	handler, err := s.newDataSourceObjectHandler(config)
 	if err != nil {
 		return nil, err
 	}
-	defer fileCreator.Close()
+	defer handler.Close()
 
 	start := time.Now()
 
-	syncer, err := s.syncer.Create(ctx, config.ConfigMap)
+	err = syncer.SyncDataSource(ctx, handler, config)
 	if err != nil {
 		return nil, err
 	}
 
-	err = syncer.SyncDataSource(ctx, fileCreator, config)
+	sec := time.Since(start).Round(time.Millisecond)
+
+	if config.Incremental {
+		logger.Info(fmt.Sprintf("Fetched %d changed and %d deleted data objects in %s", handler.GetDataObjectCount(), handler.getDeletedDataObjectCount(), sec))
+	} else {
+		logger.Info(fmt.Sprintf("Fetched %d data objects in %s", handler.GetDataObjectCount(), sec))
+	}
+
+	result := &data_source.DataSourceSyncResult{
+		DataObjects:        int32(handler.GetDataObjectCount()), //nolint:gosec
+		Incremental:        config.Incremental,
+		Watermark:          handler.getWatermark(),
+		DeletedDataObjects: int32(handler.getDeletedDataObjectCount()), //nolint:gosec
+	}
+
+	if handler.checkpoints != nil {
+		result.PartFiles = handler.files()
+	}
+
+	return result, nil
+}
+
+// newDataSourceObjectHandler creates the handler that writes the data objects to the import file.
+// Checkpoints are not used for incremental syncs, as those are expected to be short.
+func (s *dataSourceSyncFunction) newDataSourceObjectHandler(config *data_source.DataSourceSyncConfig) (*dataSourceObjectHandler, error) {
+	handler := &dataSourceObjectHandler{
+		config: config,
+	}
+
+	if config.CheckpointDir != "" && !config.Incremental {
+		checkpoints, err := loadCheckpointStore(config.CheckpointDir)
+		if err != nil {
+			return nil, err
+		}
+
+		if checkpoints.state.Cursor != "" {
+			logger.Info(fmt.Sprintf("Resuming data source sync from the last checkpoint (%d data objects already fetched)", checkpoints.count()))
+		}
+
+		handler.checkpoints = checkpoints
+		handler.fileCreatorFactory = s.partFileCreatorFactory
+	} else {
+		handler.fileCreatorFactory = func(config *data_source.DataSourceSyncConfig, _ int) (data_source.DataSourceFileCreator, error) {
+			return s.fileCreatorFactory(config)
+		}
+	}
+
+	err := handler.openPart()
 	if err != nil {
 		return nil, err
 	}
 
-	sec := time.Since(start).Round(time.Millisecond)
+	return handler, nil
+}
+
+// dataSourceObjectHandler writes the data objects to the import file.
+// When checkpoints are enabled, the data objects are written in parts. Every checkpoint flushes the current part and starts a new one.
+type dataSourceObjectHandler struct {
+	config             *data_source.DataSourceSyncConfig
+	fileCreatorFactory func(config *data_source.DataSourceSyncConfig, part int) (data_source.DataSourceFileCreator, error)
+
+	// checkpoints is nil if checkpoints are disabled
+	checkpoints *checkpointStore
+	current     data_source.DataSourceFileCreator
+}
+
+var _ IncrementalDataSourceObjectHandler = (*dataSourceObjectHandler)(nil)
+
+func (h *dataSourceObjectHandler) openPart() error {
+	config := h.config
+	part := 0
+
+	if h.checkpoints != nil {
+		config = proto.Clone(h.config).(*data_source.DataSourceSyncConfig)
+		part, config.TargetFile = h.checkpoints.nextPart()
+	}
+
+	fileCreator, err := h.fileCreatorFactory(config, part)
+	if err != nil {
+		return err
+	}
 
-	logger.Info(fmt.Sprintf("Fetched %d data objects in %s", fileCreator.GetDataObjectCount(), sec))
+	h.current = fileCreator
+
+	// The watermark set before the last checkpoint is taken over by the next part
+	if h.checkpoints != nil && h.checkpoints.state.Watermark != "" {
+		h.SetWatermark(h.checkpoints.state.Watermark)
+	}
+
+	return nil
+}
+
+func (h *dataSourceObjectHandler) AddDataObjects(dataObjects ...*data_source.DataObject) error {
+	return h.current.AddDataObjects(dataObjects...)
+}
+
+func (h *dataSourceObjectHandler) SetDataSourceName(name string) {
+	h.current.SetDataSourceName(name)
+}
+
+func (h *dataSourceObjectHandler) SetDataSourceFullname(name string) {
+	h.current.SetDataSourceFullname(name)
+}
+
+func (h *dataSourceObjectHandler) SetDataSourceDescription(desc string) {
+	h.current.SetDataSourceDescription(desc)
+}
+
+func (h *dataSourceObjectHandler) DeleteDataObjects(externalIds ...string) error {
+	fileCreator, ok := h.current.(data_source.IncrementalDataSourceFileCreator)
+	if !ok {
+		return errors.New("deleting data objects is not supported by the data source file creator")
+	}
+
+	return fileCreator.DeleteDataObjects(externalIds...)
+}
+
+func (h *dataSourceObjectHandler) SetWatermark(watermark string) {
+	if fileCreator, ok := h.current.(data_source.IncrementalDataSourceFileCreator); ok {
+		fileCreator.SetWatermark(watermark)
+	}
+}
+
+func (h *dataSourceObjectHandler) getWatermark() string {
+	if fileCreator, ok := h.current.(data_source.IncrementalDataSourceFileCreator); ok {
+		return fileCreator.GetWatermark()
+	}
+
+	return ""
+}
+
+func (h *dataSourceObjectHandler) getDeletedDataObjectCount() int {
+	if fileCreator, ok := h.current.(data_source.IncrementalDataSourceFileCreator); ok {
+		return fileCreator.GetDeletedDataObjectCount()
+	}
+
+	return 0
+}
+
+func (h *dataSourceObjectHandler) GetDataObjectCount() int {
+	if h.checkpoints == nil {
+		return h.current.GetDataObjectCount()
+	}
+
+	return h.checkpoints.count() + h.current.GetDataObjectCount()
+}
+
+func (h *dataSourceObjectHandler) CheckpointCursor() string {
+	if h.checkpoints == nil {
+		return ""
+	}
+
+	return h.checkpoints.state.Cursor
+}
+
+func (h *dataSourceObjectHandler) Checkpoint(cursor string) error {
+	if h.checkpoints == nil {
+		return nil
+	}
+
+	h.current.Close()
+
+	_, currentFile := h.checkpoints.nextPart()
+
+	part := checkpointPart{
+		Files: []string{currentFile},
+		Count: h.current.GetDataObjectCount(),
+	}
+
+	commitErr := h.checkpoints.commit(cursor, h.getWatermark(), part)
+
+	err := h.openPart()
+	if err != nil {
+		return errors.Join(commitErr, err)
+	}
+
+	return commitErr
+}
+
+// files returns all files that were written (only used when checkpoints are enabled).
+func (h *dataSourceObjectHandler) files() []string {
+	_, currentFile := h.checkpoints.nextPart()
+
+	return append(h.checkpoints.files(), currentFile)
+}
 
-	return &data_source.DataSourceSyncResult{
-		DataObjects: int32(fileCreator.GetDataObjectCount()), //nolint:gosec
-	}, nil
+func (h *dataSourceObjectHandler) Close() {
+	h.current.Close()
 }
 
 func (s *dataSourceSyncFunction) GetDataSourceMetaData(ctx context.Context, configParams *config.ConfigMap) (*data_source.MetaData, error) {
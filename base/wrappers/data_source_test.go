@@ -27,7 +27,7 @@ func TestDataSourceSyncFunction_SyncDataSource(t *testing.T) {
 	fileCreatorMock.EXPECT().GetDataObjectCount().Return(0)
 
 	syncerMock := NewMockDataSourceSyncer(t)
-	syncerMock.EXPECT().SyncDataSource(mock.Anything, fileCreatorMock, config).Return(nil).Once()
+	syncerMock.EXPECT().SyncDataSource(mock.Anything, mock.Anything, config).Return(nil).Once()
 
 	syncFunction := dataSourceSyncFunction{
 		syncer: NewSyncFactory[config2.ConfigMap, DataSourceSyncer](NewDummySyncFactoryFn[config2.ConfigMap, DataSourceSyncer](syncerMock)),
@@ -44,6 +44,86 @@ func TestDataSourceSyncFunction_SyncDataSource(t *testing.T) {
 	assert.Nil(t, result.Error)
 }
 
+type incrementalDataSourceSyncer struct {
+	*MockDataSourceSyncer
+	supported bool
+}
+
+func (s *incrementalDataSourceSyncer) SupportsIncrementalSync() bool {
+	return s.supported
+}
+
+func TestDataSourceSyncFunction_SyncDataSource_Incremental(t *testing.T) {
+	tests := []struct {
+		name                string
+		syncer              func(mock *MockDataSourceSyncer) DataSourceSyncer
+		expectedIncremental bool
+	}{
+		{
+			name: "supported",
+			syncer: func(mock *MockDataSourceSyncer) DataSourceSyncer {
+				return &incrementalDataSourceSyncer{MockDataSourceSyncer: mock, supported: true}
+			},
+			expectedIncremental: true,
+		},
+		{
+			name: "disabled by syncer",
+			syncer: func(mock *MockDataSourceSyncer) DataSourceSyncer {
+				return &incrementalDataSourceSyncer{MockDataSourceSyncer: mock, supported: false}
+			},
+			expectedIncremental: false,
+		},
+		{
+			name: "not implemented",
+			syncer: func(mock *MockDataSourceSyncer) DataSourceSyncer {
+				return mock
+			},
+			expectedIncremental: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			//Given
+			config := &data_source.DataSourceSyncConfig{
+				TargetFile:   "targetFile",
+				DataSourceId: "DataSourceId",
+				ConfigMap:    &config2.ConfigMap{Parameters: map[string]string{"key": "value"}},
+				Incremental:  true,
+				Watermark:    "cursor-1",
+			}
+
+			fileCreatorMock := ds_mocks.NewIncrementalDataSourceFileCreator(t)
+			fileCreatorMock.EXPECT().Close().Return().Once()
+			fileCreatorMock.EXPECT().GetDataObjectCount().Return(2)
+			fileCreatorMock.EXPECT().GetDeletedDataObjectCount().Return(1)
+			fileCreatorMock.EXPECT().GetWatermark().Return("cursor-2")
+
+			syncerMock := NewMockDataSourceSyncer(t)
+			syncerMock.EXPECT().SyncDataSource(mock.Anything, mock.Anything, config).Return(nil).Once()
+
+			syncFunction := dataSourceSyncFunction{
+				syncer: NewSyncFactory[config2.ConfigMap, DataSourceSyncer](NewDummySyncFactoryFn[config2.ConfigMap, DataSourceSyncer](tt.syncer(syncerMock))),
+				fileCreatorFactory: func(fileConfig *data_source.DataSourceSyncConfig) (data_source.DataSourceFileCreator, error) {
+					assert.Equal(t, tt.expectedIncremental, fileConfig.Incremental)
+
+					return fileCreatorMock, nil
+				},
+			}
+
+			//When
+			result, err := syncFunction.SyncDataSource(context.Background(), config)
+
+			//Then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedIncremental, result.Incremental)
+			assert.Equal(t, "cursor-2", result.Watermark)
+			assert.Equal(t, int32(2), result.DataObjects)
+			assert.Equal(t, int32(1), result.DeletedDataObjects)
+		})
+	}
+}
+
 func TestDataSourceSyncFunction_SyncDataSource_ErrorOnFile(t *testing.T) {
 	//Given
 	config := &data_source.DataSourceSyncConfig{
@@ -83,7 +163,7 @@ func TestDataSourceSyncFunction_SyncDataSource_ErrorSync(t *testing.T) {
 	fileCreatorMock.EXPECT().Close().Return().Once()
 
 	syncerMock := NewMockDataSourceSyncer(t)
-	syncerMock.EXPECT().SyncDataSource(mock.Anything, fileCreatorMock, config).Return(errors.New("BOOM!")).Once()
+	syncerMock.EXPECT().SyncDataSource(mock.Anything, mock.Anything, config).Return(errors.New("BOOM!")).Once()
 
 	syncFunction := dataSourceSyncFunction{
 		syncer: NewSyncFactory[config2.ConfigMap, DataSourceSyncer](NewDummySyncFactoryFn[config2.ConfigMap, DataSourceSyncer](syncerMock)),
@@ -2,9 +2,12 @@ package wrappers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"runtime/debug"
 
+	"google.golang.org/protobuf/proto"
+
 	"github.com/raito-io/cli/base/data_usage"
 	"github.com/raito-io/cli/base/util/config"
 	error2 "github.com/raito-io/cli/internal/error"
@@ -57,12 +60,12 @@ func (s *dataUsageSyncFunction) SyncDataUsage(ctx context.Context, config *data_
 	logger.Info("Starting data usage synchronisation")
 	logger.Debug("Creating file for storing data usage")
 
-	fileCreator, err := s.fileCreatorFactory(config)
+	handler, err := newDataUsageStatementHandler(config, s.fileCreatorFactory)
 	if err != nil {
 		return nil, err
 	}
 
-	defer fileCreator.Close()
+	defer handler.Close()
 
 	syncer, err := s.syncer.Create(ctx, config.ConfigMap)
 	if err != nil {
@@ -70,7 +73,7 @@ func (s *dataUsageSyncFunction) SyncDataUsage(ctx context.Context, config *data_
 	}
 
 	sec, err := timedExecution(func() error {
-		return syncer.SyncDataUsage(ctx, fileCreator, config.ConfigMap)
+		return syncer.SyncDataUsage(ctx, handler, config.ConfigMap)
 	})
 
 	if err != nil {
@@ -78,12 +81,135 @@ func (s *dataUsageSyncFunction) SyncDataUsage(ctx context.Context, config *data_
 	}
 
 	logger.Info(fmt.Sprintf("Retrieved %d rows and written them to file (total size %d bytes), for a total time of %s",
-		fileCreator.GetStatementCount(), fileCreator.GetImportFileSize(), sec))
+		handler.GetStatementCount(), handler.GetImportFileSize(), sec))
+
+	result := &data_usage.DataUsageSyncResult{
+		Statements: int32(handler.GetStatementCount()), //nolint:gosec
+	}
+
+	if handler.checkpoints != nil {
+		result.PartFiles = handler.files()
+	} else {
+		result.TargetFiles = handler.current.GetActualFileNames()
+	}
+
+	return result, nil
+}
+
+// dataUsageStatementHandler writes the statements to the import files.
+// When checkpoints are enabled, the statements are written in parts. Every checkpoint flushes the current part and starts a new one.
+type dataUsageStatementHandler struct {
+	config             *data_usage.DataUsageSyncConfig
+	fileCreatorFactory func(config *data_usage.DataUsageSyncConfig) (data_usage.DataUsageFileCreator, error)
+
+	// checkpoints is nil if checkpoints are disabled
+	checkpoints *checkpointStore
+	current     data_usage.DataUsageFileCreator
+}
+
+func newDataUsageStatementHandler(config *data_usage.DataUsageSyncConfig, fileCreatorFactory func(config *data_usage.DataUsageSyncConfig) (data_usage.DataUsageFileCreator, error)) (*dataUsageStatementHandler, error) {
+	handler := &dataUsageStatementHandler{
+		config:             config,
+		fileCreatorFactory: fileCreatorFactory,
+	}
+
+	if config.CheckpointDir != "" {
+		checkpoints, err := loadCheckpointStore(config.CheckpointDir)
+		if err != nil {
+			return nil, err
+		}
+
+		if checkpoints.state.Cursor != "" {
+			logger.Info(fmt.Sprintf("Resuming data usage sync from the last checkpoint (%d statements already fetched)", checkpoints.count()))
+		}
+
+		handler.checkpoints = checkpoints
+	}
+
+	err := handler.openPart()
+	if err != nil {
+		return nil, err
+	}
+
+	return handler, nil
+}
+
+func (h *dataUsageStatementHandler) openPart() error {
+	config := h.config
+
+	if h.checkpoints != nil {
+		config = proto.Clone(h.config).(*data_usage.DataUsageSyncConfig)
+		_, config.TargetFile = h.checkpoints.nextPart()
+	}
+
+	fileCreator, err := h.fileCreatorFactory(config)
+	if err != nil {
+		return err
+	}
+
+	h.current = fileCreator
+
+	return nil
+}
+
+func (h *dataUsageStatementHandler) AddStatements(statements []data_usage.Statement) error {
+	return h.current.AddStatements(statements)
+}
+
+func (h *dataUsageStatementHandler) GetImportFileSize() uint64 {
+	if h.checkpoints == nil {
+		return h.current.GetImportFileSize()
+	}
+
+	return h.checkpoints.bytes() + h.current.GetImportFileSize()
+}
+
+func (h *dataUsageStatementHandler) GetStatementCount() int {
+	if h.checkpoints == nil {
+		return h.current.GetStatementCount()
+	}
+
+	return h.checkpoints.count() + h.current.GetStatementCount()
+}
+
+func (h *dataUsageStatementHandler) CheckpointCursor() string {
+	if h.checkpoints == nil {
+		return ""
+	}
+
+	return h.checkpoints.state.Cursor
+}
+
+func (h *dataUsageStatementHandler) Checkpoint(cursor string) error {
+	if h.checkpoints == nil {
+		return nil
+	}
+
+	h.current.Close()
+
+	part := checkpointPart{
+		Files: h.current.GetActualFileNames(),
+		Count: h.current.GetStatementCount(),
+		Bytes: h.current.GetImportFileSize(),
+	}
+
+	commitErr := h.checkpoints.commit(cursor, "", part)
+
+	err := h.openPart()
+	if err != nil {
+		return errors.Join(commitErr, err)
+	}
+
+	return commitErr
+}
+
+// files returns all files that were written (only used when checkpoints are enabled). Should be called after closing the handler.
+func (h *dataUsageStatementHandler) files() []string {
+	return append(h.checkpoints.files(), h.current.GetActualFileNames()...)
+}
 
-	return &data_usage.DataUsageSyncResult{
-		Statements:  int32(fileCreator.GetStatementCount()), //nolint:gosec
-		TargetFiles: fileCreator.GetActualFileNames(),
-	}, nil
+func (h *dataUsageStatementHandler) Close() {
+	h.current.Close()
 }
 
 func (s *dataUsageSyncFunction) Close() {
@@ -2,7 +2,11 @@ package wrappers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,7 +32,7 @@ func TestDataUsageSyncFunction_SyncDataUsage(t *testing.T) {
 	fileCreatorMock.EXPECT().GetActualFileNames().Return([]string{"file1", "file2"})
 
 	syncerMock := NewMockDataUsageSyncer(t)
-	syncerMock.EXPECT().SyncDataUsage(mock.Anything, fileCreatorMock, config.ConfigMap).Return(nil)
+	syncerMock.EXPECT().SyncDataUsage(mock.Anything, mock.Anything, config.ConfigMap).Return(nil)
 
 	syncFunction := dataUsageSyncFunction{
 		syncer: NewSyncFactory[config2.ConfigMap, DataUsageSyncer](NewDummySyncFactoryFn[config2.ConfigMap, DataUsageSyncer](syncerMock)),
@@ -84,7 +88,7 @@ func TestDataUsageSyncFunction_SyncDataUsage_ErrorSync(t *testing.T) {
 	fileCreatorMock.EXPECT().Close().Return()
 
 	syncerMock := NewMockDataUsageSyncer(t)
-	syncerMock.EXPECT().SyncDataUsage(mock.Anything, fileCreatorMock, config.ConfigMap).Return(errors.New("BOOM!"))
+	syncerMock.EXPECT().SyncDataUsage(mock.Anything, mock.Anything, config.ConfigMap).Return(errors.New("BOOM!"))
 
 	syncFunction := dataUsageSyncFunction{
 		syncer: NewSyncFactory[config2.ConfigMap, DataUsageSyncer](NewDummySyncFactoryFn[config2.ConfigMap, DataUsageSyncer](syncerMock)),
@@ -103,6 +107,70 @@ func TestDataUsageSyncFunction_SyncDataUsage_ErrorSync(t *testing.T) {
 	fileCreatorMock.AssertNumberOfCalls(t, "Close", 1)
 }
 
+func TestDataUsageSyncFunction_SyncDataUsage_ResumeFromCheckpoint(t *testing.T) {
+	//Given
+	dir := t.TempDir()
+	config := &data_usage.DataUsageSyncConfig{
+		TargetFile:    filepath.Join(dir, "usage.json"),
+		CheckpointDir: filepath.Join(dir, "checkpoints"),
+		ConfigMap:     &config2.ConfigMap{Parameters: map[string]string{"key": "value"}},
+	}
+
+	require.NoError(t, os.MkdirAll(config.CheckpointDir, 0700))
+
+	syncerMock := NewMockDataUsageSyncer(t)
+	syncerMock.EXPECT().SyncDataUsage(mock.Anything, mock.Anything, config.ConfigMap).RunAndReturn(func(_ context.Context, handler DataUsageStatementHandler, _ *config2.ConfigMap) error {
+		checkpointer, ok := handler.(Checkpointer)
+		require.True(t, ok)
+		assert.Empty(t, checkpointer.CheckpointCursor())
+
+		require.NoError(t, handler.AddStatements([]data_usage.Statement{{ExternalId: "s1"}, {ExternalId: "s2"}}))
+		require.NoError(t, checkpointer.Checkpoint("page-2"))
+		require.NoError(t, handler.AddStatements([]data_usage.Statement{{ExternalId: "s3"}}))
+
+		return errors.New("BOOM!")
+	}).Once()
+	syncerMock.EXPECT().SyncDataUsage(mock.Anything, mock.Anything, config.ConfigMap).RunAndReturn(func(_ context.Context, handler DataUsageStatementHandler, _ *config2.ConfigMap) error {
+		checkpointer, ok := handler.(Checkpointer)
+		require.True(t, ok)
+		assert.Equal(t, "page-2", checkpointer.CheckpointCursor())
+
+		return handler.AddStatements([]data_usage.Statement{{ExternalId: "s3"}, {ExternalId: "s4"}})
+	}).Once()
+
+	syncFunction := DataUsageSync(syncerMock)
+
+	//When
+	_, err := syncFunction.SyncDataUsage(context.Background(), config)
+	require.Error(t, err)
+
+	result, err := syncFunction.SyncDataUsage(context.Background(), config)
+
+	//Then
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), result.Statements)
+	assert.Empty(t, result.TargetFiles)
+	require.Len(t, result.PartFiles, 2)
+
+	var statements []data_usage.Statement
+
+	for _, file := range result.PartFiles {
+		content, readErr := os.ReadFile(file)
+		require.NoError(t, readErr)
+
+		var part []data_usage.Statement
+		require.NoError(t, json.Unmarshal(content, &part))
+
+		statements = append(statements, part...)
+	}
+
+	require.Len(t, statements, 4)
+
+	for i, statement := range statements {
+		assert.Equal(t, fmt.Sprintf("s%d", i+1), statement.ExternalId)
+	}
+}
+
 func TestDataUsageSyncWrapper(t *testing.T) {
 	//Given
 	syncerMock := NewMockDataUsageSyncer(t)
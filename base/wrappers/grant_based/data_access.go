@@ -0,0 +1,261 @@
+package grant_based
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/raito-io/cli/base"
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/access_provider/types"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/util/config"
+	"github.com/raito-io/cli/base/wrappers"
+)
+
+var logger = base.Logger()
+
+// DefaultBatchSize is the number of grants passed to the connector at once if no batch size is provided.
+const DefaultBatchSize = 100
+
+// AccessProviderGrantSyncer is implemented by connectors for data sources that manage access by granting permissions directly to users and groups on data objects (e.g. ACLs or bucket policies), instead of through roles.
+// The wrapper calculates the grants and revokes that are needed to reach the state described by the access providers. The connector only needs to read and apply grants.
+//
+//go:generate go run github.com/vektra/mockery/v2 --name=AccessProviderGrantSyncer --with-expecter --inpackage
+type AccessProviderGrantSyncer interface {
+	SyncAccessProvidersFromTarget(ctx context.Context, accessProviderHandler wrappers.AccessProviderHandler, configMap *config.ConfigMap) error
+
+	// GetGrants returns the grants that currently exist on the given data objects in the data source.
+	GetGrants(ctx context.Context, dataObjects []data_source.DataObjectReference, configMap *config.ConfigMap) ([]Grant, error)
+
+	// GrantPermissions creates the given grants in the data source.
+	// The grants that failed can be returned together with their error. Returning an error marks all grants of the batch as failed.
+	GrantPermissions(ctx context.Context, grants []Grant, configMap *config.ConfigMap) (map[Grant]error, error)
+
+	// RevokePermissions removes the given grants from the data source.
+	// The revokes that failed can be returned together with their error. Returning an error marks all revokes of the batch as failed.
+	RevokePermissions(ctx context.Context, grants []Grant, configMap *config.ConfigMap) (map[Grant]error, error)
+}
+
+// AccessProviderGrantSync creates the access syncer for a connector that manages access through grants. Grants are passed to the connector in batches of the given size (DefaultBatchSize if 0).
+// Partial syncs are not supported, as the grants of all access providers are needed to know which grants can be revoked.
+func AccessProviderGrantSync(syncer AccessProviderGrantSyncer, batchSize int, configOpt ...func(config *access_provider.AccessSyncConfig)) *wrappers.DataAccessSyncFunction {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	grantSync := &accessProviderGrantSyncFunction{
+		syncer:    syncer,
+		batchSize: batchSize,
+	}
+
+	// Applied last, so the partial sync support can't be switched on by the given options
+	configOpt = append(configOpt[:len(configOpt):len(configOpt)], func(config *access_provider.AccessSyncConfig) {
+		config.SupportPartialSync = false
+	})
+
+	return wrappers.DataAccessSync(grantSync, configOpt...)
+}
+
+type accessProviderGrantSyncFunction struct {
+	syncer    AccessProviderGrantSyncer
+	batchSize int
+}
+
+func (s *accessProviderGrantSyncFunction) SyncAccessProvidersFromTarget(ctx context.Context, accessProviderHandler wrappers.AccessProviderHandler, configMap *config.ConfigMap) error {
+	return s.syncer.SyncAccessProvidersFromTarget(ctx, accessProviderHandler, configMap)
+}
+
+func (s *accessProviderGrantSyncFunction) SyncAccessProviderToTarget(ctx context.Context, accessProviders *sync_to_target.AccessProviderImport, accessProviderFeedbackHandler wrappers.AccessProviderFeedbackHandler, configMap *config.ConfigMap) error {
+	apByActualName := make(map[string]*sync_to_target.AccessProvider, len(accessProviders.AccessProviders))
+
+	for _, ap := range accessProviders.AccessProviders {
+		apByActualName[actualName(ap)] = ap
+	}
+
+	desired := grantSet{}
+	previouslyManaged := grantSet{}
+
+	feedback := make([]*sync_to_target.AccessProviderSyncFeedback, 0, len(accessProviders.AccessProviders))
+	feedbackMap := make(map[string]*sync_to_target.AccessProviderSyncFeedback, len(accessProviders.AccessProviders))
+
+	for _, ap := range accessProviders.AccessProviders {
+		apFeedback := &sync_to_target.AccessProviderSyncFeedback{
+			AccessProvider: ap.Id,
+			ActualName:     actualName(ap),
+		}
+
+		feedback = append(feedback, apFeedback)
+		feedbackMap[ap.Id] = apFeedback
+
+		if ap.Action != types.Grant && ap.Action != types.Purpose {
+			apFeedback.Errors = append(apFeedback.Errors, fmt.Sprintf("Unsupported action %s", ap.Action.String()))
+
+			continue
+		}
+
+		principals, warnings := resolvePrincipals(&ap.Who, apByActualName)
+		apFeedback.Warnings = append(apFeedback.Warnings, warnings...)
+
+		// All grants of a deleted access provider are revoked, unless another access provider grants the same
+		if ap.Delete {
+			previouslyManaged.addAll(principals, ap.What, ap.Id)
+			previouslyManaged.addAll(principals, ap.DeleteWhat, ap.Id)
+
+			continue
+		}
+
+		desired.addAll(principals, ap.What, ap.Id)
+		previouslyManaged.addAll(principals, ap.DeleteWhat, ap.Id)
+
+		if ap.DeletedWho != nil {
+			deletedPrincipals, _ := resolvePrincipals(ap.DeletedWho, apByActualName)
+
+			previouslyManaged.addAll(deletedPrincipals, ap.What, ap.Id)
+			previouslyManaged.addAll(deletedPrincipals, ap.DeleteWhat, ap.Id)
+		}
+	}
+
+	err := s.syncGrants(ctx, desired, previouslyManaged, feedbackMap, configMap)
+	if err != nil {
+		return err
+	}
+
+	for _, apFeedback := range feedback {
+		err = accessProviderFeedbackHandler.AddAccessProviderFeedback(*apFeedback)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *accessProviderGrantSyncFunction) syncGrants(ctx context.Context, desired grantSet, previouslyManaged grantSet, feedbackMap map[string]*sync_to_target.AccessProviderSyncFeedback, configMap *config.ConfigMap) error {
+	dataObjects := affectedDataObjects(desired, previouslyManaged)
+	if len(dataObjects) == 0 {
+		return nil
+	}
+
+	current, err := s.syncer.GetGrants(ctx, dataObjects, configMap)
+	if err != nil {
+		return fmt.Errorf("get grants: %w", err)
+	}
+
+	plan := calculateGrantPlan(desired, previouslyManaged, current)
+
+	logger.Info(fmt.Sprintf("Granting %d and revoking %d permissions on %d data objects", len(plan.toGrant), len(plan.toRevoke), len(dataObjects)))
+
+	err = s.applyInBatches(ctx, plan.toGrant, s.syncer.GrantPermissions, "grant", feedbackMap, configMap)
+	if err != nil {
+		return err
+	}
+
+	return s.applyInBatches(ctx, plan.toRevoke, s.syncer.RevokePermissions, "revoke", feedbackMap, configMap)
+}
+
+type grantFn func(ctx context.Context, grants []Grant, configMap *config.ConfigMap) (map[Grant]error, error)
+
+// applyInBatches passes the grants to the connector in batches. Failed grants are reported on the access providers that caused them.
+func (s *accessProviderGrantSyncFunction) applyInBatches(ctx context.Context, grants grantSet, fn grantFn, action string, feedbackMap map[string]*sync_to_target.AccessProviderSyncFeedback, configMap *config.ConfigMap) error {
+	grantList := make([]Grant, 0, len(grants))
+	for grant := range grants {
+		grantList = append(grantList, grant)
+	}
+
+	grantList = sortedGrants(grantList)
+
+	for start := 0; start < len(grantList); start += s.batchSize {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		batch := grantList[start:min(start+s.batchSize, len(grantList))]
+
+		failed, err := fn(ctx, batch, configMap)
+		if err != nil {
+			logger.Warn(fmt.Sprintf("Unable to %s a batch of %d permissions: %s", action, len(batch), err.Error()))
+
+			failed = make(map[Grant]error, len(batch))
+			for _, grant := range batch {
+				failed[grant] = err
+			}
+		}
+
+		for grant, grantErr := range failed {
+			if grantErr == nil {
+				continue
+			}
+
+			for _, apId := range grants[grant] {
+				if apFeedback, found := feedbackMap[apId]; found {
+					apFeedback.Errors = append(apFeedback.Errors, fmt.Sprintf("unable to %s %s: %s", action, grant, grantErr.Error()))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolvePrincipals returns the users and groups in the who item. Inherited access providers are resolved to their own users and groups.
+func resolvePrincipals(who *sync_to_target.WhoItem, apByActualName map[string]*sync_to_target.AccessProvider) ([]Principal, []string) {
+	principals := make([]Principal, 0, len(who.Users)+len(who.Groups))
+	principalSet := make(map[Principal]struct{})
+
+	var warnings []string
+
+	visited := make(map[string]struct{})
+
+	var resolve func(who *sync_to_target.WhoItem)
+	resolve = func(who *sync_to_target.WhoItem) {
+		for _, user := range who.Users {
+			principals = appendPrincipal(principals, principalSet, Principal{Type: PrincipalTypeUser, Name: user})
+		}
+
+		for _, group := range who.Groups {
+			principals = appendPrincipal(principals, principalSet, Principal{Type: PrincipalTypeGroup, Name: group})
+		}
+
+		for _, inherited := range who.InheritFrom {
+			if _, found := visited[inherited]; found {
+				continue
+			}
+
+			visited[inherited] = struct{}{}
+
+			inheritedAp, found := apByActualName[inherited]
+			if !found || inheritedAp.Delete {
+				warnings = append(warnings, fmt.Sprintf("Unable to resolve the inherited access provider %q", inherited))
+
+				continue
+			}
+
+			resolve(&inheritedAp.Who)
+		}
+	}
+
+	resolve(who)
+
+	return principals, warnings
+}
+
+func appendPrincipal(principals []Principal, principalSet map[Principal]struct{}, principal Principal) []Principal {
+	if _, found := principalSet[principal]; found {
+		return principals
+	}
+
+	principalSet[principal] = struct{}{}
+
+	return append(principals, principal)
+}
+
+// actualName returns the name by which the access provider is known in the data source.
+// As no object is created in the data source for an access provider, the id of the access provider is used if it has no actual name yet.
+func actualName(ap *sync_to_target.AccessProvider) string {
+	if ap.ActualName != nil && *ap.ActualName != "" {
+		return *ap.ActualName
+	}
+
+	return ap.Id
+}
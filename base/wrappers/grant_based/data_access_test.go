@@ -0,0 +1,206 @@
+package grant_based
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/access_provider/types"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/util/config"
+)
+
+type feedbackCollector struct {
+	feedback []sync_to_target.AccessProviderSyncFeedback
+}
+
+func (c *feedbackCollector) AddAccessProviderFeedback(accessProviderFeedback sync_to_target.AccessProviderSyncFeedback) error {
+	c.feedback = append(c.feedback, accessProviderFeedback)
+
+	return nil
+}
+
+func table(name string) data_source.DataObjectReference {
+	return data_source.DataObjectReference{FullName: name, Type: "table"}
+}
+
+func tableRef(name string) *data_source.DataObjectReference {
+	ref := table(name)
+
+	return &ref
+}
+
+func userGrant(user string, dataObject string, permission string) Grant {
+	return Grant{Principal: Principal{Type: PrincipalTypeUser, Name: user}, DataObject: table(dataObject), Permission: permission}
+}
+
+func TestAccessProviderGrantSync_NoPartialSync(t *testing.T) {
+	//Given
+	syncerMock := NewMockAccessProviderGrantSyncer(t)
+
+	syncFunction := AccessProviderGrantSync(syncerMock, 0, access_provider.WithSupportPartialSync())
+
+	//When
+	syncConfig, err := syncFunction.SyncConfig(context.Background())
+
+	//Then
+	require.NoError(t, err)
+	assert.False(t, syncConfig.SupportPartialSync)
+}
+
+func TestAccessProviderGrantSyncFunction_SyncAccessProviderFromTarget(t *testing.T) {
+	//Given
+	configMap := config.ConfigMap{Parameters: map[string]string{"key": "value"}}
+
+	syncerMock := NewMockAccessProviderGrantSyncer(t)
+	syncerMock.EXPECT().SyncAccessProvidersFromTarget(mock.Anything, nil, &configMap).Return(nil).Once()
+
+	syncFunction := accessProviderGrantSyncFunction{syncer: syncerMock}
+
+	//When
+	err := syncFunction.SyncAccessProvidersFromTarget(context.Background(), nil, &configMap)
+
+	//Then
+	assert.NoError(t, err)
+}
+
+func TestAccessProviderGrantSyncFunction_SyncAccessProviderToTarget(t *testing.T) {
+	//Given
+	configMap := config.ConfigMap{Parameters: map[string]string{"key": "value"}}
+
+	accessProviders := sync_to_target.AccessProviderImport{
+		AccessProviders: []*sync_to_target.AccessProvider{
+			{
+				Id:         "AP1",
+				Action:     types.Grant,
+				Who:        sync_to_target.WhoItem{Users: []string{"alice"}, Groups: []string{"engineering"}},
+				What:       []sync_to_target.WhatItem{{DataObject: tableRef("db.t1"), Permissions: []string{"SELECT"}}},
+				DeleteWhat: []sync_to_target.WhatItem{{DataObject: tableRef("db.t3"), Permissions: []string{"SELECT"}}},
+			},
+			{
+				Id:         "AP2",
+				ActualName: ptr.String("AP2"),
+				Action:     types.Grant,
+				Delete:     true,
+				Who:        sync_to_target.WhoItem{Users: []string{"carol", "alice"}},
+				What:       []sync_to_target.WhatItem{{DataObject: tableRef("db.t2"), Permissions: []string{"SELECT"}}, {DataObject: tableRef("db.t1"), Permissions: []string{"SELECT"}}},
+			},
+			{
+				Id:     "AP3",
+				Action: types.Mask,
+			},
+		},
+	}
+
+	syncerMock := NewMockAccessProviderGrantSyncer(t)
+	syncerMock.EXPECT().GetGrants(mock.Anything, []data_source.DataObjectReference{table("db.t1"), table("db.t2"), table("db.t3")}, &configMap).Return([]Grant{
+		userGrant("alice", "db.t1", "SELECT"),
+		userGrant("bob", "db.t1", "SELECT"),
+		userGrant("carol", "db.t2", "SELECT"),
+		userGrant("alice", "db.t3", "SELECT"),
+	}, nil).Once()
+
+	// alice keeps access on t1 through AP1, the unmanaged grant of bob is left untouched
+	syncerMock.EXPECT().GrantPermissions(mock.Anything, []Grant{{Principal: Principal{Type: PrincipalTypeGroup, Name: "engineering"}, DataObject: table("db.t1"), Permission: "SELECT"}}, &configMap).Return(nil, nil).Once()
+	syncerMock.EXPECT().RevokePermissions(mock.Anything, []Grant{userGrant("carol", "db.t2", "SELECT"), userGrant("alice", "db.t3", "SELECT")}, &configMap).Return(nil, nil).Once()
+
+	feedback := &feedbackCollector{}
+
+	syncFunction := accessProviderGrantSyncFunction{syncer: syncerMock, batchSize: DefaultBatchSize}
+
+	//When
+	err := syncFunction.SyncAccessProviderToTarget(context.Background(), &accessProviders, feedback, &configMap)
+
+	//Then
+	require.NoError(t, err)
+	assert.Equal(t, []sync_to_target.AccessProviderSyncFeedback{
+		{AccessProvider: "AP1", ActualName: "AP1"},
+		{AccessProvider: "AP2", ActualName: "AP2"},
+		{AccessProvider: "AP3", ActualName: "AP3", Errors: []string{"Unsupported action mask"}},
+	}, feedback.feedback)
+}
+
+func TestAccessProviderGrantSyncFunction_SyncAccessProviderToTarget_FailedGrants(t *testing.T) {
+	//Given
+	configMap := config.ConfigMap{Parameters: map[string]string{"key": "value"}}
+
+	accessProviders := sync_to_target.AccessProviderImport{
+		AccessProviders: []*sync_to_target.AccessProvider{
+			{
+				Id:     "AP1",
+				Action: types.Grant,
+				Who:    sync_to_target.WhoItem{Users: []string{"alice", "bob"}},
+				What:   []sync_to_target.WhatItem{{DataObject: tableRef("db.t1"), Permissions: []string{"SELECT"}}},
+			},
+			{
+				Id:     "AP2",
+				Action: types.Purpose,
+				Who:    sync_to_target.WhoItem{InheritFrom: []string{"AP1", "unknown"}},
+				What:   []sync_to_target.WhatItem{{DataObject: tableRef("db.t2"), Permissions: []string{"SELECT"}}},
+			},
+		},
+	}
+
+	syncerMock := NewMockAccessProviderGrantSyncer(t)
+	syncerMock.EXPECT().GetGrants(mock.Anything, []data_source.DataObjectReference{table("db.t1"), table("db.t2")}, &configMap).Return(nil, nil).Once()
+
+	// The grants are sent in batches of 2
+	syncerMock.EXPECT().GrantPermissions(mock.Anything, []Grant{userGrant("alice", "db.t1", "SELECT"), userGrant("bob", "db.t1", "SELECT")}, &configMap).Return(map[Grant]error{userGrant("bob", "db.t1", "SELECT"): errors.New("unknown user")}, nil).Once()
+	syncerMock.EXPECT().GrantPermissions(mock.Anything, []Grant{userGrant("alice", "db.t2", "SELECT"), userGrant("bob", "db.t2", "SELECT")}, &configMap).Return(nil, errors.New("boom")).Once()
+
+	feedback := &feedbackCollector{}
+
+	syncFunction := accessProviderGrantSyncFunction{syncer: syncerMock, batchSize: 2}
+
+	//When
+	err := syncFunction.SyncAccessProviderToTarget(context.Background(), &accessProviders, feedback, &configMap)
+
+	//Then
+	require.NoError(t, err)
+	require.Len(t, feedback.feedback, 2)
+
+	assert.Equal(t, []string{`unable to grant SELECT on table "db.t1" for user "bob": unknown user`}, feedback.feedback[0].Errors)
+
+	assert.ElementsMatch(t, []string{
+		`unable to grant SELECT on table "db.t2" for user "alice": boom`,
+		`unable to grant SELECT on table "db.t2" for user "bob": boom`,
+	}, feedback.feedback[1].Errors)
+	assert.Equal(t, []string{`Unable to resolve the inherited access provider "unknown"`}, feedback.feedback[1].Warnings)
+}
+
+func TestAccessProviderGrantSyncFunction_SyncAccessProviderToTarget_GetGrantsError(t *testing.T) {
+	//Given
+	configMap := config.ConfigMap{Parameters: map[string]string{"key": "value"}}
+
+	accessProviders := sync_to_target.AccessProviderImport{
+		AccessProviders: []*sync_to_target.AccessProvider{
+			{
+				Id:     "AP1",
+				Action: types.Grant,
+				Who:    sync_to_target.WhoItem{Users: []string{"alice"}},
+				What:   []sync_to_target.WhatItem{{DataObject: tableRef("db.t1"), Permissions: []string{"SELECT"}}},
+			},
+		},
+	}
+
+	syncerMock := NewMockAccessProviderGrantSyncer(t)
+	syncerMock.EXPECT().GetGrants(mock.Anything, mock.Anything, &configMap).Return(nil, errors.New("boom")).Once()
+
+	feedback := &feedbackCollector{}
+
+	syncFunction := accessProviderGrantSyncFunction{syncer: syncerMock, batchSize: DefaultBatchSize}
+
+	//When
+	err := syncFunction.SyncAccessProviderToTarget(context.Background(), &accessProviders, feedback, &configMap)
+
+	//Then
+	assert.EqualError(t, err, "get grants: boom")
+	assert.Empty(t, feedback.feedback)
+}
@@ -0,0 +1,143 @@
+package grant_based
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/data_source"
+)
+
+type PrincipalType string
+
+const (
+	PrincipalTypeUser  PrincipalType = "user"
+	PrincipalTypeGroup PrincipalType = "group"
+)
+
+// Principal is a user or group to which permissions can be granted in the data source.
+type Principal struct {
+	Type PrincipalType
+	Name string
+}
+
+// Grant is a single permission on a data object for a principal.
+type Grant struct {
+	Principal  Principal
+	DataObject data_source.DataObjectReference
+	Permission string
+}
+
+func (g Grant) String() string {
+	return fmt.Sprintf("%s on %s %q for %s %q", g.Permission, g.DataObject.Type, g.DataObject.FullName, g.Principal.Type, g.Principal.Name)
+}
+
+// grantSet is a set of grants, keeping track of the access providers that caused each of the grants.
+type grantSet map[Grant][]string
+
+func (s grantSet) add(grant Grant, accessProviderId string) {
+	for _, id := range s[grant] {
+		if id == accessProviderId {
+			return
+		}
+	}
+
+	s[grant] = append(s[grant], accessProviderId)
+}
+
+// addAll adds the grants of every permission of the what items to every principal.
+func (s grantSet) addAll(principals []Principal, whatItems []sync_to_target.WhatItem, accessProviderId string) {
+	for _, what := range whatItems {
+		if what.DataObject == nil {
+			continue
+		}
+
+		for _, permission := range what.Permissions {
+			for _, principal := range principals {
+				s.add(Grant{Principal: principal, DataObject: *what.DataObject, Permission: permission}, accessProviderId)
+			}
+		}
+	}
+}
+
+// sortedGrants returns the grants in a stable order, so the batches sent to the connector are deterministic.
+func sortedGrants(grants []Grant) []Grant {
+	sort.Slice(grants, func(i, j int) bool {
+		return grantSortKey(grants[i]) < grantSortKey(grants[j])
+	})
+
+	return grants
+}
+
+func grantSortKey(g Grant) string {
+	return strings.Join([]string{g.DataObject.FullName, g.DataObject.Type, string(g.Principal.Type), g.Principal.Name, g.Permission}, "\x00")
+}
+
+// grantPlan contains the changes to apply to the data source.
+type grantPlan struct {
+	// toGrant contains the desired grants that don't exist yet
+	toGrant grantSet
+
+	// toRevoke contains the existing grants that were managed by an access provider, but are not desired anymore
+	toRevoke grantSet
+}
+
+// calculateGrantPlan computes the minimal set of changes to go from the current grants to the desired grants.
+// Only grants that were managed by an access provider before are revoked, so grants that were created outside Raito are left untouched.
+func calculateGrantPlan(desired grantSet, previouslyManaged grantSet, current []Grant) grantPlan {
+	currentSet := make(map[Grant]struct{}, len(current))
+
+	for _, grant := range current {
+		currentSet[grant] = struct{}{}
+	}
+
+	plan := grantPlan{
+		toGrant:  grantSet{},
+		toRevoke: grantSet{},
+	}
+
+	for grant, aps := range desired {
+		if _, found := currentSet[grant]; !found {
+			plan.toGrant[grant] = aps
+		}
+	}
+
+	for grant, aps := range previouslyManaged {
+		if _, found := desired[grant]; found {
+			continue
+		}
+
+		if _, found := currentSet[grant]; found {
+			plan.toRevoke[grant] = aps
+		}
+	}
+
+	return plan
+}
+
+// affectedDataObjects returns the data objects of which the current grants are needed to calculate the plan.
+func affectedDataObjects(sets ...grantSet) []data_source.DataObjectReference {
+	objectSet := make(map[data_source.DataObjectReference]struct{})
+
+	for _, set := range sets {
+		for grant := range set {
+			objectSet[grant.DataObject] = struct{}{}
+		}
+	}
+
+	objects := make([]data_source.DataObjectReference, 0, len(objectSet))
+	for object := range objectSet {
+		objects = append(objects, object)
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].FullName == objects[j].FullName {
+			return objects[i].Type < objects[j].Type
+		}
+
+		return objects[i].FullName < objects[j].FullName
+	})
+
+	return objects
+}
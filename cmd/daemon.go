@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/raito-io/cli/internal/blackout"
+	"github.com/raito-io/cli/internal/clitrigger"
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/graphql"
+	"github.com/raito-io/cli/internal/logging"
+	"github.com/raito-io/cli/internal/target"
+)
+
+func initDaemonCommand(rootCmd *cobra.Command) {
+	var cmd = &cobra.Command{
+		Short: "Interact with a running daemon.",
+		Long:  "Interact with a running daemon (a 'run' command with a frequency or cron expression) on this machine, using its control socket.",
+		Use:   "daemon",
+	}
+
+	var statusCmd = &cobra.Command{
+		Short: "Show the status of the running daemon.",
+		Long:  "Show the current iteration, the target that is currently running and the queued triggers of the running daemon.",
+		Run:   executeDaemonStatusCmd,
+		Args:  cobra.NoArgs,
+		Use:   "status",
+	}
+
+	cmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(cmd)
+}
+
+func executeDaemonStatusCmd(_ *cobra.Command, _ []string) {
+	logging.SetupLogging(true)
+
+	err := daemonStatusCmd()
+	if err != nil {
+		pterm.Error.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func daemonStatusCmd() error {
+	socketPath := viper.GetString(constants.ControlSocketFlag)
+	if socketPath == "" {
+		return fmt.Errorf("the %q flag is required", constants.ControlSocketFlag)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), controlSocketTimeout)
+	defer cancel()
+
+	status, err := clitrigger.NewControlSocketClient(socketPath).Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	runningTargets := strings.Join(status.RunningTargets, ", ")
+	if runningTargets == "" {
+		runningTargets = "none (waiting for the next run)"
+	}
+
+	pterm.Println("PID: " + pterm.Bold.Sprint(status.Pid))
+	pterm.Println("Running since: " + pterm.Bold.Sprint(status.StartedAt.Local().Format(time.RFC1123)))
+	pterm.Println("Current iteration: " + pterm.Bold.Sprint(status.Iteration))
+	pterm.Println("Running targets: " + pterm.Bold.Sprint(runningTargets))
+
+	pterm.Println(fmt.Sprintf("Queued sync triggers: %d (%s)", len(status.QueuedSyncTriggers), describeQueueStatistics(&status.SyncTriggerQueue)))
+
+	for _, syncTrigger := range status.QueuedSyncTriggers {
+		pterm.Println("  - " + describeSyncTrigger(&syncTrigger))
+	}
+
+	pterm.Println(fmt.Sprintf("Queued access provider updates: %d (%s)", len(status.QueuedApUpdates), describeQueueStatistics(&status.ApUpdateQueue)))
+
+	for _, apUpdate := range status.QueuedApUpdates {
+		pterm.Println("  - data sources " + strings.Join(apUpdate.DataSourceNames, ", "))
+	}
+
+	pterm.Println("Raito API: " + status.ApiStatistics.String())
+	pterm.Println(fmt.Sprintf("Deferred syncs: %d", len(status.DeferredSyncs)))
+
+	for _, deferral := range status.DeferredSyncs {
+		pterm.Println(fmt.Sprintf("  - target %q (%s) until %s, because of blackout window %q", deferral.Target, deferral.SyncType, deferral.Until.Local().Format(time.RFC1123), deferral.Window))
+	}
+
+	return nil
+}
+
+func describeQueueStatistics(statistics *clitrigger.QueueStatistics) string {
+	return fmt.Sprintf("%d in progress, %d replayed at startup, %d received, %d handled", statistics.InFlight, statistics.Replayed, statistics.Received, statistics.Handled)
+}
+
+func describeSyncTrigger(syncTrigger *clitrigger.SyncTrigger) string {
+	var subject []string
+
+	if syncTrigger.Target != nil {
+		subject = append(subject, fmt.Sprintf("target %q", *syncTrigger.Target))
+	}
+
+	if syncTrigger.DataSource != nil {
+		subject = append(subject, fmt.Sprintf("data source %q", *syncTrigger.DataSource))
+	}
+
+	if syncTrigger.IdentityStore != nil {
+		subject = append(subject, fmt.Sprintf("identity store %q", *syncTrigger.IdentityStore))
+	}
+
+	if len(subject) == 0 {
+		subject = append(subject, "all targets")
+	}
+
+	enabledSyncTypes := map[string]bool{
+		syncTypeDataSource:       syncTrigger.DataSourceSync,
+		syncTypeIdentityStore:    syncTrigger.IdentityStoreSync,
+		syncTypeAccess:           syncTrigger.DataAccessSync,
+		syncTypeUsage:            syncTrigger.DataUsageSync,
+		syncTypeResourceProvider: syncTrigger.ResourceProviderSync,
+	}
+
+	var syncTypes []string
+
+	for _, syncType := range allSyncTypes {
+		if enabledSyncTypes[syncType] {
+			syncTypes = append(syncTypes, syncType)
+		}
+	}
+
+	return fmt.Sprintf("%s (%s)", strings.Join(subject, ", "), strings.Join(syncTypes, ", "))
+}
+
+// daemonState keeps track of the state of a continuous run, to be exposed over the control socket.
+type daemonState struct {
+	m sync.Mutex
+
+	startedAt       time.Time
+	iteration       int
+	apUpdateTrigger *clitrigger.ApUpdateTriggerHandler
+	syncTrigger     *clitrigger.SyncTriggerHandler
+}
+
+func newDaemonState() *daemonState {
+	return &daemonState{
+		startedAt: time.Now(),
+	}
+}
+
+func (s *daemonState) setIteration(iteration int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.iteration = iteration
+}
+
+func (s *daemonState) setTriggerHandlers(apUpdateTrigger *clitrigger.ApUpdateTriggerHandler, syncTrigger *clitrigger.SyncTriggerHandler) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.apUpdateTrigger = apUpdateTrigger
+	s.syncTrigger = syncTrigger
+}
+
+func (s *daemonState) Status() *clitrigger.DaemonStatus {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	status := clitrigger.DaemonStatus{
+		Pid:            os.Getpid(),
+		StartedAt:      s.startedAt,
+		Iteration:      s.iteration,
+		RunningTargets: target.RunningTargets(),
+		DeferredSyncs:  blackout.Pending(),
+		ApiStatistics:  graphql.GetStatistics(),
+	}
+
+	if s.syncTrigger != nil {
+		status.QueuedSyncTriggers = s.syncTrigger.Queued()
+		status.SyncTriggerQueue = s.syncTrigger.Statistics()
+	}
+
+	if s.apUpdateTrigger != nil {
+		status.QueuedApUpdates = s.apUpdateTrigger.Queued()
+		status.ApUpdateQueue = s.apUpdateTrigger.Statistics()
+	}
+
+	return &status
+}
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/dev"
+	"github.com/raito-io/cli/internal/health_check"
+	"github.com/raito-io/cli/internal/logging"
+	"github.com/raito-io/cli/internal/plugin"
+	"github.com/raito-io/cli/internal/recording"
+	"github.com/raito-io/cli/internal/target"
+	"github.com/raito-io/cli/internal/testserver"
+)
+
+func initDevCommand(rootCmd *cobra.Command) {
+	var cmd = &cobra.Command{
+		Short: "Tools to develop and debug connectors locally.",
+		Long:  "Tools to develop and debug connectors locally, without Raito Cloud.",
+		Use:   "dev",
+	}
+
+	var runCmd = &cobra.Command{
+		Short: "Run a single sync of a connector locally.",
+		Long: "Starts the connector and calls the requested syncer with the parameters of a target in the configuration file and/or the parameters given with the 'param' flag. " +
+			"The files produced by the connector are written to the output folder. There is no communication with Raito Cloud at all.",
+		Run:  executeDevRunCmd,
+		Args: cobra.MaximumNArgs(1),
+		Use:  fmt.Sprintf("run [<connector>] --%s %s", constants.DevSyncFlag, strings.Join(dev.SyncTypes, "|")),
+	}
+
+	runCmd.Flags().String(constants.DevSyncFlag, "", fmt.Sprintf("The sync to run. Possible values are %s.", strings.Join(dev.SyncTypes, ", ")))
+	runCmd.Flags().String(constants.DevTargetFlag, "", "The name of a target in the configuration file to take the connector and parameters from.")
+	runCmd.Flags().StringToString(constants.DevParamFlag, nil, "A connector parameter as key=value. Can be repeated and overrides the parameters of the target.")
+	runCmd.Flags().String(constants.DevOutputFlag, "raito-dev", "The folder to write the files produced by the connector to.")
+	runCmd.Flags().String(constants.DevAccessFileFlag, "", fmt.Sprintf("The file with the access providers to push to the data source. Required for the %s sync.", dev.SyncAccessTo))
+	runCmd.Flags().String(constants.ConnectorVersionFlag, "", "The version of the connector to use. If not set, the version of the target is used or 'latest' otherwise.")
+	runCmd.Flags().String(constants.DataSourceIdFlag, "", fmt.Sprintf("The data source ID to pass to the connector. If not set, the ID of the target is used or %q otherwise.", dev.DefaultDataSourceId))
+	runCmd.Flags().String(constants.IdentityStoreIdFlag, "", fmt.Sprintf("The identity store ID to pass to the connector. If not set, the ID of the target is used or %q otherwise.", dev.DefaultIdentityStoreId))
+
+	var serverCmd = &cobra.Command{
+		Short: "Start a local stand-in for Raito Cloud.",
+		Long: "Starts an in-memory stand-in for the Raito Cloud API, to run the CLI end-to-end without network access. " +
+			"It accepts jobs, uploaded files and import requests, completes all subtasks successfully and can push CLI triggers over the websocket. " +
+			fmt.Sprintf("Use the endpoints under %s to inspect the received requests (interactions), push triggers (trigger) and configure subtask results (subtask-results/<operation>).", testserver.AdminPath),
+		Run:  executeDevServerCmd,
+		Args: cobra.NoArgs,
+		Use:  "server",
+	}
+
+	serverCmd.Flags().String(constants.DevAddressFlag, "localhost:8080", "The address to listen on.")
+	serverCmd.Flags().String(constants.DevInteractionsFileFlag, "", "The file to write all received requests to as JSON when the server stops.")
+
+	var replayCmd = &cobra.Command{
+		Short: "Replay a recorded run without connecting to the data sources.",
+		Long: fmt.Sprintf("Runs the sync of the targets in the configuration file, but instead of starting the connectors, the calls recorded with the %q flag of the run command are replayed. ", constants.RecordFlag) +
+			"The files the connectors produced are processed by the CLI as if the connectors just produced them (post-processors, enrichers and the imports into Raito Cloud). " +
+			"Use it together with 'raito dev server' to reproduce a run fully offline. Accepts the same flags as the run command.",
+		Run:  executeDevReplayCmd,
+		Args: cobra.MinimumNArgs(1),
+		Use:  "replay <recording-dir>",
+	}
+
+	// The replay executes a run, so it accepts the flags of the run command. The flags are shared, so their viper bindings keep working.
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "run" {
+			replayCmd.Flags().AddFlagSet(c.PersistentFlags())
+		}
+	}
+
+	replayCmd.FParseErrWhitelist.UnknownFlags = true
+
+	cmd.AddCommand(runCmd, serverCmd, replayCmd)
+	rootCmd.AddCommand(cmd)
+}
+
+func executeDevRunCmd(cmd *cobra.Command, args []string) {
+	logging.SetupLogging(true)
+
+	err := devRunCmd(cmd, args)
+	if err != nil {
+		pterm.Error.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func devRunCmd(cmd *cobra.Command, args []string) error {
+	runConfig, connector, version, err := buildDevRunConfig(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	client, err := plugin.NewPluginClient(connector, version, hclog.L())
+	if err != nil {
+		return fmt.Errorf("initializing connector plugin %q: %w", connector, err)
+	}
+
+	defer client.Close()
+
+	files, err := dev.Run(context.Background(), client, runConfig, hclog.L())
+	if err != nil {
+		return err
+	}
+
+	pterm.Println()
+	pterm.Success.Println(fmt.Sprintf("The %s sync of connector %q finished successfully", runConfig.SyncType, connector))
+	pterm.Println()
+	pterm.Println("Produced files:")
+
+	for _, f := range files {
+		pterm.Println("  - " + f)
+	}
+
+	if runConfig.SyncType == dev.SyncAccessTo {
+		for _, f := range files {
+			feedback, err := os.ReadFile(f)
+			if err != nil {
+				return fmt.Errorf("reading feedback file %q: %w", f, err)
+			}
+
+			pterm.Println()
+			pterm.Println("Feedback:")
+			pterm.Println(string(feedback))
+		}
+	}
+
+	return nil
+}
+
+func executeDevServerCmd(cmd *cobra.Command, _ []string) {
+	logging.SetupLogging(true)
+
+	err := devServerCmd(cmd)
+	if err != nil {
+		pterm.Error.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func devServerCmd(cmd *cobra.Command) error {
+	address, _ := cmd.Flags().GetString(constants.DevAddressFlag)
+	interactionsFile, _ := cmd.Flags().GetString(constants.DevInteractionsFileFlag)
+
+	server := testserver.New(hclog.L())
+
+	err := server.Start(address)
+	if err != nil {
+		return err
+	}
+
+	pterm.Success.Println(fmt.Sprintf("Test server listening on %s", server.URL()))
+	pterm.Println()
+	pterm.Println("Point the CLI to it with:")
+	pterm.Println(fmt.Sprintf("  raito run --%s %s --%s", constants.URLOverrideFlag, server.URL(), constants.SkipAuthentication))
+	pterm.Println()
+	pterm.Println("Press 'ctrl+c' to stop the server.")
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	<-sigs
+
+	err = server.Close()
+	if err != nil {
+		return fmt.Errorf("stopping test server: %w", err)
+	}
+
+	if interactionsFile == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(server.Interactions(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("serializing interactions: %w", err)
+	}
+
+	err = os.WriteFile(interactionsFile, content, 0600)
+	if err != nil {
+		return fmt.Errorf("writing interactions to %q: %w", interactionsFile, err)
+	}
+
+	pterm.Println(fmt.Sprintf("Received requests written to %s", interactionsFile))
+
+	return nil
+}
+
+func executeDevReplayCmd(_ *cobra.Command, args []string) {
+	logging.SetupLogging(false)
+
+	err := devReplayCmd(args)
+	if err != nil {
+		pterm.Error.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func devReplayCmd(args []string) error {
+	replayer, err := recording.NewReplayer(args[0])
+	if err != nil {
+		return err
+	}
+
+	baseLogger := hclog.L()
+
+	baseConfig, err := target.BuildBaseConfigFromFlags(baseLogger, health_check.NewDummyHealthChecker(baseLogger), args[1:])
+	if err != nil {
+		return err
+	}
+
+	plugin.SetTargetClientProvider(replayer.Provider)
+	defer plugin.SetTargetClientProvider(nil)
+
+	baseLogger.Info(fmt.Sprintf("Replaying the connector calls recorded in %q", args[0]))
+
+	err = executeSingleRun(context.Background(), baseConfig)
+	if err != nil {
+		return fmt.Errorf("replaying %q: %w", args[0], err)
+	}
+
+	return nil
+}
+
+// buildDevRunConfig combines the target from the configuration file (if any) with the flags of the command.
+// The flags are read from the command itself, as their names are shared with other commands.
+func buildDevRunConfig(cmd *cobra.Command, args []string) (*dev.RunConfig, string, string, error) {
+	flags := cmd.Flags()
+
+	syncType, _ := flags.GetString(constants.DevSyncFlag)
+	if syncType == "" {
+		return nil, "", "", fmt.Errorf("the %q flag is required. Possible values are %s", constants.DevSyncFlag, strings.Join(dev.SyncTypes, ", "))
+	}
+
+	targetName, _ := flags.GetString(constants.DevTargetFlag)
+	params, _ := flags.GetStringToString(constants.DevParamFlag)
+	outputDir, _ := flags.GetString(constants.DevOutputFlag)
+	accessFile, _ := flags.GetString(constants.DevAccessFileFlag)
+
+	runConfig := &dev.RunConfig{
+		SyncType:   syncType,
+		Parameters: map[string]string{},
+		OutputDir:  outputDir,
+		AccessFile: accessFile,
+	}
+
+	var connector, version string
+
+	if len(args) > 0 {
+		connector = args[0]
+	}
+
+	if targetName != "" {
+		baseConfig, err := target.BuildBaseConfigFromFlags(hclog.L(), health_check.NewDummyHealthChecker(hclog.L()), nil)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("parsing the configuration file: %w", err)
+		}
+
+		tConfig, err := target.GetTargetConfig(targetName, baseConfig)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("locating the requested target in the configuration file: %w", err)
+		}
+
+		if tConfig == nil {
+			return nil, "", "", fmt.Errorf("no target %q found in the configuration file", targetName)
+		}
+
+		if connector == "" {
+			connector = tConfig.ConnectorName
+			version = tConfig.ConnectorVersion
+		}
+
+		runConfig.DataSourceId = tConfig.DataSourceId
+		runConfig.IdentityStoreId = tConfig.IdentityStoreId
+
+		for k, v := range tConfig.Parameters {
+			runConfig.Parameters[k] = v
+		}
+	}
+
+	if connector == "" {
+		return nil, "", "", errors.New("expected a connector as argument or a target with the 'target' flag")
+	}
+
+	for k, v := range params {
+		runConfig.Parameters[k] = v
+	}
+
+	if flags.Changed(constants.ConnectorVersionFlag) {
+		version, _ = flags.GetString(constants.ConnectorVersionFlag)
+	}
+
+	if flags.Changed(constants.DataSourceIdFlag) {
+		runConfig.DataSourceId, _ = flags.GetString(constants.DataSourceIdFlag)
+	}
+
+	if flags.Changed(constants.IdentityStoreIdFlag) {
+		runConfig.IdentityStoreId, _ = flags.GetString(constants.IdentityStoreIdFlag)
+	}
+
+	return runConfig, connector, version, nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/dev"
+)
+
+func devRunTestCommand(t *testing.T, args ...string) func() (*dev.RunConfig, string, string, error) {
+	t.Helper()
+
+	root := newRootCmd("v1.2.3", (&exitMemory{}).Exit)
+
+	runCmd, remaining, err := root.cmd.Find([]string{"dev", "run"})
+	require.NoError(t, err)
+	require.Empty(t, remaining)
+
+	require.NoError(t, runCmd.ParseFlags(args))
+
+	return func() (*dev.RunConfig, string, string, error) {
+		return buildDevRunConfig(runCmd, runCmd.Flags().Args())
+	}
+}
+
+func TestBuildDevRunConfig(t *testing.T) {
+	build := devRunTestCommand(t, "snowflake", "--sync", "data-source", "--param", "sf-account=acc1", "--param", "sf-user=raito", "--connector-version", "1.2.3", "--data-source-id", "ds1", "--output", "out")
+
+	runConfig, connector, version, err := build()
+	require.NoError(t, err)
+
+	assert.Equal(t, "snowflake", connector)
+	assert.Equal(t, "1.2.3", version)
+	assert.Equal(t, dev.SyncDataSource, runConfig.SyncType)
+	assert.Equal(t, map[string]string{"sf-account": "acc1", "sf-user": "raito"}, runConfig.Parameters)
+	assert.Equal(t, "ds1", runConfig.DataSourceId)
+	assert.Equal(t, "", runConfig.IdentityStoreId)
+	assert.Equal(t, "out", runConfig.OutputDir)
+}
+
+func TestBuildDevRunConfig_Errors(t *testing.T) {
+	build := devRunTestCommand(t, "snowflake")
+
+	_, _, _, err := build()
+	assert.ErrorContains(t, err, `the "sync" flag is required`)
+
+	build = devRunTestCommand(t, "--sync", "usage")
+
+	_, _, _, err = build()
+	assert.ErrorContains(t, err, "expected a connector as argument or a target")
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/robfig/cron/v3"
+
+	"github.com/raito-io/cli/internal/clitrigger"
+	"github.com/raito-io/cli/internal/lane"
+	"github.com/raito-io/cli/internal/schedule"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+// sequentialLanesKey is the key of the lock that is held while executing work, if the lanes are not allowed to run concurrently.
+const sequentialLanesKey = "daemon"
+
+// daemonLanes executes the work of the daemon in lanes: scheduled runs, manual syncs and access provider updates.
+// Every lane handles its own work in order, but the lanes run concurrently, so e.g. access provider updates don't need to wait for a long scheduled run.
+type daemonLanes struct {
+	baseConfig *types.BaseConfig
+	state      *daemonState
+
+	// sequential makes the lanes wait for each other, handing over to the lane with the highest priority first. Nil if the lanes run concurrently.
+	sequential *lane.PriorityLock
+
+	m         sync.Mutex
+	iteration int
+
+	wg sync.WaitGroup
+}
+
+func newDaemonLanes(baseConfig *types.BaseConfig, state *daemonState, concurrent bool) *daemonLanes {
+	lanes := &daemonLanes{
+		baseConfig: baseConfig,
+		state:      state,
+	}
+
+	if !concurrent {
+		lanes.sequential = lane.NewPriorityLock()
+	}
+
+	return lanes
+}
+
+// start runs the loop of the given lane in the background, until the context is done.
+func (l *daemonLanes) start(ctx context.Context, workLane lane.Lane, loop func(ctx context.Context)) {
+	l.wg.Add(1)
+
+	go func() {
+		defer l.wg.Done()
+
+		loop(lane.WithLane(ctx, workLane))
+	}()
+}
+
+// Wait blocks until the loops of all lanes ended.
+func (l *daemonLanes) Wait() {
+	l.wg.Wait()
+}
+
+// execute runs a piece of work of the lane of the context as the next iteration of the daemon.
+func (l *daemonLanes) execute(ctx context.Context, work func(ctx context.Context, config *types.BaseConfig) error) error {
+	if l.sequential != nil {
+		unlock, err := l.sequential.Lock(ctx, sequentialLanesKey)
+		if err != nil {
+			return err
+		}
+
+		defer unlock()
+	}
+
+	l.m.Lock()
+	l.iteration++
+	iteration := l.iteration
+	l.m.Unlock()
+
+	l.state.setIteration(iteration)
+
+	// Every piece of work gets its own copy of the configuration, as the lanes run concurrently
+	config := *l.baseConfig
+	config.BaseLogger = l.baseConfig.BaseLogger.With("lane", lane.FromContext(ctx).String(), "iteration", iteration)
+
+	err := work(ctx, &config)
+
+	hclog.L().Info("Press 'ctrl+c' to stop the program.")
+
+	return err
+}
+
+// runScheduledLane executes the runs of the schedule.
+func (l *daemonLanes) runScheduledLane(ctx context.Context, executeSyncAtStartup bool, scheduler cron.Schedule, jitter *schedule.Jitter, cliTrigger clitrigger.CliTrigger) {
+	if executeSyncAtStartup {
+		if runErr := l.execute(ctx, executeSingleRun); runErr != nil {
+			l.baseConfig.BaseLogger.Error(fmt.Sprintf("Run failed: %s", runErr.Error()))
+		}
+	}
+
+	timer := cronTimer(l.baseConfig.BaseLogger, nil, scheduler, jitter)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			cliTrigger.Reset()
+
+			if runErr := l.execute(ctx, executeSingleRun); runErr != nil {
+				l.baseConfig.BaseLogger.Error(fmt.Sprintf("Run failed: %s", runErr.Error()))
+			}
+
+			cronTimer(l.baseConfig.BaseLogger, timer, scheduler, jitter)
+		case <-ctx.Done():
+			l.baseConfig.BaseLogger.Debug("Context done: closing scheduled lane.")
+			return
+		}
+	}
+}
+
+// runManualLane executes the sync triggers, including the syncs that were deferred because of a blackout window.
+func (l *daemonLanes) runManualLane(ctx context.Context, syncTrigger *clitrigger.SyncTriggerHandler) {
+	deferredTimer := deferredSyncTimer(nil)
+	defer deferredTimer.Stop()
+
+	for {
+		select {
+		case <-syncTrigger.TriggerChannel():
+			syncRequest := syncTrigger.Pop()
+			if syncRequest == nil {
+				continue
+			}
+
+			err := l.execute(ctx, func(ctx context.Context, config *types.BaseConfig) error {
+				return handleSyncTrigger(ctx, config, syncRequest)
+			})
+			if err != nil {
+				l.baseConfig.BaseLogger.Warn(fmt.Sprintf("ClI Sync Trigger failed: %s", err.Error()))
+			}
+
+			// When the daemon is stopped during the run, the sync trigger is handled again after a restart
+			if ctx.Err() == nil {
+				syncTrigger.Done(syncRequest)
+			}
+		case <-deferredTimer.C:
+			queueDeferredSyncs(ctx, l.baseConfig.BaseLogger, syncTrigger)
+		case <-ctx.Done():
+			l.baseConfig.BaseLogger.Debug("Context done: closing manual lane.")
+			return
+		}
+
+		deferredSyncTimer(deferredTimer)
+	}
+}
+
+// runAccessUpdateLane executes the access provider updates.
+func (l *daemonLanes) runAccessUpdateLane(ctx context.Context, apUpdateTrigger *clitrigger.ApUpdateTriggerHandler) {
+	for {
+		select {
+		case <-apUpdateTrigger.TriggerChannel():
+			apUpdate := apUpdateTrigger.Pop()
+			if apUpdate == nil {
+				continue
+			}
+
+			err := l.execute(ctx, func(ctx context.Context, config *types.BaseConfig) error {
+				return handleApUpdateTrigger(ctx, config, apUpdate)
+			})
+			if err != nil {
+				l.baseConfig.BaseLogger.Warn(fmt.Sprintf("ClI ApUpdate Trigger failed: %s", err.Error()))
+			}
+
+			// When the daemon is stopped during the run, the access provider update is handled again after a restart
+			if ctx.Err() == nil {
+				apUpdateTrigger.Done(apUpdate)
+			}
+		case <-ctx.Done():
+			l.baseConfig.BaseLogger.Debug("Context done: closing access update lane.")
+			return
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"math/bits"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	sync2 "sync"
 	"syscall"
 	"time"
@@ -16,10 +18,18 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/raito-io/cli/internal/blackout"
 	"github.com/raito-io/cli/internal/clitrigger"
 	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/graphql"
 	"github.com/raito-io/cli/internal/health_check"
+	"github.com/raito-io/cli/internal/lane"
+	"github.com/raito-io/cli/internal/leader"
 	"github.com/raito-io/cli/internal/logging"
+	"github.com/raito-io/cli/internal/plugin"
+	"github.com/raito-io/cli/internal/recording"
+	"github.com/raito-io/cli/internal/runstate"
+	"github.com/raito-io/cli/internal/schedule"
 	"github.com/raito-io/cli/internal/target"
 	"github.com/raito-io/cli/internal/target/types"
 	"github.com/raito-io/cli/internal/target_sync"
@@ -41,6 +51,16 @@ func initRunCommand(rootCmd *cobra.Command) {
 	cmd.PersistentFlags().StringP(constants.DomainFlag, "d", "", "The subdomain to your Raito instance (https://<subdomain>.raito.io). This parameter can be overridden in the target configs if needed.")
 	cmd.PersistentFlags().StringP(constants.ApiUserFlag, "u", "", "The username of the API user to authenticate against Raito. This parameter can be overridden in the target configs if needed.")
 	cmd.PersistentFlags().StringP(constants.ApiSecretFlag, "s", "", "The API key secret to authenticate against Raito. This parameter can be overridden in the target configs if needed.")
+	cmd.PersistentFlags().String(constants.AuthMethodFlag, "cognito", "The method used to authenticate against Raito. Supported values are 'cognito' (username and secret of a Raito user), 'api-token' (a pre-issued API token passed as api-secret) and 'oidc' (OAuth2 client credentials flow with the client ID passed as api-user and the client secret as api-secret).")
+	cmd.PersistentFlags().String(constants.OidcTokenUrlFlag, "", "The URL of the OIDC token endpoint. Only used when the 'oidc' authentication method is used.")
+	cmd.PersistentFlags().String(constants.OidcScopesFlag, "", "A comma-separated list of scopes to request from the OIDC token endpoint. Only used when the 'oidc' authentication method is used.")
+	cmd.PersistentFlags().Bool(constants.TokenCacheFlag, false, "If set, the authentication tokens are cached (encrypted) on disk under ~/.raito/tokens so they can be reused across CLI invocations.")
+	cmd.PersistentFlags().Int(constants.GraphqlMaxRetriesFlag, 4, "The maximum number of times a failed request to Raito is retried. Only requests that are safe to retry (queries and updates linked to a job) are retried.")
+	cmd.PersistentFlags().Duration(constants.GraphqlRetryBackoffFlag, time.Second, "The initial time to wait before retrying a failed request to Raito. The wait time is doubled (with some random jitter) on every retry.")
+	cmd.PersistentFlags().Duration(constants.GraphqlMaxRetryBackoffFlag, 30*time.Second, "The maximum time to wait before retrying a failed request to Raito.")
+	cmd.PersistentFlags().Duration(constants.GraphqlRequestTimeoutFlag, 2*time.Minute, "The timeout for a single request to Raito.")
+	cmd.PersistentFlags().Int(constants.GraphqlCircuitBreakerThresholdFlag, 10, "The number of consecutive failed requests to Raito after which no more requests are sent for a while.")
+	cmd.PersistentFlags().Duration(constants.GraphqlCircuitBreakerCooldownFlag, time.Minute, fmt.Sprintf("The time to wait before sending requests to Raito again after %q consecutive failures.", constants.GraphqlCircuitBreakerThresholdFlag))
 	cmd.PersistentFlags().String(constants.URLOverrideFlag, "", "")
 	cmd.PersistentFlags().Bool(constants.SkipAuthentication, false, "")
 	cmd.PersistentFlags().Bool(constants.SkipFileUpload, false, "")
@@ -94,7 +114,29 @@ func initRunCommand(rootCmd *cobra.Command) {
 	cmd.PersistentFlags().String(constants.FullyLockByTagFlag, "", fmt.Sprintf("Same as %q, but will fully lock the access providers from being edited in Raito Cloud.", constants.LockWhoByTagFlag))
 	cmd.PersistentFlags().Bool(constants.FullyLockWhenIncompleteFlag, false, fmt.Sprintf("Same as %q, but will fully lock the access providers from being edited in Raito Cloud.", constants.LockWhoWhenIncompleteFlag))
 
-	cmd.PersistentFlags().Bool(constants.DisableWebsocketFlag, false, "If set, raito will not setup a websocket to trigger new syncs. This flag has only effect if frequency is set.")
+	cmd.PersistentFlags().Bool(constants.DisableWebsocketFlag, false, "If set, raito will not setup a websocket to trigger new syncs or to receive status updates of the processing in Raito Cloud (which is then polled instead). This flag has only effect if frequency is set.")
+	cmd.PersistentFlags().String(constants.HttpTriggerAddressFlag, "", "If set, raito listens on this address (e.g. '127.0.0.1:8484') for sync and access provider update triggers. Triggers can be sent as a JSON POST to '/trigger', in the same format as the triggers received from Raito Cloud. This can be used together with the websocket. This flag has only effect if frequency is set.")
+	cmd.PersistentFlags().String(constants.HttpTriggerTokenFlag, "", fmt.Sprintf("The token that needs to be passed as bearer token in the Authorization header of every request to the HTTP trigger endpoint (see %q). Required when the HTTP trigger endpoint is enabled.", constants.HttpTriggerAddressFlag))
+	cmd.PersistentFlags().String(constants.TriggerQueueDirFlag, "", "The directory in which the queued sync and access provider update triggers are persisted, so they are handled after a restart of the CLI. Every daemon needs its own directory. If not set, the queues are only kept in memory. This flag has only effect if frequency is set.")
+	cmd.PersistentFlags().Duration(constants.ApUpdateDebounceFlag, 0, "The time to wait for more access provider updates of the same data source before syncing it, e.g. '30s'. All data sources that are ready are synced together. By default, the updates are handled immediately. This flag has only effect if frequency is set.")
+	cmd.PersistentFlags().Duration(constants.ApUpdateMaxDelayFlag, 5*time.Minute, fmt.Sprintf("The maximum time an access provider update can be delayed because of %q. This flag has only effect if frequency is set.", constants.ApUpdateDebounceFlag))
+	cmd.PersistentFlags().String(constants.LeaderElectionFlag, "", fmt.Sprintf("Enables leader election between multiple instances of the CLI, so only one of them (the leader) executes the scheduled runs and handles the triggers. Possible values are %q (a lock file on a shared volume) and %q (a lock kept by Raito Cloud). This flag has only effect if frequency is set.", leader.FileBackend, leader.RaitoBackend))
+	cmd.PersistentFlags().String(constants.LeaderElectionLockFlag, "", fmt.Sprintf("The lock used for the leader election: the path of the lock file for %q, or the name of the lock for %q. All instances should use the same lock.", leader.FileBackend, leader.RaitoBackend))
+	cmd.PersistentFlags().Duration(constants.LeaderElectionTtlFlag, 15*time.Second, "The time after which another instance takes over when the leader stops responding. The leader renews its lease every third of this time.")
+	cmd.PersistentFlags().String(constants.LeaderElectionIdentityFlag, "", "The unique identity of this instance in the leader election. Defaults to the hostname combined with the process ID.")
+	cmd.PersistentFlags().Int(constants.RetriesFlag, 0, "The number of times a failed target is retried within the same run. Only failures caused by (probably) transient problems, like network issues or an unavailable data source, are retried. Only the sync types that didn't finish yet are executed again. Can be overwritten per target.")
+	cmd.PersistentFlags().Duration(constants.RetryBackoffFlag, time.Minute, fmt.Sprintf("The time to wait before the first retry of a failed target. The time doubles with every next retry. Can be overwritten per target. This flag has only effect if %q is set.", constants.RetriesFlag))
+	cmd.PersistentFlags().StringSlice(constants.RetryOnFlag, nil, fmt.Sprintf("The sync types of which a failure is retried (data-source, identity-store, data-access, data-usage, resource-provider, tag). By default, failures of all sync types are retried. Can be overwritten per target. This flag has only effect if %q is set.", constants.RetriesFlag))
+	cmd.PersistentFlags().Bool(constants.ResumeFlag, false, "If set, the steps (data source, identity store, data access, ...) that already finished in the last run of a target are skipped if that run failed and their configuration didn't change. When running continuously, this only applies to the first run.")
+	cmd.PersistentFlags().Bool(constants.AutoResumeFlag, false, fmt.Sprintf("Same as %q, but applies to all runs (e.g. the next scheduled run after a failed one).", constants.ResumeFlag))
+	cmd.PersistentFlags().String(constants.RunStateDirFlag, runstate.DefaultDir(), fmt.Sprintf("The directory in which the progress of the runs and the position of the last data source syncs are stored, which is used by %q, %q and %q. Set to an empty string to disable storing the progress.", constants.ResumeFlag, constants.AutoResumeFlag, constants.IncrementalDataSourceSyncFlag))
+	cmd.PersistentFlags().Duration(constants.ScheduleJitterFlag, 0, "Delays every scheduled run by an offset within this window (e.g. '15m'), so many instances sharing the same schedule don't all start at the same moment. This flag has only effect if frequency or cron is set.")
+	cmd.PersistentFlags().String(constants.ScheduleJitterModeFlag, schedule.JitterRandom, fmt.Sprintf("How the offset of %q is chosen: %q picks a new random offset for every run, %q always uses the same offset derived from the hostname, %q from the names of the configured targets and %q from %q.", constants.ScheduleJitterFlag, schedule.JitterRandom, schedule.JitterHostname, schedule.JitterTarget, schedule.JitterKey, constants.ScheduleJitterKeyFlag))
+	cmd.PersistentFlags().String(constants.ScheduleJitterKeyFlag, "", fmt.Sprintf("The key (e.g. the name of the business unit) from which the offset of %q is derived when %q is %q. Give every instance its own key to spread them across the window.", constants.ScheduleJitterFlag, constants.ScheduleJitterModeFlag, schedule.JitterKey))
+	cmd.PersistentFlags().Duration(constants.ScheduleStaggerFlag, 0, "Spreads the start of the targets of a run evenly across this window (e.g. '30m'), instead of starting the next target as soon as the previous one finished.")
+	cmd.PersistentFlags().Bool(constants.IncrementalDataSourceSyncFlag, false, fmt.Sprintf("If set, the data sources of connectors that support it are synced incrementally: only the data objects that changed (or were deleted) since the last successful sync are fetched and imported. The position of the last sync is kept in %q, so this flag has no effect if that is empty. Can be overwritten per target.", constants.RunStateDirFlag))
+	cmd.PersistentFlags().Duration(constants.FullDataSourceSyncIntervalFlag, 24*time.Hour, fmt.Sprintf("The maximum time between two full data source syncs when %q is set. Set to 0 to only do a full sync when the configuration of the target changes. Can be overwritten per target.", constants.IncrementalDataSourceSyncFlag))
+	cmd.PersistentFlags().Bool(constants.DisableConcurrentLanesFlag, false, "If set, the scheduled runs, manual syncs and access provider updates are not executed concurrently. Waiting work is then executed in order of priority: access provider updates first, then manual syncs and then scheduled runs. This flag has only effect if frequency or cron is set.")
 	cmd.PersistentFlags().Bool(constants.DisableLogForwarding, false, "If set, sync logs will not be forwarded to Raito Cloud.")
 	cmd.PersistentFlags().Bool(constants.DisableLogForwardingDataSourceSync, false, "If set, data source sync logs will not be forwarded to Raito Cloud.")
 	cmd.PersistentFlags().Bool(constants.DisableLogForwardingDataAccessSync, false, "If set, data access sync logs will not be forwarded to Raito Cloud.")
@@ -112,6 +154,11 @@ func initRunCommand(rootCmd *cobra.Command) {
 	cmd.PersistentFlags().String(constants.FileBackupLocationFlag, "", "If set, this filepath is used to store backups of the files that are used during synchronization jobs. A sub-folder is created per target, using the target name + the type of run (full, manual or webhook) as name for the folder. Underneath that, another sub-folder is created per run, using a timestamp as the folder name. The backed up files are then stored in that folder. This parameter can be overridden in the target configs if needed.")
 	cmd.PersistentFlags().Int(constants.MaximumBackupsPerTargetFlag, 0, fmt.Sprintf("When %q is defined, this parameter can be used to control how many backups should be kept per target+type. When this number is exceeded, older backups will be removed automatically. By default, this is 0, which means there is no maximum. This parameter can be overridden in the target configs if needed.", constants.FileBackupLocationFlag))
 	cmd.PersistentFlags().String(constants.MaximumFileSizesFlag, "512mb", "The maximum file size that can be uploaded to Raito Cloud. This parameter can be overridden in the target configs if needed. (only used for data usage files at this moment)")
+	cmd.PersistentFlags().String(constants.MultipartUploadThresholdFlag, "100mb", "Files larger than this size are uploaded to Raito Cloud in multiple parts. Failed parts are retried individually, so a network issue late in the transfer does not restart the complete upload.")
+	cmd.PersistentFlags().String(constants.MultipartUploadPartSizeFlag, "16mb", fmt.Sprintf("The size of the parts used when uploading files in multiple parts (see %q). Parts are at least 5mb.", constants.MultipartUploadThresholdFlag))
+	cmd.PersistentFlags().Int(constants.MultipartUploadConcurrencyFlag, 4, "The number of parts that are uploaded in parallel when uploading files in multiple parts.")
+	cmd.PersistentFlags().String(constants.RecordFlag, "", "If set, all requests to and responses from the connectors, together with the files they exchange with the CLI, are recorded in this directory (with secrets removed). The recording can be replayed with 'raito dev replay' to reproduce a run without access to the data sources.")
+	cmd.PersistentFlags().StringSlice(constants.RecordAllowedParametersFlag, nil, fmt.Sprintf("The connector parameters of which the value is kept in the recording. The values of all other connector parameters are censored. Parameters with a sensitive name (like a password or token) are always censored. This flag has only effect if %q is set.", constants.RecordFlag))
 
 	BindFlag(constants.IdentityStoreIdFlag, cmd)
 	BindFlag(constants.DataSourceIdFlag, cmd)
@@ -122,6 +169,16 @@ func initRunCommand(rootCmd *cobra.Command) {
 	BindFlag(constants.DomainFlag, cmd)
 	BindFlag(constants.ApiUserFlag, cmd)
 	BindFlag(constants.ApiSecretFlag, cmd)
+	BindFlag(constants.AuthMethodFlag, cmd)
+	BindFlag(constants.OidcTokenUrlFlag, cmd)
+	BindFlag(constants.OidcScopesFlag, cmd)
+	BindFlag(constants.TokenCacheFlag, cmd)
+	BindFlag(constants.GraphqlMaxRetriesFlag, cmd)
+	BindFlag(constants.GraphqlRetryBackoffFlag, cmd)
+	BindFlag(constants.GraphqlMaxRetryBackoffFlag, cmd)
+	BindFlag(constants.GraphqlRequestTimeoutFlag, cmd)
+	BindFlag(constants.GraphqlCircuitBreakerThresholdFlag, cmd)
+	BindFlag(constants.GraphqlCircuitBreakerCooldownFlag, cmd)
 	BindFlag(constants.URLOverrideFlag, cmd)
 	BindFlag(constants.SkipAuthentication, cmd)
 	BindFlag(constants.SkipFileUpload, cmd)
@@ -163,6 +220,28 @@ func initRunCommand(rootCmd *cobra.Command) {
 	BindFlag(constants.FullyLockByTagFlag, cmd)
 	BindFlag(constants.FullyLockWhenIncompleteFlag, cmd)
 	BindFlag(constants.DisableWebsocketFlag, cmd)
+	BindFlag(constants.HttpTriggerAddressFlag, cmd)
+	BindFlag(constants.HttpTriggerTokenFlag, cmd)
+	BindFlag(constants.TriggerQueueDirFlag, cmd)
+	BindFlag(constants.ApUpdateDebounceFlag, cmd)
+	BindFlag(constants.ApUpdateMaxDelayFlag, cmd)
+	BindFlag(constants.LeaderElectionFlag, cmd)
+	BindFlag(constants.LeaderElectionLockFlag, cmd)
+	BindFlag(constants.LeaderElectionTtlFlag, cmd)
+	BindFlag(constants.LeaderElectionIdentityFlag, cmd)
+	BindFlag(constants.RetriesFlag, cmd)
+	BindFlag(constants.RetryBackoffFlag, cmd)
+	BindFlag(constants.RetryOnFlag, cmd)
+	BindFlag(constants.ResumeFlag, cmd)
+	BindFlag(constants.AutoResumeFlag, cmd)
+	BindFlag(constants.RunStateDirFlag, cmd)
+	BindFlag(constants.ScheduleJitterFlag, cmd)
+	BindFlag(constants.ScheduleJitterModeFlag, cmd)
+	BindFlag(constants.ScheduleJitterKeyFlag, cmd)
+	BindFlag(constants.ScheduleStaggerFlag, cmd)
+	BindFlag(constants.IncrementalDataSourceSyncFlag, cmd)
+	BindFlag(constants.FullDataSourceSyncIntervalFlag, cmd)
+	BindFlag(constants.DisableConcurrentLanesFlag, cmd)
 	BindFlag(constants.DisableLogForwarding, cmd)
 	BindFlag(constants.DisableLogForwardingDataSourceSync, cmd)
 	BindFlag(constants.DisableLogForwardingDataAccessSync, cmd)
@@ -180,6 +259,11 @@ func initRunCommand(rootCmd *cobra.Command) {
 	BindFlag(constants.FileBackupLocationFlag, cmd)
 	BindFlag(constants.MaximumBackupsPerTargetFlag, cmd)
 	BindFlag(constants.MaximumFileSizesFlag, cmd)
+	BindFlag(constants.MultipartUploadThresholdFlag, cmd)
+	BindFlag(constants.MultipartUploadPartSizeFlag, cmd)
+	BindFlag(constants.MultipartUploadConcurrencyFlag, cmd)
+	BindFlag(constants.RecordFlag, cmd)
+	BindFlag(constants.RecordAllowedParametersFlag, cmd)
 
 	hideConfigOptions(cmd, constants.URLOverrideFlag, constants.SkipAuthentication, constants.SkipFileUpload, constants.ContainerLivenessFile)
 
@@ -204,12 +288,29 @@ func executeRun(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	if recordDir := viper.GetString(constants.RecordFlag); recordDir != "" {
+		recorder, recordErr := recording.NewRecorder(recordDir, baseLogger, viper.GetStringSlice(constants.RecordAllowedParametersFlag))
+		if recordErr != nil {
+			hclog.L().Error(recordErr.Error())
+			os.Exit(1)
+		}
+
+		hclog.L().Info(fmt.Sprintf("Recording the interactions with the connectors in %q", recordDir))
+		plugin.SetTargetClientProvider(recorder.Provider)
+	}
+
 	executeSyncAtStartup, scheduler, err := createSyncScheduler(baseConfig)
 	if err != nil {
 		hclog.L().Error(err.Error())
 		os.Exit(1)
 	}
 
+	jitter, err := schedule.NewJitter(viper.GetDuration(constants.ScheduleJitterFlag), viper.GetString(constants.ScheduleJitterModeFlag), jitterKey())
+	if err != nil {
+		hclog.L().Error(err.Error())
+		os.Exit(1)
+	}
+
 	if scheduler == nil {
 		hclog.L().Info("Running synchronization just once.")
 
@@ -222,11 +323,11 @@ func executeRun(cmd *cobra.Command, args []string) {
 			os.Exit(0)
 		}
 	} else {
-		executeContinuousRun(ctx, executeSyncAtStartup, scheduler, baseConfig)
+		executeContinuousRun(ctx, executeSyncAtStartup, scheduler, jitter, baseConfig)
 	}
 }
 
-func executeContinuousRun(ctx context.Context, executeSyncAtStartup bool, scheduler cron.Schedule, baseConfig *types.BaseConfig) {
+func executeContinuousRun(ctx context.Context, executeSyncAtStartup bool, scheduler cron.Schedule, jitter *schedule.Jitter, baseConfig *types.BaseConfig) {
 	hclog.L().Info("Starting continuous synchronization.")
 	hclog.L().Info("Press 'ctrl+c' to stop the program.")
 
@@ -239,88 +340,58 @@ func executeContinuousRun(ctx context.Context, executeSyncAtStartup bool, schedu
 
 	returnSignal := 0
 
+	state := newDaemonState()
+
 	waitGroup.Add(1)
 
 	go func() {
 		defer waitGroup.Done()
 
-		cliTriggerCtx, cliTriggerCancel := context.WithCancel(cancelCtx)
-		cliTrigger, apUpdateTrigger, syncTrigger := startListingToCliTriggers(cliTriggerCtx, baseConfig)
-
-		defer cliTriggerCancel()
-
-		if syncTrigger == nil {
+		elector, err := createLeaderElector(baseConfig)
+		if err != nil {
+			baseConfig.BaseLogger.Error(fmt.Sprintf("Unable to start leader election: %s", err.Error()))
 			cancelFn()
+
 			return
 		}
 
-		defer func() {
-			cliTrigger.Wait()
-			syncTrigger.Close()
-			apUpdateTrigger.Close()
-		}()
-
-		it := 1
-
-		if executeSyncAtStartup {
-			baseConfig.BaseLogger = baseConfig.BaseLogger.With("iteration", it)
-			if runErr := executeSingleRun(cancelCtx, baseConfig); runErr != nil {
-				baseConfig.BaseLogger.Error(fmt.Sprintf("Run failed: %s", runErr.Error()))
-			}
+		if elector == nil {
+			runSchedule(cancelCtx, cancelFn, executeSyncAtStartup, scheduler, jitter, baseConfig, state)
 
-			it++
+			return
 		}
 
-		timer := cronTimer(baseConfig.BaseLogger, nil, scheduler)
-		defer timer.Stop()
-
-		for {
-			select {
-			case <-timer.C:
-				cliTrigger.Reset()
-
-				baseConfig.BaseLogger = baseConfig.BaseLogger.With("iteration", it)
-				if runErr := executeSingleRun(cancelCtx, baseConfig); runErr != nil {
-					baseConfig.BaseLogger.Error(fmt.Sprintf("Run failed: %s", runErr.Error()))
-				}
+		waitGroup.Add(1)
 
-				cronTimer(baseConfig.BaseLogger, timer, scheduler)
+		go func() {
+			defer waitGroup.Done()
 
-				it++
-			case <-apUpdateTrigger.TriggerChannel():
-				apUpdate := apUpdateTrigger.Pop()
-				if apUpdate == nil {
-					continue
-				}
-
-				baseConfig.BaseLogger = baseConfig.BaseLogger.With("iteration", it)
-				err := handleApUpdateTrigger(cancelCtx, baseConfig, apUpdate)
+			elector.Run(cancelCtx)
+		}()
 
-				if err != nil {
-					baseConfig.BaseLogger.Warn(fmt.Sprintf("ClI ApUpdate Trigger failed: %s", err.Error()))
-				}
+		for {
+			// Followers are healthy as well, so they are not restarted while waiting to take over
+			healthErr := baseConfig.HealthChecker.MarkLiveness()
+			if healthErr != nil {
+				baseConfig.BaseLogger.Warn(fmt.Sprintf("Unable to set liveness marker: %s", healthErr.Error()))
+			}
 
-				it++
-			case <-syncTrigger.TriggerChannel():
-				syncRequest := syncTrigger.Pop()
-				if syncRequest == nil {
-					continue
-				}
+			baseConfig.BaseLogger.Info("Waiting to become the leader before starting the synchronization schedule.")
 
-				baseConfig.BaseLogger = baseConfig.BaseLogger.With("iteration", it)
-				err := handleSyncTrigger(cancelCtx, baseConfig, syncRequest)
+			leaderCtx, leaderCancel, leadErr := elector.Lead(cancelCtx)
+			if leadErr != nil {
+				return
+			}
 
-				if err != nil {
-					baseConfig.BaseLogger.Warn(fmt.Sprintf("ClI Sync Trigger failed: %s", err.Error()))
-				}
+			runSchedule(leaderCtx, cancelFn, executeSyncAtStartup, scheduler, jitter, baseConfig, state)
+			leaderCancel()
 
-				it++
-			case <-cancelCtx.Done():
-				baseConfig.BaseLogger.Debug("Context done: closing syncing routine.")
+			if cancelCtx.Err() != nil {
 				return
 			}
 
-			hclog.L().Info("Press 'ctrl+c' to stop the program.")
+			// The previous leader already executed the sync at startup
+			executeSyncAtStartup = false
 		}
 	}()
 
@@ -357,6 +428,74 @@ func executeContinuousRun(ctx context.Context, executeSyncAtStartup bool, schedu
 	}
 }
 
+// runSchedule executes the scheduled runs and handles the incoming triggers until the context is done.
+func runSchedule(ctx context.Context, cancelFn context.CancelFunc, executeSyncAtStartup bool, scheduler cron.Schedule, jitter *schedule.Jitter, baseConfig *types.BaseConfig, state *daemonState) {
+	cliTriggerCtx, cliTriggerCancel := context.WithCancel(ctx)
+	cliTrigger, apUpdateTrigger, syncTrigger := startListingToCliTriggers(cliTriggerCtx, baseConfig, state)
+
+	defer cliTriggerCancel()
+
+	if syncTrigger == nil {
+		cancelFn()
+		return
+	}
+
+	defer func() {
+		cliTrigger.Wait()
+		syncTrigger.Close()
+		apUpdateTrigger.Close()
+	}()
+
+	// Syncs are only deferred until the end of their blackout window by the daemon, as the manual lane queues them again once the window ends
+	err := blackout.EnableDeferrals(syncTrigger.DeferredSyncsFile())
+	if err != nil {
+		baseConfig.BaseLogger.Warn(fmt.Sprintf("Unable to restore the deferred syncs: %s", err.Error()))
+	}
+
+	defer blackout.DisableDeferrals()
+
+	lanes := newDaemonLanes(baseConfig, state, !viper.GetBool(constants.DisableConcurrentLanesFlag))
+
+	lanes.start(ctx, lane.Scheduled, func(ctx context.Context) {
+		lanes.runScheduledLane(ctx, executeSyncAtStartup, scheduler, jitter, cliTrigger)
+	})
+
+	lanes.start(ctx, lane.Manual, func(ctx context.Context) {
+		lanes.runManualLane(ctx, syncTrigger)
+	})
+
+	lanes.start(ctx, lane.AccessUpdate, func(ctx context.Context) {
+		lanes.runAccessUpdateLane(ctx, apUpdateTrigger)
+	})
+
+	lanes.Wait()
+}
+
+// createLeaderElector creates the leader election for the daemon, if enabled.
+func createLeaderElector(baseConfig *types.BaseConfig) (*leader.Elector, error) {
+	backendName := viper.GetString(constants.LeaderElectionFlag)
+	if backendName == "" {
+		return nil, nil
+	}
+
+	backend, err := leader.NewBackend(backendName, baseConfig, viper.GetString(constants.LeaderElectionLockFlag))
+	if err != nil {
+		return nil, err
+	}
+
+	identity := viper.GetString(constants.LeaderElectionIdentityFlag)
+	if identity == "" {
+		identity = leader.DefaultIdentity()
+	}
+
+	ttl := viper.GetDuration(constants.LeaderElectionTtlFlag)
+	if ttl < 3*time.Second {
+		return nil, fmt.Errorf("the %q flag should be at least 3 seconds", constants.LeaderElectionTtlFlag)
+	}
+
+	return leader.NewElector(backend, identity, ttl, baseConfig.BaseLogger), nil
+}
+
 func createHealthChecker(baseLogger hclog.Logger) health_check.HealthChecker {
 	livenessFilePath := viper.GetString(constants.ContainerLivenessFile)
 
@@ -412,8 +551,8 @@ func moreThanOneExecutionWithinAnHour(cronSchedule *cron.SpecSchedule) bool {
 	return false
 }
 
-func cronTimer(logger hclog.Logger, timer *time.Timer, scheduler cron.Schedule) *time.Timer {
-	next := scheduler.Next(time.Now())
+func cronTimer(logger hclog.Logger, timer *time.Timer, scheduler cron.Schedule, jitter *schedule.Jitter) *time.Timer {
+	next := jitter.Next(scheduler, time.Now())
 
 	logger.Info(fmt.Sprintf("Next execution at %s", next.Format(time.RFC822)))
 
@@ -427,17 +566,104 @@ func cronTimer(logger hclog.Logger, timer *time.Timer, scheduler cron.Schedule)
 	}
 }
 
+// deferredSyncPollInterval is the maximum time between two checks for deferred syncs, as the syncs can be deferred by any lane of the daemon.
+const deferredSyncPollInterval = time.Minute
+
+// deferredSyncTimer (re)sets the timer to fire when the first sync that was deferred because of a blackout window can be executed.
+func deferredSyncTimer(timer *time.Timer) *time.Timer {
+	if timer == nil {
+		timer = time.NewTimer(deferredSyncPollInterval)
+	}
+
+	timer.Stop()
+
+	wait := deferredSyncPollInterval
+
+	if next, found := blackout.NextDue(); found {
+		wait = min(time.Until(next), deferredSyncPollInterval)
+	}
+
+	timer.Reset(wait)
+
+	return timer
+}
+
+// queueDeferredSyncs adds the syncs for which the blackout window ended to the sync queue.
+func queueDeferredSyncs(ctx context.Context, logger hclog.Logger, syncTrigger *clitrigger.SyncTriggerHandler) {
+	syncTriggers := make(map[string]*clitrigger.SyncTrigger)
+
+	var targetNames []string
+
+	for _, deferral := range blackout.TakeDue(time.Now()) {
+		trigger, found := syncTriggers[deferral.Target]
+		if !found {
+			targetName := deferral.Target
+			trigger = &clitrigger.SyncTrigger{Target: &targetName}
+			syncTriggers[deferral.Target] = trigger
+			targetNames = append(targetNames, targetName)
+		}
+
+		// Tag syncs are never skipped by a sync trigger, so they don't need a flag
+		switch deferral.SyncType {
+		case constants.DataSourceSync:
+			trigger.DataSourceSync = true
+		case constants.IdentitySync:
+			trigger.IdentityStoreSync = true
+		case constants.DataAccessSync:
+			trigger.DataAccessSync = true
+		case constants.DataUsageSync:
+			trigger.DataUsageSync = true
+		case constants.ResourceProviderSync:
+			trigger.ResourceProviderSync = true
+		}
+	}
+
+	for _, targetName := range targetNames {
+		logger.Info(fmt.Sprintf("Blackout window ended: queueing deferred syncs of target %q", targetName))
+
+		syncTrigger.HandleTriggerEvent(ctx, &clitrigger.TriggerEvent{SyncTrigger: syncTriggers[targetName]})
+	}
+}
+
+// jitterKey returns the key to derive the offset of the schedule jitter from in the deterministic jitter modes.
+func jitterKey() string {
+	if viper.GetString(constants.ScheduleJitterModeFlag) != schedule.JitterTarget {
+		return viper.GetString(constants.ScheduleJitterKeyFlag)
+	}
+
+	targetNames := target.ConfiguredTargetNames()
+	slices.Sort(targetNames)
+
+	return strings.Join(targetNames, ",")
+}
+
 func executeSingleRun(ctx context.Context, baseconfig *types.BaseConfig) error {
 	start := time.Now()
 
+	// The counters are not reset, as other lanes of the daemon may be running at the same time
+	statsAtStart := graphql.GetStatistics()
+
 	err := runSync(ctx, baseconfig)
 
 	sec := time.Since(start).Round(time.Millisecond)
-	baseconfig.BaseLogger.Info(fmt.Sprintf("Finished execution of all targets in %s", sec))
+	apiStatistics := graphql.GetStatistics().Since(statsAtStart)
+
+	// Retries and failures of the requests to Raito are logged as a warning, so they stand out in the run output
+	if apiStatistics.Retries > 0 || apiStatistics.Failures > 0 {
+		baseconfig.BaseLogger.Warn(runSummary(sec, apiStatistics))
+	} else {
+		baseconfig.BaseLogger.Info(runSummary(sec, apiStatistics))
+	}
 
 	return err
 }
 
+// runSummary describes a finished run, including the counters of the requests sent to Raito during the run.
+// When lanes run concurrently, the counters include the requests of the other lanes during that time.
+func runSummary(duration time.Duration, apiStatistics graphql.Statistics) string {
+	return fmt.Sprintf("Finished execution of all targets in %s (Raito API: %s)", duration, apiStatistics.String())
+}
+
 func runSync(ctx context.Context, baseconfig *types.BaseConfig) error {
 	compatibilityInformation, err := version_management.IsCompatibleWithRaitoCloud(baseconfig)
 	if err != nil {
@@ -461,13 +687,27 @@ func runSync(ctx context.Context, baseconfig *types.BaseConfig) error {
 
 		fallthrough
 	case version_management.Supported:
-		return target.RunTargets(ctx, baseconfig, &target_sync.SyncJob{RunTypeName: "full"})
+		return target.RunTargets(ctx, baseconfig, &target_sync.SyncJob{RunTypeName: "full", RunState: runstate.NewStore(viper.GetString(constants.RunStateDirFlag)), Resume: resumeRun()}, target.WithStagger(viper.GetDuration(constants.ScheduleStaggerFlag)))
 	case version_management.CompatibilityUnknown:
 	}
 
 	return errors.New("unknown CLI version")
 }
 
+var resumeFirstRun sync2.Once
+
+// resumeRun checks if the run should resume the last (failed) runs of the targets.
+// The resume flag only applies to the first run, the auto-resume flag to all runs.
+func resumeRun() bool {
+	resume := false
+
+	resumeFirstRun.Do(func() {
+		resume = viper.GetBool(constants.ResumeFlag)
+	})
+
+	return resume || viper.GetBool(constants.AutoResumeFlag)
+}
+
 func handleApUpdateTrigger(ctx context.Context, config *types.BaseConfig, apUpdate *clitrigger.ApUpdate) error {
 	return target.RunTargets(ctx, config, &target_sync.SyncJob{RunTypeName: "webhook"}, target.WithDataSourceIds(apUpdate.DataSourceNames...), target.WithConfigOption(func(targetConfig *types.BaseTargetConfig) {
 		targetConfig.SkipIdentityStoreSync = true
@@ -501,20 +741,49 @@ func handleSyncTrigger(ctx context.Context, config *types.BaseConfig, syncTrigge
 		opts = append(opts, target.WithDataSourceIds(*syncTrigger.DataSource))
 	}
 
+	if syncTrigger.Target != nil {
+		opts = append(opts, target.WithTargetNames(*syncTrigger.Target))
+	}
+
 	return target.RunTargets(ctx, config, &target_sync.SyncJob{RunTypeName: "manual"}, opts...)
 }
 
-func startListingToCliTriggers(ctx context.Context, baseConfig *types.BaseConfig) (clitrigger.CliTrigger, *clitrigger.ApUpdateTriggerHandler, *clitrigger.SyncTriggerHandler) {
+func startListingToCliTriggers(ctx context.Context, baseConfig *types.BaseConfig, state *daemonState) (clitrigger.CliTrigger, *clitrigger.ApUpdateTriggerHandler, *clitrigger.SyncTriggerHandler) {
 	cliTrigger, err := clitrigger.CreateCliTrigger(baseConfig)
 	if err != nil {
 		baseConfig.BaseLogger.Error(fmt.Sprintf("Unable to start asynchronous access provider sync: %s", err.Error()))
 		return cliTrigger, nil, nil
 	}
 
-	apUpdateTriggerHandler := clitrigger.NewApUpdateTrigger(cliTrigger)
-	syncTriggerHandler := clitrigger.NewSyncTrigger(cliTrigger)
+	if socketPath := viper.GetString(constants.ControlSocketFlag); socketPath != "" {
+		controlSocketTrigger, controlErr := clitrigger.NewControlSocketCliTrigger(baseConfig, socketPath, state.Status, validateControlSocketTrigger)
+		if controlErr != nil {
+			baseConfig.BaseLogger.Warn(fmt.Sprintf("Unable to start control socket: %s", controlErr.Error()))
+		} else {
+			cliTrigger = clitrigger.NewFanInCliTrigger(cliTrigger, controlSocketTrigger)
+		}
+	}
+
+	apUpdateTriggerHandler := clitrigger.NewApUpdateTrigger(baseConfig, cliTrigger)
+	syncTriggerHandler := clitrigger.NewSyncTrigger(baseConfig, cliTrigger)
+
+	state.setTriggerHandlers(apUpdateTriggerHandler, syncTriggerHandler)
 
 	cliTrigger.Start(ctx)
 
 	return cliTrigger, apUpdateTriggerHandler, syncTriggerHandler
 }
+
+// validateControlSocketTrigger makes sure a sync triggered with 'raito trigger' targets a configured target, as it would be silently ignored otherwise.
+func validateControlSocketTrigger(triggerEvent *clitrigger.TriggerEvent) error {
+	if triggerEvent.SyncTrigger == nil || triggerEvent.SyncTrigger.Target == nil {
+		return nil
+	}
+
+	targetNames := target.ConfiguredTargetNames()
+	if !slices.Contains(targetNames, *triggerEvent.SyncTrigger.Target) {
+		return fmt.Errorf("unknown target %q. Configured targets are %s", *triggerEvent.SyncTrigger.Target, strings.Join(targetNames, ", "))
+	}
+
+	return nil
+}
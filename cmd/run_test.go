@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"testing"
+	"time"
 
 	"github.com/robfig/cron/v3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/graphql"
 )
 
 func Test_moreThanOneExecutionWithinAnHour(t *testing.T) {
@@ -57,3 +60,9 @@ func Test_moreThanOneExecutionWithinAnHour(t *testing.T) {
 		})
 	}
 }
+
+func Test_runSummary(t *testing.T) {
+	summary := runSummary(1500*time.Millisecond, graphql.Statistics{Requests: 12, Retries: 2, Failures: 1, CircuitBreakerOpened: 1})
+
+	assert.Equal(t, "Finished execution of all targets in 1.5s (Raito API: 12 requests, 2 retries, 1 failures, circuit breaker opened 1 times)", summary)
+}
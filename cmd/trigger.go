@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/raito-io/cli/internal/clitrigger"
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/logging"
+)
+
+const (
+	syncTypeDataSource       = "data-source"
+	syncTypeIdentityStore    = "identity-store"
+	syncTypeAccess           = "access"
+	syncTypeUsage            = "usage"
+	syncTypeResourceProvider = "resource-provider"
+
+	controlSocketTimeout = 10 * time.Second
+)
+
+var allSyncTypes = []string{syncTypeDataSource, syncTypeIdentityStore, syncTypeAccess, syncTypeUsage, syncTypeResourceProvider}
+
+func initTriggerCommand(rootCmd *cobra.Command) {
+	var cmd = &cobra.Command{
+		Short: "Ask a running daemon to sync a target now.",
+		Long:  "Ask a running daemon (a 'run' command with a frequency or cron expression) on this machine to sync the given target now. The sync is added to the queue of the daemon and executed as soon as the current run is finished.",
+		Run:   executeTriggerCmd,
+		Args:  cobra.NoArgs,
+		Use:   "trigger --target <target-name> [--sync data-source,access]",
+	}
+
+	cmd.PersistentFlags().String(constants.TriggerTargetFlag, "", "The name of the target to sync.")
+	cmd.PersistentFlags().String(constants.TriggerSyncFlag, strings.Join(allSyncTypes, ","), fmt.Sprintf("A comma-separated list of the parts to sync. Possible values are %s.", strings.Join(allSyncTypes, ", ")))
+
+	BindFlag(constants.TriggerTargetFlag, cmd)
+	BindFlag(constants.TriggerSyncFlag, cmd)
+
+	rootCmd.AddCommand(cmd)
+}
+
+func executeTriggerCmd(_ *cobra.Command, _ []string) {
+	logging.SetupLogging(true)
+
+	err := triggerCmd()
+	if err != nil {
+		pterm.Error.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func triggerCmd() error {
+	targetName := viper.GetString(constants.TriggerTargetFlag)
+	if targetName == "" {
+		return fmt.Errorf("the %q flag is required", constants.TriggerTargetFlag)
+	}
+
+	syncTrigger, err := createSyncTrigger(targetName, viper.GetString(constants.TriggerSyncFlag))
+	if err != nil {
+		return err
+	}
+
+	socketPath := viper.GetString(constants.ControlSocketFlag)
+	if socketPath == "" {
+		return fmt.Errorf("the %q flag is required", constants.ControlSocketFlag)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), controlSocketTimeout)
+	defer cancel()
+
+	err = clitrigger.NewControlSocketClient(socketPath).Trigger(ctx, &clitrigger.TriggerEvent{SyncTrigger: syncTrigger})
+	if err != nil {
+		return err
+	}
+
+	pterm.Success.Println(fmt.Sprintf("Sync of target %q queued.", targetName))
+
+	return nil
+}
+
+func createSyncTrigger(targetName string, syncTypes string) (*clitrigger.SyncTrigger, error) {
+	syncTrigger := clitrigger.SyncTrigger{
+		Target: &targetName,
+	}
+
+	for _, syncType := range strings.Split(syncTypes, ",") {
+		switch strings.TrimSpace(syncType) {
+		case syncTypeDataSource:
+			syncTrigger.DataSourceSync = true
+		case syncTypeIdentityStore:
+			syncTrigger.IdentityStoreSync = true
+		case syncTypeAccess:
+			syncTrigger.DataAccessSync = true
+		case syncTypeUsage:
+			syncTrigger.DataUsageSync = true
+		case syncTypeResourceProvider:
+			syncTrigger.ResourceProviderSync = true
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown sync type %q. Possible values are %s", syncType, strings.Join(allSyncTypes, ", "))
+		}
+	}
+
+	if !syncTrigger.DataSourceSync && !syncTrigger.IdentityStoreSync && !syncTrigger.DataAccessSync && !syncTrigger.DataUsageSync && !syncTrigger.ResourceProviderSync {
+		return nil, errors.New("nothing to sync")
+	}
+
+	return &syncTrigger, nil
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSyncTrigger(t *testing.T) {
+	syncTrigger, err := createSyncTrigger("target1", "data-source, access")
+	require.NoError(t, err)
+
+	assert.Equal(t, "target1", *syncTrigger.Target)
+	assert.True(t, syncTrigger.DataSourceSync)
+	assert.True(t, syncTrigger.DataAccessSync)
+	assert.False(t, syncTrigger.IdentityStoreSync)
+	assert.False(t, syncTrigger.DataUsageSync)
+	assert.False(t, syncTrigger.ResourceProviderSync)
+
+	assert.Equal(t, `target "target1" (data-source, access)`, describeSyncTrigger(syncTrigger))
+
+	_, err = createSyncTrigger("target1", "data-source,unknown")
+	assert.ErrorContains(t, err, `unknown sync type "unknown"`)
+
+	_, err = createSyncTrigger("target1", "")
+	assert.ErrorContains(t, err, "nothing to sync")
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -18,6 +17,7 @@ import (
 	"github.com/raito-io/cli/internal/job"
 	"github.com/raito-io/cli/internal/target/types"
 	"github.com/raito-io/cli/internal/util/file"
+	"github.com/raito-io/cli/internal/util/httpclient"
 )
 
 type AccessProviderExportResult struct {
@@ -56,7 +56,7 @@ func (d *accessProviderExporter) TriggerExport(ctx context.Context, jobId string
 	}
 
 	result := &AccessProviderExportResult{}
-	subtask, err := job.WaitForJobToComplete(ctx, jobId, constants.DataAccessSync, subTaskId, result, &d.config.BaseTargetConfig, status, 1)
+	subtask, err := job.WaitForJobToComplete(ctx, jobId, constants.DataAccessSync, subTaskId, result, &d.config.BaseTargetConfig, status)
 
 	if err != nil {
 		return job.Failed, "", err
@@ -92,7 +92,12 @@ func (d *accessProviderExporter) download(url string) (string, error) {
 
 	defer downloadedFile.Close()
 
-	resp, err := http.Get(url) //nolint
+	client, err := httpclient.NewClient()
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Get(url) //nolint
 
 	if err != nil {
 		return "", fmt.Errorf("error while fetching access controls for datasource %q: %s", d.config.DataSourceId, err.Error())
@@ -221,6 +221,7 @@ func (s *dataAccessImportSubtask) accessSyncImport(client plugin.PluginClient, t
 		LockDeleteByName:              slice.ParseCommaSeparatedList(s.TargetConfig.LockDeleteByName),
 		LockDeleteByTag:               slice.ParseCommaSeparatedList(s.TargetConfig.LockDeleteByTag),
 		LockDeleteWhenIncomplete:      s.TargetConfig.LockDeleteWhenIncomplete,
+		LockRules:                     lockRules(s.TargetConfig.LockRules),
 	}
 
 	das, err := client.GetAccessSyncer()
@@ -228,6 +229,16 @@ func (s *dataAccessImportSubtask) accessSyncImport(client plugin.PluginClient, t
 		return err
 	}
 
+	if len(syncerConfig.LockRules) > 0 {
+		// Connectors built with an older version of the CLI silently drop the lock rules, so the access providers would be imported without locks
+		supported, versionErr := version_management.SupportsFeature(context.Background(), das, dapc.LockRulesMinimalCliVersion)
+		if versionErr != nil {
+			return versionErr
+		} else if !supported {
+			return fmt.Errorf("connector %q does not support lock rules. Update the connector to a version built with CLI %s or later, or use the lock flags instead", s.TargetConfig.ConnectorName, dapc.LockRulesMinimalCliVersion)
+		}
+	}
+
 	s.TargetConfig.TargetLogger.Info("Synchronizing access providers between data source and Raito")
 
 	res, err := das.SyncFromTarget(context.Background(), &syncerConfig)
@@ -399,3 +410,24 @@ func mapErrorResult(result *error1.ErrorResult) error {
 
 	return errors.New(result.ErrorMessage)
 }
+
+func lockRules(rules []types.LockRule) []*dapc.LockRule {
+	result := make([]*dapc.LockRule, 0, len(rules))
+
+	for _, rule := range rules {
+		result = append(result, &dapc.LockRule{
+			Name:            rule.Name,
+			Names:           rule.Names,
+			Types:           rule.Types,
+			Tags:            rule.Tags,
+			WhatDataObjects: rule.WhatDataObjects,
+			Actions:         rule.Actions,
+			IncompleteOnly:  rule.IncompleteOnly,
+			Owners:          rule.Owners,
+			Locks:           rule.Locks,
+			Reason:          rule.Reason,
+		})
+	}
+
+	return result
+}
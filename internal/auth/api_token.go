@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+// apiTokenAuthenticator uses a pre-issued API token (passed as api-secret) as bearer token.
+// As these tokens are issued externally, they are never refreshed nor cached on disk.
+type apiTokenAuthenticator struct{}
+
+func (a *apiTokenAuthenticator) Name() string {
+	return AuthMethodApiToken
+}
+
+func (a *apiTokenAuthenticator) AuthorizationHeader(tokens *Tokens) string {
+	return "Bearer " + tokens.IdToken
+}
+
+func (a *apiTokenAuthenticator) FetchTokens(_ context.Context, config *types.BaseConfig, tokens *Tokens) error {
+	if config.ApiSecret == "" {
+		return errors.New("no API token specified (use the api-secret parameter to provide it)")
+	}
+
+	tokens.UserName = config.ApiUser
+	tokens.IdToken = config.ApiSecret
+	tokens.RefreshToken = ""
+	tokens.Expiration = nil
+
+	return nil
+}
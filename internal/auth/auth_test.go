@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/util/test"
+)
+
+func setupTokenCache(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	originalDir := tokenCacheDir
+
+	tokenCacheDir = func() (string, error) {
+		return dir, nil
+	}
+
+	t.Cleanup(func() {
+		tokenCacheDir = originalDir
+		tokenMap = make(map[string]*Tokens)
+
+		viper.Set(constants.AuthMethodFlag, "")
+		viper.Set(constants.OidcTokenUrlFlag, "")
+		viper.Set(constants.OidcScopesFlag, "")
+		viper.Set(constants.TokenCacheFlag, false)
+	})
+
+	return dir
+}
+
+func TestAddTokenToHeader_Cognito(t *testing.T) {
+	setupTokenCache(t)
+
+	config, closer := test.CreateBaseConfig("testdomain", "user", "secret", "")
+	defer closer()
+
+	h := http.Header{}
+	err := AddTokenToHeader(&h, config)
+
+	require.NoError(t, err)
+	assert.Equal(t, "token idToken", h.Get("Authorization"))
+}
+
+func TestAddTokenToHeader_ApiToken(t *testing.T) {
+	dir := setupTokenCache(t)
+
+	viper.Set(constants.AuthMethodFlag, AuthMethodApiToken)
+	viper.Set(constants.TokenCacheFlag, true)
+
+	config, closer := test.CreateBaseConfig("testdomain", "", "my-api-token", "")
+	defer closer()
+
+	h := http.Header{}
+	err := AddTokenToHeader(&h, config)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer my-api-token", h.Get("Authorization"))
+
+	// API tokens should never be written to disk
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestAddTokenToHeader_UnknownMethod(t *testing.T) {
+	setupTokenCache(t)
+
+	viper.Set(constants.AuthMethodFlag, "unknown")
+
+	config, closer := test.CreateBaseConfig("testdomain", "user", "secret", "")
+	defer closer()
+
+	h := http.Header{}
+	err := AddTokenToHeader(&h, config)
+
+	require.Error(t, err)
+}
+
+func TestAddTokenToHeader_Oidc(t *testing.T) {
+	dir := setupTokenCache(t)
+
+	calls := 0
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		calls++
+
+		clientId, clientSecret, ok := req.BasicAuth()
+		if !ok || clientId != "client" || clientSecret != "secret" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_ = req.ParseForm()
+
+		if req.Form.Get("grant_type") != "client_credentials" || req.Form.Get("scope") != "raito.read raito.write" {
+			res.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		res.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(res).Encode(map[string]interface{}{
+			"access_token": "oidc-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer tokenServer.Close()
+
+	viper.Set(constants.AuthMethodFlag, AuthMethodOidc)
+	viper.Set(constants.OidcTokenUrlFlag, tokenServer.URL)
+	viper.Set(constants.OidcScopesFlag, "raito.read, raito.write")
+	viper.Set(constants.TokenCacheFlag, true)
+
+	config, closer := test.CreateBaseConfig("testdomain", "client", "secret", "")
+	defer closer()
+
+	h := http.Header{}
+	err := AddTokenToHeader(&h, config)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer oidc-access-token", h.Get("Authorization"))
+	assert.Equal(t, 1, calls)
+
+	// The token is still valid, so no new call to the token endpoint is expected
+	err = AddTokenToHeader(&h, config)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// Simulate a new CLI invocation: the tokens should be loaded from the disk cache
+	tokenMap = make(map[string]*Tokens)
+
+	h = http.Header{}
+	err = AddTokenToHeader(&h, config)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer oidc-access-token", h.Get("Authorization"))
+	assert.Equal(t, 1, calls)
+}
+
+func TestAddTokenToHeader_OidcError(t *testing.T) {
+	setupTokenCache(t)
+
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer tokenServer.Close()
+
+	viper.Set(constants.AuthMethodFlag, AuthMethodOidc)
+	viper.Set(constants.OidcTokenUrlFlag, tokenServer.URL)
+
+	config, closer := test.CreateBaseConfig("testdomain", "client", "wrong", "")
+	defer closer()
+
+	h := http.Header{}
+	err := AddTokenToHeader(&h, config)
+
+	require.Error(t, err)
+	assert.Empty(t, h.Get("Authorization"))
+}
+
+func TestTokenCache_SecretChanged(t *testing.T) {
+	setupTokenCache(t)
+
+	config, closer := test.CreateBaseConfig("testdomain", "user", "secret", "")
+	defer closer()
+
+	config.TokenCache = true
+
+	authenticator := &cognitoAuthenticator{}
+	tokens := &Tokens{UserName: "user", IdToken: "cached"}
+	setExpiration(tokens, 3600)
+
+	storeCachedTokens(authenticator, config, tokens)
+
+	loaded := loadCachedTokens(authenticator, config)
+	assert.Equal(t, "cached", loaded.IdToken)
+
+	config.ApiSecret = "other-secret"
+
+	loaded = loadCachedTokens(authenticator, config)
+	assert.Empty(t, loaded.IdToken)
+	assert.Equal(t, "user", loaded.UserName)
+}
@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	idp "github.com/aws/aws-sdk-go-v2/service/cognitoidentityprovider"
+
+	"github.com/raito-io/cli/internal/target/types"
+	"github.com/raito-io/cli/internal/util/httpclient"
+	"github.com/raito-io/cli/internal/util/url"
+)
+
+var clientAppId string
+
+// cognitoAuthenticator authenticates using the username (api-user) and secret (api-secret) of a Raito user.
+type cognitoAuthenticator struct{}
+
+func (a *cognitoAuthenticator) Name() string {
+	return AuthMethodCognito
+}
+
+func (a *cognitoAuthenticator) AuthorizationHeader(tokens *Tokens) string {
+	return "token " + tokens.IdToken
+}
+
+func (a *cognitoAuthenticator) FetchTokens(ctx context.Context, config *types.BaseConfig, tokens *Tokens) error {
+	tokens.UserName = config.ApiUser
+
+	if tokens.RefreshToken != "" {
+		err := refreshTokens(ctx, config, tokens)
+		if err != nil {
+			config.BaseLogger.Warn(fmt.Sprintf("error while trying to refresh tokens: %s. Trying to fetch tokens from scratch instead", err.Error()))
+		} else {
+			return nil
+		}
+	}
+
+	// If no refresh token or refreshing failed
+	return fetchNewTokens(ctx, config, tokens)
+}
+
+func refreshTokens(ctx context.Context, baseConfig *types.BaseConfig, tokens *Tokens) error {
+	baseConfig.BaseLogger.Debug(fmt.Sprintf("Refreshing tokens for user %q", tokens.UserName))
+
+	err := fetchClientAppId(baseConfig)
+	if err != nil {
+		return fmt.Errorf("error while fetching clientAppId: %s", err.Error())
+	}
+
+	if isInTest() {
+		return setTestTokens(tokens)
+	}
+
+	cfg, err := loadAwsConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("error while configuring AWS SDK: %s", err.Error())
+	}
+	idpClient := idp.NewFromConfig(cfg)
+	output, err := idpClient.InitiateAuth(ctx, &idp.InitiateAuthInput{
+		AuthFlow:       "REFRESH_TOKEN_AUTH",
+		ClientId:       &clientAppId,
+		AuthParameters: map[string]string{"REFRESH_TOKEN": tokens.RefreshToken},
+	})
+
+	if err != nil {
+		return fmt.Errorf("error while refreshing tokens %q: %s", tokens.UserName, err.Error())
+	}
+
+	return handleAuthOutput(output, tokens)
+}
+
+func loadAwsConfig(ctx context.Context) (aws.Config, error) {
+	client, err := httpclient.NewClient()
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	// TODO configurable region
+	return config.LoadDefaultConfig(ctx, config.WithRegion("eu-central-1"), config.WithHTTPClient(client))
+}
+
+func setTestTokens(tokens *Tokens) error {
+	tokens.IdToken = "idToken"
+	tokens.RefreshToken = "refreshToken"
+	setExpiration(tokens, 3600)
+
+	return nil
+}
+
+func fetchNewTokens(ctx context.Context, baseConfig *types.BaseConfig, tokens *Tokens) error {
+	baseConfig.BaseLogger.Debug("Fetching new tokens")
+
+	err := fetchClientAppId(baseConfig)
+	if err != nil {
+		return fmt.Errorf("error while fetching clientAppId: %s", err.Error())
+	}
+
+	if isInTest() {
+		return setTestTokens(tokens)
+	}
+
+	cfg, err := loadAwsConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("error while configuring AWS SDK: %s", err.Error())
+	}
+	idpClient := idp.NewFromConfig(cfg)
+	output, err := idpClient.InitiateAuth(ctx, &idp.InitiateAuthInput{
+		AuthFlow:       "USER_PASSWORD_AUTH",
+		ClientId:       &clientAppId,
+		AuthParameters: map[string]string{"USERNAME": baseConfig.ApiUser, "PASSWORD": baseConfig.ApiSecret},
+	})
+
+	if err != nil {
+		return fmt.Errorf("error while initiating authentication flow for user %q: %s", tokens.UserName, err.Error())
+	}
+
+	return handleAuthOutput(output, tokens)
+}
+
+func handleAuthOutput(output *idp.InitiateAuthOutput, tokens *Tokens) error {
+	if output.AuthenticationResult != nil {
+		if output.AuthenticationResult.IdToken == nil {
+			return fmt.Errorf("no id token found in authentication result")
+		}
+
+		if output.AuthenticationResult.RefreshToken != nil {
+			tokens.RefreshToken = *output.AuthenticationResult.RefreshToken
+		}
+
+		tokens.IdToken = *output.AuthenticationResult.IdToken
+		setExpiration(tokens, int64(output.AuthenticationResult.ExpiresIn))
+
+		return nil
+	} else {
+		return fmt.Errorf("invalid authentication result received (challenge %q)", output.ChallengeName)
+	}
+}
+
+func fetchClientAppId(baseConfig *types.BaseConfig) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if clientAppId == "" {
+		err := validateDomain(baseConfig.Domain)
+		if err != nil {
+			return err
+		}
+
+		domain := strings.ToLower(baseConfig.Domain)
+
+		if isInTest() {
+			clientAppId = "testclient"
+			return nil
+		}
+
+		url := url.CreateRaitoURL(url.GetRaitoURL(), "admin/org/"+domain)
+
+		req, err := http.NewRequest("GET", url, http.NoBody)
+		if err != nil {
+			return fmt.Errorf("error while creating HTTP GET request to %q: %s", url, err.Error())
+		}
+
+		client, err := httpclient.NewClient()
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("error while doing HTTP GET to %q: %s", url, err.Error())
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("unexpected status code %q received when calling URL %q", resp.StatusCode, url)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("error while reading body for call to %q: %s", url, err.Error())
+		}
+
+		org := orgInfo{}
+
+		err = json.Unmarshal(body, &org)
+		if err != nil {
+			return fmt.Errorf("error while parsing organization info response from %q: %s", url, err.Error())
+		}
+
+		clientAppId = org.ClientAppId
+		baseConfig.BaseLogger.Debug(fmt.Sprintf("Received clientAppId %q for domain %q", clientAppId, domain))
+	}
+
+	return nil
+}
+
+type orgInfo struct {
+	AuthOrgId   string
+	ClientAppId string
+}
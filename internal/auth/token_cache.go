@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+// tokenCacheDir returns the directory where the encrypted tokens are cached.
+// Defined as a variable to make it possible to override in tests.
+var tokenCacheDir = func() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".raito", "tokens"), nil
+}
+
+// loadCachedTokens loads the tokens from the on-disk token cache if enabled.
+// If the cache is disabled or no (valid) cache entry exists, empty tokens are returned.
+func loadCachedTokens(authenticator Authenticator, config *types.BaseConfig) *Tokens {
+	tokens := &Tokens{UserName: config.ApiUser}
+
+	if !config.TokenCache {
+		return tokens
+	}
+
+	cacheFile, err := tokenCacheFile(authenticator, config)
+	if err != nil {
+		config.BaseLogger.Debug(fmt.Sprintf("Unable to determine token cache location: %s", err.Error()))
+		return tokens
+	}
+
+	encrypted, err := os.ReadFile(cacheFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			config.BaseLogger.Debug(fmt.Sprintf("Unable to read token cache file %q: %s", cacheFile, err.Error()))
+		}
+
+		return tokens
+	}
+
+	decrypted, err := decryptTokens(tokenCacheEncryptionKey(config), encrypted)
+	if err != nil {
+		// Most likely the secret has changed. Ignoring the cached tokens.
+		config.BaseLogger.Debug(fmt.Sprintf("Unable to decrypt token cache file %q: %s", cacheFile, err.Error()))
+		return tokens
+	}
+
+	cached := Tokens{}
+
+	err = json.Unmarshal(decrypted, &cached)
+	if err != nil || cached.UserName != config.ApiUser {
+		return tokens
+	}
+
+	config.BaseLogger.Debug(fmt.Sprintf("Loaded tokens for user %q from token cache", config.ApiUser))
+
+	return &cached
+}
+
+// storeCachedTokens stores the given tokens in the on-disk token cache if enabled.
+// Tokens without expiration (like pre-issued API tokens) are never stored.
+func storeCachedTokens(authenticator Authenticator, config *types.BaseConfig, tokens *Tokens) {
+	if !config.TokenCache || tokens.Expiration == nil {
+		return
+	}
+
+	cacheFile, err := tokenCacheFile(authenticator, config)
+	if err != nil {
+		config.BaseLogger.Warn(fmt.Sprintf("Unable to determine token cache location: %s", err.Error()))
+		return
+	}
+
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		config.BaseLogger.Warn(fmt.Sprintf("Unable to serialize tokens for token cache: %s", err.Error()))
+		return
+	}
+
+	encrypted, err := encryptTokens(tokenCacheEncryptionKey(config), data)
+	if err != nil {
+		config.BaseLogger.Warn(fmt.Sprintf("Unable to encrypt tokens for token cache: %s", err.Error()))
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(cacheFile), 0700)
+	if err != nil {
+		config.BaseLogger.Warn(fmt.Sprintf("Unable to create token cache directory: %s", err.Error()))
+		return
+	}
+
+	err = os.WriteFile(cacheFile, encrypted, 0600)
+	if err != nil {
+		config.BaseLogger.Warn(fmt.Sprintf("Unable to write token cache file %q: %s", cacheFile, err.Error()))
+	}
+}
+
+// tokenCacheFile returns the path of the cache file for the authentication method, domain and user in the given configuration.
+func tokenCacheFile(authenticator Authenticator, config *types.BaseConfig) (string, error) {
+	dir, err := tokenCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(tokenKey(authenticator, config)))
+
+	return filepath.Join(dir, hex.EncodeToString(hash[:])+".cache"), nil
+}
+
+// tokenCacheEncryptionKey derives the encryption key from the domain, user and secret.
+// This way, only someone with access to the secret can use the cached tokens and changing the secret invalidates the cache.
+func tokenCacheEncryptionKey(config *types.BaseConfig) []byte {
+	key := sha256.Sum256([]byte(strings.ToLower(config.Domain) + "|" + config.ApiUser + "|" + config.ApiSecret))
+
+	return key[:]
+}
+
+func encryptTokens(key []byte, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+func decryptTokens(key []byte, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("invalid token cache content")
+	}
+
+	nonce, cipherText := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+
+	return gcm.Open(nil, nonce, cipherText, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
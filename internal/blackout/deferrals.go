@@ -0,0 +1,226 @@
+package blackout
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// Deferral is a sync of a target that was not executed because of a blackout window.
+// It should be executed once the window ends.
+type Deferral struct {
+	Target   string    `json:"target"`
+	SyncType string    `json:"syncType"`
+	Window   string    `json:"window"`
+	Until    time.Time `json:"until"`
+}
+
+type deferralKey struct {
+	target   string
+	syncType string
+}
+
+var deferrals = struct {
+	m       sync.Mutex
+	enabled bool
+	path    string
+	items   map[deferralKey]Deferral
+}{
+	items: make(map[deferralKey]Deferral),
+}
+
+// EnableDeferrals allows syncs to be deferred until their blackout window ends. This is only done by the daemon, which executes them once the window ends.
+// If a path is given, the deferrals are persisted in that file so they survive a restart of the daemon. The deferrals that are already in the file are restored.
+func EnableDeferrals(path string) error {
+	deferrals.m.Lock()
+	defer deferrals.m.Unlock()
+
+	deferrals.enabled = true
+	deferrals.path = path
+
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("read deferred syncs from %q: %w", path, err)
+	}
+
+	var restored []Deferral
+
+	err = json.Unmarshal(data, &restored)
+	if err != nil {
+		return fmt.Errorf("parse deferred syncs in %q: %w", path, err)
+	}
+
+	for _, deferral := range restored {
+		deferrals.items[deferralKey{target: deferral.Target, syncType: deferral.SyncType}] = deferral
+	}
+
+	return nil
+}
+
+// DisableDeferrals stops deferring syncs. The deferred syncs are forgotten, but are kept in the file they were persisted in.
+func DisableDeferrals() {
+	deferrals.m.Lock()
+	defer deferrals.m.Unlock()
+
+	deferrals.enabled = false
+	deferrals.path = ""
+	deferrals.items = make(map[deferralKey]Deferral)
+}
+
+// DeferralsEnabled returns whether syncs can be deferred until their blackout window ends. If not, the syncs should be skipped instead.
+func DeferralsEnabled() bool {
+	deferrals.m.Lock()
+	defer deferrals.m.Unlock()
+
+	return deferrals.enabled
+}
+
+// Defer registers a sync that was deferred because of a blackout window.
+func Defer(deferral Deferral) {
+	deferrals.m.Lock()
+	defer deferrals.m.Unlock()
+
+	deferrals.items[deferralKey{target: deferral.Target, syncType: deferral.SyncType}] = deferral
+
+	persist()
+}
+
+// Executed removes the deferral of the sync type of the target, as it was executed in the meantime.
+func Executed(target, syncType string) {
+	deferrals.m.Lock()
+	defer deferrals.m.Unlock()
+
+	key := deferralKey{target: target, syncType: syncType}
+	if _, found := deferrals.items[key]; !found {
+		return
+	}
+
+	delete(deferrals.items, key)
+
+	persist()
+}
+
+// NextDue returns the moment the first deferred sync can be executed. Returns false if there are no deferred syncs.
+func NextDue() (time.Time, bool) {
+	deferrals.m.Lock()
+	defer deferrals.m.Unlock()
+
+	var next time.Time
+
+	for _, deferral := range deferrals.items {
+		if next.IsZero() || deferral.Until.Before(next) {
+			next = deferral.Until
+		}
+	}
+
+	return next, !next.IsZero()
+}
+
+// TakeDue removes and returns the deferred syncs whose blackout window ended at the given time.
+// The deferred syncs are removed from the persisted file as well, so they should be queued in a way that survives a restart.
+func TakeDue(now time.Time) []Deferral {
+	deferrals.m.Lock()
+	defer deferrals.m.Unlock()
+
+	var due []Deferral
+
+	for key, deferral := range deferrals.items {
+		if !deferral.Until.After(now) {
+			due = append(due, deferral)
+			delete(deferrals.items, key)
+		}
+	}
+
+	if len(due) > 0 {
+		persist()
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].Target != due[j].Target {
+			return due[i].Target < due[j].Target
+		}
+
+		return due[i].SyncType < due[j].SyncType
+	})
+
+	return due
+}
+
+// Pending returns the deferred syncs that are waiting for their blackout window to end.
+func Pending() []Deferral {
+	deferrals.m.Lock()
+	defer deferrals.m.Unlock()
+
+	return pending()
+}
+
+func pending() []Deferral {
+	result := make([]Deferral, 0, len(deferrals.items))
+
+	for _, deferral := range deferrals.items {
+		result = append(result, deferral)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Until.Equal(result[j].Until) {
+			return result[i].Until.Before(result[j].Until)
+		}
+
+		return result[i].Target < result[j].Target
+	})
+
+	return result
+}
+
+// persist writes the deferred syncs to the file, if any. The caller needs to hold the lock.
+// Failing to persist is only logged, as the deferred syncs are still executed if the daemon keeps running.
+func persist() {
+	if deferrals.path == "" {
+		return
+	}
+
+	err := writeDeferrals(deferrals.path, pending())
+	if err != nil {
+		hclog.L().Warn(fmt.Sprintf("Deferred syncs will not survive a restart: %s", err.Error()))
+	}
+}
+
+// writeDeferrals replaces the file with the given deferred syncs. A temporary file is renamed, so the file is never left half written.
+func writeDeferrals(path string, items []Deferral) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("marshal deferred syncs: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return fmt.Errorf("create directory for deferred syncs: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+
+	err = os.WriteFile(tmpPath, data, 0600)
+	if err != nil {
+		return fmt.Errorf("write deferred syncs to %q: %w", tmpPath, err)
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return fmt.Errorf("write deferred syncs to %q: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package blackout
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/constants"
+)
+
+func TestDeferrals_Persisted(t *testing.T) {
+	t.Cleanup(DisableDeferrals)
+
+	path := filepath.Join(t.TempDir(), "deferred-syncs.json")
+	until := time.Date(2024, 12, 27, 8, 30, 0, 0, time.UTC)
+
+	assert.False(t, DeferralsEnabled())
+
+	require.NoError(t, EnableDeferrals(path))
+	assert.True(t, DeferralsEnabled())
+
+	Defer(Deferral{Target: "snowflake1", SyncType: constants.DataAccessSync, Window: "month-end", Until: until})
+	Defer(Deferral{Target: "snowflake1", SyncType: constants.DataUsageSync, Window: "month-end", Until: until})
+	Executed("snowflake1", constants.DataUsageSync)
+
+	// The deferrals are restored after a restart of the daemon
+	DisableDeferrals()
+	assert.Empty(t, Pending())
+
+	require.NoError(t, EnableDeferrals(path))
+
+	pending := Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, "snowflake1", pending[0].Target)
+	assert.Equal(t, constants.DataAccessSync, pending[0].SyncType)
+	assert.True(t, until.Equal(pending[0].Until))
+
+	// Deferrals that are due are no longer persisted
+	assert.Len(t, TakeDue(until), 1)
+
+	DisableDeferrals()
+	require.NoError(t, EnableDeferrals(path))
+	assert.Empty(t, Pending())
+}
+
+func TestDeferrals_InMemory(t *testing.T) {
+	t.Cleanup(DisableDeferrals)
+
+	require.NoError(t, EnableDeferrals(""))
+
+	until := time.Now().Add(time.Hour)
+	Defer(Deferral{Target: "snowflake1", SyncType: constants.DataAccessSync, Window: "month-end", Until: until})
+
+	next, found := NextDue()
+	assert.True(t, found)
+	assert.True(t, until.Equal(next))
+
+	assert.Empty(t, TakeDue(time.Now()))
+	assert.Len(t, TakeDue(until), 1)
+
+	_, found = NextDue()
+	assert.False(t, found)
+}
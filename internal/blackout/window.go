@@ -0,0 +1,310 @@
+package blackout
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/raito-io/cli/internal/constants"
+)
+
+const (
+	SyncTypeAll = "all"
+
+	calendarDateFormat     = "2006-01-02"
+	calendarDateTimeFormat = "2006-01-02T15:04"
+
+	// maxChainedWindows limits the number of overlapping windows that are followed to find the end of a blackout
+	maxChainedWindows = 100
+)
+
+// syncTypes maps the sync type names that can be used in the configuration to the internal sync types.
+var syncTypes = map[string]string{
+	"data-source":       constants.DataSourceSync,
+	"identity-store":    constants.IdentitySync,
+	"access":            constants.DataAccessSync,
+	"usage":             constants.DataUsageSync,
+	"resource-provider": constants.ResourceProviderSync,
+	"tag":               constants.TagSync,
+}
+
+// Window is a period in which (some types of) syncs are not allowed to run.
+// A window is either recurring (a cron expression defining the start of the window and a duration) or a calendar range (from - to).
+type Window struct {
+	Name string
+
+	Cron     string
+	Duration time.Duration
+
+	From time.Time
+	To   time.Time
+
+	Location *time.Location
+
+	// SyncTypes contains the internal sync types that are blocked during the window.
+	SyncTypes []string
+
+	schedule cron.Schedule
+}
+
+// ParseWindows parses the blackout windows as defined in the configuration file.
+//
+//	blackout-windows:
+//	  - name: month-end-close
+//	    cron: "0 0 28 * *"
+//	    duration: 72h
+//	    timezone: Europe/Brussels
+//	  - name: migration
+//	    from: 2024-12-24T00:00
+//	    to: 2024-12-27
+//	    sync-types: [all]
+//
+// By default, only the access sync (which pushes access to the data source) is blocked.
+func ParseWindows(value interface{}) ([]Window, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	windowList, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the blackout windows should be defined as a list (%v)", value)
+	}
+
+	windows := make([]Window, 0, len(windowList))
+
+	for i, windowObj := range windowList {
+		windowMap, ok := windowObj.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("the blackout window definition could not be parsed correctly (%v)", windowObj)
+		}
+
+		window, err := parseWindow(windowMap)
+		if err != nil {
+			name := fmt.Sprintf("%d", i+1)
+			if n, found := windowMap["name"]; found {
+				name = fmt.Sprintf("%q", n)
+			}
+
+			return nil, fmt.Errorf("blackout window %s: %w", name, err)
+		}
+
+		if window.Name == "" {
+			window.Name = fmt.Sprintf("%d", i+1)
+		}
+
+		windows = append(windows, *window)
+	}
+
+	return windows, nil
+}
+
+func parseWindow(windowMap map[string]interface{}) (*Window, error) {
+	window := Window{
+		Name:     stringValue(windowMap, "name"),
+		Cron:     stringValue(windowMap, "cron"),
+		Location: time.Local,
+	}
+
+	if timezone := stringValue(windowMap, "timezone"); timezone != "" {
+		location, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+		}
+
+		window.Location = location
+	}
+
+	from, hasFrom := windowMap["from"]
+	to, hasTo := windowMap["to"]
+
+	switch {
+	case window.Cron != "" && (hasFrom || hasTo):
+		return nil, errors.New("either a cron expression or a from-to range should be defined, not both")
+	case window.Cron != "":
+		schedule, err := cron.ParseStandard(window.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", window.Cron, err)
+		}
+
+		duration, err := time.ParseDuration(stringValue(windowMap, "duration"))
+		if err != nil || duration <= 0 {
+			return nil, errors.New("a positive duration (e.g. '4h') is required for a recurring blackout window")
+		}
+
+		window.schedule = schedule
+		window.Duration = duration
+	case hasFrom && hasTo:
+		var err error
+
+		window.From, err = parseCalendarTime(from, window.Location)
+		if err != nil {
+			return nil, err
+		}
+
+		window.To, err = parseCalendarTime(to, window.Location)
+		if err != nil {
+			return nil, err
+		}
+
+		if !window.To.After(window.From) {
+			return nil, fmt.Errorf("the end of the window (%s) should be after the start (%s)", window.To, window.From)
+		}
+	default:
+		return nil, errors.New("either a cron expression and duration, or a from-to range should be defined")
+	}
+
+	configuredSyncTypes, err := stringListValue(windowMap, "sync-types")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(configuredSyncTypes) == 0 {
+		configuredSyncTypes = []string{"access"}
+	}
+
+	for _, syncType := range configuredSyncTypes {
+		if syncType == SyncTypeAll {
+			for _, st := range syncTypes {
+				window.SyncTypes = append(window.SyncTypes, st)
+			}
+
+			continue
+		}
+
+		st, found := syncTypes[syncType]
+		if !found {
+			return nil, fmt.Errorf("unknown sync type %q", syncType)
+		}
+
+		window.SyncTypes = append(window.SyncTypes, st)
+	}
+
+	return &window, nil
+}
+
+// Blocks returns true if the given (internal) sync type is not allowed to run during the window.
+func (w *Window) Blocks(syncType string) bool {
+	for _, st := range w.SyncTypes {
+		if st == syncType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ActiveAt returns true if the window is active at the given time, together with the end of the window.
+func (w *Window) ActiveAt(t time.Time) (bool, time.Time) {
+	if w.schedule == nil {
+		if !t.Before(w.From) && t.Before(w.To) {
+			return true, w.To
+		}
+
+		return false, time.Time{}
+	}
+
+	// The window is active if it started during the last 'duration'
+	start := w.schedule.Next(t.In(w.Location).Add(-w.Duration))
+	if start.After(t) {
+		return false, time.Time{}
+	}
+
+	return true, start.Add(w.Duration)
+}
+
+// ActiveWindow returns the window blocking the given sync type at the given time and the moment the sync type is no longer blocked.
+// Returns nil if the sync type is not blocked.
+func ActiveWindow(windows []Window, syncType string, t time.Time) (*Window, time.Time) {
+	activeWindow, until := activeWindowAt(windows, syncType, t)
+	if activeWindow == nil {
+		return nil, time.Time{}
+	}
+
+	// Windows can overlap, so making sure the sync type is also not blocked by another window when this one ends
+	for i := 0; i < maxChainedWindows; i++ {
+		next, nextUntil := activeWindowAt(windows, syncType, until)
+		if next == nil {
+			break
+		}
+
+		until = nextUntil
+	}
+
+	return activeWindow, until
+}
+
+func activeWindowAt(windows []Window, syncType string, t time.Time) (*Window, time.Time) {
+	var activeWindow *Window
+
+	var until time.Time
+
+	for i := range windows {
+		if !windows[i].Blocks(syncType) {
+			continue
+		}
+
+		if active, end := windows[i].ActiveAt(t); active && end.After(until) {
+			activeWindow = &windows[i]
+			until = end
+		}
+	}
+
+	return activeWindow, until
+}
+
+func parseCalendarTime(value interface{}, location *time.Location) (time.Time, error) {
+	// The YAML parser already converts unquoted dates
+	if t, ok := value.(time.Time); ok {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, location), nil
+	}
+
+	for _, format := range []string{calendarDateTimeFormat, calendarDateFormat} {
+		t, err := time.ParseInLocation(format, strings.TrimSpace(fmt.Sprintf("%v", value)), location)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date \"%v\": expected format %q or %q", value, calendarDateTimeFormat, calendarDateFormat)
+}
+
+func stringValue(m map[string]interface{}, key string) string {
+	if v, found := m[key]; found && v != nil {
+		return strings.TrimSpace(fmt.Sprintf("%v", v))
+	}
+
+	return ""
+}
+
+func stringListValue(m map[string]interface{}, key string) ([]string, error) {
+	v, found := m[key]
+	if !found || v == nil {
+		return nil, nil
+	}
+
+	switch list := v.(type) {
+	case string:
+		var result []string
+
+		for _, item := range strings.Split(list, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+
+		return result, nil
+	case []interface{}:
+		result := make([]string, 0, len(list))
+
+		for _, item := range list {
+			result = append(result, strings.TrimSpace(fmt.Sprintf("%v", item)))
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("%q should be a list", key)
+	}
+}
@@ -0,0 +1,150 @@
+package blackout
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/constants"
+)
+
+func TestParseWindows(t *testing.T) {
+	windows, err := ParseWindows([]interface{}{
+		map[string]interface{}{
+			"name":     "weekly-maintenance",
+			"cron":     "0 22 * * SAT",
+			"duration": "4h",
+			"timezone": "Europe/Brussels",
+		},
+		map[string]interface{}{
+			"from":       time.Date(2024, 12, 24, 0, 0, 0, 0, time.UTC),
+			"to":         "2024-12-27T08:30",
+			"sync-types": []interface{}{"access", "usage"},
+		},
+		map[string]interface{}{
+			"from":       "2025-01-01",
+			"to":         "2025-01-02",
+			"sync-types": "all",
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, windows, 3)
+
+	brussels, err := time.LoadLocation("Europe/Brussels")
+	require.NoError(t, err)
+
+	assert.Equal(t, "weekly-maintenance", windows[0].Name)
+	assert.Equal(t, 4*time.Hour, windows[0].Duration)
+	assert.Equal(t, brussels, windows[0].Location)
+	assert.Equal(t, []string{constants.DataAccessSync}, windows[0].SyncTypes)
+
+	assert.Equal(t, "2", windows[1].Name)
+	assert.Equal(t, time.Date(2024, 12, 24, 0, 0, 0, 0, time.Local), windows[1].From)
+	assert.Equal(t, time.Date(2024, 12, 27, 8, 30, 0, 0, time.Local), windows[1].To)
+	assert.Equal(t, []string{constants.DataAccessSync, constants.DataUsageSync}, windows[1].SyncTypes)
+
+	assert.Len(t, windows[2].SyncTypes, len(syncTypes))
+}
+
+func TestParseWindows_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		window map[string]interface{}
+		err    string
+	}{
+		{name: "no period", window: map[string]interface{}{"name": "w"}, err: "either a cron expression and duration"},
+		{name: "cron and range", window: map[string]interface{}{"cron": "0 0 * * *", "duration": "1h", "from": "2024-01-01", "to": "2024-01-02"}, err: "not both"},
+		{name: "invalid cron", window: map[string]interface{}{"cron": "every day", "duration": "1h"}, err: "invalid cron expression"},
+		{name: "no duration", window: map[string]interface{}{"cron": "0 0 * * *"}, err: "positive duration"},
+		{name: "invalid date", window: map[string]interface{}{"from": "24/12/2024", "to": "2024-12-27"}, err: "invalid date"},
+		{name: "end before start", window: map[string]interface{}{"from": "2024-12-27", "to": "2024-12-24"}, err: "should be after the start"},
+		{name: "invalid timezone", window: map[string]interface{}{"from": "2024-12-24", "to": "2024-12-27", "timezone": "Mars/Olympus"}, err: "invalid timezone"},
+		{name: "invalid sync type", window: map[string]interface{}{"from": "2024-12-24", "to": "2024-12-27", "sync-types": []interface{}{"grants"}}, err: `unknown sync type "grants"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWindows([]interface{}{tt.window})
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestWindow_ActiveAt(t *testing.T) {
+	windows, err := ParseWindows([]interface{}{
+		map[string]interface{}{"cron": "0 22 * * SAT", "duration": "4h", "timezone": "Europe/Brussels"},
+	})
+	require.NoError(t, err)
+
+	window := windows[0]
+	brussels := window.Location
+
+	active, _ := window.ActiveAt(time.Date(2024, 6, 1, 21, 59, 0, 0, brussels))
+	assert.False(t, active)
+
+	active, until := window.ActiveAt(time.Date(2024, 6, 1, 22, 0, 0, 0, brussels))
+	assert.True(t, active)
+	assert.True(t, time.Date(2024, 6, 2, 2, 0, 0, 0, brussels).Equal(until))
+
+	// Same moment, expressed in UTC
+	active, _ = window.ActiveAt(time.Date(2024, 6, 2, 1, 30, 0, 0, time.UTC))
+	assert.False(t, active)
+
+	active, _ = window.ActiveAt(time.Date(2024, 6, 1, 23, 30, 0, 0, time.UTC))
+	assert.True(t, active)
+
+	active, _ = window.ActiveAt(time.Date(2024, 6, 2, 2, 0, 0, 0, brussels))
+	assert.False(t, active)
+}
+
+func TestActiveWindow(t *testing.T) {
+	windows, err := ParseWindows([]interface{}{
+		map[string]interface{}{"name": "first", "from": "2024-12-24T00:00", "to": "2024-12-25T00:00"},
+		map[string]interface{}{"name": "second", "from": "2024-12-24T12:00", "to": "2024-12-26T00:00", "sync-types": "access,usage"},
+		map[string]interface{}{"name": "third", "from": "2024-12-26T00:00", "to": "2024-12-27T00:00"},
+	})
+	require.NoError(t, err)
+
+	window, until := ActiveWindow(windows, constants.DataAccessSync, time.Date(2024, 12, 24, 6, 0, 0, 0, time.Local))
+	require.NotNil(t, window)
+	assert.Equal(t, "first", window.Name)
+	assert.Equal(t, time.Date(2024, 12, 27, 0, 0, 0, 0, time.Local), until)
+
+	window, until = ActiveWindow(windows, constants.DataUsageSync, time.Date(2024, 12, 24, 13, 0, 0, 0, time.Local))
+	require.NotNil(t, window)
+	assert.Equal(t, "second", window.Name)
+	assert.Equal(t, time.Date(2024, 12, 26, 0, 0, 0, 0, time.Local), until)
+
+	window, _ = ActiveWindow(windows, constants.DataUsageSync, time.Date(2024, 12, 24, 6, 0, 0, 0, time.Local))
+	assert.Nil(t, window)
+
+	window, _ = ActiveWindow(windows, constants.DataSourceSync, time.Date(2024, 12, 24, 13, 0, 0, 0, time.Local))
+	assert.Nil(t, window)
+}
+
+func TestDeferrals(t *testing.T) {
+	now := time.Now()
+
+	Defer(Deferral{Target: "snowflake", SyncType: constants.DataAccessSync, Window: "w", Until: now.Add(time.Hour)})
+	Defer(Deferral{Target: "bigquery", SyncType: constants.DataAccessSync, Window: "w", Until: now.Add(time.Minute)})
+	Defer(Deferral{Target: "bigquery", SyncType: constants.DataUsageSync, Window: "w", Until: now.Add(2 * time.Hour)})
+
+	Executed("bigquery", constants.DataUsageSync)
+
+	next, found := NextDue()
+	assert.True(t, found)
+	assert.Equal(t, now.Add(time.Minute), next)
+	assert.Len(t, Pending(), 2)
+
+	assert.Empty(t, TakeDue(now))
+
+	due := TakeDue(now.Add(time.Hour))
+	require.Len(t, due, 2)
+	assert.Equal(t, "bigquery", due[0].Target)
+	assert.Equal(t, "snowflake", due[1].Target)
+
+	_, found = NextDue()
+	assert.False(t, found)
+}
@@ -2,35 +2,103 @@ package clitrigger
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
 	"sync"
+	"time"
 
-	"github.com/raito-io/golang-set/set"
+	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/viper"
 
 	"github.com/raito-io/cli/internal/constants"
 )
 
+// ApUpdateTriggerHandler queues the access provider updates per data source.
+// Updates for the same data source are debounced: the data source is only handed out once no new updates were received during the debounce window,
+// or when the maximum delay since the first queued update has passed. All data sources that are ready are coalesced into one update.
 type ApUpdateTriggerHandler struct {
-	queuedDataSources set.Set[string]
+	queuedDataSources map[string]*queuedDataSource
 	apUpdateQueue     []ApUpdate
 
+	debounce time.Duration
+	maxDelay time.Duration
+
 	m sync.Mutex
 
+	logger hclog.Logger
+	wal    *queueWal[ApUpdate]
+	now    func() time.Time
+	timer  *time.Timer
+	closed bool
+
+	// inFlight are the updates (one per data source) that were handed out by Pop, but are not Done yet
+	inFlight   []ApUpdate
+	statistics QueueStatistics
+
 	outputChan chan struct{}
 }
 
+type queuedDataSource struct {
+	firstEvent time.Time
+	lastEvent  time.Time
+	events     int
+}
+
 func NewApUpdateTriggerHandler() *ApUpdateTriggerHandler {
 	targets := len(viper.Get(constants.Targets).([]interface{}))
 
 	h := &ApUpdateTriggerHandler{
-		queuedDataSources: set.NewSet[string](),
+		queuedDataSources: make(map[string]*queuedDataSource),
 		apUpdateQueue:     make([]ApUpdate, 0, targets),
+		debounce:          viper.GetDuration(constants.ApUpdateDebounceFlag),
+		maxDelay:          viper.GetDuration(constants.ApUpdateMaxDelayFlag),
+		logger:            hclog.NewNullLogger(),
+		now:               time.Now,
 		outputChan:        make(chan struct{}, 1),
 	}
 
 	return h
 }
 
+// NewDurableApUpdateTriggerHandler creates an ApUpdateTriggerHandler that persists its queue in the given directory.
+// The access provider updates that were still queued when the CLI stopped are queued again, without waiting for the debounce window.
+func NewDurableApUpdateTriggerHandler(logger hclog.Logger, queueDir string) (*ApUpdateTriggerHandler, error) {
+	h := NewApUpdateTriggerHandler()
+	h.logger = logger
+
+	path := filepath.Join(queueDir, apUpdateQueueWalFile)
+
+	lock, err := lockQueueWal(path)
+	if err != nil {
+		return nil, err
+	}
+
+	replayed, err := replayQueueWal[ApUpdate](logger, path)
+	if err != nil {
+		lock.Close()
+
+		return nil, err
+	}
+
+	for i := range replayed {
+		h.enqueue(&replayed[i], time.Time{})
+	}
+
+	h.wal, err = openQueueWal(path, h.apUpdateQueue, lock)
+	if err != nil {
+		return nil, err
+	}
+
+	h.statistics.Replayed = len(h.apUpdateQueue)
+
+	if len(h.apUpdateQueue) > 0 {
+		logger.Info(fmt.Sprintf("Restored %d queued access provider update(s) from %s", len(h.apUpdateQueue), path))
+		h.notifyChannel()
+	}
+
+	return h, nil
+}
+
 func (h *ApUpdateTriggerHandler) HandleTriggerEvent(_ context.Context, triggerEvent *TriggerEvent) {
 	if triggerEvent.ApUpdate == nil {
 		return
@@ -39,53 +107,227 @@ func (h *ApUpdateTriggerHandler) HandleTriggerEvent(_ context.Context, triggerEv
 	h.m.Lock()
 	defer h.m.Unlock()
 
-	apUpdate := ApUpdate{
-		Domain: triggerEvent.ApUpdate.Domain,
-	}
+	added := h.enqueue(triggerEvent.ApUpdate, h.now())
+	h.statistics.Received += len(added)
 
-	for _, dataSource := range triggerEvent.ApUpdate.DataSourceNames {
-		if !h.queuedDataSources.Contains(dataSource) {
-			apUpdate.DataSourceNames = append(apUpdate.DataSourceNames, dataSource)
-			h.queuedDataSources.Add(dataSource)
+	if h.wal != nil {
+		for i := range added {
+			err := h.wal.push(&added[i])
+			if err != nil {
+				h.logger.Warn(fmt.Sprintf("Access provider update will not survive a restart: %s", err.Error()))
+			}
 		}
 	}
 
-	if len(apUpdate.DataSourceNames) > 0 {
+	h.logger.Debug(fmt.Sprintf("Received access provider update for data sources %v. %d data source(s) in queue", triggerEvent.ApUpdate.DataSourceNames, len(h.apUpdateQueue)))
+
+	h.scheduleNotification()
+}
+
+// enqueue registers an update event for the data sources of the given update at the given time and returns the updates that were added to the queue.
+// Every update in the queue contains a single data source. Data sources that are already queued are not added again.
+func (h *ApUpdateTriggerHandler) enqueue(update *ApUpdate, eventTime time.Time) []ApUpdate {
+	var added []ApUpdate
+
+	for _, dataSource := range update.DataSourceNames {
+		if queued, found := h.queuedDataSources[dataSource]; found {
+			queued.lastEvent = eventTime
+			queued.events++
+
+			continue
+		}
+
+		h.queuedDataSources[dataSource] = &queuedDataSource{
+			firstEvent: eventTime,
+			lastEvent:  eventTime,
+			events:     1,
+		}
+
+		apUpdate := ApUpdate{
+			Domain:          update.Domain,
+			DataSourceNames: []string{dataSource},
+		}
+
 		h.apUpdateQueue = append(h.apUpdateQueue, apUpdate)
-		h.notifyChannel()
+		added = append(added, apUpdate)
 	}
+
+	return added
 }
 
 func (h *ApUpdateTriggerHandler) Close() {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.closed = true
+
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+
 	close(h.outputChan)
+
+	if h.wal != nil {
+		err := h.wal.close()
+		if err != nil {
+			h.logger.Warn(fmt.Sprintf("Failed to close access provider update queue: %s", err.Error()))
+		}
+	}
 }
 
+// Pop returns one update containing all the queued data sources that are ready to be synced, or nil if there are none.
 func (h *ApUpdateTriggerHandler) Pop() *ApUpdate {
 	h.m.Lock()
 	defer h.m.Unlock()
 
-	if len(h.apUpdateQueue) == 0 {
-		return nil
+	now := h.now()
+
+	var apUpdate *ApUpdate
+
+	events := 0
+	remaining := make([]ApUpdate, 0, len(h.apUpdateQueue))
+
+	for i := range h.apUpdateQueue {
+		queuedUpdate := h.apUpdateQueue[i]
+		dataSource := queuedUpdate.DataSourceNames[0]
+		queued := h.queuedDataSources[dataSource]
+
+		if !h.isReady(queued, now) || (apUpdate != nil && apUpdate.Domain != queuedUpdate.Domain) {
+			remaining = append(remaining, queuedUpdate)
+
+			continue
+		}
+
+		if apUpdate == nil {
+			apUpdate = &ApUpdate{Domain: queuedUpdate.Domain}
+		}
+
+		apUpdate.DataSourceNames = append(apUpdate.DataSourceNames, dataSource)
+		events += queued.events
+
+		delete(h.queuedDataSources, dataSource)
+
+		h.inFlight = append(h.inFlight, queuedUpdate)
 	}
 
-	apUpdate := h.apUpdateQueue[0]
-	h.apUpdateQueue = h.apUpdateQueue[1:]
+	h.apUpdateQueue = remaining
 
-	for _, dataSource := range apUpdate.DataSourceNames {
-		h.queuedDataSources.Remove(dataSource)
+	if apUpdate != nil && events > 1 {
+		h.logger.Info(fmt.Sprintf("Merged %d access provider update event(s) into one update for %d data source(s)", events, len(apUpdate.DataSourceNames)))
 	}
 
-	if len(h.apUpdateQueue) > 0 {
-		h.notifyChannel()
+	h.scheduleNotification()
+
+	return apUpdate
+}
+
+// Done marks the update that was returned by Pop as handled, so it is not handled again after a restart.
+// Updates that are popped but never marked as done are queued again when the CLI restarts.
+func (h *ApUpdateTriggerHandler) Done(apUpdate *ApUpdate) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	for _, dataSource := range apUpdate.DataSourceNames {
+		for i := range h.inFlight {
+			if h.inFlight[i].Domain != apUpdate.Domain || h.inFlight[i].DataSourceNames[0] != dataSource {
+				continue
+			}
+
+			if h.wal != nil {
+				err := h.wal.pop(&h.inFlight[i])
+				if err != nil {
+					h.logger.Warn(fmt.Sprintf("Access provider update may be handled again after a restart: %s", err.Error()))
+				}
+			}
+
+			h.inFlight = append(h.inFlight[:i], h.inFlight[i+1:]...)
+			h.statistics.Handled++
+
+			break
+		}
 	}
 
-	return &apUpdate
+	h.logger.Debug(fmt.Sprintf("Handled access provider update for data sources %v. %d data source(s) in queue", apUpdate.DataSourceNames, len(h.apUpdateQueue)))
+}
+
+// Statistics returns the statistics of the access provider update queue.
+func (h *ApUpdateTriggerHandler) Statistics() QueueStatistics {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	statistics := h.statistics
+	statistics.Depth = len(h.apUpdateQueue)
+	statistics.InFlight = len(h.inFlight)
+
+	return statistics
+}
+
+// Queued returns a copy of the access provider updates that are waiting to be handled.
+func (h *ApUpdateTriggerHandler) Queued() []ApUpdate {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	return append([]ApUpdate{}, h.apUpdateQueue...)
 }
 
 func (h *ApUpdateTriggerHandler) TriggerChannel() <-chan struct{} {
 	return h.outputChan
 }
 
+func (h *ApUpdateTriggerHandler) isReady(queued *queuedDataSource, now time.Time) bool {
+	return !now.Before(h.readyAt(queued))
+}
+
+// readyAt returns the moment the data source can be synced: after the debounce window without new events, but never later than the maximum delay.
+func (h *ApUpdateTriggerHandler) readyAt(queued *queuedDataSource) time.Time {
+	readyAt := queued.lastEvent.Add(h.debounce)
+
+	if h.maxDelay > 0 {
+		deadline := queued.firstEvent.Add(h.maxDelay)
+		if deadline.Before(readyAt) {
+			return deadline
+		}
+	}
+
+	return readyAt
+}
+
+// scheduleNotification notifies the trigger channel when the first data source in the queue is ready. Should be called while holding the lock.
+func (h *ApUpdateTriggerHandler) scheduleNotification() {
+	if h.closed || len(h.queuedDataSources) == 0 {
+		return
+	}
+
+	var next time.Time
+
+	for _, queued := range h.queuedDataSources {
+		readyAt := h.readyAt(queued)
+		if next.IsZero() || readyAt.Before(next) {
+			next = readyAt
+		}
+	}
+
+	wait := next.Sub(h.now())
+	if wait <= 0 {
+		h.notifyChannel()
+
+		return
+	}
+
+	if h.timer != nil {
+		h.timer.Stop()
+	}
+
+	h.timer = time.AfterFunc(wait, func() {
+		h.m.Lock()
+		defer h.m.Unlock()
+
+		if !h.closed {
+			h.notifyChannel()
+		}
+	})
+}
+
 func (h *ApUpdateTriggerHandler) notifyChannel() {
 	select {
 	case h.outputChan <- struct{}{}:
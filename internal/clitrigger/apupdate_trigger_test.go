@@ -0,0 +1,113 @@
+package clitrigger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDebouncingApUpdateTriggerHandler(t *testing.T, debounce, maxDelay time.Duration) (*ApUpdateTriggerHandler, *time.Time) {
+	t.Helper()
+
+	setupQueueWalTest(t)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	h := NewApUpdateTriggerHandler()
+	h.debounce = debounce
+	h.maxDelay = maxDelay
+	h.now = func() time.Time { return now }
+
+	t.Cleanup(h.Close)
+
+	return h, &now
+}
+
+func apUpdateEvent(dataSources ...string) *TriggerEvent {
+	return &TriggerEvent{ApUpdate: &ApUpdate{Domain: "domain", DataSourceNames: dataSources}}
+}
+
+func TestApUpdateTriggerHandler_NoDebounce(t *testing.T) {
+	h, _ := newDebouncingApUpdateTriggerHandler(t, 0, 0)
+	ctx := context.Background()
+
+	h.HandleTriggerEvent(ctx, apUpdateEvent("ds1"))
+	h.HandleTriggerEvent(ctx, apUpdateEvent("ds2", "ds1"))
+
+	assert.Len(t, h.TriggerChannel(), 1)
+
+	apUpdate := h.Pop()
+	require.NotNil(t, apUpdate)
+	assert.Equal(t, &ApUpdate{Domain: "domain", DataSourceNames: []string{"ds1", "ds2"}}, apUpdate)
+
+	assert.Nil(t, h.Pop())
+}
+
+func TestApUpdateTriggerHandler_Debounce(t *testing.T) {
+	h, now := newDebouncingApUpdateTriggerHandler(t, 30*time.Second, 5*time.Minute)
+	ctx := context.Background()
+
+	h.HandleTriggerEvent(ctx, apUpdateEvent("ds1"))
+	h.HandleTriggerEvent(ctx, apUpdateEvent("ds2"))
+
+	*now = now.Add(20 * time.Second)
+	h.HandleTriggerEvent(ctx, apUpdateEvent("ds1"))
+
+	assert.Nil(t, h.Pop())
+
+	// ds2 was quiet for 30 seconds, ds1 got a new update 10 seconds ago
+	*now = now.Add(10 * time.Second)
+
+	apUpdate := h.Pop()
+	require.NotNil(t, apUpdate)
+	assert.Equal(t, []string{"ds2"}, apUpdate.DataSourceNames)
+
+	*now = now.Add(20 * time.Second)
+
+	apUpdate = h.Pop()
+	require.NotNil(t, apUpdate)
+	assert.Equal(t, []string{"ds1"}, apUpdate.DataSourceNames)
+	assert.Empty(t, h.Queued())
+}
+
+func TestApUpdateTriggerHandler_MaxDelay(t *testing.T) {
+	h, now := newDebouncingApUpdateTriggerHandler(t, 30*time.Second, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 6; i++ {
+		h.HandleTriggerEvent(ctx, apUpdateEvent("ds1", "ds2"))
+		assert.Nil(t, h.Pop())
+
+		*now = now.Add(10 * time.Second)
+	}
+
+	// Still receiving updates, but the maximum delay is reached
+	apUpdate := h.Pop()
+	require.NotNil(t, apUpdate)
+	assert.Equal(t, []string{"ds1", "ds2"}, apUpdate.DataSourceNames)
+}
+
+func TestApUpdateTriggerHandler_NotifiesWhenReady(t *testing.T) {
+	setupQueueWalTest(t)
+
+	h := NewApUpdateTriggerHandler()
+	h.debounce = 20 * time.Millisecond
+
+	defer h.Close()
+
+	h.HandleTriggerEvent(context.Background(), apUpdateEvent("ds1"))
+	assert.Len(t, h.TriggerChannel(), 0)
+
+	select {
+	case <-h.TriggerChannel():
+	case <-time.After(time.Second):
+		require.Fail(t, "not notified after the debounce window")
+	}
+
+	apUpdate := h.Pop()
+	require.NotNil(t, apUpdate)
+	assert.Equal(t, []string{"ds1"}, apUpdate.DataSourceNames)
+}
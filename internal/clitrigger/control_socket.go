@@ -0,0 +1,192 @@
+package clitrigger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/raito-io/cli/internal/blackout"
+	"github.com/raito-io/cli/internal/graphql"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+const controlSocketDialTimeout = 2 * time.Second
+
+// DaemonStatus describes the state of a running daemon (a 'raito run' with a frequency or cron expression).
+type DaemonStatus struct {
+	Pid                int           `json:"pid"`
+	StartedAt          time.Time     `json:"startedAt"`
+	Iteration          int           `json:"iteration"`
+	RunningTargets     []string      `json:"runningTargets,omitempty"`
+	QueuedSyncTriggers []SyncTrigger `json:"queuedSyncTriggers"`
+	QueuedApUpdates    []ApUpdate    `json:"queuedApUpdates"`
+
+	SyncTriggerQueue QueueStatistics `json:"syncTriggerQueue"`
+	ApUpdateQueue    QueueStatistics `json:"apUpdateQueue"`
+
+	// DeferredSyncs are the syncs waiting for a blackout window to end
+	DeferredSyncs []blackout.Deferral `json:"deferredSyncs"`
+
+	// ApiStatistics are the counters of the requests sent to the Raito API since the daemon started
+	ApiStatistics graphql.Statistics `json:"apiStatistics"`
+}
+
+// QueueStatistics describes the state of a trigger queue since the daemon started.
+// For the access provider update queue, every data source is counted separately.
+type QueueStatistics struct {
+	// Depth is the number of items waiting to be handled
+	Depth int `json:"depth"`
+	// InFlight is the number of items that are being handled
+	InFlight int `json:"inFlight"`
+	// Replayed is the number of items that were restored from the persisted queue at startup
+	Replayed int `json:"replayed"`
+	// Received is the number of items that were added to the queue
+	Received int `json:"received"`
+	// Handled is the number of items that were handled completely
+	Handled int `json:"handled"`
+}
+
+// NewControlSocketCliTrigger creates a trigger listening on a Unix domain socket, so other CLI commands on the same machine can control the daemon.
+// Next to the trigger events, the control socket exposes the status of the daemon. Access is restricted to the current user by the file permissions.
+// The validate function (optional) is called for every trigger event before it is accepted.
+func NewControlSocketCliTrigger(config *types.BaseConfig, socketPath string, status func() *DaemonStatus, validate func(triggerEvent *TriggerEvent) error) (*HttpCliTrigger, error) {
+	err := removeStaleControlSocket(socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(socketPath), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("create directory for control socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("listen on control socket %q: %w", socketPath, err)
+	}
+
+	err = os.Chmod(socketPath, 0600)
+	if err != nil {
+		listener.Close()
+
+		return nil, fmt.Errorf("restrict access to control socket %q: %w", socketPath, err)
+	}
+
+	return &HttpCliTrigger{
+		logger:   config.BaseLogger,
+		listener: listener,
+		endpoint: "control socket " + socketPath,
+		status:   status,
+		validate: validate,
+	}, nil
+}
+
+// removeStaleControlSocket removes the socket file left behind by a daemon that didn't stop cleanly.
+// An error is returned if another daemon is still listening on the socket.
+func removeStaleControlSocket(socketPath string) error {
+	if _, err := os.Stat(socketPath); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	conn, err := net.DialTimeout("unix", socketPath, controlSocketDialTimeout)
+	if err == nil {
+		conn.Close()
+
+		return fmt.Errorf("another daemon is already listening on control socket %q", socketPath)
+	}
+
+	err = os.Remove(socketPath)
+	if err != nil {
+		return fmt.Errorf("remove stale control socket %q: %w", socketPath, err)
+	}
+
+	return nil
+}
+
+// ControlSocketClient sends requests to a daemon over its control socket.
+type ControlSocketClient struct {
+	socketPath string
+	client     *http.Client
+}
+
+func NewControlSocketClient(socketPath string) *ControlSocketClient {
+	return &ControlSocketClient{
+		socketPath: socketPath,
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					dialer := net.Dialer{Timeout: controlSocketDialTimeout}
+
+					return dialer.DialContext(ctx, "unix", socketPath)
+				},
+			},
+		},
+	}
+}
+
+// Trigger sends the trigger event to the daemon, which adds it to its queue.
+func (c *ControlSocketClient) Trigger(ctx context.Context, triggerEvent *TriggerEvent) error {
+	body, err := json.Marshal(triggerEvent)
+	if err != nil {
+		return fmt.Errorf("marshal trigger: %w", err)
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, httpTriggerPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// Status fetches the current status of the daemon.
+func (c *ControlSocketClient) Status(ctx context.Context) (*DaemonStatus, error) {
+	resp, err := c.do(ctx, http.MethodGet, httpStatusPath, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	status := DaemonStatus{}
+
+	err = json.NewDecoder(resp.Body).Decode(&status)
+	if err != nil {
+		return nil, fmt.Errorf("parse daemon status: %w", err)
+	}
+
+	return &status, nil
+}
+
+func (c *ControlSocketClient) do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	// The host is ignored as the connection is always made to the socket
+	req, err := http.NewRequestWithContext(ctx, method, "http://daemon"+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("no running daemon found on control socket %q: %w", c.socketPath, err)
+	}
+
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+
+		msg, _ := io.ReadAll(resp.Body)
+
+		return nil, fmt.Errorf("daemon returned an error (HTTP %d): %s", resp.StatusCode, string(bytes.TrimSpace(msg)))
+	}
+
+	return resp, nil
+}
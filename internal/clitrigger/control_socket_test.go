@@ -0,0 +1,128 @@
+package clitrigger
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/graphql"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+func controlSocketPath(t *testing.T) string {
+	t.Helper()
+
+	// Unix socket paths are limited in length, so not using t.TempDir()
+	dir, err := os.MkdirTemp("", "raito")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	return filepath.Join(dir, "daemon.sock")
+}
+
+func TestControlSocketCliTrigger(t *testing.T) {
+	socketPath := controlSocketPath(t)
+	startedAt := time.Now().Truncate(time.Second)
+	target := "target1"
+
+	trigger, err := NewControlSocketCliTrigger(&types.BaseConfig{BaseLogger: hclog.NewNullLogger()}, socketPath, func() *DaemonStatus {
+		return &DaemonStatus{
+			Pid:                42,
+			StartedAt:          startedAt,
+			Iteration:          3,
+			RunningTargets:     []string{"target2"},
+			QueuedSyncTriggers: []SyncTrigger{{Target: &target, DataSourceSync: true}},
+			ApiStatistics:      graphql.Statistics{Requests: 10, Retries: 2, Failures: 1},
+		}
+	}, func(triggerEvent *TriggerEvent) error {
+		if triggerEvent.SyncTrigger != nil && *triggerEvent.SyncTrigger.Target != target {
+			return fmt.Errorf("unknown target %q", *triggerEvent.SyncTrigger.Target)
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	stat, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+
+	handler := &recordingTriggerHandler{}
+	trigger.Subscribe(handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	trigger.Start(ctx)
+
+	client := NewControlSocketClient(socketPath)
+
+	err = client.Trigger(context.Background(), &TriggerEvent{SyncTrigger: &SyncTrigger{Target: &target, DataAccessSync: true}})
+	require.NoError(t, err)
+
+	require.Len(t, handler.events, 1)
+	assert.Equal(t, "target1", *handler.events[0].SyncTrigger.Target)
+	assert.True(t, handler.events[0].SyncTrigger.DataAccessSync)
+
+	status, err := client.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 42, status.Pid)
+	assert.Equal(t, 3, status.Iteration)
+	assert.Equal(t, []string{"target2"}, status.RunningTargets)
+	assert.Equal(t, graphql.Statistics{Requests: 10, Retries: 2, Failures: 1}, status.ApiStatistics)
+	assert.True(t, startedAt.Equal(status.StartedAt))
+	require.Len(t, status.QueuedSyncTriggers, 1)
+	assert.Equal(t, "target1", *status.QueuedSyncTriggers[0].Target)
+
+	err = client.Trigger(context.Background(), &TriggerEvent{})
+	assert.ErrorContains(t, err, "HTTP 400")
+
+	unknownTarget := "unknown"
+	err = client.Trigger(context.Background(), &TriggerEvent{SyncTrigger: &SyncTrigger{Target: &unknownTarget, DataAccessSync: true}})
+	assert.ErrorContains(t, err, `invalid trigger: unknown target "unknown"`)
+	assert.Len(t, handler.events, 1)
+
+	cancel()
+	trigger.Wait()
+
+	_, err = os.Stat(socketPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestControlSocketCliTrigger_AlreadyRunning(t *testing.T) {
+	socketPath := controlSocketPath(t)
+
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	_, err = NewControlSocketCliTrigger(&types.BaseConfig{BaseLogger: hclog.NewNullLogger()}, socketPath, nil, nil)
+	assert.ErrorContains(t, err, "another daemon")
+}
+
+func TestControlSocketCliTrigger_StaleSocket(t *testing.T) {
+	socketPath := controlSocketPath(t)
+
+	require.NoError(t, os.WriteFile(socketPath, []byte{}, 0600))
+
+	trigger, err := NewControlSocketCliTrigger(&types.BaseConfig{BaseLogger: hclog.NewNullLogger()}, socketPath, nil, nil)
+	require.NoError(t, err)
+
+	trigger.listener.Close()
+}
+
+func TestControlSocketClient_NoDaemon(t *testing.T) {
+	_, err := NewControlSocketClient(controlSocketPath(t)).Status(context.Background())
+
+	assert.ErrorContains(t, err, "no running daemon found")
+}
@@ -19,17 +19,39 @@ type websocketResult struct {
 }
 
 func CreateCliTrigger(config *types.BaseConfig) (CliTrigger, error) {
+	var triggers []CliTrigger
+
 	if viper.GetBool(constants.DisableWebsocketFlag) {
-		config.BaseLogger.Info("Websocket sync is disabled. No CLI triggers will be captured")
-		return &DummyCliTrigger{}, nil
+		config.BaseLogger.Info("Websocket sync is disabled. No CLI triggers will be captured from Raito Cloud")
+	} else {
+		websocketTrigger, err := createWebsocketTrigger(config)
+
+		switch {
+		case err != nil:
+			// The local HTTP trigger is still started, as it is most useful when Raito Cloud is unreachable
+			config.BaseLogger.Error(fmt.Sprintf("Unable to capture CLI triggers from Raito Cloud: %s", err.Error()))
+		case websocketTrigger != nil:
+			triggers = append(triggers, websocketTrigger)
+		}
 	}
 
-	cliTrigger, err := createWebsocketTrigger(config)
-	if err != nil || cliTrigger == nil {
-		return &DummyCliTrigger{}, err
+	if address := viper.GetString(constants.HttpTriggerAddressFlag); address != "" {
+		httpTrigger, err := NewHttpCliTrigger(config, address, viper.GetString(constants.HttpTriggerTokenFlag))
+		if err != nil {
+			return &DummyCliTrigger{}, fmt.Errorf("create HTTP trigger: %w", err)
+		}
+
+		triggers = append(triggers, httpTrigger)
 	}
 
-	return cliTrigger, nil
+	switch len(triggers) {
+	case 0:
+		return &DummyCliTrigger{}, nil
+	case 1:
+		return triggers[0], nil
+	default:
+		return NewFanInCliTrigger(triggers...), nil
+	}
 }
 
 func createWebsocketTrigger(config *types.BaseConfig) (*WebsocketCliTrigger, error) {
@@ -54,18 +76,43 @@ func createWebsocketTrigger(config *types.BaseConfig) (*WebsocketCliTrigger, err
 	return NewWebsocketCliTrigger(config, result.CliTriggerUrl.Url), nil
 }
 
-func NewApUpdateTrigger(cliTrigger CliTrigger) *ApUpdateTriggerHandler {
+func NewApUpdateTrigger(config *types.BaseConfig, cliTrigger CliTrigger) *ApUpdateTriggerHandler {
 	updateTrigger := NewApUpdateTriggerHandler()
+	updateTrigger.logger = config.BaseLogger
+
+	if queueDir := TriggerQueueDir(); queueDir != "" {
+		durableUpdateTrigger, err := NewDurableApUpdateTriggerHandler(config.BaseLogger, queueDir)
+		if err != nil {
+			config.BaseLogger.Warn(fmt.Sprintf("Unable to persist access provider update queue, keeping it in memory: %s", err.Error()))
+		} else {
+			updateTrigger = durableUpdateTrigger
+		}
+	}
 
 	cliTrigger.Subscribe(updateTrigger)
 
 	return updateTrigger
 }
 
-func NewSyncTrigger(cliTrigger CliTrigger) *SyncTriggerHandler {
+func NewSyncTrigger(config *types.BaseConfig, cliTrigger CliTrigger) *SyncTriggerHandler {
 	syncTrigger := NewSyncTriggerHandler()
+	syncTrigger.logger = config.BaseLogger
+
+	if queueDir := TriggerQueueDir(); queueDir != "" {
+		durableSyncTrigger, err := NewDurableSyncTriggerHandler(config.BaseLogger, queueDir)
+		if err != nil {
+			config.BaseLogger.Warn(fmt.Sprintf("Unable to persist sync trigger queue, keeping it in memory: %s", err.Error()))
+		} else {
+			syncTrigger = durableSyncTrigger
+		}
+	}
 
 	cliTrigger.Subscribe(syncTrigger)
 
 	return syncTrigger
 }
+
+// TriggerQueueDir returns the directory to persist the trigger queues in, or an empty string if they should only be kept in memory.
+func TriggerQueueDir() string {
+	return viper.GetString(constants.TriggerQueueDirFlag)
+}
@@ -0,0 +1,40 @@
+package clitrigger
+
+import (
+	"context"
+)
+
+var _ CliTrigger = (*FanInCliTrigger)(nil)
+
+// FanInCliTrigger combines multiple CliTriggers, so trigger events from all of them are handled by the same handlers.
+type FanInCliTrigger struct {
+	triggers []CliTrigger
+}
+
+func NewFanInCliTrigger(triggers ...CliTrigger) *FanInCliTrigger {
+	return &FanInCliTrigger{triggers: triggers}
+}
+
+func (f *FanInCliTrigger) Start(ctx context.Context) {
+	for _, trigger := range f.triggers {
+		trigger.Start(ctx)
+	}
+}
+
+func (f *FanInCliTrigger) Subscribe(handler TriggerHandler) {
+	for _, trigger := range f.triggers {
+		trigger.Subscribe(handler)
+	}
+}
+
+func (f *FanInCliTrigger) Reset() {
+	for _, trigger := range f.triggers {
+		trigger.Reset()
+	}
+}
+
+func (f *FanInCliTrigger) Wait() {
+	for _, trigger := range f.triggers {
+		trigger.Wait()
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package clitrigger
+
+import (
+	"os"
+	"syscall"
+)
+
+// tryLockFile takes an exclusive lock on the file, without waiting if another process holds the lock.
+func tryLockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+}
@@ -0,0 +1,12 @@
+//go:build windows
+
+package clitrigger
+
+import (
+	"errors"
+	"os"
+)
+
+func tryLockFile(_ *os.File) error {
+	return errors.New("persisting the trigger queue is not supported on Windows")
+}
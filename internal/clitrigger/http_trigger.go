@@ -0,0 +1,201 @@
+package clitrigger
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+const (
+	httpTriggerPath        = "/trigger"
+	httpStatusPath         = "/status"
+	httpTriggerMaxBodySize = 1 << 20
+	httpShutdownTimeout    = 10 * time.Second
+)
+
+var _ CliTrigger = (*HttpCliTrigger)(nil)
+
+// HttpCliTrigger listens on a local HTTP endpoint for trigger events, so external tools (like an orchestrator) can trigger syncs.
+// The trigger events are in the same format as the ones received over the websocket from Raito Cloud.
+type HttpCliTrigger struct {
+	triggerSubscribers
+
+	logger   hclog.Logger
+	token    string
+	listener net.Listener
+	endpoint string
+
+	// status is only set for the control socket, to expose the status of the daemon
+	status func() *DaemonStatus
+	// validate is only set for the control socket, to reject triggers the daemon can't handle
+	validate func(triggerEvent *TriggerEvent) error
+
+	wg sync.WaitGroup
+}
+
+// NewHttpCliTrigger creates a new HTTP trigger listening on the given address. Every request needs to pass the token as bearer token.
+func NewHttpCliTrigger(config *types.BaseConfig, address, token string) (*HttpCliTrigger, error) {
+	if token == "" {
+		return nil, fmt.Errorf("no token defined for the HTTP trigger endpoint: use %q to define one", constants.HttpTriggerTokenFlag)
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %q: %w", address, err)
+	}
+
+	return &HttpCliTrigger{
+		logger:   config.BaseLogger,
+		token:    token,
+		listener: listener,
+		endpoint: fmt.Sprintf("http://%s%s", listener.Addr().String(), httpTriggerPath),
+	}, nil
+}
+
+// Address returns the address the HTTP trigger is listening on.
+func (t *HttpCliTrigger) Address() string {
+	return t.listener.Addr().String()
+}
+
+func (t *HttpCliTrigger) Start(ctx context.Context) {
+	server := &http.Server{
+		Handler:           t.handler(ctx),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	t.wg.Add(2)
+
+	go func() {
+		defer t.wg.Done()
+
+		t.logger.Info(fmt.Sprintf("Listening for triggers on %s", t.endpoint))
+
+		err := server.Serve(t.listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			t.logger.Error(fmt.Sprintf("HTTP trigger endpoint stopped: %s", err.Error()))
+		}
+	}()
+
+	go func() {
+		defer t.wg.Done()
+
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+		defer cancel()
+
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			t.logger.Warn(fmt.Sprintf("Failed to gracefully stop HTTP trigger endpoint: %s", err.Error()))
+		}
+	}()
+}
+
+func (t *HttpCliTrigger) Reset() {
+}
+
+func (t *HttpCliTrigger) Wait() {
+	t.wg.Wait()
+}
+
+func (t *HttpCliTrigger) handler(ctx context.Context) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc(httpTriggerPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		if !t.isAuthorized(r) {
+			t.logger.Warn(fmt.Sprintf("Received unauthorized trigger request from %s", r.RemoteAddr))
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+
+			return
+		}
+
+		triggerEvent := TriggerEvent{}
+
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, httpTriggerMaxBodySize))
+		decoder.DisallowUnknownFields()
+
+		err := decoder.Decode(&triggerEvent)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid trigger: %s", err.Error()), http.StatusBadRequest)
+
+			return
+		}
+
+		// Subtask updates can only be pushed by Raito Cloud
+		triggerEvent.SubtaskUpdate = nil
+
+		if triggerEvent.SyncTrigger == nil && triggerEvent.ApUpdate == nil {
+			http.Error(w, "invalid trigger: either syncTrigger or apUpdate should be defined", http.StatusBadRequest)
+
+			return
+		}
+
+		if t.validate != nil {
+			err = t.validate(&triggerEvent)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid trigger: %s", err.Error()), http.StatusBadRequest)
+
+				return
+			}
+		}
+
+		t.logger.Info(fmt.Sprintf("Received trigger on %s", t.endpoint))
+
+		t.publish(ctx, &triggerEvent)
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	if t.status != nil {
+		mux.HandleFunc(httpStatusPath, func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				w.Header().Set("Allow", http.MethodGet)
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+
+			err := json.NewEncoder(w).Encode(t.status())
+			if err != nil {
+				t.logger.Warn(fmt.Sprintf("Failed to send daemon status: %s", err.Error()))
+			}
+		})
+	}
+
+	return mux
+}
+
+func (t *HttpCliTrigger) isAuthorized(r *http.Request) bool {
+	if t.token == "" {
+		// Only the case for the control socket, which is protected by the file permissions
+		return true
+	}
+
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(t.token)) == 1
+}
@@ -0,0 +1,140 @@
+package clitrigger
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+type recordingTriggerHandler struct {
+	m      sync.Mutex
+	events []TriggerEvent
+}
+
+func (h *recordingTriggerHandler) HandleTriggerEvent(_ context.Context, triggerEvent *TriggerEvent) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.events = append(h.events, *triggerEvent)
+}
+
+func startHttpTrigger(t *testing.T) (*HttpCliTrigger, *recordingTriggerHandler) {
+	t.Helper()
+
+	trigger, err := NewHttpCliTrigger(&types.BaseConfig{BaseLogger: hclog.NewNullLogger()}, "127.0.0.1:0", "secret")
+	require.NoError(t, err)
+
+	handler := &recordingTriggerHandler{}
+	trigger.Subscribe(handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	trigger.Start(ctx)
+
+	t.Cleanup(func() {
+		cancel()
+		trigger.Wait()
+	})
+
+	return trigger, handler
+}
+
+func postTrigger(t *testing.T, trigger *HttpCliTrigger, method, token, body string) int {
+	t.Helper()
+
+	req, err := http.NewRequest(method, "http://"+trigger.Address()+httpTriggerPath, strings.NewReader(body))
+	require.NoError(t, err)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func TestHttpCliTrigger(t *testing.T) {
+	trigger, handler := startHttpTrigger(t)
+
+	status := postTrigger(t, trigger, http.MethodPost, "secret", `{"syncTrigger": {"dataSource": "ds1", "dataSourceSync": true}}`)
+	assert.Equal(t, http.StatusAccepted, status)
+
+	status = postTrigger(t, trigger, http.MethodPost, "secret", `{"apUpdate": {"dataSourceNames": ["ds2"]}, "subtaskUpdate": {"jobId": "job1"}}`)
+	assert.Equal(t, http.StatusAccepted, status)
+
+	require.Len(t, handler.events, 2)
+	assert.Equal(t, "ds1", *handler.events[0].SyncTrigger.DataSource)
+	assert.True(t, handler.events[0].SyncTrigger.DataSourceSync)
+	assert.Equal(t, []string{"ds2"}, handler.events[1].ApUpdate.DataSourceNames)
+	assert.Nil(t, handler.events[1].SubtaskUpdate)
+}
+
+func TestHttpCliTrigger_InvalidRequests(t *testing.T) {
+	trigger, handler := startHttpTrigger(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		token          string
+		body           string
+		expectedStatus int
+	}{
+		{name: "no token", method: http.MethodPost, body: `{"syncTrigger": {}}`, expectedStatus: http.StatusUnauthorized},
+		{name: "wrong token", method: http.MethodPost, token: "wrong", body: `{"syncTrigger": {}}`, expectedStatus: http.StatusUnauthorized},
+		{name: "wrong method", method: http.MethodGet, token: "secret", expectedStatus: http.StatusMethodNotAllowed},
+		{name: "invalid json", method: http.MethodPost, token: "secret", body: `{"syncTrigger": `, expectedStatus: http.StatusBadRequest},
+		{name: "unknown fields", method: http.MethodPost, token: "secret", body: `{"other": {}}`, expectedStatus: http.StatusBadRequest},
+		{name: "empty trigger", method: http.MethodPost, token: "secret", body: `{}`, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedStatus, postTrigger(t, trigger, tt.method, tt.token, tt.body))
+		})
+	}
+
+	assert.Empty(t, handler.events)
+}
+
+func TestNewHttpCliTrigger_NoToken(t *testing.T) {
+	_, err := NewHttpCliTrigger(&types.BaseConfig{BaseLogger: hclog.NewNullLogger()}, "127.0.0.1:0", "")
+
+	assert.Error(t, err)
+}
+
+func TestFanInCliTrigger(t *testing.T) {
+	trigger1, err := NewHttpCliTrigger(&types.BaseConfig{BaseLogger: hclog.NewNullLogger()}, "127.0.0.1:0", "secret")
+	require.NoError(t, err)
+
+	trigger2, err := NewHttpCliTrigger(&types.BaseConfig{BaseLogger: hclog.NewNullLogger()}, "127.0.0.1:0", "secret")
+	require.NoError(t, err)
+
+	fanIn := NewFanInCliTrigger(trigger1, trigger2, &DummyCliTrigger{})
+
+	handler := &recordingTriggerHandler{}
+	fanIn.Subscribe(handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fanIn.Start(ctx)
+
+	assert.Equal(t, http.StatusAccepted, postTrigger(t, trigger1, http.MethodPost, "secret", `{"apUpdate": {"dataSourceNames": ["ds1"]}}`))
+	assert.Equal(t, http.StatusAccepted, postTrigger(t, trigger2, http.MethodPost, "secret", `{"apUpdate": {"dataSourceNames": ["ds2"]}}`))
+
+	cancel()
+	fanIn.Wait()
+
+	require.Len(t, handler.events, 2)
+	assert.Equal(t, []string{"ds1"}, handler.events[0].ApUpdate.DataSourceNames)
+	assert.Equal(t, []string{"ds2"}, handler.events[1].ApUpdate.DataSourceNames)
+}
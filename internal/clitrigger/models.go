@@ -1,5 +1,9 @@
 package clitrigger
 
+import (
+	"github.com/raito-io/cli/internal/job"
+)
+
 type ApUpdate struct {
 	Domain          string   `json:"domain"`
 	DataSourceNames []string `json:"dataSourceNames"`
@@ -12,6 +16,8 @@ type SyncTrigger struct {
 	DataSource *string `json:"dataSource"`
 	// The id of the identity store to sync. Note: this should be set if identity store needs to be synced
 	IdentityStore *string `json:"identityStore"`
+	// Optional: the name of the target to sync. Only used for triggers sent to the CLI directly (e.g. with 'raito trigger')
+	Target *string `json:"target,omitempty"`
 	// Boolean to indicate if the identity store needs to be synced or not
 	IdentityStoreSync bool `json:"identityStoreSync"`
 	// Boolean to indicate if the data source needs to be synced or not
@@ -31,6 +37,7 @@ type SyncTrigger struct {
 }
 
 type TriggerEvent struct {
-	ApUpdate    *ApUpdate    `json:"apUpdate,omitempty"`
-	SyncTrigger *SyncTrigger `json:"syncTrigger,omitempty"`
+	ApUpdate      *ApUpdate          `json:"apUpdate,omitempty"`
+	SyncTrigger   *SyncTrigger       `json:"syncTrigger,omitempty"`
+	SubtaskUpdate *job.SubtaskUpdate `json:"subtaskUpdate,omitempty"`
 }
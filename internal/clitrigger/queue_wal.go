@@ -0,0 +1,221 @@
+package clitrigger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/raito-io/cli/internal/constants"
+)
+
+const (
+	walOpPush = "push"
+	walOpPop  = "pop"
+
+	apUpdateQueueWalFile = "apupdate-queue.wal"
+	syncQueueWalFile     = "sync-queue.wal"
+
+	// deferredSyncsFile stores the syncs that are deferred until the end of a blackout window, next to the sync queue they are added to afterwards
+	deferredSyncsFile = "deferred-syncs.json"
+)
+
+type walRecord[T any] struct {
+	Op   string `json:"op"`
+	Item *T     `json:"item,omitempty"`
+}
+
+// queueWal is a write-ahead log for a FIFO trigger queue, so queued triggers survive a restart of the CLI.
+// Every push is appended to the file (and synced to disk) before the in-memory queue is updated.
+// The pop of an item is only appended once it is handled, so items that were being handled when the CLI stopped are handled again.
+type queueWal[T any] struct {
+	m sync.Mutex
+
+	path string
+	file *os.File
+	lock *os.File
+}
+
+// lockQueueWal takes an exclusive lock on the write-ahead log at the given path, so it can't be used by another daemon at the same time.
+// The lock is held until the write-ahead log is closed and should be taken before replaying it.
+func lockQueueWal(path string) (*os.File, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("create directory for trigger queue: %w", err)
+	}
+
+	lock, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open lock of trigger queue %q: %w", path, err)
+	}
+
+	err = tryLockFile(lock)
+	if err != nil {
+		lock.Close()
+
+		return nil, fmt.Errorf("trigger queue %q is in use by another daemon (use %q to give every daemon its own queue): %w", path, constants.TriggerQueueDirFlag, err)
+	}
+
+	return lock, nil
+}
+
+// openQueueWal creates a write-ahead log at the given path, only containing the given items that are still queued.
+// Use lockQueueWal and replayQueueWal first to find the items that were queued when the CLI stopped.
+// The lock is released when the write-ahead log is closed, or when it can't be opened.
+func openQueueWal[T any](path string, queued []T, lock *os.File) (*queueWal[T], error) {
+	err := compactQueueWal(path, queued)
+	if err != nil {
+		lock.Close()
+
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		lock.Close()
+
+		return nil, fmt.Errorf("open trigger queue %q: %w", path, err)
+	}
+
+	return &queueWal[T]{path: path, file: file, lock: lock}, nil
+}
+
+// replayQueueWal reads the write-ahead log at the given path and returns the items that are still queued.
+func replayQueueWal[T any](logger hclog.Logger, path string) ([]T, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("open trigger queue %q: %w", path, err)
+	}
+
+	defer file.Close()
+
+	var queued []T
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), httpTriggerMaxBodySize)
+
+	for scanner.Scan() {
+		var record walRecord[T]
+
+		err = json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			// Most likely the last record was only partially written when the CLI stopped
+			logger.Warn(fmt.Sprintf("Ignoring the rest of trigger queue %q, as it contains an invalid record: %s", path, err.Error()))
+
+			break
+		}
+
+		switch {
+		case record.Op == walOpPush && record.Item != nil:
+			queued = append(queued, *record.Item)
+		case record.Op == walOpPop:
+			queued = removeFromQueue(queued, record.Item)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		logger.Warn(fmt.Sprintf("Ignoring the rest of trigger queue %q: %s", path, err.Error()))
+	}
+
+	return queued, nil
+}
+
+// removeFromQueue removes the first queued item equal to the given item, or the head of the queue if no item is given.
+func removeFromQueue[T any](queued []T, item *T) []T {
+	for i := range queued {
+		if item == nil || reflect.DeepEqual(queued[i], *item) {
+			return append(queued[:i:i], queued[i+1:]...)
+		}
+	}
+
+	return queued
+}
+
+// compactQueueWal replaces the write-ahead log with one that only contains the given items.
+func compactQueueWal[T any](path string, queued []T) error {
+	tmpPath := path + ".tmp"
+
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("compact trigger queue %q: %w", path, err)
+	}
+
+	encoder := json.NewEncoder(file)
+
+	for i := range queued {
+		err = encoder.Encode(walRecord[T]{Op: walOpPush, Item: &queued[i]})
+		if err != nil {
+			file.Close()
+
+			return fmt.Errorf("compact trigger queue %q: %w", path, err)
+		}
+	}
+
+	err = file.Sync()
+	if err != nil {
+		file.Close()
+
+		return fmt.Errorf("compact trigger queue %q: %w", path, err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("compact trigger queue %q: %w", path, err)
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return fmt.Errorf("compact trigger queue %q: %w", path, err)
+	}
+
+	return nil
+}
+
+func (w *queueWal[T]) push(item *T) error {
+	return w.write(walRecord[T]{Op: walOpPush, Item: item})
+}
+
+// pop registers the removal of the given item from the queue. If no item is given, the head of the queue is removed.
+func (w *queueWal[T]) pop(item *T) error {
+	return w.write(walRecord[T]{Op: walOpPop, Item: item})
+}
+
+func (w *queueWal[T]) write(record walRecord[T]) error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("write to trigger queue %q: %w", w.path, err)
+	}
+
+	_, err = w.file.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("write to trigger queue %q: %w", w.path, err)
+	}
+
+	err = w.file.Sync()
+	if err != nil {
+		return fmt.Errorf("write to trigger queue %q: %w", w.path, err)
+	}
+
+	return nil
+}
+
+func (w *queueWal[T]) close() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	// Closing the lock file releases the lock
+	defer w.lock.Close()
+
+	return w.file.Close()
+}
@@ -0,0 +1,187 @@
+package clitrigger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/constants"
+)
+
+func setupQueueWalTest(t *testing.T) string {
+	t.Helper()
+
+	viper.Set(constants.Targets, []interface{}{})
+
+	t.Cleanup(func() {
+		viper.Set(constants.Targets, nil)
+	})
+
+	return t.TempDir()
+}
+
+func TestDurableApUpdateTriggerHandler(t *testing.T) {
+	queueDir := setupQueueWalTest(t)
+	ctx := context.Background()
+
+	handler, err := NewDurableApUpdateTriggerHandler(hclog.NewNullLogger(), queueDir)
+	require.NoError(t, err)
+
+	handler.HandleTriggerEvent(ctx, &TriggerEvent{ApUpdate: &ApUpdate{Domain: "domain", DataSourceNames: []string{"ds1", "ds2"}}})
+
+	apUpdate := handler.Pop()
+	require.NotNil(t, apUpdate)
+	assert.Equal(t, []string{"ds1", "ds2"}, apUpdate.DataSourceNames)
+	assert.Equal(t, QueueStatistics{InFlight: 2, Received: 2}, handler.Statistics())
+
+	handler.Done(apUpdate)
+	assert.Equal(t, QueueStatistics{Received: 2, Handled: 2}, handler.Statistics())
+
+	// Updates that are debounced when the CLI stops, are not lost
+	viper.Set(constants.ApUpdateDebounceFlag, time.Hour)
+	t.Cleanup(func() { viper.Set(constants.ApUpdateDebounceFlag, nil) })
+
+	handler.debounce = time.Hour
+
+	handler.HandleTriggerEvent(ctx, &TriggerEvent{ApUpdate: &ApUpdate{Domain: "domain", DataSourceNames: []string{"ds2", "ds3"}}})
+	handler.HandleTriggerEvent(ctx, &TriggerEvent{ApUpdate: &ApUpdate{Domain: "domain", DataSourceNames: []string{"ds3"}}})
+	assert.Nil(t, handler.Pop())
+
+	handler.Close()
+
+	// Simulate a restart
+	handler, err = NewDurableApUpdateTriggerHandler(hclog.NewNullLogger(), queueDir)
+	require.NoError(t, err)
+
+	defer handler.Close()
+
+	assert.Len(t, handler.TriggerChannel(), 1)
+	assert.Equal(t, []ApUpdate{
+		{Domain: "domain", DataSourceNames: []string{"ds2"}},
+		{Domain: "domain", DataSourceNames: []string{"ds3"}},
+	}, handler.Queued())
+	assert.Equal(t, QueueStatistics{Depth: 2, Replayed: 2}, handler.Statistics())
+
+	// Data sources that are already queued are still deduplicated after the restart
+	handler.HandleTriggerEvent(ctx, &TriggerEvent{ApUpdate: &ApUpdate{Domain: "domain", DataSourceNames: []string{"ds3", "ds4"}}})
+	assert.Len(t, handler.Queued(), 3)
+
+	// Restored updates are handled without waiting for the debounce window, unless new updates were received
+	apUpdate = handler.Pop()
+	require.NotNil(t, apUpdate)
+	assert.Equal(t, []string{"ds2"}, apUpdate.DataSourceNames)
+	assert.Equal(t, []ApUpdate{
+		{Domain: "domain", DataSourceNames: []string{"ds3"}},
+		{Domain: "domain", DataSourceNames: []string{"ds4"}},
+	}, handler.Queued())
+}
+
+func TestDurableSyncTriggerHandler(t *testing.T) {
+	queueDir := setupQueueWalTest(t)
+	ctx := context.Background()
+	dataSource := "ds1"
+	identityStore := "is1"
+
+	handler, err := NewDurableSyncTriggerHandler(hclog.NewNullLogger(), queueDir)
+	require.NoError(t, err)
+
+	handler.HandleTriggerEvent(ctx, &TriggerEvent{SyncTrigger: &SyncTrigger{DataSource: &dataSource, DataSourceSync: true}})
+	handler.HandleTriggerEvent(ctx, &TriggerEvent{SyncTrigger: &SyncTrigger{DataSource: &dataSource, DataSourceSync: true}})
+	handler.HandleTriggerEvent(ctx, &TriggerEvent{SyncTrigger: &SyncTrigger{IdentityStore: &identityStore, IdentityStoreSync: true}})
+
+	handler.Close()
+
+	handler, err = NewDurableSyncTriggerHandler(hclog.NewNullLogger(), queueDir)
+	require.NoError(t, err)
+
+	syncTrigger := handler.Pop()
+	require.NotNil(t, syncTrigger)
+	assert.Equal(t, "ds1", *syncTrigger.DataSource)
+
+	// The CLI stops before the sync trigger is handled
+	handler.Close()
+
+	handler, err = NewDurableSyncTriggerHandler(hclog.NewNullLogger(), queueDir)
+	require.NoError(t, err)
+
+	assert.Len(t, handler.Queued(), 2)
+
+	syncTrigger = handler.Pop()
+	require.NotNil(t, syncTrigger)
+	assert.Equal(t, "ds1", *syncTrigger.DataSource)
+
+	handler.Done(syncTrigger)
+	assert.Equal(t, QueueStatistics{Depth: 1, Replayed: 2, Handled: 1}, handler.Statistics())
+
+	handler.Close()
+
+	handler, err = NewDurableSyncTriggerHandler(hclog.NewNullLogger(), queueDir)
+	require.NoError(t, err)
+
+	defer handler.Close()
+
+	queued := handler.Queued()
+	require.Len(t, queued, 1)
+	assert.Equal(t, "is1", *queued[0].IdentityStore)
+	assert.True(t, queued[0].IdentityStoreSync)
+}
+
+func TestDurableSyncTriggerHandler_InUse(t *testing.T) {
+	queueDir := setupQueueWalTest(t)
+
+	handler, err := NewDurableSyncTriggerHandler(hclog.NewNullLogger(), queueDir)
+	require.NoError(t, err)
+
+	_, err = NewDurableSyncTriggerHandler(hclog.NewNullLogger(), queueDir)
+	require.ErrorContains(t, err, "in use by another daemon")
+
+	handler.Close()
+
+	// The queue can be used again once the other daemon stopped
+	handler, err = NewDurableSyncTriggerHandler(hclog.NewNullLogger(), queueDir)
+	require.NoError(t, err)
+
+	handler.Close()
+}
+
+func TestReplayQueueWal_PartialRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.wal")
+
+	content := `{"op":"push","item":{"domain":"domain","dataSourceNames":["ds1"]}}
+{"op":"push","item":{"domain":"domain","dataSourceNames":["ds2"]}}
+{"op":"pop"}
+{"op":"push","item":{"domain":"dom`
+
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	queued, err := replayQueueWal[ApUpdate](hclog.NewNullLogger(), path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ApUpdate{{Domain: "domain", DataSourceNames: []string{"ds2"}}}, queued)
+}
+
+func TestReplayQueueWal_NotExisting(t *testing.T) {
+	queued, err := replayQueueWal[ApUpdate](hclog.NewNullLogger(), filepath.Join(t.TempDir(), "queue.wal"))
+	require.NoError(t, err)
+
+	assert.Empty(t, queued)
+}
+
+func TestTriggerQueueDir(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set(constants.TriggerQueueDirFlag, nil)
+	})
+
+	// The queues are only persisted when a directory is configured
+	assert.Empty(t, TriggerQueueDir())
+
+	viper.Set(constants.TriggerQueueDirFlag, "/tmp/queue")
+	assert.Equal(t, "/tmp/queue", TriggerQueueDir())
+}
@@ -0,0 +1,18 @@
+package clitrigger
+
+import (
+	"context"
+
+	"github.com/raito-io/cli/internal/job"
+)
+
+// SubtaskUpdateTriggerHandler forwards the subtask status changes pushed by Raito Cloud to the jobs waiting for them.
+type SubtaskUpdateTriggerHandler struct{}
+
+func (h *SubtaskUpdateTriggerHandler) HandleTriggerEvent(_ context.Context, triggerEvent *TriggerEvent) {
+	if triggerEvent.SubtaskUpdate == nil {
+		return
+	}
+
+	job.NotifySubtaskUpdate(triggerEvent.SubtaskUpdate)
+}
@@ -2,9 +2,12 @@ package clitrigger
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
 	"reflect"
 	"sync"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/spf13/viper"
 
 	"github.com/raito-io/cli/internal/constants"
@@ -15,6 +18,13 @@ type SyncTriggerHandler struct {
 
 	m sync.Mutex
 
+	logger hclog.Logger
+	wal    *queueWal[SyncTrigger]
+
+	// inFlight are the sync triggers that were handed out by Pop, but are not Done yet
+	inFlight   []SyncTrigger
+	statistics QueueStatistics
+
 	outputChan chan struct{}
 }
 
@@ -23,12 +33,52 @@ func NewSyncTriggerHandler() *SyncTriggerHandler {
 
 	h := &SyncTriggerHandler{
 		syncQueue:  make([]SyncTrigger, 0, targets),
+		logger:     hclog.NewNullLogger(),
 		outputChan: make(chan struct{}, 1),
 	}
 
 	return h
 }
 
+// NewDurableSyncTriggerHandler creates a SyncTriggerHandler that persists its queue in the given directory.
+// The sync triggers that were still queued when the CLI stopped are queued again.
+func NewDurableSyncTriggerHandler(logger hclog.Logger, queueDir string) (*SyncTriggerHandler, error) {
+	h := NewSyncTriggerHandler()
+	h.logger = logger
+
+	path := filepath.Join(queueDir, syncQueueWalFile)
+
+	lock, err := lockQueueWal(path)
+	if err != nil {
+		return nil, err
+	}
+
+	replayed, err := replayQueueWal[SyncTrigger](logger, path)
+	if err != nil {
+		lock.Close()
+
+		return nil, err
+	}
+
+	for i := range replayed {
+		h.enqueue(&replayed[i])
+	}
+
+	h.wal, err = openQueueWal(path, h.syncQueue, lock)
+	if err != nil {
+		return nil, err
+	}
+
+	h.statistics.Replayed = len(h.syncQueue)
+
+	if len(h.syncQueue) > 0 {
+		logger.Info(fmt.Sprintf("Restored %d queued sync trigger(s) from %s", len(h.syncQueue), path))
+		h.notifyChannel()
+	}
+
+	return h, nil
+}
+
 func (h *SyncTriggerHandler) HandleTriggerEvent(_ context.Context, triggerEvent *TriggerEvent) {
 	if triggerEvent.SyncTrigger == nil {
 		return
@@ -37,20 +87,59 @@ func (h *SyncTriggerHandler) HandleTriggerEvent(_ context.Context, triggerEvent
 	h.m.Lock()
 	defer h.m.Unlock()
 
+	if !h.enqueue(triggerEvent.SyncTrigger) {
+		return
+	}
+
+	h.statistics.Received++
+
+	if h.wal != nil {
+		err := h.wal.push(triggerEvent.SyncTrigger)
+		if err != nil {
+			h.logger.Warn(fmt.Sprintf("Sync trigger will not survive a restart: %s", err.Error()))
+		}
+	}
+
+	h.logger.Debug(fmt.Sprintf("Queued sync trigger. %d trigger(s) in queue", len(h.syncQueue)))
+
+	h.notifyChannel()
+}
+
+// enqueue adds the sync trigger to the queue, unless an equal trigger is already queued.
+func (h *SyncTriggerHandler) enqueue(syncTrigger *SyncTrigger) bool {
 	// Checking if there is an existing trigger in the queue that is equal to the requested one
 	for _, queued := range h.syncQueue {
-		if reflect.DeepEqual(queued, *triggerEvent.SyncTrigger) {
+		if reflect.DeepEqual(queued, *syncTrigger) {
 			// Already queued, ignoring
-			return
+			return false
 		}
 	}
 
-	h.syncQueue = append(h.syncQueue, *triggerEvent.SyncTrigger)
-	h.notifyChannel()
+	h.syncQueue = append(h.syncQueue, *syncTrigger)
+
+	return true
+}
+
+// DeferredSyncsFile returns the file to persist the syncs in that are deferred because of a blackout window.
+// As they are queued again once the window ends, they are stored next to the sync trigger queue, protected by the same lock.
+// Returns an empty string if the queue is only kept in memory.
+func (h *SyncTriggerHandler) DeferredSyncsFile() string {
+	if h.wal == nil {
+		return ""
+	}
+
+	return filepath.Join(filepath.Dir(h.wal.path), deferredSyncsFile)
 }
 
 func (h *SyncTriggerHandler) Close() {
 	close(h.outputChan)
+
+	if h.wal != nil {
+		err := h.wal.close()
+		if err != nil {
+			h.logger.Warn(fmt.Sprintf("Failed to close sync trigger queue: %s", err.Error()))
+		}
+	}
 }
 
 func (h *SyncTriggerHandler) Pop() *SyncTrigger {
@@ -63,6 +152,7 @@ func (h *SyncTriggerHandler) Pop() *SyncTrigger {
 
 	syncTrigger := h.syncQueue[0]
 	h.syncQueue = h.syncQueue[1:]
+	h.inFlight = append(h.inFlight, syncTrigger)
 
 	if len(h.syncQueue) > 0 {
 		h.notifyChannel()
@@ -71,6 +161,53 @@ func (h *SyncTriggerHandler) Pop() *SyncTrigger {
 	return &syncTrigger
 }
 
+// Done marks the sync trigger that was returned by Pop as handled, so it is not handled again after a restart.
+// Sync triggers that are popped but never marked as done are queued again when the CLI restarts.
+func (h *SyncTriggerHandler) Done(syncTrigger *SyncTrigger) {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	for i := range h.inFlight {
+		if !reflect.DeepEqual(h.inFlight[i], *syncTrigger) {
+			continue
+		}
+
+		if h.wal != nil {
+			err := h.wal.pop(&h.inFlight[i])
+			if err != nil {
+				h.logger.Warn(fmt.Sprintf("Sync trigger may be handled again after a restart: %s", err.Error()))
+			}
+		}
+
+		h.inFlight = append(h.inFlight[:i], h.inFlight[i+1:]...)
+		h.statistics.Handled++
+
+		break
+	}
+
+	h.logger.Debug(fmt.Sprintf("Handled sync trigger. %d trigger(s) in queue", len(h.syncQueue)))
+}
+
+// Statistics returns the statistics of the sync trigger queue.
+func (h *SyncTriggerHandler) Statistics() QueueStatistics {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	statistics := h.statistics
+	statistics.Depth = len(h.syncQueue)
+	statistics.InFlight = len(h.inFlight)
+
+	return statistics
+}
+
+// Queued returns a copy of the sync triggers that are waiting to be handled.
+func (h *SyncTriggerHandler) Queued() []SyncTrigger {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	return append([]SyncTrigger{}, h.syncQueue...)
+}
+
 func (h *SyncTriggerHandler) TriggerChannel() <-chan struct{} {
 	return h.outputChan
 }
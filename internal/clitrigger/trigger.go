@@ -2,6 +2,7 @@ package clitrigger
 
 import (
 	"context"
+	"sync"
 )
 
 type CliTrigger interface {
@@ -11,6 +12,42 @@ type CliTrigger interface {
 	Wait()
 }
 
+type TriggerHandler interface {
+	HandleTriggerEvent(ctx context.Context, triggerEvent *TriggerEvent)
+}
+
+// triggerSubscribers keeps track of the handlers to notify about the trigger events received by a CliTrigger.
+type triggerSubscribers struct {
+	subscriberMutex sync.Mutex
+	subscribers     []TriggerHandler
+}
+
+func (s *triggerSubscribers) Subscribe(handler TriggerHandler) {
+	s.subscriberMutex.Lock()
+	defer s.subscriberMutex.Unlock()
+
+	s.subscribers = append(s.subscribers, handler)
+}
+
+func (s *triggerSubscribers) publish(ctx context.Context, triggerEvent *TriggerEvent) {
+	s.subscriberMutex.Lock()
+	defer s.subscriberMutex.Unlock()
+
+	wg := sync.WaitGroup{}
+
+	for i := range s.subscribers {
+		wg.Add(1)
+
+		go func(subscriber TriggerHandler) {
+			defer wg.Done()
+
+			subscriber.HandleTriggerEvent(ctx, triggerEvent)
+		}(s.subscribers[i])
+	}
+
+	wg.Wait()
+}
+
 var _ CliTrigger = (*DummyCliTrigger)(nil)
 
 type DummyCliTrigger struct {
@@ -14,9 +14,11 @@ import (
 	plugin2 "github.com/raito-io/cli/base/util/plugin"
 	"github.com/raito-io/cli/internal/auth"
 	"github.com/raito-io/cli/internal/health_check"
+	"github.com/raito-io/cli/internal/job"
 	"github.com/raito-io/cli/internal/plugin"
 	"github.com/raito-io/cli/internal/target"
 	"github.com/raito-io/cli/internal/target/types"
+	"github.com/raito-io/cli/internal/util/httpclient"
 )
 
 const heartbeatTimeout = time.Minute * 5
@@ -26,6 +28,9 @@ type WebsocketClient struct {
 	wg           sync.WaitGroup
 	config       *types.BaseConfig
 	websocketUrl string
+
+	connMutex sync.Mutex
+	conn      *websocket.Conn
 }
 
 type WebsocketMessageError struct {
@@ -45,11 +50,17 @@ func NewWebsocketClient(config *types.BaseConfig, websocketUrl string) *Websocke
 }
 
 func (s *WebsocketClient) Start(ctx context.Context) (<-chan interface{}, error) {
+	httpClient, err := httpclient.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
 	options := websocket.DialOptions{
+		HTTPClient: httpClient,
 		HTTPHeader: map[string][]string{},
 	}
 
-	err := auth.AddTokenToHeader(&options.HTTPHeader, s.config)
+	err = auth.AddTokenToHeader(&options.HTTPHeader, s.config)
 	if err != nil {
 		return nil, err
 	}
@@ -69,9 +80,43 @@ func (s *WebsocketClient) Start(ctx context.Context) (<-chan interface{}, error)
 		return nil, err
 	}
 
+	s.connMutex.Lock()
+	s.conn = conn
+	s.connMutex.Unlock()
+
 	return s.readMessageFromWebsocket(ctx, conn), nil
 }
 
+// SubscribeSubtask requests Raito Cloud to push the status changes of the given subtask over the websocket.
+func (s *WebsocketClient) SubscribeSubtask(ctx context.Context, jobId, jobType, subtaskId string) error {
+	s.connMutex.Lock()
+	conn := s.conn
+	s.connMutex.Unlock()
+
+	if conn == nil {
+		return errors.New("websocket not connected")
+	}
+
+	subscribeMsg, err := json.Marshal(struct {
+		Message   string `json:"message"`
+		JobId     string `json:"jobId"`
+		JobType   string `json:"jobType"`
+		SubtaskId string `json:"subtaskId"`
+	}{
+		Message:   "subscribeSubtask",
+		JobId:     jobId,
+		JobType:   jobType,
+		SubtaskId: subtaskId,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.config.BaseLogger.Debug(fmt.Sprintf("Subscribe to status changes of subtask %q", subtaskId))
+
+	return conn.Write(ctx, websocket.MessageText, subscribeMsg)
+}
+
 func (s *WebsocketClient) Wait() {
 	s.wg.Wait()
 }
@@ -155,7 +200,15 @@ func (s *WebsocketClient) heartbeat(ctx context.Context, conn *websocket.Conn) e
 	go func() {
 		defer s.wg.Done()
 
-		defer conn.Close(websocket.StatusNormalClosure, "Closing websocket")
+		defer func() {
+			s.connMutex.Lock()
+			if s.conn == conn {
+				s.conn = nil
+			}
+			s.connMutex.Unlock()
+
+			conn.Close(websocket.StatusNormalClosure, "Closing websocket")
+		}()
 
 		timer := time.NewTimer(0)
 
@@ -192,36 +245,28 @@ func (s *WebsocketClient) heartbeat(ctx context.Context, conn *websocket.Conn) e
 	return nil
 }
 
-type TriggerHandler interface {
-	HandleTriggerEvent(ctx context.Context, triggerEvent *TriggerEvent)
-}
-
 type WebsocketCliTrigger struct {
+	triggerSubscribers
+
 	client        *WebsocketClient
 	logger        hclog.Logger
 	healthChecker health_check.HealthChecker
 
-	subscriberMutex sync.Mutex
-	subscribers     []TriggerHandler
-
 	m sync.Mutex
 
 	cancelFn func()
 }
 
 func NewWebsocketCliTrigger(config *types.BaseConfig, websocketUrl string) *WebsocketCliTrigger {
-	return &WebsocketCliTrigger{
+	trigger := &WebsocketCliTrigger{
 		client:        NewWebsocketClient(config, websocketUrl),
 		logger:        config.BaseLogger,
 		healthChecker: config.HealthChecker,
 	}
-}
 
-func (s *WebsocketCliTrigger) Subscribe(handler TriggerHandler) {
-	s.subscriberMutex.Lock()
-	defer s.subscriberMutex.Unlock()
+	trigger.Subscribe(&SubtaskUpdateTriggerHandler{})
 
-	s.subscribers = append(s.subscribers, handler)
+	return trigger
 }
 
 func (s *WebsocketCliTrigger) Start(ctx context.Context) {
@@ -295,6 +340,10 @@ func (s *WebsocketCliTrigger) readChannel(ctx context.Context) error {
 		s.logger.Warn(fmt.Sprintf("Unable to set liveness marker: %s", healthErr.Error()))
 	}
 
+	// As long as the websocket is connected, subtask status changes are pushed instead of polled
+	job.SetSubtaskSubscriber(s.client.SubscribeSubtask)
+	defer job.SetSubtaskSubscriber(nil)
+
 	defer func() {
 		healthErr := s.healthChecker.RemoveLivenessMark()
 		s.logger.Info("Going to remove liveness mark")
@@ -325,21 +374,7 @@ func (s *WebsocketCliTrigger) readChannel(ctx context.Context) error {
 					return &WebsocketMessageError{err: m}
 				}
 			case TriggerEvent:
-				s.subscriberMutex.Lock()
-				wg := sync.WaitGroup{}
-
-				for i := range s.subscribers {
-					wg.Add(1)
-
-					go func(subscriber TriggerHandler) {
-						defer wg.Done()
-
-						subscriber.HandleTriggerEvent(ctx, &m)
-					}(s.subscribers[i])
-				}
-
-				wg.Wait()
-				s.subscriberMutex.Unlock()
+				s.publish(ctx, &m)
 			}
 		}
 	}
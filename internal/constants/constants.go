@@ -9,6 +9,14 @@ var KnownFlags = map[string]struct{}{
 	DomainFlag:                  {},
 	ApiUserFlag:                 {},
 	ApiSecretFlag:               {},
+	AuthMethodFlag:              {},
+	OidcTokenUrlFlag:            {},
+	OidcScopesFlag:              {},
+	TokenCacheFlag:              {},
+	ProxyUrlFlag:                {},
+	CaBundleFlag:                {},
+	ClientCertFlag:              {},
+	ClientKeyFlag:               {},
 	ConfigFileFlag:              {},
 	FrequencyFlag:               {},
 	CronFlag:                    {},
@@ -28,6 +36,53 @@ var KnownFlags = map[string]struct{}{
 	ReplaceGroupsFlag:           {},
 	FileBackupLocationFlag:      {},
 	MaximumBackupsPerTargetFlag: {},
+
+	GraphqlMaxRetriesFlag:              {},
+	GraphqlRetryBackoffFlag:            {},
+	GraphqlMaxRetryBackoffFlag:         {},
+	GraphqlRequestTimeoutFlag:          {},
+	GraphqlCircuitBreakerThresholdFlag: {},
+	GraphqlCircuitBreakerCooldownFlag:  {},
+
+	MultipartUploadThresholdFlag:   {},
+	MultipartUploadPartSizeFlag:    {},
+	MultipartUploadConcurrencyFlag: {},
+
+	HttpTriggerAddressFlag: {},
+	HttpTriggerTokenFlag:   {},
+	ControlSocketFlag:      {},
+	TriggerQueueDirFlag:    {},
+	ApUpdateDebounceFlag:   {},
+	ApUpdateMaxDelayFlag:   {},
+
+	BlackoutWindows: {},
+	LockRules:       {},
+
+	LeaderElectionFlag:         {},
+	LeaderElectionLockFlag:     {},
+	LeaderElectionTtlFlag:      {},
+	LeaderElectionIdentityFlag: {},
+
+	RetriesFlag:      {},
+	RetryBackoffFlag: {},
+	RetryOnFlag:      {},
+
+	ResumeFlag:      {},
+	AutoResumeFlag:  {},
+	RunStateDirFlag: {},
+
+	ScheduleJitterFlag:     {},
+	ScheduleJitterModeFlag: {},
+	ScheduleJitterKeyFlag:  {},
+	ScheduleStaggerFlag:    {},
+
+	DisableConcurrentLanesFlag: {},
+
+	RecordFlag:                  {},
+	RecordAllowedParametersFlag: {},
+
+	IncrementalDataSourceSyncFlag:  {},
+	FullDataSourceSyncIntervalFlag: {},
 }
 
 const (
@@ -40,6 +95,14 @@ const (
 	DomainFlag                               = "domain"
 	ApiUserFlag                              = "api-user"
 	ApiSecretFlag                            = "api-secret"
+	AuthMethodFlag                           = "auth-method"
+	OidcTokenUrlFlag                         = "oidc-token-url"
+	OidcScopesFlag                           = "oidc-scopes"
+	TokenCacheFlag                           = "token-cache"
+	ProxyUrlFlag                             = "proxy-url"
+	CaBundleFlag                             = "ca-bundle"
+	ClientCertFlag                           = "client-cert"
+	ClientKeyFlag                            = "client-key"
 	ConfigFileFlag                           = "config-file"
 	FrequencyFlag                            = "frequency"
 	CronFlag                                 = "cron"
@@ -62,6 +125,47 @@ const (
 	DisableLogForwardingResourceProviderSync = "disable-log-forwarding-resource-provider-sync"
 	DisableLogForwardingTagSync              = "disable-log-forwarding-tag-sync"
 
+	// Retry policy for the calls to the Raito GraphQL API
+	GraphqlMaxRetriesFlag              = "graphql-max-retries"
+	GraphqlRetryBackoffFlag            = "graphql-retry-backoff"
+	GraphqlMaxRetryBackoffFlag         = "graphql-max-retry-backoff"
+	GraphqlRequestTimeoutFlag          = "graphql-request-timeout"
+	GraphqlCircuitBreakerThresholdFlag = "graphql-circuit-breaker-threshold"
+	GraphqlCircuitBreakerCooldownFlag  = "graphql-circuit-breaker-cooldown"
+
+	// Local HTTP trigger endpoint
+	HttpTriggerAddressFlag = "http-trigger-address"
+	HttpTriggerTokenFlag   = "http-trigger-token"
+	ControlSocketFlag      = "control-socket"
+	TriggerQueueDirFlag    = "trigger-queue-dir"
+	ApUpdateDebounceFlag   = "ap-update-debounce"
+	ApUpdateMaxDelayFlag   = "ap-update-max-delay"
+
+	// Leader election between multiple instances of the daemon
+	LeaderElectionFlag         = "leader-election"
+	LeaderElectionLockFlag     = "leader-election-lock"
+	LeaderElectionTtlFlag      = "leader-election-ttl"
+	LeaderElectionIdentityFlag = "leader-election-identity"
+
+	// Retry policy for failed targets
+	RetriesFlag      = "target-retries"
+	RetryBackoffFlag = "target-retry-backoff"
+	RetryOnFlag      = "target-retry-on"
+
+	// Resuming failed runs
+	ResumeFlag      = "resume"
+	AutoResumeFlag  = "auto-resume"
+	RunStateDirFlag = "run-state-dir"
+
+	// Spreading scheduled runs
+	ScheduleJitterFlag     = "schedule-jitter"
+	ScheduleJitterModeFlag = "schedule-jitter-mode"
+	ScheduleJitterKeyFlag  = "schedule-jitter-key"
+	ScheduleStaggerFlag    = "schedule-stagger"
+
+	// Lanes of the daemon
+	DisableConcurrentLanesFlag = "disable-concurrent-lanes"
+
 	// Locking parameters
 	LockAllWhoFlag            = "lock-all-who"
 	LockWhoByNameFlag         = "lock-who-by-name"
@@ -103,6 +207,19 @@ const (
 	DeleteTempFilesFlag         = "delete-temp-files"
 	MaximumFileSizesFlag        = "maximum-file-size"
 
+	// Multipart upload config tags
+	MultipartUploadThresholdFlag   = "multipart-upload-threshold"
+	MultipartUploadPartSizeFlag    = "multipart-upload-part-size"
+	MultipartUploadConcurrencyFlag = "multipart-upload-concurrency"
+
+	// Records the interactions with the connector plugins to replay them with 'raito dev replay'
+	RecordFlag                  = "record"
+	RecordAllowedParametersFlag = "record-allowed-parameters"
+
+	// Incremental data source syncs
+	IncrementalDataSourceSyncFlag  = "incremental-data-source-sync"
+	FullDataSourceSyncIntervalFlag = "full-data-source-sync-interval"
+
 	TagOverwriteKeyForAccessProviderName   = "tag-overwrite-key-for-access-provider-name"
 	TagOverwriteKeyForAccessProviderOwners = "tag-overwrite-key-for-access-provider-owners"
 	TagOverwriteKeyForDataObjectOwners     = "tag-overwrite-key-for-data-object-owners"
@@ -120,8 +237,23 @@ const (
 	// For the apply-access command
 	FilterAccessFlag = "filter-access"
 
+	// For the trigger command
+	TriggerTargetFlag = "target"
+	TriggerSyncFlag   = "sync"
+
+	// For the dev command. These are read from the command itself instead of viper, as some names are shared with other commands.
+	DevSyncFlag             = "sync"
+	DevTargetFlag           = "target"
+	DevParamFlag            = "param"
+	DevOutputFlag           = "output"
+	DevAccessFileFlag       = "access-file"
+	DevAddressFlag          = "address"
+	DevInteractionsFileFlag = "interactions-file"
+
 	Targets             = "targets"
 	DataObjectEnrichers = "data-object-enrichers"
+	BlackoutWindows     = "blackout-windows"
+	LockRules           = "lock-rules"
 	Repositories        = "repositories"
 
 	GitHubToken = "token"
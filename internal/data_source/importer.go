@@ -2,6 +2,7 @@ package data_source
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -26,6 +27,9 @@ type DataSourceImportConfig struct {
 	// TagSourcesScope is the set of sources that will be looked at when merging the tags. Tags with other sources will remain untouched.
 	// If not specified, the default is to take all sources for which tags are defined in the import file.
 	TagSourcesScope []string `json:"tagSourcesScope"`
+
+	// DeletedDataObjects contains the external ids of the data objects that were deleted since the last sync (in case of an incremental sync).
+	DeletedDataObjects []string
 }
 
 type DataSourceImporter interface {
@@ -80,7 +84,7 @@ func (d *dataSourceImporter) doImport(jobId, fileKey string) (job.JobStatus, str
             dataSource: \"%s\",
             deleteUntouched: %t,
             fileKey: \"%s\",
-            tagSourcesScope: %s
+            tagSourcesScope: %s%s
           }
         }) {
           subtask {
@@ -88,7 +92,7 @@ func (d *dataSourceImporter) doImport(jobId, fileKey string) (job.JobStatus, str
             subtaskId
           }
         }
-    }" }"`, jobId, d.config.DataSourceId, d.config.DeleteUntouched, fileKey, strings.ReplaceAll(tag.SerializeTagList(d.config.TagSourcesScope), "\"", "\\\""))
+    }" }"`, jobId, d.config.DataSourceId, d.config.DeleteUntouched, fileKey, strings.ReplaceAll(tag.SerializeTagList(d.config.TagSourcesScope), "\"", "\\\""), d.deletedDataObjectsSetting())
 
 	gqlQuery = strings.ReplaceAll(gqlQuery, "\n", "\\n")
 
@@ -106,6 +110,25 @@ func (d *dataSourceImporter) doImport(jobId, fileKey string) (job.JobStatus, str
 	return subtask.Status, subtask.SubtaskId, nil
 }
 
+// deletedDataObjectsSetting returns the import setting containing the deleted data objects (escaped to be used inside the JSON request), if any.
+func (d *dataSourceImporter) deletedDataObjectsSetting() string {
+	if len(d.config.DeletedDataObjects) == 0 {
+		return ""
+	}
+
+	ids := make([]string, 0, len(d.config.DeletedDataObjects))
+
+	for _, externalId := range d.config.DeletedDataObjects {
+		// Marshalling a string can't fail. The result is a valid GraphQL string literal.
+		id, _ := json.Marshal(externalId)
+		ids = append(ids, jsonStringEscaper.Replace(string(id)))
+	}
+
+	return ",\n            deletedDataObjects: [" + strings.Join(ids, ", ") + "]"
+}
+
+var jsonStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
 type subtaskResponse struct {
 	Status    job.JobStatus `json:"status"`
 	SubtaskId string        `json:"subtaskId"`
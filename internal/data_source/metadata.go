@@ -63,7 +63,8 @@ func SetMetaData(ctx context.Context, config *types.BaseTargetConfig, metadata *
 		ShareMetadata:         metadata.ShareMetadata,
 	}
 
-	err = graphql.NewClient(&config.BaseConfig).Mutate(ctx, &m, map[string]interface{}{"id": graphql2.ID(config.DataSourceId), "input": input})
+	// Setting the metadata can safely be retried as it overwrites the existing metadata
+	err = graphql.NewClient(&config.BaseConfig).Mutate(graphql.WithIdempotent(ctx), &m, map[string]interface{}{"id": graphql2.ID(config.DataSourceId), "input": input})
 	if err != nil {
 		err = graphql.ParseErrors(err)
 
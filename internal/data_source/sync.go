@@ -2,8 +2,10 @@ package data_source
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -12,8 +14,10 @@ import (
 	"github.com/raito-io/cli/base/data_object_enricher"
 	dspc "github.com/raito-io/cli/base/data_source"
 	baseconfig "github.com/raito-io/cli/base/util/config"
+	"github.com/raito-io/cli/internal/constants"
 	"github.com/raito-io/cli/internal/job"
 	"github.com/raito-io/cli/internal/plugin"
+	"github.com/raito-io/cli/internal/runstate"
 	"github.com/raito-io/cli/internal/target/types"
 	"github.com/raito-io/cli/internal/util/file"
 	"github.com/raito-io/cli/internal/util/tag"
@@ -34,7 +38,17 @@ type DataSourceSync struct {
 	TargetConfig *types.BaseTargetConfig
 	JobId        string
 
+	// RunState keeps the position of the last successful sync of the data source and the checkpoints of the current sync.
+	// Incremental syncs and checkpoints are not possible if nil.
+	RunState *runstate.Store
+
+	// ResumeCheckpoint indicates whether the sync continues from the checkpoints of the previous (failed) attempt.
+	ResumeCheckpoint bool
+
 	result *job.TaskResult
+
+	// pendingWatermark is stored once the import of the synced data objects finished successfully
+	pendingWatermark *runstate.Watermark
 }
 
 func (s *DataSourceSync) IsClientValid(ctx context.Context, c plugin.PluginClient) (bool, error) {
@@ -73,6 +87,25 @@ func (s *DataSourceSync) StartSyncAndQueueTaskPart(ctx context.Context, client p
 		DataObjectExcludes: s.TargetConfig.DataObjectExcludes,
 	}
 
+	syncStart := time.Now()
+
+	lastWatermark := s.incrementalWatermark(syncStart)
+	if lastWatermark != nil {
+		deletedObjectsFile, err2 := filepath.Abs(file.CreateUniqueFileNameForTarget(s.TargetConfig.Name, "fromTarget-deletedDataObjects", "json"))
+		if err2 != nil {
+			return job.Failed, "", err2
+		}
+
+		defer s.handleOptionalTempFile(deletedObjectsFile)
+
+		syncerConfig.Incremental = true
+		syncerConfig.Watermark = lastWatermark.Cursor
+		syncerConfig.LastSuccessfulSync = lastWatermark.LastSync.Unix()
+		syncerConfig.DeletedObjectsFile = deletedObjectsFile
+	} else {
+		syncerConfig.CheckpointDir = s.prepareCheckpointDir()
+	}
+
 	dss, err := client.GetDataSourceSyncer()
 	if err != nil {
 		return job.Failed, "", err
@@ -103,6 +136,32 @@ func (s *DataSourceSync) StartSyncAndQueueTaskPart(ctx context.Context, client p
 		return job.Failed, "", errors.New(res.Error.ErrorMessage) //nolint:staticcheck
 	}
 
+	if len(res.PartFiles) > 0 {
+		s.TargetConfig.TargetLogger.Debug(fmt.Sprintf("Combining %d data object files written at checkpoints", len(res.PartFiles)))
+
+		err = file.StitchJSONArrays(res.PartFiles, targetFile)
+		if err != nil {
+			return job.Failed, "", err
+		}
+	}
+
+	incremental := syncerConfig.Incremental && res.Incremental
+
+	var deletedDataObjects []string
+
+	if incremental {
+		deletedDataObjects, err = readDeletedDataObjects(syncerConfig.DeletedObjectsFile)
+		if err != nil {
+			return job.Failed, "", err
+		}
+
+		s.TargetConfig.TargetLogger.Info(fmt.Sprintf("Incrementally synced %d changed and %d deleted data objects", res.DataObjects, len(deletedDataObjects)))
+	} else if syncerConfig.Incremental {
+		s.TargetConfig.TargetLogger.Info("The connector doesn't support incremental syncs and executed a full data source sync")
+	}
+
+	s.pendingWatermark = s.nextWatermark(lastWatermark, res.Watermark, incremental, syncStart)
+
 	// Fetching the tagSource from the plugin
 	tagSourcesScope, err := tag.FetchTagSourceFromPlugin(ctx, client, nil)
 	if err != nil {
@@ -140,15 +199,16 @@ func (s *DataSourceSync) StartSyncAndQueueTaskPart(ctx context.Context, client p
 	}
 
 	deleteUntouched := s.TargetConfig.DeleteUntouched
-	if deleteUntouched && s.TargetConfig.DataObjectParent != nil && *s.TargetConfig.DataObjectParent != "" {
+	if deleteUntouched && (incremental || s.isPartialSync()) {
 		deleteUntouched = false
 	}
 
 	importerConfig := DataSourceImportConfig{
-		BaseTargetConfig: *s.TargetConfig,
-		TargetFile:       toProcessFile,
-		DeleteUntouched:  deleteUntouched,
-		TagSourcesScope:  tagSourcesScope,
+		BaseTargetConfig:   *s.TargetConfig,
+		TargetFile:         toProcessFile,
+		DeleteUntouched:    deleteUntouched,
+		TagSourcesScope:    tagSourcesScope,
+		DeletedDataObjects: deletedDataObjects,
 	}
 	dsImporter := NewDataSourceImporter(&importerConfig, statusUpdater)
 
@@ -168,6 +228,113 @@ func (s *DataSourceSync) StartSyncAndQueueTaskPart(ctx context.Context, client p
 	return status, subtaskId, nil
 }
 
+func (s *DataSourceSync) isPartialSync() bool {
+	return s.TargetConfig.DataObjectParent != nil && *s.TargetConfig.DataObjectParent != ""
+}
+
+// prepareCheckpointDir returns the directory in which the connector can store checkpoints, or an empty string if checkpoints are not possible.
+func (s *DataSourceSync) prepareCheckpointDir() string {
+	if s.RunState == nil {
+		return ""
+	}
+
+	dir, err := s.RunState.PrepareCheckpointDir(s.TargetConfig, constants.DataSourceSync, s.ResumeCheckpoint)
+	if err != nil {
+		s.TargetConfig.TargetLogger.Warn(fmt.Sprintf("Executing the data source sync without checkpoints: %s", err.Error()))
+
+		return ""
+	}
+
+	return dir
+}
+
+// incrementalWatermark returns the watermark of the last sync to continue from incrementally, or nil if a full sync is needed.
+func (s *DataSourceSync) incrementalWatermark(now time.Time) *runstate.Watermark {
+	policy := &s.TargetConfig.IncrementalSync
+	logger := s.TargetConfig.TargetLogger
+
+	if !policy.Enabled || s.isPartialSync() {
+		return nil
+	}
+
+	if s.RunState == nil {
+		logger.Info("Executing a full data source sync, as incremental syncs are only done in scheduled runs that keep a run state")
+
+		return nil
+	}
+
+	watermark, err := s.RunState.LoadWatermark(s.TargetConfig.DataSourceId)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Executing a full data source sync, as the position of the last sync can't be loaded: %s", err.Error()))
+
+		return nil
+	}
+
+	switch {
+	case watermark == nil:
+		logger.Info("Executing a full data source sync, as there is no previous sync to continue from")
+	case watermark.Inputs != runstate.Fingerprint(s.TargetConfig, constants.DataSourceSync):
+		logger.Info("Executing a full data source sync, as the configuration of the target changed since the last sync")
+	case policy.NeedsFullSync(watermark.LastFullSync, now):
+		logger.Info(fmt.Sprintf("Executing a full data source sync, as the last one was executed at %s", watermark.LastFullSync.Format(time.RFC822)))
+	default:
+		logger.Info(fmt.Sprintf("Executing an incremental data source sync, continuing from the sync at %s", watermark.LastSync.Format(time.RFC822)))
+
+		return watermark
+	}
+
+	return nil
+}
+
+// nextWatermark returns the watermark to store when the current sync finishes successfully, or nil if it shouldn't be stored.
+func (s *DataSourceSync) nextWatermark(last *runstate.Watermark, cursor string, incremental bool, syncStart time.Time) *runstate.Watermark {
+	if !s.TargetConfig.IncrementalSync.Enabled || s.RunState == nil || s.isPartialSync() {
+		return nil
+	}
+
+	watermark := &runstate.Watermark{
+		DataSourceId: s.TargetConfig.DataSourceId,
+		Cursor:       cursor,
+		LastSync:     syncStart,
+		LastFullSync: syncStart,
+		Inputs:       runstate.Fingerprint(s.TargetConfig, constants.DataSourceSync),
+	}
+
+	if incremental && last != nil {
+		watermark.LastFullSync = last.LastFullSync
+	}
+
+	return watermark
+}
+
+// handleOptionalTempFile handles a temporary file that is only created by the connector in specific cases.
+func (s *DataSourceSync) handleOptionalTempFile(path string) {
+	if _, err := os.Stat(path); err == nil {
+		s.TargetConfig.HandleTempFile(path, false)
+	}
+}
+
+// readDeletedDataObjects reads the external ids of the deleted data objects. The file is only created if there are deleted data objects.
+func readDeletedDataObjects(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading deleted data objects: %w", err)
+	}
+
+	var deleted []string
+
+	err = json.Unmarshal(data, &deleted)
+	if err != nil {
+		return nil, fmt.Errorf("parsing deleted data objects: %w", err)
+	}
+
+	return deleted, nil
+}
+
 func (s *DataSourceSync) enrichDataObjects(ctx context.Context, sourceFile string, tagSourcesScope []string) (string, []string, []string, error) {
 	enrichedFile := sourceFile
 
@@ -219,7 +386,7 @@ func (s *DataSourceSync) postProcessDataObjects(postProcessor PostProcessor, toP
 }
 
 func (s *DataSourceSync) callEnricher(ctx context.Context, enricher *types.EnricherConfig, sourceFile string, index int, tagSourcesScope []string) (string, int, []string, error) {
-	client, err := plugin.NewPluginClient(enricher.ConnectorName, enricher.ConnectorVersion, s.TargetConfig.TargetLogger)
+	client, err := plugin.NewTargetPluginClient(fmt.Sprintf("%s/enricher-%d", s.TargetConfig.Name, index), enricher.ConnectorName, enricher.ConnectorVersion, s.TargetConfig.TargetLogger)
 	if err != nil {
 		s.TargetConfig.TargetLogger.Error(fmt.Sprintf("Error initializing enricher plugin %q: %s", enricher.ConnectorName, err.Error()))
 		return "", 0, tagSourcesScope, fmt.Errorf("creating client for plugin %s: %w", enricher.ConnectorName, err)
@@ -280,6 +447,22 @@ func (s *DataSourceSync) ProcessResults(results interface{}) error {
 			Failed:     len(dsResult.Warnings),
 		}
 
+		if s.pendingWatermark != nil {
+			err := s.RunState.SaveWatermark(s.pendingWatermark)
+			if err != nil {
+				s.TargetConfig.TargetLogger.Warn(fmt.Sprintf("Unable to store the position of the data source sync. The next sync will be a full sync: %s", err.Error()))
+			}
+
+			s.pendingWatermark = nil
+		}
+
+		if s.RunState != nil {
+			err := s.RunState.ClearCheckpointDir(s.TargetConfig.Name, constants.DataSourceSync)
+			if err != nil {
+				s.TargetConfig.TargetLogger.Warn(fmt.Sprintf("Unable to remove the checkpoints of the data source sync: %s", err.Error()))
+			}
+		}
+
 		return nil
 	}
 
@@ -0,0 +1,137 @@
+package data_source
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/runstate"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+func incrementalTestSync(t *testing.T) *DataSourceSync {
+	t.Helper()
+
+	cfg := &types.BaseTargetConfig{
+		Name:            "snowflake1",
+		ConnectorName:   "raito-io/cli-plugin-snowflake",
+		DataSourceId:    "ds1",
+		IncrementalSync: types.IncrementalSyncPolicy{Enabled: true, FullSyncInterval: 24 * time.Hour},
+		TargetLogger:    hclog.NewNullLogger(),
+	}
+	cfg.Parameters = map[string]string{"sf-account": "somewhere"}
+
+	return &DataSourceSync{TargetConfig: cfg, JobId: "job1", RunState: runstate.NewStore(t.TempDir())}
+}
+
+func TestDataSourceSync_IncrementalWatermark(t *testing.T) {
+	now := time.Date(2024, 10, 2, 8, 0, 0, 0, time.UTC)
+
+	s := incrementalTestSync(t)
+
+	// No previous sync
+	assert.Nil(t, s.incrementalWatermark(now))
+
+	watermark := &runstate.Watermark{
+		DataSourceId: "ds1",
+		Cursor:       "cursor-1",
+		LastSync:     now.Add(-time.Hour),
+		LastFullSync: now.Add(-2 * time.Hour),
+		Inputs:       runstate.Fingerprint(s.TargetConfig, constants.DataSourceSync),
+	}
+	require.NoError(t, s.RunState.SaveWatermark(watermark))
+
+	assert.Equal(t, watermark.Cursor, s.incrementalWatermark(now).Cursor)
+
+	// Periodic full sync
+	assert.Nil(t, s.incrementalWatermark(now.Add(23*time.Hour)))
+
+	// Partial syncs are never incremental
+	parent := "db1"
+	s.TargetConfig.DataObjectParent = &parent
+	assert.Nil(t, s.incrementalWatermark(now))
+
+	// Changed configuration
+	s.TargetConfig.DataObjectParent = nil
+	s.TargetConfig.Parameters["sf-account"] = "elsewhere"
+	assert.Nil(t, s.incrementalWatermark(now))
+
+	// Disabled
+	s.TargetConfig.Parameters["sf-account"] = "somewhere"
+	s.TargetConfig.IncrementalSync.Enabled = false
+	assert.Nil(t, s.incrementalWatermark(now))
+
+	// No run state
+	s.TargetConfig.IncrementalSync.Enabled = true
+	s.RunState = nil
+	assert.Nil(t, s.incrementalWatermark(now))
+}
+
+func TestDataSourceSync_NextWatermark(t *testing.T) {
+	start := time.Date(2024, 10, 2, 8, 0, 0, 0, time.UTC)
+	last := &runstate.Watermark{LastSync: start.Add(-time.Hour), LastFullSync: start.Add(-5 * time.Hour)}
+
+	s := incrementalTestSync(t)
+
+	full := s.nextWatermark(last, "cursor-2", false, start)
+	assert.Equal(t, "cursor-2", full.Cursor)
+	assert.Equal(t, start, full.LastSync)
+	assert.Equal(t, start, full.LastFullSync)
+
+	incremental := s.nextWatermark(last, "cursor-3", true, start)
+	assert.Equal(t, start, incremental.LastSync)
+	assert.Equal(t, last.LastFullSync, incremental.LastFullSync)
+
+	// The watermark is only stored after a successful import
+	require.NoError(t, s.ProcessResults(&DataSourceImportResult{}))
+
+	s.pendingWatermark = incremental
+	require.NoError(t, s.ProcessResults(&DataSourceImportResult{}))
+
+	stored, err := s.RunState.LoadWatermark("ds1")
+	require.NoError(t, err)
+	assert.Equal(t, "cursor-3", stored.Cursor)
+
+	s.TargetConfig.IncrementalSync.Enabled = false
+	assert.Nil(t, s.nextWatermark(last, "cursor-4", false, start))
+}
+
+func TestReadDeletedDataObjects(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deleted.json")
+
+	deleted, err := readDeletedDataObjects(path)
+	require.NoError(t, err)
+	assert.Empty(t, deleted)
+
+	require.NoError(t, os.WriteFile(path, []byte("[\n\"db1.schema1\",\n\"db1.schema2\"\n]"), 0600))
+
+	deleted, err = readDeletedDataObjects(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db1.schema1", "db1.schema2"}, deleted)
+}
+
+func TestDataSourceSync_PrepareCheckpointDir(t *testing.T) {
+	s := incrementalTestSync(t)
+
+	dir := s.prepareCheckpointDir()
+	require.NotEmpty(t, dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "checkpoint.json"), []byte("{}"), 0600))
+
+	// Checkpoints are only kept when resuming
+	s.ResumeCheckpoint = true
+	assert.Equal(t, dir, s.prepareCheckpointDir())
+	assert.FileExists(t, filepath.Join(dir, "checkpoint.json"))
+
+	s.ResumeCheckpoint = false
+	assert.Equal(t, dir, s.prepareCheckpointDir())
+	assert.NoFileExists(t, filepath.Join(dir, "checkpoint.json"))
+
+	s.RunState = nil
+	assert.Empty(t, s.prepareCheckpointDir())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"path/filepath"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/raito-io/cli/internal/constants"
 	"github.com/raito-io/cli/internal/job"
 	"github.com/raito-io/cli/internal/plugin"
+	"github.com/raito-io/cli/internal/runstate"
 	"github.com/raito-io/cli/internal/target/types"
 	"github.com/raito-io/cli/internal/util/file"
 	"github.com/raito-io/cli/internal/version_management"
@@ -25,6 +27,12 @@ type DataUsageSync struct {
 	TargetConfig *types.BaseTargetConfig
 	JobId        string
 
+	// RunState keeps the checkpoints of the current sync. Checkpoints are not possible if nil.
+	RunState *runstate.Store
+
+	// ResumeCheckpoint indicates whether the sync continues from the checkpoints of the previous (failed) attempt.
+	ResumeCheckpoint bool
+
 	result *job.TaskResult
 }
 
@@ -63,9 +71,10 @@ func (s *DataUsageSync) StartSyncAndQueueTaskPart(ctx context.Context, client pl
 	defer s.TargetConfig.HandleTempFile(targetFile, false)
 
 	syncerConfig := dupc.DataUsageSyncConfig{
-		ConfigMap:       &baseconfig.ConfigMap{Parameters: s.TargetConfig.Parameters},
+		ConfigMap:       &baseconfig.ConfigMap{},
 		TargetFile:      targetFile,
 		MaxBytesPerFile: s.GetMaxBytesPerFile(),
+		CheckpointDir:   s.prepareCheckpointDir(),
 	}
 
 	dus, err := client.GetDataUsageSyncer()
@@ -88,15 +97,7 @@ func (s *DataUsageSync) StartSyncAndQueueTaskPart(ctx context.Context, client pl
 		return job.Failed, "", err
 	}
 
-	if lastUsed != nil {
-		lastUsedValue := *lastUsed
-		syncerConfig.ConfigMap.Parameters["lastUsed"] = lastUsedValue.Format(time.RFC3339)
-	}
-
-	if firstUsed != nil {
-		firstUsedValue := *firstUsed
-		syncerConfig.ConfigMap.Parameters["firstUsed"] = firstUsedValue.Format(time.RFC3339)
-	}
+	syncerConfig.ConfigMap.Parameters = s.syncerParameters(firstUsed, lastUsed)
 
 	s.TargetConfig.TargetLogger.Info(fmt.Sprintf("Fetching usage data from the data source, using first used %v and last used %v", syncerConfig.ConfigMap.Parameters["firstUsed"], syncerConfig.ConfigMap.Parameters["lastUsed"]))
 
@@ -110,7 +111,10 @@ func (s *DataUsageSync) StartSyncAndQueueTaskPart(ctx context.Context, client pl
 	s.TargetConfig.TargetLogger.Info("Importing usage data into Raito")
 
 	var filesCreated []string
-	if len(res.TargetFiles) > 0 {
+	if len(res.PartFiles) > 0 {
+		// The files written at checkpoints are already split according to the maximum file size
+		filesCreated = res.PartFiles
+	} else if len(res.TargetFiles) > 0 {
 		filesCreated = res.TargetFiles
 	} else {
 		filesCreated = []string{targetFile}
@@ -130,6 +134,41 @@ func (s *DataUsageSync) StartSyncAndQueueTaskPart(ctx context.Context, client pl
 	return status, subtaskId, nil
 }
 
+// syncerParameters returns the parameters of the target, extended with the usage dates to sync from.
+// The parameters of the target are copied, so the dates don't end up in the fingerprint of the checkpoints of a retry.
+func (s *DataUsageSync) syncerParameters(firstUsed, lastUsed *time.Time) map[string]string {
+	parameters := maps.Clone(s.TargetConfig.Parameters)
+	if parameters == nil {
+		parameters = make(map[string]string)
+	}
+
+	if lastUsed != nil {
+		parameters["lastUsed"] = lastUsed.Format(time.RFC3339)
+	}
+
+	if firstUsed != nil {
+		parameters["firstUsed"] = firstUsed.Format(time.RFC3339)
+	}
+
+	return parameters
+}
+
+// prepareCheckpointDir returns the directory in which the connector can store checkpoints, or an empty string if checkpoints are not possible.
+func (s *DataUsageSync) prepareCheckpointDir() string {
+	if s.RunState == nil {
+		return ""
+	}
+
+	dir, err := s.RunState.PrepareCheckpointDir(s.TargetConfig, constants.DataUsageSync, s.ResumeCheckpoint)
+	if err != nil {
+		s.TargetConfig.TargetLogger.Warn(fmt.Sprintf("Executing the data usage sync without checkpoints: %s", err.Error()))
+
+		return ""
+	}
+
+	return dir
+}
+
 func (s *DataUsageSync) ProcessResults(results interface{}) error {
 	if duResult, ok := results.(*DataUsageImportResult); ok {
 		if duResult != nil && len(duResult.Warnings) > 0 {
@@ -149,6 +188,13 @@ func (s *DataUsageSync) ProcessResults(results interface{}) error {
 			Failed:     duResult.StatementsFailed,
 		}
 
+		if s.RunState != nil {
+			err := s.RunState.ClearCheckpointDir(s.TargetConfig.Name, constants.DataUsageSync)
+			if err != nil {
+				s.TargetConfig.TargetLogger.Warn(fmt.Sprintf("Unable to remove the checkpoints of the data usage sync: %s", err.Error()))
+			}
+		}
+
 		return nil
 	}
 
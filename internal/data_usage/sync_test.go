@@ -0,0 +1,49 @@
+package data_usage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/runstate"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+func TestDataUsageSync_RetryKeepsCheckpoints(t *testing.T) {
+	store := runstate.NewStore(t.TempDir())
+	cfg := &types.BaseTargetConfig{
+		Name:          "snowflake1",
+		ConnectorName: "raito-io/cli-plugin-snowflake",
+		DataSourceId:  "SnowflakeDataSource",
+		TargetLogger:  hclog.NewNullLogger(),
+	}
+
+	cfg.Parameters = map[string]string{"sf-account": "somewhere.eu-central-1"}
+
+	firstUsed := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastUsed := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	// First attempt, which fails after writing a checkpoint
+	sync := DataUsageSync{TargetConfig: cfg, RunState: store}
+
+	dir := sync.prepareCheckpointDir()
+	require.NotEmpty(t, dir)
+
+	parameters := sync.syncerParameters(&firstUsed, &lastUsed)
+	assert.Equal(t, "2024-01-01T00:00:00Z", parameters["firstUsed"])
+	assert.Equal(t, "2024-02-01T00:00:00Z", parameters["lastUsed"])
+	assert.Equal(t, map[string]string{"sf-account": "somewhere.eu-central-1"}, cfg.Parameters)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "checkpoint.json"), []byte("{}"), 0600))
+
+	// The retry resumes from the checkpoint of the first attempt
+	retry := DataUsageSync{TargetConfig: cfg, RunState: store, ResumeCheckpoint: true}
+
+	assert.Equal(t, dir, retry.prepareCheckpointDir())
+	assert.FileExists(t, filepath.Join(dir, "checkpoint.json"))
+}
@@ -0,0 +1,300 @@
+package dev
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/data_usage"
+	"github.com/raito-io/cli/base/identity_store"
+	"github.com/raito-io/cli/base/tag"
+	baseconfig "github.com/raito-io/cli/base/util/config"
+	"github.com/raito-io/cli/internal/plugin"
+	"github.com/raito-io/cli/internal/version_management"
+)
+
+const (
+	SyncDataSource    = "data-source"
+	SyncIdentityStore = "identity-store"
+	SyncAccessFrom    = "access-from"
+	SyncAccessTo      = "access-to"
+	SyncUsage         = "usage"
+	SyncTags          = "tags"
+)
+
+// DefaultDataSourceId and DefaultIdentityStoreId are passed to the connector if no ID is configured, as there is no Raito Cloud to get them from.
+const (
+	DefaultDataSourceId    = "local-data-source"
+	DefaultIdentityStoreId = "local-identity-store"
+)
+
+var SyncTypes = []string{SyncDataSource, SyncIdentityStore, SyncAccessFrom, SyncAccessTo, SyncUsage, SyncTags}
+
+// RunConfig describes which syncer of a connector to call and with which configuration.
+type RunConfig struct {
+	SyncType   string
+	Parameters map[string]string
+
+	// OutputDir is the folder in which the files produced by the syncer are written.
+	OutputDir string
+
+	DataSourceId    string
+	IdentityStoreId string
+
+	// AccessFile is the file with the access providers to push to the data source. Only used for the access-to sync.
+	AccessFile string
+}
+
+// Run calls the syncer of the configured sync type of the connector, without any communication with Raito Cloud.
+// It returns the files produced by the syncer.
+func Run(ctx context.Context, client plugin.PluginClient, config *RunConfig, logger hclog.Logger) ([]string, error) {
+	outputDir, err := filepath.Abs(config.OutputDir)
+	if err != nil {
+		return nil, fmt.Errorf("determine full path for output folder %q: %w", config.OutputDir, err)
+	}
+
+	err = os.MkdirAll(outputDir, 0750)
+	if err != nil {
+		return nil, fmt.Errorf("creating output folder %q: %w", config.OutputDir, err)
+	}
+
+	config.OutputDir = outputDir
+
+	if config.DataSourceId == "" {
+		config.DataSourceId = DefaultDataSourceId
+	}
+
+	if config.IdentityStoreId == "" {
+		config.IdentityStoreId = DefaultIdentityStoreId
+	}
+
+	configMap := &baseconfig.ConfigMap{Parameters: config.Parameters}
+
+	logger.Info(fmt.Sprintf("Running the %s sync", config.SyncType))
+
+	switch config.SyncType {
+	case SyncDataSource:
+		return runDataSourceSync(ctx, client, config, configMap)
+	case SyncIdentityStore:
+		return runIdentityStoreSync(ctx, client, config, configMap)
+	case SyncAccessFrom:
+		return runAccessFromTargetSync(ctx, client, config, configMap)
+	case SyncAccessTo:
+		return runAccessToTargetSync(ctx, client, config, configMap)
+	case SyncUsage:
+		return runDataUsageSync(ctx, client, config, configMap)
+	case SyncTags:
+		return runTagSync(ctx, client, config, configMap)
+	default:
+		return nil, fmt.Errorf("unknown sync type %q. Possible values are %s", config.SyncType, strings.Join(SyncTypes, ", "))
+	}
+}
+
+func runDataSourceSync(ctx context.Context, client plugin.PluginClient, config *RunConfig, configMap *baseconfig.ConfigMap) ([]string, error) {
+	syncer, err := client.GetDataSourceSyncer()
+	if err != nil {
+		return nil, fmt.Errorf("fetching the data source syncer: %w", err)
+	}
+
+	_, err = version_management.IsValidToSync(ctx, syncer, data_source.MinimalCliVersion)
+	if err != nil {
+		return nil, fmt.Errorf("checking version compatibility: %w", err)
+	}
+
+	targetFile := outputFile(config, "data-source.json")
+
+	_, err = syncer.SyncDataSource(ctx, &data_source.DataSourceSyncConfig{
+		ConfigMap:    configMap,
+		TargetFile:   targetFile,
+		DataSourceId: config.DataSourceId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("syncing data source: %w", err)
+	}
+
+	metaData, err := syncer.GetDataSourceMetaData(ctx, configMap)
+	if err != nil {
+		return nil, fmt.Errorf("fetching data source meta data: %w", err)
+	}
+
+	metaDataFile := outputFile(config, "data-source-metadata.json")
+
+	err = writeJSONFile(metaDataFile, metaData)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{targetFile, metaDataFile}, nil
+}
+
+func runIdentityStoreSync(ctx context.Context, client plugin.PluginClient, config *RunConfig, configMap *baseconfig.ConfigMap) ([]string, error) {
+	syncer, err := client.GetIdentityStoreSyncer()
+	if err != nil {
+		return nil, fmt.Errorf("fetching the identity store syncer: %w", err)
+	}
+
+	_, err = version_management.IsValidToSync(ctx, syncer, identity_store.MinimalCliVersion)
+	if err != nil {
+		return nil, fmt.Errorf("checking version compatibility: %w", err)
+	}
+
+	userFile := outputFile(config, "users.json")
+	groupFile := outputFile(config, "groups.json")
+
+	_, err = syncer.SyncIdentityStore(ctx, &identity_store.IdentityStoreSyncConfig{
+		ConfigMap: configMap,
+		UserFile:  userFile,
+		GroupFile: groupFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("syncing identity store: %w", err)
+	}
+
+	return []string{userFile, groupFile}, nil
+}
+
+func runAccessFromTargetSync(ctx context.Context, client plugin.PluginClient, config *RunConfig, configMap *baseconfig.ConfigMap) ([]string, error) {
+	syncer, err := accessSyncer(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	targetFile := outputFile(config, "access-from-target.json")
+
+	_, err = syncer.SyncFromTarget(ctx, &access_provider.AccessSyncFromTarget{
+		ConfigMap:  configMap,
+		TargetFile: targetFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("syncing access providers from target: %w", err)
+	}
+
+	return []string{targetFile}, nil
+}
+
+func runAccessToTargetSync(ctx context.Context, client plugin.PluginClient, config *RunConfig, configMap *baseconfig.ConfigMap) ([]string, error) {
+	if config.AccessFile == "" {
+		return nil, fmt.Errorf("an access file is required for the %s sync", SyncAccessTo)
+	}
+
+	sourceFile, err := filepath.Abs(config.AccessFile)
+	if err != nil {
+		return nil, fmt.Errorf("determine full path for file %q: %w", config.AccessFile, err)
+	}
+
+	_, err = os.Stat(sourceFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read file %q: %w", config.AccessFile, err)
+	}
+
+	syncer, err := accessSyncer(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	feedbackFile := outputFile(config, "access-feedback.json")
+
+	_, err = syncer.SyncToTarget(ctx, &access_provider.AccessSyncToTarget{
+		ConfigMap:          configMap,
+		SourceFile:         sourceFile,
+		FeedbackTargetFile: feedbackFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("syncing access providers to target: %w", err)
+	}
+
+	return []string{feedbackFile}, nil
+}
+
+func accessSyncer(ctx context.Context, client plugin.PluginClient) (access_provider.AccessSyncer, error) {
+	syncer, err := client.GetAccessSyncer()
+	if err != nil {
+		return nil, fmt.Errorf("fetching the access syncer: %w", err)
+	}
+
+	_, err = version_management.IsValidToSync(ctx, syncer, access_provider.MinimalCliVersion)
+	if err != nil {
+		return nil, fmt.Errorf("checking version compatibility: %w", err)
+	}
+
+	return syncer, nil
+}
+
+func runDataUsageSync(ctx context.Context, client plugin.PluginClient, config *RunConfig, configMap *baseconfig.ConfigMap) ([]string, error) {
+	syncer, err := client.GetDataUsageSyncer()
+	if err != nil {
+		return nil, fmt.Errorf("fetching the data usage syncer: %w", err)
+	}
+
+	_, err = version_management.IsValidToSync(ctx, syncer, data_usage.MinimalCliVersion)
+	if err != nil {
+		return nil, fmt.Errorf("checking version compatibility: %w", err)
+	}
+
+	targetFile := outputFile(config, "usage.json")
+
+	result, err := syncer.SyncDataUsage(ctx, &data_usage.DataUsageSyncConfig{
+		ConfigMap:  configMap,
+		TargetFile: targetFile,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("syncing data usage: %w", err)
+	}
+
+	if len(result.GetTargetFiles()) > 0 {
+		return result.GetTargetFiles(), nil
+	}
+
+	return []string{targetFile}, nil
+}
+
+func runTagSync(ctx context.Context, client plugin.PluginClient, config *RunConfig, configMap *baseconfig.ConfigMap) ([]string, error) {
+	syncer, err := client.GetTagSyncer()
+	if err != nil {
+		return nil, fmt.Errorf("fetching the tag syncer: %w", err)
+	}
+
+	_, err = version_management.IsValidToSync(ctx, syncer, tag.MinimalCliVersion)
+	if err != nil {
+		return nil, fmt.Errorf("checking version compatibility: %w", err)
+	}
+
+	targetFile := outputFile(config, "tags.json")
+
+	_, err = syncer.SyncTags(ctx, &tag.TagSyncConfig{
+		ConfigMap:       configMap,
+		TargetFile:      targetFile,
+		DataSourceId:    config.DataSourceId,
+		IdentityStoreId: config.IdentityStoreId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("syncing tags: %w", err)
+	}
+
+	return []string{targetFile}, nil
+}
+
+func outputFile(config *RunConfig, name string) string {
+	return filepath.Join(config.OutputDir, name)
+}
+
+func writeJSONFile(path string, content interface{}) error {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serializing %q: %w", filepath.Base(path), err)
+	}
+
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("writing %q: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package dev
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/access_provider/sync_to_target"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/identity_store"
+	"github.com/raito-io/cli/base/util/config"
+	"github.com/raito-io/cli/base/wrappers"
+	"github.com/raito-io/cli/internal/plugin"
+)
+
+type testConnector struct {
+	parameters map[string]string
+}
+
+func (c *testConnector) GetDataSourceMetaData(_ context.Context, _ *config.ConfigMap) (*data_source.MetaData, error) {
+	return &data_source.MetaData{Type: "test"}, nil
+}
+
+func (c *testConnector) SyncDataSource(_ context.Context, handler wrappers.DataSourceObjectHandler, syncConfig *data_source.DataSourceSyncConfig) error {
+	c.parameters = syncConfig.ConfigMap.Parameters
+
+	return handler.AddDataObjects(&data_source.DataObject{ExternalId: "schema1", Name: "schema1", FullName: "schema1", Type: "schema"})
+}
+
+func (c *testConnector) SyncAccessProvidersFromTarget(_ context.Context, _ wrappers.AccessProviderHandler, _ *config.ConfigMap) error {
+	return nil
+}
+
+func (c *testConnector) SyncAccessProviderToTarget(_ context.Context, accessProviders *sync_to_target.AccessProviderImport, handler wrappers.AccessProviderFeedbackHandler, _ *config.ConfigMap) error {
+	for _, ap := range accessProviders.AccessProviders {
+		err := handler.AddAccessProviderFeedback(sync_to_target.AccessProviderSyncFeedback{AccessProvider: ap.Id, ActualName: "ROLE_" + ap.Name})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// testPluginClient only implements the data source and access syncers of the connector.
+type testPluginClient struct {
+	plugin.PluginClient
+
+	connector *testConnector
+}
+
+func (c *testPluginClient) GetDataSourceSyncer() (data_source.DataSourceSyncer, error) {
+	return wrappers.DataSourceSync(c.connector), nil
+}
+
+func (c *testPluginClient) GetAccessSyncer() (access_provider.AccessSyncer, error) {
+	return wrappers.DataAccessSync(c.connector), nil
+}
+
+func (c *testPluginClient) GetIdentityStoreSyncer() (identity_store.IdentityStoreSyncer, error) {
+	return nil, errors.New("identity store syncer not implemented")
+}
+
+func TestRun_DataSource(t *testing.T) {
+	client := &testPluginClient{connector: &testConnector{}}
+	outputDir := filepath.Join(t.TempDir(), "out")
+
+	files, err := Run(context.Background(), client, &RunConfig{
+		SyncType:   SyncDataSource,
+		Parameters: map[string]string{"account": "test"},
+		OutputDir:  outputDir,
+	}, hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join(outputDir, "data-source.json"), filepath.Join(outputDir, "data-source-metadata.json")}, files)
+	assert.Equal(t, map[string]string{"account": "test"}, client.connector.parameters)
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"externalId":"`+DefaultDataSourceId+`"`)
+	assert.Contains(t, string(content), `"externalId":"schema1"`)
+
+	metaData, err := os.ReadFile(files[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(metaData), `"type": "test"`)
+}
+
+func TestRun_AccessToTarget(t *testing.T) {
+	client := &testPluginClient{connector: &testConnector{}}
+	dir := t.TempDir()
+
+	accessFile := filepath.Join(dir, "access.yaml")
+	require.NoError(t, os.WriteFile(accessFile, []byte("accessProviders:\n  - id: ap1\n    name: ap1\n"), 0600))
+
+	files, err := Run(context.Background(), client, &RunConfig{
+		SyncType:   SyncAccessTo,
+		OutputDir:  dir,
+		AccessFile: accessFile,
+	}, hclog.NewNullLogger())
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	feedback, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(feedback), `"actualName":"ROLE_ap1"`)
+}
+
+func TestRun_AccessToTargetWithoutFile(t *testing.T) {
+	client := &testPluginClient{connector: &testConnector{}}
+
+	_, err := Run(context.Background(), client, &RunConfig{SyncType: SyncAccessTo, OutputDir: t.TempDir()}, hclog.NewNullLogger())
+	assert.ErrorContains(t, err, "an access file is required")
+}
+
+func TestRun_Errors(t *testing.T) {
+	client := &testPluginClient{connector: &testConnector{}}
+
+	_, err := Run(context.Background(), client, &RunConfig{SyncType: "unknown", OutputDir: t.TempDir()}, hclog.NewNullLogger())
+	assert.ErrorContains(t, err, `unknown sync type "unknown"`)
+
+	_, err = Run(context.Background(), client, &RunConfig{SyncType: SyncIdentityStore, OutputDir: t.TempDir()}, hclog.NewNullLogger())
+	assert.ErrorContains(t, err, "fetching the identity store syncer")
+}
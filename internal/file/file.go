@@ -15,6 +15,7 @@ import (
 
 	"github.com/raito-io/cli/internal/target/types"
 	"github.com/raito-io/cli/internal/util/connect"
+	"github.com/raito-io/cli/internal/util/httpclient"
 )
 
 func calculateChecksum(file io.Reader) (string, error) {
@@ -27,9 +28,10 @@ func calculateChecksum(file io.Reader) (string, error) {
 }
 
 // UploadFile uploads the file from the given path.
+// Files larger than the multipart upload threshold are uploaded in multiple parts.
 // It returns the key to use to pass to the Raito backend to use the file.
 func UploadFile(file string, config *types.BaseTargetConfig) (string, error) {
-	return uploadHashedFile(file, config, getUploadURL)
+	return uploadHashedFile(file, config, getUploadURL, true)
 }
 
 // UploadLogFile uploads the file from the given path.
@@ -37,10 +39,10 @@ func UploadFile(file string, config *types.BaseTargetConfig) (string, error) {
 func UploadLogFile(file string, config *types.BaseTargetConfig, task string) (string, error) {
 	return uploadHashedFile(file, config, func(config *types.BaseTargetConfig, checksum string, fileSize int64) (string, string, map[string][]string, error) {
 		return getUploadLogsURL(config, task, checksum, fileSize)
-	})
+	}, false)
 }
 
-func uploadHashedFile(file string, config *types.BaseTargetConfig, uploadURL func(config *types.BaseTargetConfig, checksum string, fileSize int64) (string, string, map[string][]string, error)) (string, error) {
+func uploadHashedFile(file string, config *types.BaseTargetConfig, uploadURL func(config *types.BaseTargetConfig, checksum string, fileSize int64) (string, string, map[string][]string, error), allowMultipart bool) (string, error) {
 	data, err := os.Open(file)
 	if err != nil {
 		return "", fmt.Errorf("open file: %w", err)
@@ -65,6 +67,13 @@ func uploadHashedFile(file string, config *types.BaseTargetConfig, uploadURL fun
 		return "", fmt.Errorf("stat file: %w", err)
 	}
 
+	if allowMultipart {
+		settings := multipartSettingsFromConfig(config)
+		if stats.Size() > settings.Threshold {
+			return uploadFileInParts(data, checksum, stats.Size(), &settings, config)
+		}
+	}
+
 	url, key, headers, err := uploadURL(config, checksum, stats.Size())
 
 	if err != nil {
@@ -97,7 +106,11 @@ func uploadFileToBucket(data *os.File, url string, key string, contentLength int
 			}
 		}
 
-		client := &http.Client{}
+		client, err := httpclient.NewClient()
+		if err != nil {
+			return retry.Unrecoverable(err)
+		}
+
 		res, err := client.Do(req)
 
 		if err != nil {
@@ -0,0 +1,369 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/c2h5oh/datasize"
+	"github.com/spf13/viper"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/target/types"
+	"github.com/raito-io/cli/internal/util/connect"
+	"github.com/raito-io/cli/internal/util/httpclient"
+)
+
+const (
+	defaultMultipartThreshold   = 100 * datasize.MB
+	defaultMultipartPartSize    = 16 * datasize.MB
+	defaultMultipartConcurrency = 4
+
+	// maximumParts is the maximum number of parts for a single upload, as imposed by S3.
+	maximumParts = 10000
+
+	partUploadAttempts = 5
+)
+
+// minimumPartSize is the minimum size of all but the last part, as imposed by S3.
+var minimumPartSize int64 = 5 * int64(datasize.MB)
+
+type multipartSettings struct {
+	Threshold   int64
+	PartSize    int64
+	Concurrency int
+}
+
+func multipartSettingsFromConfig(config *types.BaseTargetConfig) multipartSettings {
+	settings := multipartSettings{
+		Threshold:   int64(defaultMultipartThreshold),
+		PartSize:    int64(defaultMultipartPartSize),
+		Concurrency: defaultMultipartConcurrency,
+	}
+
+	if v := viper.GetString(constants.MultipartUploadThresholdFlag); v != "" {
+		var size datasize.ByteSize
+		if err := size.UnmarshalText([]byte(v)); err != nil {
+			config.TargetLogger.Warn(fmt.Sprintf("Error parsing multipart upload threshold: %s. Will use %s instead.", err.Error(), defaultMultipartThreshold.HR()))
+		} else {
+			settings.Threshold = int64(size.Bytes())
+		}
+	}
+
+	if v := viper.GetString(constants.MultipartUploadPartSizeFlag); v != "" {
+		var size datasize.ByteSize
+		if err := size.UnmarshalText([]byte(v)); err != nil {
+			config.TargetLogger.Warn(fmt.Sprintf("Error parsing multipart upload part size: %s. Will use %s instead.", err.Error(), defaultMultipartPartSize.HR()))
+		} else {
+			settings.PartSize = int64(size.Bytes())
+		}
+	}
+
+	if v := viper.GetInt(constants.MultipartUploadConcurrencyFlag); v > 0 {
+		settings.Concurrency = v
+	}
+
+	return settings
+}
+
+// partSizeFor returns the part size to use for a file of the given size, taking into account the minimum part size and maximum number of parts.
+func (s *multipartSettings) partSizeFor(fileSize int64) int64 {
+	partSize := s.PartSize
+
+	if partSize < minimumPartSize {
+		partSize = minimumPartSize
+	}
+
+	if minForMaxParts := (fileSize + maximumParts - 1) / maximumParts; partSize < minForMaxParts {
+		partSize = minForMaxParts
+	}
+
+	return partSize
+}
+
+type multipartUpload struct {
+	Key      string `json:"key"`
+	UploadId string `json:"uploadId"`
+}
+
+type completedPart struct {
+	PartNumber int    `json:"partNumber"`
+	ETag       string `json:"etag"`
+}
+
+// uploadFileInParts uploads the given file in multiple parts, which are uploaded in parallel and retried individually.
+// It returns the key to use to pass to the Raito backend to use the file.
+func uploadFileInParts(data *os.File, checksum string, fileSize int64, settings *multipartSettings, config *types.BaseTargetConfig) (string, error) {
+	start := time.Now()
+
+	partSize := settings.partSizeFor(fileSize)
+	nrOfParts := int((fileSize + partSize - 1) / partSize)
+
+	upload, err := startMultipartUpload(config, checksum, fileSize, partSize, nrOfParts)
+	if err != nil {
+		return "", err
+	}
+
+	config.TargetLogger.Debug(fmt.Sprintf("Started multipart upload for file with key %q (%d parts of %d bytes)", upload.Key, nrOfParts, partSize))
+
+	parts, err := uploadParts(data, upload, fileSize, partSize, nrOfParts, settings.Concurrency, config)
+	if err != nil {
+		abortMultipartUpload(config, upload)
+
+		return "", err
+	}
+
+	err = completeMultipartUpload(config, upload, parts)
+	if err != nil {
+		abortMultipartUpload(config, upload)
+
+		return "", err
+	}
+
+	sec := time.Since(start).Round(time.Millisecond)
+
+	config.TargetLogger.Info(fmt.Sprintf("Successfully uploaded file with key %q (%d bytes in %d parts) in %s.", upload.Key, fileSize, nrOfParts, sec))
+
+	return upload.Key, nil
+}
+
+func uploadParts(data *os.File, upload *multipartUpload, fileSize, partSize int64, nrOfParts, concurrency int, config *types.BaseTargetConfig) ([]completedPart, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	partNumbers := make(chan int)
+	parts := make([]completedPart, 0, nrOfParts)
+
+	var (
+		wg       sync.WaitGroup
+		m        sync.Mutex
+		firstErr error
+	)
+
+	for i := 0; i < concurrency && i < nrOfParts; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for partNumber := range partNumbers {
+				offset := int64(partNumber-1) * partSize
+				size := min(partSize, fileSize-offset)
+
+				etag, err := uploadPart(ctx, io.NewSectionReader(data, offset, size), upload, partNumber, size, config)
+
+				m.Lock()
+
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+
+					cancel()
+				} else {
+					parts = append(parts, completedPart{PartNumber: partNumber, ETag: etag})
+				}
+
+				m.Unlock()
+			}
+		}()
+	}
+
+	for partNumber := 1; partNumber <= nrOfParts && ctx.Err() == nil; partNumber++ {
+		select {
+		case partNumbers <- partNumber:
+		case <-ctx.Done():
+		}
+	}
+
+	close(partNumbers)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+
+	return parts, nil
+}
+
+// uploadPart uploads a single part and returns its ETag. Every attempt requests a new signed URL, so expired URLs are no problem when retrying.
+func uploadPart(ctx context.Context, part *io.SectionReader, upload *multipartUpload, partNumber int, size int64, config *types.BaseTargetConfig) (string, error) {
+	var etag string
+
+	err := retry.Do(func() error {
+		// Ensure to read the part from the beginning (in case of retries)
+		_, err := part.Seek(0, io.SeekStart)
+		if err != nil {
+			return retry.Unrecoverable(fmt.Errorf("error while seeking file: %s", err.Error()))
+		}
+
+		partURL, headers, err := getPartUploadURL(config, upload, partNumber, size)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "PUT", partURL, part)
+		if err != nil {
+			return retry.Unrecoverable(fmt.Errorf("error while executing upload of part %d: %s", partNumber, err.Error()))
+		}
+		req.ContentLength = size
+
+		for headerKey, headerValue := range headers {
+			for _, value := range headerValue {
+				req.Header.Add(headerKey, value)
+			}
+		}
+
+		client, err := httpclient.NewClient()
+		if err != nil {
+			return retry.Unrecoverable(err)
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("error while executing upload of part %d: %s", partNumber, err.Error())
+		}
+
+		defer res.Body.Close()
+
+		if res.StatusCode >= 300 {
+			buf, _ := io.ReadAll(res.Body)
+
+			return fmt.Errorf("error (HTTP %d) while executing upload of part %d: %s - %s", res.StatusCode, partNumber, res.Status, string(buf))
+		}
+
+		etag = res.Header.Get("ETag")
+		if etag == "" {
+			return fmt.Errorf("no ETag received for upload of part %d", partNumber)
+		}
+
+		return nil
+	}, retry.Context(ctx), retry.Attempts(partUploadAttempts), retry.DelayType(retry.BackOffDelay), retry.LastErrorOnly(true), retry.OnRetry(func(attempt uint, err error) {
+		config.TargetLogger.Warn(fmt.Sprintf("Failed to upload part %d of file with key %q. Will retry (%d/%d): %s", partNumber, upload.Key, attempt+1, partUploadAttempts-1, err.Error()))
+	}))
+
+	if err != nil {
+		return "", err
+	}
+
+	return etag, nil
+}
+
+func startMultipartUpload(config *types.BaseTargetConfig, checksum string, fileSize, partSize int64, nrOfParts int) (*multipartUpload, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"sha256":        checksum,
+		"contentLength": fileSize,
+		"partSize":      partSize,
+		"parts":         nrOfParts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while creating multipart upload request: %s", err.Error())
+	}
+
+	resp, err := connect.DoPostToRaito("file/upload/multipart/start", string(body), "application/json", &config.BaseConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error while trying to start a multipart upload: %s", err.Error())
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("error (HTTP %d) while trying to start a multipart upload: %s", resp.StatusCode, resp.Status)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading result body for starting multipart upload: %s", err.Error())
+	}
+
+	var upload multipartUpload
+
+	err = json.Unmarshal(respBody, &upload)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing result body for starting multipart upload: %s", err.Error())
+	}
+
+	if upload.Key == "" || upload.UploadId == "" {
+		return nil, fmt.Errorf("invalid response received when starting multipart upload: %s", string(respBody))
+	}
+
+	return &upload, nil
+}
+
+func getPartUploadURL(config *types.BaseTargetConfig, upload *multipartUpload, partNumber int, size int64) (string, map[string][]string, error) {
+	params := url.Values{}
+	params.Add("key", upload.Key)
+	params.Add("uploadId", upload.UploadId)
+	params.Add("partNumber", fmt.Sprintf("%d", partNumber))
+	params.Add("contentLength", fmt.Sprintf("%d", size))
+
+	partURL, _, headers, err := getUploadUrlAndKey(config, "file/upload/multipart/signed-url?"+params.Encode())
+
+	return partURL, headers, err
+}
+
+func completeMultipartUpload(config *types.BaseTargetConfig, upload *multipartUpload, parts []completedPart) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"key":      upload.Key,
+		"uploadId": upload.UploadId,
+		"parts":    parts,
+	})
+	if err != nil {
+		return fmt.Errorf("error while creating multipart upload completion request: %s", err.Error())
+	}
+
+	return retry.Do(func() error {
+		resp, err := connect.DoPostToRaito("file/upload/multipart/complete", string(body), "application/json", &config.BaseConfig)
+		if err != nil {
+			return fmt.Errorf("error while trying to complete multipart upload: %s", err.Error())
+		}
+
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			buf, _ := io.ReadAll(resp.Body)
+			err = fmt.Errorf("error (HTTP %d) while trying to complete multipart upload: %s - %s", resp.StatusCode, resp.Status, string(buf))
+
+			if resp.StatusCode < 500 {
+				return retry.Unrecoverable(err)
+			}
+
+			return err
+		}
+
+		return nil
+	}, retry.Attempts(3), retry.DelayType(retry.BackOffDelay), retry.LastErrorOnly(true))
+}
+
+// abortMultipartUpload makes sure the storage of the parts that were already uploaded is freed up. Errors are only logged.
+func abortMultipartUpload(config *types.BaseTargetConfig, upload *multipartUpload) {
+	body, err := json.Marshal(upload)
+	if err != nil {
+		return
+	}
+
+	resp, err := connect.DoPostToRaito("file/upload/multipart/abort", string(body), "application/json", &config.BaseConfig)
+	if err != nil {
+		config.TargetLogger.Warn(fmt.Sprintf("Failed to abort multipart upload for file with key %q: %s", upload.Key, err.Error()))
+
+		return
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		config.TargetLogger.Warn(fmt.Sprintf("Failed to abort multipart upload for file with key %q: HTTP %d", upload.Key, resp.StatusCode))
+	}
+}
@@ -0,0 +1,304 @@
+package file
+
+import (
+	"bytes"
+	"crypto/md5" //nolint:gosec
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/target/types"
+	"github.com/raito-io/cli/internal/util/test"
+)
+
+// fakeMultipartServer emulates both the Raito backend endpoints for multipart uploads and the S3 multipart semantics of the bucket.
+type fakeMultipartServer struct {
+	*httptest.Server
+
+	m            sync.Mutex
+	uploadCount  int
+	uploads      map[string]map[int][]byte
+	uploadKeys   map[string]string
+	objects      map[string][]byte
+	aborted      []string
+	partFailures map[int]int
+	partAttempts map[int]int
+	minPartSize  int
+}
+
+func newFakeMultipartServer(t *testing.T, minPartSize int) *fakeMultipartServer {
+	t.Helper()
+
+	s := &fakeMultipartServer{
+		uploads:      map[string]map[int][]byte{},
+		uploadKeys:   map[string]string{},
+		objects:      map[string][]byte{},
+		partFailures: map[int]int{},
+		partAttempts: map[int]int{},
+		minPartSize:  minPartSize,
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *fakeMultipartServer) handle(res http.ResponseWriter, req *http.Request) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	switch {
+	case req.Method == http.MethodPost && req.URL.Path == "/file/upload/multipart/start":
+		s.uploadCount++
+		uploadId := fmt.Sprintf("upload-%d", s.uploadCount)
+		key := fmt.Sprintf("multipart-file-%d", s.uploadCount)
+		s.uploads[uploadId] = map[int][]byte{}
+		s.uploadKeys[uploadId] = key
+
+		writeJSON(res, multipartUpload{Key: key, UploadId: uploadId})
+	case req.Method == http.MethodGet && req.URL.Path == "/file/upload/multipart/signed-url":
+		q := req.URL.Query()
+		partURL := fmt.Sprintf("%s/bucket/%s?uploadId=%s&partNumber=%s", s.URL, q.Get("key"), q.Get("uploadId"), q.Get("partNumber"))
+
+		writeJSON(res, signedURL{URL: partURL, Key: q.Get("key"), SignedHeaders: map[string][]string{"X-Amz-Test": {"signed"}}})
+	case req.Method == http.MethodPut && strings.HasPrefix(req.URL.Path, "/bucket/"):
+		s.handleUploadPart(res, req)
+	case req.Method == http.MethodPost && req.URL.Path == "/file/upload/multipart/complete":
+		s.handleComplete(res, req)
+	case req.Method == http.MethodPost && req.URL.Path == "/file/upload/multipart/abort":
+		var upload multipartUpload
+		_ = json.NewDecoder(req.Body).Decode(&upload)
+
+		delete(s.uploads, upload.UploadId)
+		s.aborted = append(s.aborted, upload.UploadId)
+	default:
+		res.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *fakeMultipartServer) handleUploadPart(res http.ResponseWriter, req *http.Request) {
+	parts, found := s.uploads[req.URL.Query().Get("uploadId")]
+	if !found {
+		http.Error(res, "NoSuchUpload", http.StatusNotFound)
+		return
+	}
+
+	if req.Header.Get("X-Amz-Test") != "signed" {
+		http.Error(res, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+
+	partNumber, err := strconv.Atoi(req.URL.Query().Get("partNumber"))
+	if err != nil || partNumber < 1 || partNumber > maximumParts {
+		http.Error(res, "InvalidArgument", http.StatusBadRequest)
+		return
+	}
+
+	s.partAttempts[partNumber]++
+
+	if s.partFailures[partNumber] > 0 {
+		s.partFailures[partNumber]--
+		http.Error(res, "SlowDown", http.StatusServiceUnavailable)
+
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil || int64(len(body)) != req.ContentLength {
+		http.Error(res, "IncompleteBody", http.StatusBadRequest)
+		return
+	}
+
+	// Uploading the same part again overwrites the previous one
+	parts[partNumber] = body
+
+	res.Header().Set("ETag", etagFor(body))
+	res.WriteHeader(http.StatusOK)
+}
+
+func (s *fakeMultipartServer) handleComplete(res http.ResponseWriter, req *http.Request) {
+	var input struct {
+		multipartUpload
+		Parts []completedPart `json:"parts"`
+	}
+
+	if err := json.NewDecoder(req.Body).Decode(&input); err != nil {
+		http.Error(res, "MalformedXML", http.StatusBadRequest)
+		return
+	}
+
+	parts, found := s.uploads[input.UploadId]
+	if !found || s.uploadKeys[input.UploadId] != input.Key {
+		http.Error(res, "NoSuchUpload", http.StatusNotFound)
+		return
+	}
+
+	var object bytes.Buffer
+
+	for i, part := range input.Parts {
+		if i > 0 && part.PartNumber <= input.Parts[i-1].PartNumber {
+			http.Error(res, "InvalidPartOrder", http.StatusBadRequest)
+			return
+		}
+
+		data, partFound := parts[part.PartNumber]
+		if !partFound || etagFor(data) != part.ETag {
+			http.Error(res, "InvalidPart", http.StatusBadRequest)
+			return
+		}
+
+		if i < len(input.Parts)-1 && len(data) < s.minPartSize {
+			http.Error(res, "EntityTooSmall", http.StatusBadRequest)
+			return
+		}
+
+		object.Write(data)
+	}
+
+	s.objects[input.Key] = object.Bytes()
+	delete(s.uploads, input.UploadId)
+
+	res.WriteHeader(http.StatusOK)
+}
+
+func etagFor(data []byte) string {
+	h := md5.Sum(data) //nolint:gosec
+
+	return `"` + hex.EncodeToString(h[:]) + `"`
+}
+
+func writeJSON(res http.ResponseWriter, v interface{}) {
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(res).Encode(v)
+}
+
+func setupMultipartTest(t *testing.T, fileSize int) (*fakeMultipartServer, string, []byte, *types.BaseTargetConfig) {
+	t.Helper()
+
+	originalMinimumPartSize := minimumPartSize
+	minimumPartSize = 16
+
+	server := newFakeMultipartServer(t, 16)
+
+	viper.Set(constants.MultipartUploadThresholdFlag, "32b")
+	viper.Set(constants.MultipartUploadPartSizeFlag, "16b")
+	viper.Set(constants.MultipartUploadConcurrencyFlag, 3)
+
+	baseConfig, closer := test.CreateBaseConfig("mydomain", "api-user", "api-secret", server.URL)
+
+	t.Cleanup(func() {
+		closer()
+		minimumPartSize = originalMinimumPartSize
+		viper.Set(constants.MultipartUploadThresholdFlag, "")
+		viper.Set(constants.MultipartUploadPartSizeFlag, "")
+		viper.Set(constants.MultipartUploadConcurrencyFlag, 0)
+	})
+
+	content := make([]byte, fileSize)
+	_, err := rand.Read(content)
+	require.NoError(t, err)
+
+	filePath := filepath.Join(t.TempDir(), "bigfile.json")
+	require.NoError(t, os.WriteFile(filePath, content, 0600))
+
+	return server, filePath, content, &types.BaseTargetConfig{
+		TargetLogger: hclog.L(),
+		BaseConfig:   *baseConfig,
+	}
+}
+
+func TestFileUploadMultipart(t *testing.T) {
+	server, filePath, content, config := setupMultipartTest(t, 150)
+
+	key, err := UploadFile(filePath, config)
+
+	require.NoError(t, err)
+	assert.Equal(t, "multipart-file-1", key)
+	assert.Equal(t, content, server.objects[key])
+	assert.Len(t, server.partAttempts, 10)
+	assert.Empty(t, server.aborted)
+}
+
+func TestFileUploadMultipartBelowThreshold(t *testing.T) {
+	server, _, _, config := setupMultipartTest(t, 0)
+
+	var uploadMethod string
+
+	singleUploadServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		uploadMethod = req.Method
+		res.WriteHeader(200)
+	}))
+	defer singleUploadServer.Close()
+
+	getUrlTestServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		writeJSON(res, signedURL{URL: singleUploadServer.URL, Key: "filekey"})
+	}))
+	defer getUrlTestServer.Close()
+
+	viper.Set(constants.URLOverrideFlag, getUrlTestServer.URL)
+
+	key, err := UploadFile("testdata/testfile.txt", config)
+
+	require.NoError(t, err)
+	assert.Equal(t, "filekey", key)
+	assert.Equal(t, "PUT", uploadMethod)
+	assert.Equal(t, 0, server.uploadCount)
+}
+
+func TestFileUploadMultipartRetryPart(t *testing.T) {
+	server, filePath, content, config := setupMultipartTest(t, 100)
+	server.partFailures[3] = 2
+	server.partFailures[7] = 1
+
+	key, err := UploadFile(filePath, config)
+
+	require.NoError(t, err)
+	assert.Equal(t, content, server.objects[key])
+	assert.Equal(t, 3, server.partAttempts[3])
+	assert.Equal(t, 2, server.partAttempts[7])
+	assert.Equal(t, 1, server.partAttempts[1])
+}
+
+func TestFileUploadMultipartAbortOnFailure(t *testing.T) {
+	server, filePath, _, config := setupMultipartTest(t, 100)
+	server.partFailures[2] = partUploadAttempts
+
+	key, err := UploadFile(filePath, config)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SlowDown")
+	assert.Empty(t, key)
+	assert.Equal(t, []string{"upload-1"}, server.aborted)
+	assert.Empty(t, server.objects)
+}
+
+func TestMultipartPartSize(t *testing.T) {
+	settings := multipartSettings{PartSize: 1024}
+
+	assert.Equal(t, minimumPartSize, settings.partSizeFor(100*minimumPartSize))
+
+	settings.PartSize = 2 * minimumPartSize
+	assert.Equal(t, 2*minimumPartSize, settings.partSizeFor(100*minimumPartSize))
+
+	// The part size grows to stay below the maximum number of parts
+	fileSize := 3 * maximumParts * minimumPartSize
+	assert.Equal(t, 3*minimumPartSize, settings.partSizeFor(fileSize))
+}
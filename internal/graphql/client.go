@@ -7,6 +7,7 @@ import (
 
 	"github.com/raito-io/cli/internal/target/types"
 	"github.com/raito-io/cli/internal/util/connect"
+	"github.com/raito-io/cli/internal/util/httpclient"
 
 	"github.com/hasura/go-graphql-client"
 
@@ -24,9 +25,12 @@ func (d *authedDoer) Do(req *http.Request) (*http.Response, error) {
 		return nil, err
 	}
 
-	client := &http.Client{}
+	client, err := httpclient.NewClient()
+	if err != nil {
+		return nil, err
+	}
 
-	resp, err := client.Do(req)
+	resp, err := doWithRetry(req, d.config, client.Do)
 	if err != nil {
 		return nil, fmt.Errorf("error while doing HTTP POST to %q: %s", req.URL.String(), err.Error())
 	}
@@ -5,11 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
 
 	"github.com/hashicorp/go-multierror"
 
 	"github.com/raito-io/cli/internal/target/types"
-	"github.com/raito-io/cli/internal/util/connect"
+	"github.com/raito-io/cli/internal/util/url"
 )
 
 type GraphqlResponse struct {
@@ -53,8 +55,17 @@ func ExecuteGraphQL(gql string, config *types.BaseConfig, resultObject interface
 }
 
 func executeGraphQL(gql string, config *types.BaseConfig) ([]byte, error) {
-	resp, err := connect.DoPostToRaito("query", gql, "application/json", config)
+	url := url.CreateRaitoURL(url.GetRaitoURL(), "query")
+	config.BaseLogger.Debug("Calling HTTP POST", "URL", url)
 
+	req, err := http.NewRequest("POST", url, strings.NewReader(gql))
+	if err != nil {
+		return nil, fmt.Errorf("error while executing graphql: %s", err.Error())
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := (&authedDoer{config: config}).Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error while executing graphql: %s", err.Error())
 	}
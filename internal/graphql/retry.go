@@ -0,0 +1,346 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+const (
+	defaultMaxRetries              = 4
+	defaultInitialBackoff          = time.Second
+	defaultMaxBackoff              = 30 * time.Second
+	defaultRequestTimeout          = 2 * time.Minute
+	defaultCircuitBreakerThreshold = 10
+	defaultCircuitBreakerCooldown  = time.Minute
+)
+
+var ErrCircuitOpen = errors.New("Raito Cloud seems to be unavailable: too many consecutive failures, not sending any requests for now") //nolint:stylecheck
+
+// idempotencyKeyRegex matches the (variable) keys that indicate a mutation is linked to a job or subtask and can safely be retried.
+var idempotencyKeyRegex = regexp.MustCompile(`\b(jobId|subtaskId)"?\s*:`)
+
+type idempotentKey struct{}
+
+// WithIdempotent marks all requests done with the returned context as idempotent, so they can safely be retried.
+// Queries and mutations carrying a job or subtask ID are detected automatically and don't need this.
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// RetryPolicy defines how requests to the Raito GraphQL API are retried.
+type RetryPolicy struct {
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	RequestTimeout time.Duration
+}
+
+// RetryPolicyFromConfig builds the retry policy based on the configuration. Missing or invalid values fall back to the defaults.
+func RetryPolicyFromConfig() RetryPolicy {
+	policy := RetryPolicy{
+		MaxRetries:     defaultMaxRetries,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		RequestTimeout: defaultRequestTimeout,
+	}
+
+	if viper.IsSet(constants.GraphqlMaxRetriesFlag) && viper.GetInt(constants.GraphqlMaxRetriesFlag) >= 0 {
+		policy.MaxRetries = viper.GetInt(constants.GraphqlMaxRetriesFlag)
+	}
+
+	if d := viper.GetDuration(constants.GraphqlRetryBackoffFlag); d > 0 {
+		policy.InitialBackoff = d
+	}
+
+	if d := viper.GetDuration(constants.GraphqlMaxRetryBackoffFlag); d > 0 {
+		policy.MaxBackoff = d
+	}
+
+	if d := viper.GetDuration(constants.GraphqlRequestTimeoutFlag); d > 0 {
+		policy.RequestTimeout = d
+	}
+
+	return policy
+}
+
+// backoff calculates the time to wait before the given retry (starting from 1), using exponential backoff with jitter.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := p.InitialBackoff
+
+	for i := 1; i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	// Equal jitter: wait at least half of the calculated backoff
+	half := d / 2
+
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// Statistics contains the counters about the requests sent to the Raito GraphQL API.
+type Statistics struct {
+	Requests             uint64 `json:"requests"`
+	Retries              uint64 `json:"retries"`
+	Failures             uint64 `json:"failures"`
+	CircuitBreakerOpened uint64 `json:"circuitBreakerOpened"`
+}
+
+func (s Statistics) String() string {
+	return fmt.Sprintf("%d requests, %d retries, %d failures, circuit breaker opened %d times", s.Requests, s.Retries, s.Failures, s.CircuitBreakerOpened)
+}
+
+// Since returns the counters that were added since the given earlier statistics were taken.
+func (s Statistics) Since(earlier Statistics) Statistics {
+	return Statistics{
+		Requests:             s.Requests - earlier.Requests,
+		Retries:              s.Retries - earlier.Retries,
+		Failures:             s.Failures - earlier.Failures,
+		CircuitBreakerOpened: s.CircuitBreakerOpened - earlier.CircuitBreakerOpened,
+	}
+}
+
+var (
+	requestCounter        atomic.Uint64
+	retryCounter          atomic.Uint64
+	failureCounter        atomic.Uint64
+	circuitOpenedCounter  atomic.Uint64
+	globalCircuitBreaker  = &circuitBreaker{}
+	errRetryableHTTPError = errors.New("retryable HTTP status")
+)
+
+// GetStatistics returns the counters of the requests sent to the Raito GraphQL API since the last reset.
+func GetStatistics() Statistics {
+	return Statistics{
+		Requests:             requestCounter.Load(),
+		Retries:              retryCounter.Load(),
+		Failures:             failureCounter.Load(),
+		CircuitBreakerOpened: circuitOpenedCounter.Load(),
+	}
+}
+
+// ResetStatistics resets the counters of the requests sent to the Raito GraphQL API.
+// As the counters are shared by all runs of the daemon, use Statistics.Since to get the counters of a single run instead.
+func ResetStatistics() {
+	requestCounter.Store(0)
+	retryCounter.Store(0)
+	failureCounter.Store(0)
+	circuitOpenedCounter.Store(0)
+}
+
+// circuitBreaker stops sending requests for a while when too many consecutive requests failed.
+// After the cooldown period, requests are let through again. A single new failure reopens the circuit immediately.
+type circuitBreaker struct {
+	m                   sync.Mutex
+	consecutiveFailures int
+	openUntil           time.Time
+}
+
+func (c *circuitBreaker) allow() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return !time.Now().Before(c.openUntil)
+}
+
+func (c *circuitBreaker) success() {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.consecutiveFailures = 0
+	c.openUntil = time.Time{}
+}
+
+// failure registers a failed request and returns true if the circuit got opened because of it.
+func (c *circuitBreaker) failure() bool {
+	threshold := viper.GetInt(constants.GraphqlCircuitBreakerThresholdFlag)
+	if threshold <= 0 {
+		threshold = defaultCircuitBreakerThreshold
+	}
+
+	cooldown := viper.GetDuration(constants.GraphqlCircuitBreakerCooldownFlag)
+	if cooldown <= 0 {
+		cooldown = defaultCircuitBreakerCooldown
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.consecutiveFailures++
+
+	if c.consecutiveFailures >= threshold {
+		c.openUntil = time.Now().Add(cooldown)
+
+		return true
+	}
+
+	return false
+}
+
+// doWithRetry executes the given request using the retry policy from the configuration.
+// Only idempotent requests (queries and mutations linked to a job or subtask) are retried.
+// The response body is fully read, so the per-request timeout can be applied to the complete request.
+func doWithRetry(req *http.Request, config *types.BaseConfig, do func(req *http.Request) (*http.Response, error)) (*http.Response, error) {
+	policy := RetryPolicyFromConfig()
+
+	var body []byte
+
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error while reading request body: %s", err.Error())
+		}
+
+		_ = req.Body.Close()
+	}
+
+	ctx := req.Context()
+
+	retries := 0
+	if ctx.Value(idempotentKey{}) != nil || isIdempotent(body) {
+		retries = policy.MaxRetries
+	}
+
+	var lastErr error
+
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			wait := policy.backoff(attempt)
+			config.BaseLogger.Warn(fmt.Sprintf("Request to Raito failed: %s. Will retry in %s (%d/%d)", lastErr.Error(), wait.Round(time.Millisecond), attempt, retries))
+
+			retryCounter.Add(1)
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+
+		if !globalCircuitBreaker.allow() {
+			failureCounter.Add(1)
+
+			return nil, ErrCircuitOpen
+		}
+
+		requestCounter.Add(1)
+
+		resp, err := doAttempt(ctx, req, body, policy.RequestTimeout, do)
+		if err == nil {
+			globalCircuitBreaker.success()
+
+			return resp, nil
+		}
+
+		lastErr = err
+
+		if !isRetryable(ctx, err) {
+			failureCounter.Add(1)
+
+			return resp, err
+		}
+
+		if globalCircuitBreaker.failure() {
+			circuitOpenedCounter.Add(1)
+			config.BaseLogger.Error("Too many consecutive failed requests to Raito. Pausing all requests for a while.")
+		}
+
+		if resp != nil && attempt == retries {
+			// Returning the last response, so the caller can handle the HTTP error itself
+			failureCounter.Add(1)
+
+			return resp, nil
+		}
+	}
+
+	failureCounter.Add(1)
+
+	return nil, lastErr
+}
+
+func doAttempt(ctx context.Context, req *http.Request, body []byte, timeout time.Duration, do func(req *http.Request) (*http.Response, error)) (*http.Response, error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	attemptReq := req.Clone(attemptCtx)
+	if body != nil {
+		attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+		attemptReq.ContentLength = int64(len(body))
+	}
+
+	resp, err := do(attemptReq)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading response body: %s", err.Error())
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	switch resp.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return resp, fmt.Errorf("%w: HTTP %d", errRetryableHTTPError, resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+func isRetryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		// The parent context is done, so no need to retry
+		return false
+	}
+
+	// Transport errors (connection refused, reset, timeouts, ...) are always returned as url.Error by the HTTP client
+	var urlErr *url.Error
+
+	return errors.Is(err, errRetryableHTTPError) || errors.As(err, &urlErr)
+}
+
+// isIdempotent checks if the given GraphQL request body can safely be retried.
+// This is the case for queries and for mutations that carry a job or subtask ID.
+func isIdempotent(body []byte) bool {
+	request := struct {
+		Query     string          `json:"query"`
+		Variables json.RawMessage `json:"variables"`
+	}{}
+
+	if err := json.Unmarshal(body, &request); err != nil || request.Query == "" {
+		return false
+	}
+
+	query := strings.TrimSpace(request.Query)
+
+	if !strings.HasPrefix(query, "mutation") {
+		return true
+	}
+
+	return idempotencyKeyRegex.MatchString(query) || idempotencyKeyRegex.Match(request.Variables)
+}
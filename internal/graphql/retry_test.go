@@ -0,0 +1,182 @@
+package graphql
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/util/test"
+)
+
+func setupRetryTest(t *testing.T, statusCodes ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+
+		call := int(calls.Add(1)) - 1
+		if call < len(statusCodes) {
+			res.WriteHeader(statusCodes[call])
+			return
+		}
+
+		res.WriteHeader(200)
+		res.Write([]byte(`{"data": {"name": "Luke Skywalker", "height": 1.72, "mass": 77}}`))
+	}))
+
+	viper.Set(constants.GraphqlRetryBackoffFlag, time.Millisecond)
+	viper.Set(constants.GraphqlMaxRetryBackoffFlag, 5*time.Millisecond)
+
+	t.Cleanup(func() {
+		testServer.Close()
+		viper.Set(constants.GraphqlRetryBackoffFlag, nil)
+		viper.Set(constants.GraphqlMaxRetryBackoffFlag, nil)
+		viper.Set(constants.GraphqlMaxRetriesFlag, nil)
+		viper.Set(constants.GraphqlCircuitBreakerThresholdFlag, nil)
+		globalCircuitBreaker.success()
+		ResetStatistics()
+	})
+
+	ResetStatistics()
+
+	return testServer, &calls
+}
+
+func TestRetryQuery(t *testing.T) {
+	testServer, calls := setupRetryTest(t, 502, 503)
+
+	config, closer := test.CreateBaseConfig("TestRaito", "Userke", "SecretStuff", testServer.URL)
+	defer closer()
+
+	data := dataObject{}
+	_, err := ExecuteGraphQL(`{ "query": "query { name }" }`, config, &data)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Luke Skywalker", data.Name)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, Statistics{Requests: 3, Retries: 2}, GetStatistics())
+}
+
+func TestRetryQueryExhausted(t *testing.T) {
+	testServer, calls := setupRetryTest(t, 503, 503, 503)
+	viper.Set(constants.GraphqlMaxRetriesFlag, 2)
+
+	config, closer := test.CreateBaseConfig("TestRaito", "Userke", "SecretStuff", testServer.URL)
+	defer closer()
+
+	data := dataObject{}
+	_, err := ExecuteGraphQL(`{ "query": "query { name }" }`, config, &data)
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, uint64(1), GetStatistics().Failures)
+}
+
+func TestRetryMutation(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		ctx           context.Context
+		expectedCalls int32
+	}{
+		{
+			name:          "Mutation without job is not retried",
+			body:          `{ "query": "mutation { doSomething(input: $input) }", "variables": {"input": {"name": "x"}} }`,
+			expectedCalls: 1,
+		},
+		{
+			name:          "Mutation with job is retried",
+			body:          `{ "query": "mutation { doSomething(input: $input) }", "variables": {"input": {"jobId": "job1"}} }`,
+			expectedCalls: 2,
+		},
+		{
+			name:          "Mutation marked as idempotent is retried",
+			body:          `{ "query": "mutation { doSomething(input: $input) }", "variables": {"input": {"name": "x"}} }`,
+			ctx:           WithIdempotent(context.Background()),
+			expectedCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testServer, calls := setupRetryTest(t, 502)
+
+			config, closer := test.CreateBaseConfig("TestRaito", "Userke", "SecretStuff", testServer.URL)
+			defer closer()
+
+			ctx := tt.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, testServer.URL+"/query", strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			resp, err := (&authedDoer{config: config}).Do(req)
+			require.NoError(t, err)
+
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	testServer, calls := setupRetryTest(t, 503, 503, 503, 503, 503)
+	viper.Set(constants.GraphqlMaxRetriesFlag, 5)
+	viper.Set(constants.GraphqlCircuitBreakerThresholdFlag, 2)
+
+	config, closer := test.CreateBaseConfig("TestRaito", "Userke", "SecretStuff", testServer.URL)
+	defer closer()
+
+	data := dataObject{}
+	_, err := ExecuteGraphQL(`{ "query": "query { name }" }`, config, &data)
+
+	assert.ErrorContains(t, err, ErrCircuitOpen.Error())
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, uint64(1), GetStatistics().CircuitBreakerOpened)
+
+	// Requests are blocked while the circuit is open
+	_, err = ExecuteGraphQL(`{ "query": "query { name }" }`, config, &data)
+	assert.ErrorContains(t, err, ErrCircuitOpen.Error())
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestIsIdempotent(t *testing.T) {
+	assert.True(t, isIdempotent([]byte(`{"query": "query { a }"}`)))
+	assert.True(t, isIdempotent([]byte(`{"query": "{ a }"}`)))
+	assert.True(t, isIdempotent([]byte(`{"query": "mutation { a(jobId: $id) }"}`)))
+	assert.True(t, isIdempotent([]byte(`{"query": "mutation { a(input: $input) }", "variables": {"input": {"subtaskId": "x"}}}`)))
+	assert.False(t, isIdempotent([]byte(`{"query": "mutation { a(input: $input) }", "variables": {"input": {"name": "x"}}}`)))
+	assert.False(t, isIdempotent([]byte(`not json`)))
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	for retry := 1; retry < 6; retry++ {
+		d := policy.backoff(retry)
+		assert.LessOrEqual(t, d, 5*time.Second)
+		assert.GreaterOrEqual(t, d, 500*time.Millisecond)
+	}
+}
+
+func TestStatistics_Since(t *testing.T) {
+	earlier := Statistics{Requests: 3, Retries: 1}
+	later := Statistics{Requests: 8, Retries: 2, Failures: 1, CircuitBreakerOpened: 1}
+
+	assert.Equal(t, Statistics{Requests: 5, Retries: 1, Failures: 1, CircuitBreakerOpened: 1}, later.Since(earlier))
+}
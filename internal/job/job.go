@@ -26,6 +26,7 @@ type TaskEventUpdater interface {
 	SetStatusToCompleted(ctx context.Context, results []TaskResult)
 	SetStatusToFailed(ctx context.Context, err error)
 	SetStatusToSkipped(ctx context.Context)
+	SetStatusToSkippedWithReason(ctx context.Context, reason string)
 
 	GetSubtaskEventUpdater(subtask string) SubtaskEventUpdater
 }
@@ -66,7 +67,7 @@ func NewTaskEventUpdater(cfg *types.BaseTargetConfig, jobId, jobType string, war
 	return &taskEventUpdater{cfg, jobId, jobType, warningCollector}
 }
 
-func (u *taskEventUpdater) setStatus(ctx context.Context, status JobStatus, results []TaskResult, err error) {
+func (u *taskEventUpdater) setStatus(ctx context.Context, status JobStatus, results []TaskResult, err error, extraWarnings ...string) {
 	var errors []error
 	if err != nil {
 		errors = append(errors, err)
@@ -77,6 +78,8 @@ func (u *taskEventUpdater) setStatus(ctx context.Context, status JobStatus, resu
 		warnings = u.warningCollector.GetWarnings()
 	}
 
+	warnings = append(warnings, extraWarnings...)
+
 	AddTaskEvent(ctx, u.Cfg, u.JobId, u.JobType, status, results, warnings, errors)
 }
 
@@ -112,6 +115,11 @@ func (u *taskEventUpdater) SetStatusToSkipped(ctx context.Context) {
 	u.setStatus(ctx, Skipped, nil, nil)
 }
 
+// SetStatusToSkippedWithReason marks the task as skipped. The reason is sent as a warning.
+func (u *taskEventUpdater) SetStatusToSkippedWithReason(ctx context.Context, reason string) {
+	u.setStatus(ctx, Skipped, nil, nil, reason)
+}
+
 func (u *taskEventUpdater) GetSubtaskEventUpdater(subtask string) SubtaskEventUpdater {
 	return &subtaskEventUpdater{
 		Cfg:     u.Cfg,
@@ -202,7 +210,7 @@ func UpdateJobEvent(cfg *types.BaseTargetConfig, jobID string, status JobStatus,
 		input.IdentityStoreId = &cfg.IdentityStoreId
 	}
 
-	err := graphql.NewClient(&cfg.BaseConfig).Mutate(context.Background(), &mutation, map[string]interface{}{"id": jobID, "input": input})
+	err := graphql.NewClient(&cfg.BaseConfig).Mutate(graphql.WithIdempotent(context.Background()), &mutation, map[string]interface{}{"id": jobID, "input": input})
 	if err != nil {
 		cfg.TargetLogger.Debug(fmt.Sprintf("job update failed: %s", err.Error()))
 	}
@@ -481,16 +489,40 @@ func (e JobStatus) MarshalJSON() ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
-func WaitForJobToComplete(ctx context.Context, jobID string, syncType string, subtaskId string, syncResult interface{}, cfg *types.BaseTargetConfig, currentStatus JobStatus, waitInterval int) (*Subtask, error) {
+// WaitForJobToComplete waits until the given subtask is no longer running.
+// When Raito Cloud can push status changes (over the websocket), the subtask is only fetched again when notified (or as a safety net every 30 seconds).
+// Otherwise, the subtask is polled using an interval that gradually increases from 1 to 30 seconds while the status doesn't change.
+func WaitForJobToComplete(ctx context.Context, jobID string, syncType string, subtaskId string, syncResult interface{}, cfg *types.BaseTargetConfig, currentStatus JobStatus) (*Subtask, error) {
 	i := 0
 	errorCount := 0
 
 	var subtask *Subtask
 	var err error
 
+	updates, stopWatching := watchSubtask(ctx, jobID, syncType, subtaskId)
+	defer stopWatching()
+
+	if updates != nil {
+		cfg.TargetLogger.Debug("Waiting for subtask status changes pushed by Raito Cloud")
+	}
+
+	pollInterval := minPollInterval
+
 	for currentStatus.IsRunning() || i == 0 {
 		if currentStatus.IsRunning() {
-			time.Sleep(time.Duration(waitInterval) * time.Second)
+			waitInterval := pollInterval
+			if updates != nil {
+				waitInterval = maxPollInterval
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-updates:
+			case <-time.After(waitInterval):
+			}
+
+			pollInterval = nextPollInterval(pollInterval)
 		}
 
 		subtask, err = GetSubtask(ctx, cfg, jobID, syncType, subtaskId, syncResult)
@@ -512,6 +544,9 @@ func WaitForJobToComplete(ctx context.Context, jobID string, syncType string, su
 
 		if currentStatus != subtask.Status {
 			cfg.TargetLogger.Info(fmt.Sprintf("Update task status to %s", subtask.Status.String()))
+
+			// Something is happening, so check again soon
+			pollInterval = minPollInterval
 		}
 
 		currentStatus = subtask.Status
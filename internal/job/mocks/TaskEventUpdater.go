@@ -303,6 +303,40 @@ func (_c *TaskEventUpdater_SetStatusToSkipped_Call) RunAndReturn(run func(contex
 	return _c
 }
 
+// SetStatusToSkippedWithReason provides a mock function with given fields: ctx, reason
+func (_m *TaskEventUpdater) SetStatusToSkippedWithReason(ctx context.Context, reason string) {
+	_m.Called(ctx, reason)
+}
+
+// TaskEventUpdater_SetStatusToSkippedWithReason_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetStatusToSkippedWithReason'
+type TaskEventUpdater_SetStatusToSkippedWithReason_Call struct {
+	*mock.Call
+}
+
+// SetStatusToSkippedWithReason is a helper method to define mock.On call
+//   - ctx context.Context
+//   - reason string
+func (_e *TaskEventUpdater_Expecter) SetStatusToSkippedWithReason(ctx interface{}, reason interface{}) *TaskEventUpdater_SetStatusToSkippedWithReason_Call {
+	return &TaskEventUpdater_SetStatusToSkippedWithReason_Call{Call: _e.mock.On("SetStatusToSkippedWithReason", ctx, reason)}
+}
+
+func (_c *TaskEventUpdater_SetStatusToSkippedWithReason_Call) Run(run func(ctx context.Context, reason string)) *TaskEventUpdater_SetStatusToSkippedWithReason_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *TaskEventUpdater_SetStatusToSkippedWithReason_Call) Return() *TaskEventUpdater_SetStatusToSkippedWithReason_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *TaskEventUpdater_SetStatusToSkippedWithReason_Call) RunAndReturn(run func(context.Context, string)) *TaskEventUpdater_SetStatusToSkippedWithReason_Call {
+	_c.Run(run)
+	return _c
+}
+
 // SetStatusToStarted provides a mock function with given fields: ctx
 func (_m *TaskEventUpdater) SetStatusToStarted(ctx context.Context) {
 	_m.Called(ctx)
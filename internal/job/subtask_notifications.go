@@ -0,0 +1,137 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	minPollInterval = time.Second
+	maxPollInterval = 30 * time.Second
+
+	subscribeTimeout = 10 * time.Second
+)
+
+// SubtaskUpdate is pushed by Raito Cloud when the status of a subtask changes.
+type SubtaskUpdate struct {
+	JobId     string    `json:"jobId"`
+	JobType   string    `json:"jobType"`
+	SubtaskId string    `json:"subtaskId"`
+	Status    JobStatus `json:"status"`
+}
+
+// SubtaskSubscriber requests Raito Cloud to push the status changes of the given subtask.
+type SubtaskSubscriber func(ctx context.Context, jobId, jobType, subtaskId string) error
+
+type subtaskKey struct {
+	jobId     string
+	jobType   string
+	subtaskId string
+}
+
+var subtaskNotifications = struct {
+	m          sync.Mutex
+	subscriber SubtaskSubscriber
+	watchers   map[subtaskKey][]chan JobStatus
+}{
+	watchers: map[subtaskKey][]chan JobStatus{},
+}
+
+// SetSubtaskSubscriber registers the subscriber to use to get notified about subtask status changes (typically over the websocket).
+// All subtasks that are currently being waited for are subscribed again. Passing nil falls back to polling.
+func SetSubtaskSubscriber(subscriber SubtaskSubscriber) {
+	subtaskNotifications.m.Lock()
+
+	subtaskNotifications.subscriber = subscriber
+
+	var keys []subtaskKey
+
+	if subscriber != nil {
+		for key, watchers := range subtaskNotifications.watchers {
+			if len(watchers) > 0 {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	subtaskNotifications.m.Unlock()
+
+	// Subscribing writes to the websocket, so it is done without holding the lock
+	for _, key := range keys {
+		subscribe(context.Background(), subscriber, key) //nolint:errcheck
+	}
+}
+
+// NotifySubtaskUpdate wakes up everyone waiting for the subtask in the given update.
+func NotifySubtaskUpdate(update *SubtaskUpdate) {
+	subtaskNotifications.m.Lock()
+	defer subtaskNotifications.m.Unlock()
+
+	for _, ch := range subtaskNotifications.watchers[subtaskKey{jobId: update.JobId, jobType: update.JobType, subtaskId: update.SubtaskId}] {
+		select {
+		case ch <- update.Status:
+		default:
+			// A notification is already pending, which will trigger a refresh anyway
+		}
+	}
+}
+
+// watchSubtask returns a channel that receives the status changes pushed for the given subtask, and a function to stop watching.
+// The returned channel is nil if no push notifications are available, in which case the caller needs to poll.
+func watchSubtask(ctx context.Context, jobId, jobType, subtaskId string) (<-chan JobStatus, func()) {
+	subtaskNotifications.m.Lock()
+
+	subscriber := subtaskNotifications.subscriber
+	if subscriber == nil {
+		subtaskNotifications.m.Unlock()
+
+		return nil, func() {}
+	}
+
+	// The channel is registered before subscribing, so no update pushed right after subscribing is missed
+	key := subtaskKey{jobId: jobId, jobType: jobType, subtaskId: subtaskId}
+	ch := make(chan JobStatus, 1)
+	subtaskNotifications.watchers[key] = append(subtaskNotifications.watchers[key], ch)
+
+	subtaskNotifications.m.Unlock()
+
+	stop := func() {
+		subtaskNotifications.m.Lock()
+		defer subtaskNotifications.m.Unlock()
+
+		watchers := subtaskNotifications.watchers[key]
+		for i := range watchers {
+			if watchers[i] == ch {
+				watchers = append(watchers[:i], watchers[i+1:]...)
+				break
+			}
+		}
+
+		if len(watchers) == 0 {
+			delete(subtaskNotifications.watchers, key)
+		} else {
+			subtaskNotifications.watchers[key] = watchers
+		}
+	}
+
+	if err := subscribe(ctx, subscriber, key); err != nil {
+		stop()
+
+		return nil, func() {}
+	}
+
+	return ch, stop
+}
+
+func subscribe(ctx context.Context, subscriber SubtaskSubscriber, key subtaskKey) error {
+	subscribeCtx, cancel := context.WithTimeout(ctx, subscribeTimeout)
+	defer cancel()
+
+	return subscriber(subscribeCtx, key.jobId, key.jobType, key.subtaskId)
+}
+
+// nextPollInterval doubles the given poll interval, up to the maximum poll interval.
+func nextPollInterval(interval time.Duration) time.Duration {
+	return min(interval*2, maxPollInterval)
+}
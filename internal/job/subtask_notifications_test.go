@@ -0,0 +1,163 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/target/types"
+	"github.com/raito-io/cli/internal/util/test"
+)
+
+func subtaskTestServer(t *testing.T, statuses ...string) (*types.BaseTargetConfig, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, _ = io.ReadAll(req.Body)
+
+		call := int(calls.Add(1)) - 1
+		status := statuses[min(call, len(statuses)-1)]
+
+		res.WriteHeader(200)
+		res.Write([]byte(fmt.Sprintf(`{"data": {"jobSubtask": {"jobId": "job1", "jobType": "type1", "subtaskId": "subtask1", "status": %q}}}`, status)))
+	}))
+
+	baseConfig, closer := test.CreateBaseConfig("TestRaito", "Userke", "SecretStuff", testServer.URL)
+
+	t.Cleanup(func() {
+		closer()
+		testServer.Close()
+		SetSubtaskSubscriber(nil)
+	})
+
+	return &types.BaseTargetConfig{BaseConfig: *baseConfig, TargetLogger: hclog.L()}, &calls
+}
+
+func TestWaitForJobToComplete_Push(t *testing.T) {
+	cfg, calls := subtaskTestServer(t, "COMPLETED")
+
+	subscribed := make(chan SubtaskUpdate, 1)
+
+	SetSubtaskSubscriber(func(_ context.Context, jobId, jobType, subtaskId string) error {
+		subscribed <- SubtaskUpdate{JobId: jobId, JobType: jobType, SubtaskId: subtaskId}
+
+		return nil
+	})
+
+	go func() {
+		update := <-subscribed
+		update.Status = Completed
+
+		// Unrelated subtasks are ignored
+		NotifySubtaskUpdate(&SubtaskUpdate{JobId: "job1", JobType: "type1", SubtaskId: "other", Status: Completed})
+		NotifySubtaskUpdate(&update)
+	}()
+
+	start := time.Now()
+	subtask, err := WaitForJobToComplete(context.Background(), "job1", "type1", "subtask1", nil, cfg, Queued)
+
+	require.NoError(t, err)
+	assert.Equal(t, Completed, subtask.Status)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assert.Empty(t, subtaskNotifications.watchers)
+}
+
+func TestWaitForJobToComplete_SubscribeFailed(t *testing.T) {
+	cfg, calls := subtaskTestServer(t, "COMPLETED")
+
+	SetSubtaskSubscriber(func(_ context.Context, _, _, _ string) error {
+		return fmt.Errorf("websocket not connected")
+	})
+
+	// Falls back to polling, starting with a short interval
+	subtask, err := WaitForJobToComplete(context.Background(), "job1", "type1", "subtask1", nil, cfg, Queued)
+
+	require.NoError(t, err)
+	assert.Equal(t, Completed, subtask.Status)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestWaitForJobToComplete_Cancelled(t *testing.T) {
+	cfg, _ := subtaskTestServer(t, "QUEUED")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := WaitForJobToComplete(ctx, "job1", "type1", "subtask1", nil, cfg, Queued)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSetSubtaskSubscriber_Resubscribe(t *testing.T) {
+	t.Cleanup(func() {
+		SetSubtaskSubscriber(nil)
+	})
+
+	SetSubtaskSubscriber(func(_ context.Context, _, _, _ string) error {
+		return nil
+	})
+
+	_, stop := watchSubtask(context.Background(), "job1", "type1", "subtask1")
+
+	var resubscribed []string
+
+	SetSubtaskSubscriber(func(_ context.Context, _, _, subtaskId string) error {
+		resubscribed = append(resubscribed, subtaskId)
+
+		return nil
+	})
+
+	assert.Equal(t, []string{"subtask1"}, resubscribed)
+
+	stop()
+
+	assert.Empty(t, subtaskNotifications.watchers)
+}
+
+func TestWatchSubtask_SubscribeWithoutLock(t *testing.T) {
+	t.Cleanup(func() {
+		SetSubtaskSubscriber(nil)
+	})
+
+	// The subscriber is free to call back into the notifications, e.g. when the status is pushed immediately
+	SetSubtaskSubscriber(func(_ context.Context, jobId, jobType, subtaskId string) error {
+		NotifySubtaskUpdate(&SubtaskUpdate{JobId: jobId, JobType: jobType, SubtaskId: subtaskId, Status: Completed})
+
+		return nil
+	})
+
+	ch, stop := watchSubtask(context.Background(), "job1", "type1", "subtask1")
+	defer stop()
+
+	require.NotNil(t, ch)
+
+	select {
+	case status := <-ch:
+		assert.Equal(t, Completed, status)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "status update was not received")
+	}
+}
+
+func TestNextPollInterval(t *testing.T) {
+	interval := minPollInterval
+
+	for i := 0; i < 10; i++ {
+		interval = nextPollInterval(interval)
+	}
+
+	assert.Equal(t, maxPollInterval, interval)
+	assert.Equal(t, 2*time.Second, nextPollInterval(time.Second))
+}
@@ -0,0 +1,155 @@
+package lane
+
+import (
+	"context"
+	"sync"
+)
+
+// Lane is a kind of work handled by the daemon. Every lane processes its work in order, but the lanes run concurrently.
+// Lanes with a higher value have a higher priority when they compete for the same resource.
+type Lane int
+
+const (
+	// Scheduled is the lane of the runs started by the cron schedule or frequency
+	Scheduled Lane = iota
+
+	// Manual is the lane of the syncs triggered manually (e.g. from Raito Cloud or the control socket)
+	Manual
+
+	// AccessUpdate is the lane of the access provider updates, for which users are waiting
+	AccessUpdate
+)
+
+func (l Lane) String() string {
+	switch l {
+	case Scheduled:
+		return "scheduled"
+	case Manual:
+		return "manual"
+	case AccessUpdate:
+		return "access-update"
+	default:
+		return "unknown"
+	}
+}
+
+type laneKey struct{}
+
+// WithLane marks all work done with the returned context as part of the given lane.
+func WithLane(ctx context.Context, lane Lane) context.Context {
+	return context.WithValue(ctx, laneKey{}, lane)
+}
+
+// FromContext returns the lane of the work done with the given context. Work outside the daemon is considered scheduled work.
+func FromContext(ctx context.Context) Lane {
+	if lane, ok := ctx.Value(laneKey{}).(Lane); ok {
+		return lane
+	}
+
+	return Scheduled
+}
+
+// PriorityLock is a set of locks identified by a key.
+// When a lock is released, it is handed to the waiter of the lane with the highest priority. Waiters of the same lane get the lock in order of arrival.
+type PriorityLock struct {
+	m       sync.Mutex
+	held    map[string]struct{}
+	waiters map[string][]*waiter
+}
+
+type waiter struct {
+	lane  Lane
+	ready chan struct{}
+}
+
+func NewPriorityLock() *PriorityLock {
+	return &PriorityLock{
+		held:    make(map[string]struct{}),
+		waiters: make(map[string][]*waiter),
+	}
+}
+
+// Lock blocks until the lock with the given key is acquired for the lane of the context, or the context is done.
+// The returned function releases the lock.
+func (l *PriorityLock) Lock(ctx context.Context, key string) (func(), error) {
+	l.m.Lock()
+
+	if _, found := l.held[key]; !found {
+		l.held[key] = struct{}{}
+		l.m.Unlock()
+
+		return l.releaseFn(key), nil
+	}
+
+	w := &waiter{lane: FromContext(ctx), ready: make(chan struct{})}
+	l.waiters[key] = append(l.waiters[key], w)
+	l.m.Unlock()
+
+	select {
+	case <-w.ready:
+		return l.releaseFn(key), nil
+	case <-ctx.Done():
+		l.m.Lock()
+		defer l.m.Unlock()
+
+		select {
+		case <-w.ready:
+			// The lock was handed over while giving up, so passing it on
+			l.release(key)
+		default:
+			l.removeWaiter(key, w)
+		}
+
+		return nil, ctx.Err()
+	}
+}
+
+func (l *PriorityLock) releaseFn(key string) func() {
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			l.m.Lock()
+			defer l.m.Unlock()
+
+			l.release(key)
+		})
+	}
+}
+
+// release hands the lock over to the waiter with the highest priority. Should be called while holding the mutex.
+func (l *PriorityLock) release(key string) {
+	waiters := l.waiters[key]
+
+	if len(waiters) == 0 {
+		delete(l.held, key)
+		delete(l.waiters, key)
+
+		return
+	}
+
+	next := 0
+
+	for i, w := range waiters {
+		if w.lane > waiters[next].lane {
+			next = i
+		}
+	}
+
+	w := waiters[next]
+	l.waiters[key] = append(waiters[:next:next], waiters[next+1:]...)
+
+	close(w.ready)
+}
+
+func (l *PriorityLock) removeWaiter(key string, w *waiter) {
+	waiters := l.waiters[key]
+
+	for i := range waiters {
+		if waiters[i] == w {
+			l.waiters[key] = append(waiters[:i:i], waiters[i+1:]...)
+
+			return
+		}
+	}
+}
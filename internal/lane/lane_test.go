@@ -0,0 +1,94 @@
+package lane
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Scheduled, FromContext(context.Background()))
+	assert.Equal(t, AccessUpdate, FromContext(WithLane(context.Background(), AccessUpdate)))
+	assert.Equal(t, "access-update", AccessUpdate.String())
+}
+
+func TestPriorityLock_HandsOverToHighestPriority(t *testing.T) {
+	lock := NewPriorityLock()
+	ctx := context.Background()
+
+	unlock, err := lock.Lock(ctx, "ds1")
+	require.NoError(t, err)
+
+	var m sync.Mutex
+
+	var order []Lane
+
+	wg := sync.WaitGroup{}
+
+	for _, l := range []Lane{Scheduled, Manual, AccessUpdate} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			unlockWaiter, lockErr := lock.Lock(WithLane(ctx, l), "ds1")
+			if !assert.NoError(t, lockErr) {
+				return
+			}
+
+			m.Lock()
+			order = append(order, l)
+			m.Unlock()
+
+			unlockWaiter()
+		}()
+
+		// Making sure the waiters arrive in order
+		assert.Eventually(t, func() bool {
+			lock.m.Lock()
+			defer lock.m.Unlock()
+
+			return len(lock.waiters["ds1"]) == int(l)+1
+		}, time.Second, time.Millisecond)
+	}
+
+	// Other keys are not blocked
+	unlockOther, err := lock.Lock(ctx, "ds2")
+	require.NoError(t, err)
+	unlockOther()
+
+	unlock()
+	wg.Wait()
+
+	assert.Equal(t, []Lane{AccessUpdate, Manual, Scheduled}, order)
+	assert.Empty(t, lock.held)
+}
+
+func TestPriorityLock_ContextDone(t *testing.T) {
+	lock := NewPriorityLock()
+
+	unlock, err := lock.Lock(context.Background(), "ds1")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = lock.Lock(ctx, "ds1")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+
+	// Calling unlock twice has no effect
+	unlock()
+
+	unlock, err = lock.Lock(context.Background(), "ds1")
+	require.NoError(t, err)
+	unlock()
+
+	assert.Empty(t, lock.held)
+	assert.Empty(t, lock.waiters)
+}
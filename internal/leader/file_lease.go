@@ -0,0 +1,147 @@
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ LeaseBackend = (*FileLease)(nil)
+
+// lockRetryInterval is the time to wait before trying to lock the file again, when another instance holds the lock.
+const lockRetryInterval = 50 * time.Millisecond
+
+// FileLease is a lease stored in a file on a volume that is shared by all instances (e.g. a network file system).
+// The file is locked while the lease is read and updated. As the expiry time is written by the instances themselves, their clocks should be in sync.
+type FileLease struct {
+	path string
+}
+
+type leaseRecord struct {
+	Holder    string    `json:"holder"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func NewFileLease(path string) (*FileLease, error) {
+	if path == "" {
+		return nil, errors.New("no lock file defined for the leader election")
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, fmt.Errorf("create directory for lock file: %w", err)
+	}
+
+	return &FileLease{path: path}, nil
+}
+
+func (l *FileLease) TryAcquire(ctx context.Context, identity string, ttl time.Duration) (bool, error) {
+	acquired := false
+
+	err := l.update(ctx, func(record *leaseRecord) bool {
+		now := time.Now()
+
+		if record.Holder != "" && record.Holder != identity && now.Before(record.ExpiresAt) {
+			return false
+		}
+
+		record.Holder = identity
+		record.ExpiresAt = now.Add(ttl)
+		acquired = true
+
+		return true
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+func (l *FileLease) Release(ctx context.Context, identity string) error {
+	return l.update(ctx, func(record *leaseRecord) bool {
+		if record.Holder != identity {
+			return false
+		}
+
+		*record = leaseRecord{}
+
+		return true
+	})
+}
+
+// update reads the lease record while holding the lock on the file, and writes it back if fn returns true.
+func (l *FileLease) update(ctx context.Context, fn func(record *leaseRecord) bool) error {
+	file, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("open lock file %q: %w", l.path, err)
+	}
+
+	defer file.Close()
+
+	err = lockFile(ctx, file)
+	if err != nil {
+		return fmt.Errorf("lock file %q: %w", l.path, err)
+	}
+
+	defer unlockFile(file) //nolint:errcheck
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("read lock file %q: %w", l.path, err)
+	}
+
+	record := leaseRecord{}
+
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &record)
+		if err != nil {
+			// Overwriting a corrupt lease is safe, as we hold the lock
+			record = leaseRecord{}
+		}
+	}
+
+	if !fn(&record) {
+		return nil
+	}
+
+	data, err = json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("write lock file %q: %w", l.path, err)
+	}
+
+	err = file.Truncate(0)
+	if err != nil {
+		return fmt.Errorf("write lock file %q: %w", l.path, err)
+	}
+
+	_, err = file.WriteAt(data, 0)
+	if err != nil {
+		return fmt.Errorf("write lock file %q: %w", l.path, err)
+	}
+
+	return file.Sync()
+}
+
+// lockFile waits until it holds an exclusive lock on the file, or the context is done.
+func lockFile(ctx context.Context, file *os.File) error {
+	for {
+		locked, err := tryLockFile(file)
+		if err != nil {
+			return err
+		} else if locked {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+}
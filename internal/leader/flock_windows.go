@@ -0,0 +1,16 @@
+//go:build windows
+
+package leader
+
+import (
+	"errors"
+	"os"
+)
+
+func tryLockFile(_ *os.File) (bool, error) {
+	return false, errors.New("file based leader election is not supported on Windows")
+}
+
+func unlockFile(_ *os.File) error {
+	return nil
+}
@@ -0,0 +1,231 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+// LeaseBackend is a lock with an expiration time, shared by all the instances of the CLI that take part in the leader election.
+type LeaseBackend interface {
+	// TryAcquire acquires the lease for the given identity, or extends it if the identity already holds it.
+	// Returns false if another identity holds a lease that did not expire yet.
+	TryAcquire(ctx context.Context, identity string, ttl time.Duration) (bool, error)
+
+	// Release gives up the lease if it is held by the given identity, so another instance can take over immediately.
+	Release(ctx context.Context, identity string) error
+}
+
+// BackendFactory creates a lease backend for the given lock (e.g. a file path or a lock name).
+type BackendFactory func(config *types.BaseConfig, lock string) (LeaseBackend, error)
+
+var backends = struct {
+	m         sync.Mutex
+	factories map[string]BackendFactory
+}{
+	factories: map[string]BackendFactory{
+		FileBackend: func(_ *types.BaseConfig, lock string) (LeaseBackend, error) {
+			return NewFileLease(lock)
+		},
+		RaitoBackend: func(config *types.BaseConfig, lock string) (LeaseBackend, error) {
+			return NewRaitoLease(config, lock)
+		},
+	},
+}
+
+const (
+	FileBackend  = "file"
+	RaitoBackend = "raito"
+)
+
+// RegisterBackend makes a lease backend available under the given name (e.g. to use a Kubernetes Lease).
+func RegisterBackend(name string, factory BackendFactory) {
+	backends.m.Lock()
+	defer backends.m.Unlock()
+
+	backends.factories[name] = factory
+}
+
+// NewBackend creates the lease backend registered with the given name.
+func NewBackend(name string, config *types.BaseConfig, lock string) (LeaseBackend, error) {
+	backends.m.Lock()
+	factory, found := backends.factories[name]
+
+	names := make([]string, 0, len(backends.factories))
+	for n := range backends.factories {
+		names = append(names, n)
+	}
+	backends.m.Unlock()
+
+	if !found {
+		sort.Strings(names)
+
+		return nil, fmt.Errorf("unknown leader election backend %q. Possible values are %s", name, strings.Join(names, ", "))
+	}
+
+	return factory(config, lock)
+}
+
+// DefaultIdentity returns an identity that is unique for this instance of the CLI.
+func DefaultIdentity() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// Elector keeps trying to acquire (or extend) the lease, so exactly one instance of the CLI is the leader.
+type Elector struct {
+	backend  LeaseBackend
+	identity string
+	ttl      time.Duration
+	logger   hclog.Logger
+
+	m        sync.Mutex
+	leading  bool
+	acquired chan struct{}
+	lost     chan struct{}
+}
+
+func NewElector(backend LeaseBackend, identity string, ttl time.Duration, logger hclog.Logger) *Elector {
+	return &Elector{
+		backend:  backend,
+		identity: identity,
+		ttl:      ttl,
+		logger:   logger,
+		acquired: make(chan struct{}),
+		lost:     make(chan struct{}),
+	}
+}
+
+// Run takes part in the leader election until the context is done. The lease is released when stopping.
+func (e *Elector) Run(ctx context.Context) {
+	// Renewing well before the lease expires, so a missed renewal doesn't immediately cost the leadership.
+	// This is also the interval at which followers check if they can take over.
+	interval := e.ttl / 3
+
+	// The leader steps down one interval before its lease can expire, so it has stopped leading before another instance can take over.
+	// It is measured from the moment the last successful renewal was requested, as the lease may have been extended at any moment after that.
+	var stepDownAt time.Time
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		requestedAt := time.Now()
+
+		timeout := interval
+		if e.IsLeader() {
+			timeout = max(min(timeout, time.Until(stepDownAt)), 0)
+		}
+
+		acquireCtx, cancel := context.WithTimeout(ctx, timeout)
+		acquired, err := e.backend.TryAcquire(acquireCtx, e.identity, e.ttl)
+
+		cancel()
+
+		switch {
+		case err != nil && ctx.Err() == nil:
+			e.logger.Warn(fmt.Sprintf("Unable to acquire leadership lease: %s", err.Error()))
+
+			if e.IsLeader() && !time.Now().Before(stepDownAt) {
+				e.setLeading(false)
+			}
+		case err == nil:
+			if acquired {
+				stepDownAt = requestedAt.Add(e.ttl - interval)
+			}
+
+			e.setLeading(acquired)
+		}
+
+		select {
+		case <-ctx.Done():
+			e.setLeading(false)
+
+			releaseCtx, cancel := context.WithTimeout(context.Background(), interval)
+
+			err = e.backend.Release(releaseCtx, e.identity)
+			if err != nil {
+				e.logger.Warn(fmt.Sprintf("Unable to release leadership lease: %s", err.Error()))
+			}
+
+			cancel()
+
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// IsLeader returns true if this instance currently holds the lease.
+func (e *Elector) IsLeader() bool {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	return e.leading
+}
+
+// Lead blocks until this instance is the leader and returns a context that is cancelled as soon as the leadership is lost.
+func (e *Elector) Lead(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	for {
+		e.m.Lock()
+		leading := e.leading
+		acquired := e.acquired
+		lost := e.lost
+		e.m.Unlock()
+
+		if leading {
+			leaderCtx, cancel := context.WithCancel(ctx)
+
+			go func() {
+				select {
+				case <-lost:
+					cancel()
+				case <-leaderCtx.Done():
+				}
+			}()
+
+			return leaderCtx, cancel, nil
+		}
+
+		select {
+		case <-acquired:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+func (e *Elector) setLeading(leading bool) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	if e.leading == leading {
+		return
+	}
+
+	e.leading = leading
+
+	if leading {
+		e.logger.Info(fmt.Sprintf("Acquired leadership as %q", e.identity))
+
+		close(e.acquired)
+		e.lost = make(chan struct{})
+	} else {
+		e.logger.Info(fmt.Sprintf("Lost leadership as %q", e.identity))
+
+		close(e.lost)
+		e.acquired = make(chan struct{})
+	}
+}
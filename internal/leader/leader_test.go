@@ -0,0 +1,282 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+// memoryLease is a stand-in for a shared lease backend
+type memoryLease struct {
+	m         sync.Mutex
+	holder    string
+	expiresAt time.Time
+	failing   map[string]bool
+	hanging   bool
+}
+
+func (l *memoryLease) TryAcquire(ctx context.Context, identity string, ttl time.Duration) (bool, error) {
+	l.m.Lock()
+	hanging := l.hanging
+	l.m.Unlock()
+
+	if hanging {
+		<-ctx.Done()
+
+		return false, ctx.Err()
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if l.failing[identity] {
+		return false, errors.New("backend unavailable")
+	}
+
+	if l.holder != "" && l.holder != identity && time.Now().Before(l.expiresAt) {
+		return false, nil
+	}
+
+	l.holder = identity
+	l.expiresAt = time.Now().Add(ttl)
+
+	return true, nil
+}
+
+func (l *memoryLease) Release(_ context.Context, identity string) error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if l.holder == identity {
+		l.holder = ""
+	}
+
+	return nil
+}
+
+func (l *memoryLease) setFailing(identity string, failing bool) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if l.failing == nil {
+		l.failing = make(map[string]bool)
+	}
+
+	l.failing[identity] = failing
+}
+
+func startElector(t *testing.T, backend LeaseBackend, identity string, ttl time.Duration) (*Elector, context.CancelFunc) {
+	t.Helper()
+
+	elector := NewElector(backend, identity, ttl, hclog.NewNullLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		elector.Run(ctx)
+	}()
+
+	stop := func() {
+		cancel()
+		<-done
+	}
+
+	t.Cleanup(stop)
+
+	return elector, stop
+}
+
+func lead(t *testing.T, elector *Elector) context.Context {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	t.Cleanup(cancel)
+
+	leaderCtx, leaderCancel, err := elector.Lead(ctx)
+	require.NoError(t, err)
+
+	t.Cleanup(leaderCancel)
+
+	return leaderCtx
+}
+
+func TestElector_FailoverOnRelease(t *testing.T) {
+	backend := &memoryLease{}
+
+	first, stopFirst := startElector(t, backend, "first", 300*time.Millisecond)
+	leaderCtx := lead(t, first)
+
+	second, _ := startElector(t, backend, "second", 300*time.Millisecond)
+
+	time.Sleep(150 * time.Millisecond)
+	assert.False(t, second.IsLeader())
+
+	// Releasing the lease when stopping, so the follower can take over without waiting for the lease to expire
+	stopFirst()
+
+	select {
+	case <-leaderCtx.Done():
+	case <-time.After(time.Second):
+		require.Fail(t, "leadership not lost")
+	}
+
+	lead(t, second)
+	assert.True(t, second.IsLeader())
+	assert.False(t, first.IsLeader())
+}
+
+func TestElector_StepsDownWhenLeaseCannotBeRenewed(t *testing.T) {
+	backend := &memoryLease{}
+
+	first, _ := startElector(t, backend, "first", 300*time.Millisecond)
+	leaderCtx := lead(t, first)
+
+	second, _ := startElector(t, backend, "second", 300*time.Millisecond)
+
+	backend.setFailing("first", true)
+
+	select {
+	case <-leaderCtx.Done():
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "leadership not lost")
+	}
+
+	lead(t, second)
+	assert.False(t, first.IsLeader())
+}
+
+func TestElector_StepsDownBeforeLeaseExpires(t *testing.T) {
+	backend := &memoryLease{}
+
+	first, _ := startElector(t, backend, "first", 300*time.Millisecond)
+	leaderCtx := lead(t, first)
+
+	// The renewal requests don't return, so the leader can't know whether the lease is still extended
+	backend.m.Lock()
+	backend.hanging = true
+	expiresAt := backend.expiresAt
+	backend.m.Unlock()
+
+	select {
+	case <-leaderCtx.Done():
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "leadership not lost")
+	}
+
+	assert.True(t, time.Now().Before(expiresAt), "stepped down after the lease expired")
+}
+
+func TestElector_LeadCancelled(t *testing.T) {
+	backend := &memoryLease{holder: "other", expiresAt: time.Now().Add(time.Hour)}
+
+	elector, _ := startElector(t, backend, "first", 300*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, _, err := elector.Lead(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestFileLease(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "leases", "raito.lock")
+
+	lease, err := NewFileLease(path)
+	require.NoError(t, err)
+
+	otherLease, err := NewFileLease(path)
+	require.NoError(t, err)
+
+	acquired, err := lease.TryAcquire(ctx, "first", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = otherLease.TryAcquire(ctx, "second", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	// Renewing
+	acquired, err = lease.TryAcquire(ctx, "first", time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	// Taking over an expired lease
+	time.Sleep(5 * time.Millisecond)
+
+	acquired, err = otherLease.TryAcquire(ctx, "second", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	// Only the holder can release the lease
+	require.NoError(t, lease.Release(ctx, "first"))
+
+	acquired, err = lease.TryAcquire(ctx, "first", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	require.NoError(t, otherLease.Release(ctx, "second"))
+
+	acquired, err = lease.TryAcquire(ctx, "first", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestFileLease_LockedByOtherProcess(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "raito.lock")
+
+	lease, err := NewFileLease(path)
+	require.NoError(t, err)
+
+	// Another process holding the lock on the file
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	locked, err := tryLockFile(file)
+	require.NoError(t, err)
+	require.True(t, locked)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err = lease.TryAcquire(ctx, "first", time.Hour)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The lease can be acquired once the lock is released
+	require.NoError(t, unlockFile(file))
+
+	acquired, err := lease.TryAcquire(context.Background(), "first", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestNewBackend(t *testing.T) {
+	RegisterBackend("test", func(_ *types.BaseConfig, lock string) (LeaseBackend, error) {
+		return &memoryLease{holder: lock}, nil
+	})
+
+	backend, err := NewBackend("test", nil, "lock")
+	require.NoError(t, err)
+	assert.Equal(t, "lock", backend.(*memoryLease).holder)
+
+	_, err = NewBackend("zookeeper", nil, "lock")
+	assert.ErrorContains(t, err, `unknown leader election backend "zookeeper". Possible values are file, raito, test`)
+
+	_, err = NewBackend(FileBackend, nil, "")
+	assert.ErrorContains(t, err, "no lock file defined")
+}
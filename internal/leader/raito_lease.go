@@ -0,0 +1,79 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/raito-io/cli/internal/graphql"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+var _ LeaseBackend = (*RaitoLease)(nil)
+
+// RaitoLease is a lease kept by Raito Cloud, so no shared volume is needed between the instances.
+type RaitoLease struct {
+	config *types.BaseConfig
+	name   string
+}
+
+func NewRaitoLease(config *types.BaseConfig, name string) (*RaitoLease, error) {
+	if name == "" {
+		return nil, errors.New("no lock name defined for the leader election")
+	}
+
+	return &RaitoLease{config: config, name: name}, nil
+}
+
+func (l *RaitoLease) TryAcquire(ctx context.Context, identity string, ttl time.Duration) (bool, error) {
+	var mutation struct {
+		AcquireCliLease struct {
+			Acquired bool
+		} `graphql:"acquireCliLease(input: $input)"`
+	}
+
+	type CliLeaseInput struct {
+		Name       string `json:"name"`
+		Holder     string `json:"holder"`
+		TtlSeconds int    `json:"ttlSeconds"`
+	}
+
+	input := CliLeaseInput{
+		Name:       l.name,
+		Holder:     identity,
+		TtlSeconds: int(ttl.Round(time.Second).Seconds()),
+	}
+
+	err := graphql.NewClient(l.config).Mutate(ctx, &mutation, map[string]interface{}{"input": input})
+	if err != nil {
+		return false, fmt.Errorf("error while acquiring lease %q: %s", l.name, err.Error())
+	}
+
+	return mutation.AcquireCliLease.Acquired, nil
+}
+
+func (l *RaitoLease) Release(ctx context.Context, identity string) error {
+	var mutation struct {
+		ReleaseCliLease struct {
+			Released bool
+		} `graphql:"releaseCliLease(input: $input)"`
+	}
+
+	type CliLeaseReleaseInput struct {
+		Name   string `json:"name"`
+		Holder string `json:"holder"`
+	}
+
+	input := CliLeaseReleaseInput{
+		Name:   l.name,
+		Holder: identity,
+	}
+
+	err := graphql.NewClient(l.config).Mutate(ctx, &mutation, map[string]interface{}{"input": input})
+	if err != nil {
+		return fmt.Errorf("error while releasing lease %q: %s", l.name, err.Error())
+	}
+
+	return nil
+}
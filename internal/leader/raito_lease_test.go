@@ -0,0 +1,97 @@
+package leader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/util/test"
+)
+
+// raitoLeaseStandIn mimics the lease mutations of Raito Cloud
+func raitoLeaseStandIn(t *testing.T) *RaitoLease {
+	t.Helper()
+
+	var m sync.Mutex
+
+	holders := map[string]string{}
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var request struct {
+			Query     string `json:"query"`
+			Variables struct {
+				Input struct {
+					Name       string `json:"name"`
+					Holder     string `json:"holder"`
+					TtlSeconds int    `json:"ttlSeconds"`
+				} `json:"input"`
+			} `json:"variables"`
+		}
+
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&request))
+
+		m.Lock()
+		defer m.Unlock()
+
+		input := request.Variables.Input
+		holder := holders[input.Name]
+
+		res.WriteHeader(http.StatusOK)
+
+		switch {
+		case strings.Contains(request.Query, "acquireCliLease"):
+			acquired := holder == "" || holder == input.Holder
+			if acquired {
+				holders[input.Name] = input.Holder
+			}
+
+			_, _ = res.Write([]byte(fmt.Sprintf(`{"data": {"acquireCliLease": {"acquired": %t}}}`, acquired)))
+		default:
+			if holder == input.Holder {
+				delete(holders, input.Name)
+			}
+
+			_, _ = res.Write([]byte(`{"data": {"releaseCliLease": {"released": true}}}`))
+		}
+	}))
+
+	baseConfig, closer := test.CreateBaseConfig("TestRaito", "Userke", "SecretStuff", testServer.URL)
+
+	t.Cleanup(func() {
+		closer()
+		testServer.Close()
+	})
+
+	lease, err := NewRaitoLease(baseConfig, "daemon")
+	require.NoError(t, err)
+
+	return lease
+}
+
+func TestRaitoLease(t *testing.T) {
+	ctx := context.Background()
+	lease := raitoLeaseStandIn(t)
+
+	acquired, err := lease.TryAcquire(ctx, "first", 15*time.Second)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = lease.TryAcquire(ctx, "second", 15*time.Second)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	require.NoError(t, lease.Release(ctx, "first"))
+
+	acquired, err = lease.TryAcquire(ctx, "second", 15*time.Second)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
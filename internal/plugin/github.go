@@ -15,6 +15,7 @@ import (
 
 	"github.com/raito-io/cli/internal/config"
 	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/util/httpclient"
 )
 
 // downloadAndExtractPluginFromGitHubRepo looks for the plugin with the given information and stores it in the 'targetPath'.
@@ -64,8 +65,10 @@ func downloadAndExtractPluginFromGitHubRepo(pluginRequest *pluginRequest, target
 func getGitHubAsset(pluginRequest *pluginRequest, logger hclog.Logger) (*gitHubReleaseAsset, error) {
 	url := getGitHubReleaseURL(pluginRequest)
 
-	client := retryablehttp.NewClient()
-	client.Logger = logger
+	client, err := newRetryableClient(logger)
+	if err != nil {
+		return nil, err
+	}
 
 	request, err := retryablehttp.NewRequest("GET", url, nil)
 	if err != nil {
@@ -119,8 +122,10 @@ func getGitHubAsset(pluginRequest *pluginRequest, logger hclog.Logger) (*gitHubR
 // Returns the filename of the file created (if there is one, otherwise, empty string).
 // Returns an error if an error occurred.
 func downloadGitHubAsset(pluginRequest *pluginRequest, url string, logger hclog.Logger) (string, error) {
-	client := retryablehttp.NewClient()
-	client.Logger = logger
+	client, err := newRetryableClient(logger)
+	if err != nil {
+		return "", err
+	}
 
 	request, err := retryablehttp.NewRequest("GET", url, nil)
 	if err != nil {
@@ -168,6 +173,20 @@ func downloadGitHubAsset(pluginRequest *pluginRequest, url string, logger hclog.
 	return out.Name(), nil
 }
 
+// newRetryableClient creates a retryable HTTP client that uses the shared CLI transport.
+func newRetryableClient(logger hclog.Logger) (*retryablehttp.Client, error) {
+	httpClient, err := httpclient.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	client := retryablehttp.NewClient()
+	client.HTTPClient = httpClient
+	client.Logger = logger
+
+	return client, nil
+}
+
 // getGitHubReleaseURL builds the github URL to fetch the assets of a specific release (either latest or a given version).
 func getGitHubReleaseURL(pluginRequest *pluginRequest) string {
 	url := "https://api.github.com/repos/" + pluginRequest.Group + "/" + pluginRequest.RepositoryName + "/releases/"
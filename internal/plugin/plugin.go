@@ -15,6 +15,7 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/hashicorp/go-hclog"
@@ -77,6 +78,36 @@ type PluginClient interface {
 	GetInfo() (plugin2.Info, error)
 }
 
+// TargetClientProvider creates the plugin client to use for a target.
+type TargetClientProvider func(key string, connector string, version string, logger hclog.Logger) (PluginClient, error)
+
+var targetClientProvider = struct {
+	m        sync.Mutex
+	provider TargetClientProvider
+}{}
+
+// SetTargetClientProvider replaces how the plugin clients for targets are created (e.g. to record or replay the interactions with the connectors).
+// Passing nil falls back to starting the connector plugin.
+func SetTargetClientProvider(provider TargetClientProvider) {
+	targetClientProvider.m.Lock()
+	defer targetClientProvider.m.Unlock()
+
+	targetClientProvider.provider = provider
+}
+
+// NewTargetPluginClient creates the plugin client to sync a target. The key identifies the client within a run (e.g. the name of the target).
+func NewTargetPluginClient(key string, connector string, version string, logger hclog.Logger) (PluginClient, error) {
+	targetClientProvider.m.Lock()
+	provider := targetClientProvider.provider
+	targetClientProvider.m.Unlock()
+
+	if provider != nil {
+		return provider(key, connector, version, logger)
+	}
+
+	return NewPluginClient(connector, version, logger)
+}
+
 func NewPluginClient(connector string, version string, logger hclog.Logger) (PluginClient, error) {
 	pluginPath, err := findMatchingPlugin(connector, version, logger)
 	if err != nil {
@@ -0,0 +1,477 @@
+package recording
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/data_object_enricher"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/data_usage"
+	"github.com/raito-io/cli/base/identity_store"
+	"github.com/raito-io/cli/base/resource_provider"
+	"github.com/raito-io/cli/base/tag"
+	"github.com/raito-io/cli/base/util/config"
+	plugin2 "github.com/raito-io/cli/base/util/plugin"
+	"github.com/raito-io/cli/base/util/version"
+	"github.com/raito-io/cli/internal/plugin"
+)
+
+// Recorder records all interactions with the connector plugins in a directory.
+// Failing to record an interaction is logged, but doesn't fail the sync itself.
+type Recorder struct {
+	dir    string
+	logger hclog.Logger
+
+	// allowedParameters are the connector parameters of which the value is recorded. The values of all other parameters are censored.
+	allowedParameters map[string]struct{}
+
+	// newClient creates the plugin client to record the interactions of.
+	newClient plugin.TargetClientProvider
+
+	m       sync.Mutex
+	clients map[string]*clientRecording
+}
+
+// NewRecorder creates a recorder that stores the recorded interactions in the given directory.
+// The values of the connector parameters are censored, except for the given allowed parameters that don't have a sensitive name (like a password).
+func NewRecorder(dir string, logger hclog.Logger, allowedParameters []string) (*Recorder, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("creating recording directory %q: %w", dir, err)
+	}
+
+	allowed := make(map[string]struct{}, len(allowedParameters))
+	for _, parameter := range allowedParameters {
+		allowed[parameter] = struct{}{}
+	}
+
+	return &Recorder{
+		dir:               dir,
+		logger:            logger,
+		allowedParameters: allowed,
+		newClient: func(_ string, connector string, version string, logger hclog.Logger) (plugin.PluginClient, error) {
+			return plugin.NewPluginClient(connector, version, logger)
+		},
+		clients: map[string]*clientRecording{},
+	}, nil
+}
+
+// Provider starts the connector plugin and records all interactions with it. It can be passed to plugin.SetTargetClientProvider.
+func (r *Recorder) Provider(key string, connector string, version string, logger hclog.Logger) (plugin.PluginClient, error) {
+	client, err := r.newClient(key, connector, version, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingClient{PluginClient: client, recording: r.recording(key)}, nil
+}
+
+// recording returns the recording of the client with the given key. Clients that are created multiple times with the same key (e.g. in consecutive runs) share the same recording.
+func (r *Recorder) recording(key string) *clientRecording {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if c, found := r.clients[key]; found {
+		return c
+	}
+
+	c := &clientRecording{
+		dir:               clientDir(r.dir, key),
+		logger:            r.logger.With("recording", key),
+		secrets:           map[string]struct{}{},
+		allowedParameters: r.allowedParameters,
+	}
+
+	r.clients[key] = c
+
+	return c
+}
+
+type fileRef struct {
+	role string
+	path string
+}
+
+// clientRecording stores the calls of a single plugin client.
+type clientRecording struct {
+	dir    string
+	logger hclog.Logger
+
+	m                 sync.Mutex
+	sequence          int
+	secrets           map[string]struct{}
+	allowedParameters map[string]struct{}
+}
+
+// record executes the call and stores its request, response and the exchanged files.
+// The input files are copied before the call, as the CLI may remove them afterwards. The output files are copied after the call.
+func record[T proto.Message](c *clientRecording, method string, request proto.Message, inputs []fileRef, call func() (T, error), outputs func(T) []fileRef) (T, error) {
+	c.m.Lock()
+	c.sequence++
+	entry := recordedCall{Sequence: c.sequence, Method: method, Files: map[string]string{}}
+
+	if request != nil {
+		raw, err := redactMessage(request, c.secrets, c.allowedParameters)
+		if err != nil {
+			c.logger.Warn(fmt.Sprintf("Unable to record request of %s: %s", method, err.Error()))
+		}
+
+		entry.Request = raw
+	}
+
+	c.copyFiles(&entry, inputs)
+	c.m.Unlock()
+
+	response, callErr := call()
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if callErr != nil {
+		entry.Error = newSecretReplacer(c.secrets).Replace(callErr.Error())
+	} else {
+		raw, err := redactMessage(response, c.secrets, c.allowedParameters)
+		if err != nil {
+			c.logger.Warn(fmt.Sprintf("Unable to record response of %s: %s", method, err.Error()))
+		}
+
+		entry.Response = raw
+
+		if outputs != nil {
+			c.copyFiles(&entry, outputs(response))
+		}
+	}
+
+	err := c.write(&entry)
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("Unable to record call of %s: %s", method, err.Error()))
+	}
+
+	return response, callErr
+}
+
+// copyFiles copies the exchanged files that exist into the files directory. Should be called with the lock held.
+func (c *clientRecording) copyFiles(entry *recordedCall, files []fileRef) {
+	replacer := newSecretReplacer(c.secrets)
+
+	for _, f := range files {
+		if f.path == "" || !fileExists(f.path) {
+			continue
+		}
+
+		name := fmt.Sprintf("%d-%s-%s", entry.Sequence, f.role, filepath.Base(f.path))
+
+		err := copyFile(f.path, filepath.Join(c.dir, filesDir, name), replacer)
+		if err != nil {
+			c.logger.Warn(fmt.Sprintf("Unable to record %s of %s: %s", f.role, entry.Method, err.Error()))
+
+			continue
+		}
+
+		entry.Files[f.role] = name
+	}
+}
+
+// write appends the call to the calls file. Should be called with the lock held.
+func (c *clientRecording) write(entry *recordedCall) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("serializing call: %w", err)
+	}
+
+	err = os.MkdirAll(c.dir, 0700)
+	if err != nil {
+		return fmt.Errorf("creating directory %q: %w", c.dir, err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(c.dir, callsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening calls file: %w", err)
+	}
+
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		return fmt.Errorf("writing calls file: %w", err)
+	}
+
+	return nil
+}
+
+// recordingClient wraps all syncers of a plugin client to record their calls.
+type recordingClient struct {
+	plugin.PluginClient
+
+	recording *clientRecording
+}
+
+func (c *recordingClient) GetDataSourceSyncer() (data_source.DataSourceSyncer, error) {
+	syncer, err := c.PluginClient.GetDataSourceSyncer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingDataSourceSyncer{syncer: syncer, recording: c.recording}, nil
+}
+
+func (c *recordingClient) GetDataObjectEnricher() (data_object_enricher.DataObjectEnricher, error) {
+	enricher, err := c.PluginClient.GetDataObjectEnricher()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingEnricher{enricher: enricher, recording: c.recording}, nil
+}
+
+func (c *recordingClient) GetIdentityStoreSyncer() (identity_store.IdentityStoreSyncer, error) {
+	syncer, err := c.PluginClient.GetIdentityStoreSyncer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingIdentityStoreSyncer{syncer: syncer, recording: c.recording}, nil
+}
+
+func (c *recordingClient) GetAccessSyncer() (access_provider.AccessSyncer, error) {
+	syncer, err := c.PluginClient.GetAccessSyncer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingAccessSyncer{syncer: syncer, recording: c.recording}, nil
+}
+
+func (c *recordingClient) GetDataUsageSyncer() (data_usage.DataUsageSyncer, error) {
+	syncer, err := c.PluginClient.GetDataUsageSyncer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingDataUsageSyncer{syncer: syncer, recording: c.recording}, nil
+}
+
+func (c *recordingClient) GetResourceProvider() (resource_provider.ResourceProviderSyncer, error) {
+	syncer, err := c.PluginClient.GetResourceProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingResourceProvider{syncer: syncer, recording: c.recording}, nil
+}
+
+func (c *recordingClient) GetTagSyncer() (tag.TagSyncer, error) {
+	syncer, err := c.PluginClient.GetTagSyncer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingTagSyncer{syncer: syncer, recording: c.recording}, nil
+}
+
+func (c *recordingClient) GetInfo() (plugin2.Info, error) {
+	info, err := c.PluginClient.GetInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordingInfo{info: info, recording: c.recording}, nil
+}
+
+type recordingDataSourceSyncer struct {
+	syncer    data_source.DataSourceSyncer
+	recording *clientRecording
+}
+
+func (s *recordingDataSourceSyncer) CliVersionInformation(ctx context.Context) (*version.CliBuildInformation, error) {
+	return record(s.recording, methodDataSourceVersion, nil, nil, func() (*version.CliBuildInformation, error) {
+		return s.syncer.CliVersionInformation(ctx)
+	}, nil)
+}
+
+func (s *recordingDataSourceSyncer) SyncDataSource(ctx context.Context, syncConfig *data_source.DataSourceSyncConfig) (*data_source.DataSourceSyncResult, error) {
+	return record(s.recording, methodSyncDataSource, syncConfig, nil, func() (*data_source.DataSourceSyncResult, error) {
+		return s.syncer.SyncDataSource(ctx, syncConfig)
+	}, func(result *data_source.DataSourceSyncResult) []fileRef {
+		files := []fileRef{{role: roleTargetFile, path: syncConfig.TargetFile}, {role: roleDeletedObjectsFile, path: syncConfig.DeletedObjectsFile}}
+
+		return append(files, indexedFileRefs(rolePartFiles, result.GetPartFiles())...)
+	})
+}
+
+func (s *recordingDataSourceSyncer) GetDataSourceMetaData(ctx context.Context, configMap *config.ConfigMap) (*data_source.MetaData, error) {
+	return record(s.recording, methodDataSourceMetaData, configMap, nil, func() (*data_source.MetaData, error) {
+		return s.syncer.GetDataSourceMetaData(ctx, configMap)
+	}, nil)
+}
+
+type recordingEnricher struct {
+	enricher  data_object_enricher.DataObjectEnricher
+	recording *clientRecording
+}
+
+func (s *recordingEnricher) CliVersionInformation(ctx context.Context) (*version.CliBuildInformation, error) {
+	return record(s.recording, methodEnricherVersion, nil, nil, func() (*version.CliBuildInformation, error) {
+		return s.enricher.CliVersionInformation(ctx)
+	}, nil)
+}
+
+func (s *recordingEnricher) Enrich(ctx context.Context, enricherConfig *data_object_enricher.DataObjectEnricherConfig) (*data_object_enricher.DataObjectEnricherResult, error) {
+	return record(s.recording, methodEnrich, enricherConfig, []fileRef{{role: roleInputFile, path: enricherConfig.InputFile}}, func() (*data_object_enricher.DataObjectEnricherResult, error) {
+		return s.enricher.Enrich(ctx, enricherConfig)
+	}, func(*data_object_enricher.DataObjectEnricherResult) []fileRef {
+		return []fileRef{{role: roleOutputFile, path: enricherConfig.OutputFile}}
+	})
+}
+
+type recordingIdentityStoreSyncer struct {
+	syncer    identity_store.IdentityStoreSyncer
+	recording *clientRecording
+}
+
+func (s *recordingIdentityStoreSyncer) CliVersionInformation(ctx context.Context) (*version.CliBuildInformation, error) {
+	return record(s.recording, methodIdentityStoreVersion, nil, nil, func() (*version.CliBuildInformation, error) {
+		return s.syncer.CliVersionInformation(ctx)
+	}, nil)
+}
+
+func (s *recordingIdentityStoreSyncer) SyncIdentityStore(ctx context.Context, syncConfig *identity_store.IdentityStoreSyncConfig) (*identity_store.IdentityStoreSyncResult, error) {
+	return record(s.recording, methodSyncIdentityStore, syncConfig, nil, func() (*identity_store.IdentityStoreSyncResult, error) {
+		return s.syncer.SyncIdentityStore(ctx, syncConfig)
+	}, func(*identity_store.IdentityStoreSyncResult) []fileRef {
+		return []fileRef{{role: roleUserFile, path: syncConfig.UserFile}, {role: roleGroupFile, path: syncConfig.GroupFile}}
+	})
+}
+
+func (s *recordingIdentityStoreSyncer) GetIdentityStoreMetaData(ctx context.Context, configMap *config.ConfigMap) (*identity_store.MetaData, error) {
+	return record(s.recording, methodIdentityStoreMetaData, configMap, nil, func() (*identity_store.MetaData, error) {
+		return s.syncer.GetIdentityStoreMetaData(ctx, configMap)
+	}, nil)
+}
+
+type recordingAccessSyncer struct {
+	syncer    access_provider.AccessSyncer
+	recording *clientRecording
+}
+
+func (s *recordingAccessSyncer) CliVersionInformation(ctx context.Context) (*version.CliBuildInformation, error) {
+	return record(s.recording, methodAccessVersion, nil, nil, func() (*version.CliBuildInformation, error) {
+		return s.syncer.CliVersionInformation(ctx)
+	}, nil)
+}
+
+func (s *recordingAccessSyncer) SyncFromTarget(ctx context.Context, syncConfig *access_provider.AccessSyncFromTarget) (*access_provider.AccessSyncResult, error) {
+	return record(s.recording, methodSyncFromTarget, syncConfig, nil, func() (*access_provider.AccessSyncResult, error) {
+		return s.syncer.SyncFromTarget(ctx, syncConfig)
+	}, func(*access_provider.AccessSyncResult) []fileRef {
+		return []fileRef{{role: roleTargetFile, path: syncConfig.TargetFile}}
+	})
+}
+
+func (s *recordingAccessSyncer) SyncToTarget(ctx context.Context, syncConfig *access_provider.AccessSyncToTarget) (*access_provider.AccessSyncResult, error) {
+	return record(s.recording, methodSyncToTarget, syncConfig, []fileRef{{role: roleSourceFile, path: syncConfig.SourceFile}}, func() (*access_provider.AccessSyncResult, error) {
+		return s.syncer.SyncToTarget(ctx, syncConfig)
+	}, func(*access_provider.AccessSyncResult) []fileRef {
+		return []fileRef{{role: roleFeedbackTargetFile, path: syncConfig.FeedbackTargetFile}}
+	})
+}
+
+func (s *recordingAccessSyncer) SyncConfig(ctx context.Context) (*access_provider.AccessSyncConfig, error) {
+	return record(s.recording, methodSyncConfig, nil, nil, func() (*access_provider.AccessSyncConfig, error) {
+		return s.syncer.SyncConfig(ctx)
+	}, nil)
+}
+
+type recordingDataUsageSyncer struct {
+	syncer    data_usage.DataUsageSyncer
+	recording *clientRecording
+}
+
+func (s *recordingDataUsageSyncer) CliVersionInformation(ctx context.Context) (*version.CliBuildInformation, error) {
+	return record(s.recording, methodDataUsageVersion, nil, nil, func() (*version.CliBuildInformation, error) {
+		return s.syncer.CliVersionInformation(ctx)
+	}, nil)
+}
+
+func (s *recordingDataUsageSyncer) SyncDataUsage(ctx context.Context, syncConfig *data_usage.DataUsageSyncConfig) (*data_usage.DataUsageSyncResult, error) {
+	return record(s.recording, methodSyncDataUsage, syncConfig, nil, func() (*data_usage.DataUsageSyncResult, error) {
+		return s.syncer.SyncDataUsage(ctx, syncConfig)
+	}, func(result *data_usage.DataUsageSyncResult) []fileRef {
+		if len(result.GetPartFiles()) > 0 {
+			return indexedFileRefs(rolePartFiles, result.PartFiles)
+		}
+
+		// Connectors that split the usage in multiple files return them instead of writing the target file
+		if len(result.GetTargetFiles()) == 0 {
+			return []fileRef{{role: roleTargetFile, path: syncConfig.TargetFile}}
+		}
+
+		return indexedFileRefs(roleTargetFiles, result.TargetFiles)
+	})
+}
+
+// indexedFileRefs returns the references to a list of files returned by a connector.
+func indexedFileRefs(role string, paths []string) []fileRef {
+	files := make([]fileRef, 0, len(paths))
+
+	for i, f := range paths {
+		files = append(files, fileRef{role: indexedRole(role, i), path: f})
+	}
+
+	return files
+}
+
+type recordingResourceProvider struct {
+	syncer    resource_provider.ResourceProviderSyncer
+	recording *clientRecording
+}
+
+func (s *recordingResourceProvider) CliVersionInformation(ctx context.Context) (*version.CliBuildInformation, error) {
+	return record(s.recording, methodResourceProviderVersion, nil, nil, func() (*version.CliBuildInformation, error) {
+		return s.syncer.CliVersionInformation(ctx)
+	}, nil)
+}
+
+func (s *recordingResourceProvider) UpdateResources(ctx context.Context, input *resource_provider.UpdateResourceInput) (*resource_provider.UpdateResourceResult, error) {
+	return record(s.recording, methodUpdateResources, input, nil, func() (*resource_provider.UpdateResourceResult, error) {
+		return s.syncer.UpdateResources(ctx, input)
+	}, nil)
+}
+
+type recordingTagSyncer struct {
+	syncer    tag.TagSyncer
+	recording *clientRecording
+}
+
+func (s *recordingTagSyncer) CliVersionInformation(ctx context.Context) (*version.CliBuildInformation, error) {
+	return record(s.recording, methodTagVersion, nil, nil, func() (*version.CliBuildInformation, error) {
+		return s.syncer.CliVersionInformation(ctx)
+	}, nil)
+}
+
+func (s *recordingTagSyncer) SyncTags(ctx context.Context, syncConfig *tag.TagSyncConfig) (*tag.TagSyncResult, error) {
+	return record(s.recording, methodSyncTags, syncConfig, nil, func() (*tag.TagSyncResult, error) {
+		return s.syncer.SyncTags(ctx, syncConfig)
+	}, func(*tag.TagSyncResult) []fileRef {
+		return []fileRef{{role: roleTargetFile, path: syncConfig.TargetFile}}
+	})
+}
+
+type recordingInfo struct {
+	info      plugin2.Info
+	recording *clientRecording
+}
+
+func (s *recordingInfo) GetInfo(ctx context.Context) (*plugin2.PluginInfo, error) {
+	return record(s.recording, methodGetInfo, nil, nil, func() (*plugin2.PluginInfo, error) {
+		return s.info.GetInfo(ctx)
+	}, nil)
+}
@@ -0,0 +1,270 @@
+// Package recording records the interactions of the CLI with the connector plugins (the RPC requests and responses and the files they exchange),
+// so they can be replayed later on through the CLI without access to the data source.
+//
+// A recording directory contains a sub-directory per plugin client (the name of the target, or the target and enricher for enrichers).
+// Each of them has a 'calls.jsonl' file with a line per RPC call and a 'files' directory with the copies of the exchanged files.
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/raito-io/cli/internal/util/stringops"
+)
+
+const (
+	callsFile = "calls.jsonl"
+	filesDir  = "files"
+
+	censored = "**censured**"
+
+	// parametersField is the field of a serialized config map that contains the connector parameters
+	parametersField = "parameters"
+)
+
+const (
+	methodDataSourceVersion       = "DataSourceSyncer.CliVersionInformation"
+	methodSyncDataSource          = "DataSourceSyncer.SyncDataSource"
+	methodDataSourceMetaData      = "DataSourceSyncer.GetDataSourceMetaData"
+	methodEnricherVersion         = "DataObjectEnricher.CliVersionInformation"
+	methodEnrich                  = "DataObjectEnricher.Enrich"
+	methodIdentityStoreVersion    = "IdentityStoreSyncer.CliVersionInformation"
+	methodSyncIdentityStore       = "IdentityStoreSyncer.SyncIdentityStore"
+	methodIdentityStoreMetaData   = "IdentityStoreSyncer.GetIdentityStoreMetaData"
+	methodAccessVersion           = "AccessSyncer.CliVersionInformation"
+	methodSyncFromTarget          = "AccessSyncer.SyncFromTarget"
+	methodSyncToTarget            = "AccessSyncer.SyncToTarget"
+	methodSyncConfig              = "AccessSyncer.SyncConfig"
+	methodDataUsageVersion        = "DataUsageSyncer.CliVersionInformation"
+	methodSyncDataUsage           = "DataUsageSyncer.SyncDataUsage"
+	methodResourceProviderVersion = "ResourceProviderSyncer.CliVersionInformation"
+	methodUpdateResources         = "ResourceProviderSyncer.UpdateResources"
+	methodTagVersion              = "TagSyncer.CliVersionInformation"
+	methodSyncTags                = "TagSyncer.SyncTags"
+	methodGetInfo                 = "Info.GetInfo"
+)
+
+// Roles of the files exchanged with the plugins, as stored in a recorded call.
+const (
+	roleTargetFile         = "targetFile"
+	roleTargetFiles        = "targetFiles"
+	rolePartFiles          = "partFiles"
+	roleSourceFile         = "sourceFile"
+	roleFeedbackTargetFile = "feedbackTargetFile"
+	roleUserFile           = "userFile"
+	roleGroupFile          = "groupFile"
+	roleInputFile          = "inputFile"
+	roleOutputFile         = "outputFile"
+	roleDeletedObjectsFile = "deletedObjectsFile"
+)
+
+// recordedCall is a single RPC call to a plugin, as stored on a line of the calls file.
+type recordedCall struct {
+	Sequence int             `json:"sequence"`
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+
+	// Files maps the role of the exchanged files (e.g. 'targetFile') to their copy in the files directory.
+	Files map[string]string `json:"files,omitempty"`
+}
+
+var unsafeKeyCharacters = regexp.MustCompile(`[^a-zA-Z0-9._/-]`)
+
+// clientDir returns the directory in which the calls of the plugin client with the given key are stored.
+func clientDir(dir string, key string) string {
+	parts := strings.Split(unsafeKeyCharacters.ReplaceAllString(key, "_"), "/")
+
+	for i, part := range parts {
+		if part == "" || part == "." || part == ".." {
+			parts[i] = "_"
+		}
+	}
+
+	return filepath.Join(append([]string{dir}, parts...)...)
+}
+
+func indexedRole(role string, index int) string {
+	return role + "." + strconv.Itoa(index)
+}
+
+// censorSensitiveFields replaces the values of the fields with a sensitive name (like passwords) and adds them to the secrets.
+// The values of the connector parameters are all replaced, except for the allowed parameters that don't have a sensitive name.
+func censorSensitiveFields(v interface{}, secrets map[string]struct{}, allowedParameters map[string]struct{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, field := range value {
+			if s, ok := field.(string); ok && stringops.IsSensitiveKey(k) {
+				if isSecretValue(s) {
+					secrets[s] = struct{}{}
+				}
+
+				value[k] = censored
+
+				continue
+			}
+
+			if parameters, ok := field.(map[string]interface{}); ok && k == parametersField {
+				value[k] = censorParameters(parameters, secrets, allowedParameters)
+
+				continue
+			}
+
+			value[k] = censorSensitiveFields(field, secrets, allowedParameters)
+		}
+	case []interface{}:
+		for i := range value {
+			value[i] = censorSensitiveFields(value[i], secrets, allowedParameters)
+		}
+	}
+
+	return v
+}
+
+// censorParameters replaces the values of all connector parameters that are not allowed explicitly.
+// Only the values of the parameters with a sensitive name are added to the secrets, as replacing every parameter value in the rest of the recording would make it useless.
+func censorParameters(parameters map[string]interface{}, secrets map[string]struct{}, allowedParameters map[string]struct{}) map[string]interface{} {
+	for k, field := range parameters {
+		if s, ok := field.(string); ok && stringops.IsSensitiveKey(k) && isSecretValue(s) {
+			secrets[s] = struct{}{}
+		}
+
+		if _, allowed := allowedParameters[k]; allowed && !stringops.IsSensitiveKey(k) {
+			continue
+		}
+
+		parameters[k] = censored
+	}
+
+	return parameters
+}
+
+// isSecretValue returns false for values that are too generic to be replaced in the rest of the recording (like booleans and numbers).
+func isSecretValue(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	if _, err := strconv.ParseBool(s); err == nil {
+		return false
+	}
+
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return false
+	}
+
+	return true
+}
+
+// replaceSecrets replaces the known secrets in all string values.
+func replaceSecrets(v interface{}, replacer *strings.Replacer) interface{} {
+	switch value := v.(type) {
+	case string:
+		return replacer.Replace(value)
+	case map[string]interface{}:
+		for k, field := range value {
+			value[k] = replaceSecrets(field, replacer)
+		}
+	case []interface{}:
+		for i := range value {
+			value[i] = replaceSecrets(value[i], replacer)
+		}
+	}
+
+	return v
+}
+
+func newSecretReplacer(secrets map[string]struct{}) *strings.Replacer {
+	oldNew := make([]string, 0, 2*len(secrets))
+
+	for secret := range secrets {
+		oldNew = append(oldNew, secret, censored)
+	}
+
+	return strings.NewReplacer(oldNew...)
+}
+
+// redactMessage serializes the message, censors its sensitive fields and connector parameters and replaces all known secrets in it.
+func redactMessage(msg proto.Message, secrets map[string]struct{}, allowedParameters map[string]struct{}) (json.RawMessage, error) {
+	raw, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("serializing %T: %w", msg, err)
+	}
+
+	var value interface{}
+
+	err = json.Unmarshal(raw, &value)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %T: %w", msg, err)
+	}
+
+	value = censorSensitiveFields(value, secrets, allowedParameters)
+	value = replaceSecrets(value, newSecretReplacer(secrets))
+
+	return json.Marshal(value)
+}
+
+// copyFile copies a file line by line, replacing the known secrets in it.
+func copyFile(source string, target string, replacer *strings.Replacer) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("opening %q: %w", source, err)
+	}
+
+	defer in.Close()
+
+	err = os.MkdirAll(filepath.Dir(target), 0700)
+	if err != nil {
+		return fmt.Errorf("creating directory for %q: %w", target, err)
+	}
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("creating %q: %w", target, err)
+	}
+
+	defer out.Close()
+
+	reader := bufio.NewReader(in)
+	writer := bufio.NewWriter(out)
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		if line != "" {
+			if _, err = writer.WriteString(replacer.Replace(line)); err != nil {
+				return fmt.Errorf("writing %q: %w", target, err)
+			}
+		}
+
+		if errors.Is(readErr, io.EOF) {
+			break
+		} else if readErr != nil {
+			return fmt.Errorf("reading %q: %w", source, readErr)
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return fmt.Errorf("writing %q: %w", target, err)
+	}
+
+	return nil
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && !info.IsDir()
+}
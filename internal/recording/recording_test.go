@@ -0,0 +1,221 @@
+package recording
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/data_usage"
+	"github.com/raito-io/cli/base/util/config"
+	"github.com/raito-io/cli/base/util/version"
+	"github.com/raito-io/cli/internal/plugin"
+)
+
+type testDataSourceSyncer struct{}
+
+func (s *testDataSourceSyncer) CliVersionInformation(_ context.Context) (*version.CliBuildInformation, error) {
+	return &version.CliBuildInformation{CliBuildVersion: &version.SemVer{Major: 1, Minor: 2}}, nil
+}
+
+func (s *testDataSourceSyncer) SyncDataSource(_ context.Context, syncConfig *data_source.DataSourceSyncConfig) (*data_source.DataSourceSyncResult, error) {
+	if syncConfig.DataSourceId == "fail" {
+		return nil, errors.New("unable to connect with password " + syncConfig.ConfigMap.GetString("sf-password"))
+	}
+
+	content := `[{"externalId":"schema1","description":"connected as ` + syncConfig.ConfigMap.GetString("sf-user") + ` with ` + syncConfig.ConfigMap.GetString("sf-password") + `"}]`
+
+	return &data_source.DataSourceSyncResult{DataObjects: 1}, os.WriteFile(syncConfig.TargetFile, []byte(content), 0600)
+}
+
+func (s *testDataSourceSyncer) GetDataSourceMetaData(_ context.Context, _ *config.ConfigMap) (*data_source.MetaData, error) {
+	return &data_source.MetaData{Type: "test"}, nil
+}
+
+type testDataUsageSyncer struct{}
+
+func (s *testDataUsageSyncer) CliVersionInformation(_ context.Context) (*version.CliBuildInformation, error) {
+	return &version.CliBuildInformation{CliBuildVersion: &version.SemVer{Major: 1}}, nil
+}
+
+func (s *testDataUsageSyncer) SyncDataUsage(_ context.Context, syncConfig *data_usage.DataUsageSyncConfig) (*data_usage.DataUsageSyncResult, error) {
+	var files []string
+
+	for _, name := range []string{"usage-a.json", "usage-b.json"} {
+		f := filepath.Join(filepath.Dir(syncConfig.TargetFile), name)
+
+		err := os.WriteFile(f, []byte(name), 0600)
+		if err != nil {
+			return nil, err
+		}
+
+		files = append(files, f)
+	}
+
+	return &data_usage.DataUsageSyncResult{Statements: 2, TargetFiles: files}, nil
+}
+
+type testPluginClient struct {
+	plugin.PluginClient
+}
+
+func (c *testPluginClient) Close() {}
+
+func (c *testPluginClient) GetDataSourceSyncer() (data_source.DataSourceSyncer, error) {
+	return &testDataSourceSyncer{}, nil
+}
+
+func (c *testPluginClient) GetDataUsageSyncer() (data_usage.DataUsageSyncer, error) {
+	return &testDataUsageSyncer{}, nil
+}
+
+func newTestRecorder(t *testing.T) (*Recorder, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	recorder, err := NewRecorder(dir, hclog.NewNullLogger(), []string{"sf-user", "sf-password"})
+	require.NoError(t, err)
+
+	recorder.newClient = func(_ string, _ string, _ string, _ hclog.Logger) (plugin.PluginClient, error) {
+		return &testPluginClient{}, nil
+	}
+
+	return recorder, dir
+}
+
+func TestRecordAndReplay_DataSource(t *testing.T) {
+	recorder, dir := newTestRecorder(t)
+	ctx := context.Background()
+	configMap := &config.ConfigMap{Parameters: map[string]string{"sf-user": "bob", "sf-password": "hunter22", "sf-password-auth": "true"}}
+
+	client, err := recorder.Provider("snowflake", "raito-io/cli-plugin-snowflake", "", hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	syncer, err := client.GetDataSourceSyncer()
+	require.NoError(t, err)
+
+	_, err = syncer.CliVersionInformation(ctx)
+	require.NoError(t, err)
+
+	targetFile := filepath.Join(t.TempDir(), "data-source.json")
+	result, err := syncer.SyncDataSource(ctx, &data_source.DataSourceSyncConfig{ConfigMap: configMap, TargetFile: targetFile, DataSourceId: "ds1"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), result.DataObjects)
+
+	_, err = syncer.SyncDataSource(ctx, &data_source.DataSourceSyncConfig{ConfigMap: configMap, TargetFile: targetFile, DataSourceId: "fail"})
+	require.Error(t, err)
+
+	// Secrets are removed from the requests, errors and files
+	calls, err := os.ReadFile(filepath.Join(dir, "snowflake", callsFile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(calls), "hunter22")
+	assert.Contains(t, string(calls), `"sf-password":"**censured**"`)
+	assert.Contains(t, string(calls), `"sf-user":"bob"`)
+	assert.Contains(t, string(calls), `"sf-password-auth":"**censured**"`, "parameters that are not allowed are censored")
+	assert.Contains(t, string(calls), `"error":"unable to connect with password **censured**"`)
+
+	files, err := os.ReadDir(filepath.Join(dir, "snowflake", filesDir))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "2-targetFile-data-source.json", files[0].Name())
+
+	recordedFile, err := os.ReadFile(filepath.Join(dir, "snowflake", filesDir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Equal(t, `[{"externalId":"schema1","description":"connected as bob with **censured**"}]`, string(recordedFile))
+
+	// The recording is only accessible for the current user
+	for path, mode := range map[string]os.FileMode{
+		filepath.Join(dir, "snowflake"):                            0700,
+		filepath.Join(dir, "snowflake", filesDir):                  0700,
+		filepath.Join(dir, "snowflake", callsFile):                 0600,
+		filepath.Join(dir, "snowflake", filesDir, files[0].Name()): 0600,
+	} {
+		info, statErr := os.Stat(path)
+		require.NoError(t, statErr)
+		assert.Equal(t, mode, info.Mode().Perm(), path)
+	}
+
+	// Replaying returns the recorded responses and restores the recorded files
+	replayer, err := NewReplayer(dir)
+	require.NoError(t, err)
+
+	replayClient, err := replayer.Provider("snowflake", "raito-io/cli-plugin-snowflake", "", hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	replaySyncer, err := replayClient.GetDataSourceSyncer()
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		info, versionErr := replaySyncer.CliVersionInformation(ctx)
+		require.NoError(t, versionErr)
+		assert.Equal(t, uint64(2), info.CliBuildVersion.Minor)
+	}
+
+	replayFile := filepath.Join(t.TempDir(), "replayed.json")
+	result, err = replaySyncer.SyncDataSource(ctx, &data_source.DataSourceSyncConfig{TargetFile: replayFile})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), result.DataObjects)
+
+	replayed, err := os.ReadFile(replayFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(recordedFile), string(replayed))
+
+	_, err = replaySyncer.SyncDataSource(ctx, &data_source.DataSourceSyncConfig{TargetFile: replayFile})
+	assert.EqualError(t, err, "unable to connect with password **censured**")
+
+	_, err = replaySyncer.SyncDataSource(ctx, &data_source.DataSourceSyncConfig{TargetFile: replayFile})
+	assert.ErrorContains(t, err, "no more recorded calls of DataSourceSyncer.SyncDataSource")
+
+	_, err = replaySyncer.GetDataSourceMetaData(ctx, configMap)
+	assert.ErrorContains(t, err, "no more recorded calls of DataSourceSyncer.GetDataSourceMetaData")
+}
+
+func TestRecordAndReplay_DataUsageFiles(t *testing.T) {
+	recorder, dir := newTestRecorder(t)
+	ctx := context.Background()
+
+	client, err := recorder.Provider("bigquery/enricher-0", "raito-io/cli-plugin-bigquery", "", hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	syncer, err := client.GetDataUsageSyncer()
+	require.NoError(t, err)
+
+	_, err = syncer.SyncDataUsage(ctx, &data_usage.DataUsageSyncConfig{TargetFile: filepath.Join(t.TempDir(), "usage.json")})
+	require.NoError(t, err)
+
+	replayer, err := NewReplayer(dir)
+	require.NoError(t, err)
+
+	replayClient, err := replayer.Provider("bigquery/enricher-0", "raito-io/cli-plugin-bigquery", "", hclog.NewNullLogger())
+	require.NoError(t, err)
+
+	replaySyncer, err := replayClient.GetDataUsageSyncer()
+	require.NoError(t, err)
+
+	replayDir := t.TempDir()
+
+	result, err := replaySyncer.SyncDataUsage(ctx, &data_usage.DataUsageSyncConfig{TargetFile: filepath.Join(replayDir, "usage.json")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(replayDir, "0-usage-a.json"), filepath.Join(replayDir, "1-usage-b.json")}, result.TargetFiles)
+
+	for i, name := range []string{"usage-a.json", "usage-b.json"} {
+		content, readErr := os.ReadFile(result.TargetFiles[i])
+		require.NoError(t, readErr)
+		assert.Equal(t, name, string(content))
+	}
+
+	_, err = replayer.Provider("unknown", "raito-io/cli-plugin-bigquery", "", hclog.NewNullLogger())
+	assert.EqualError(t, err, `no recorded plugin calls found for "unknown"`)
+}
+
+func TestClientDir(t *testing.T) {
+	assert.Equal(t, filepath.Join("rec", "my_target", "enricher-0"), clientDir("rec", "my target/enricher-0"))
+	assert.Equal(t, filepath.Join("rec", "_", "_", "etc"), clientDir("rec", "../../etc"))
+}
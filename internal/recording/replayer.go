@@ -0,0 +1,409 @@
+package recording
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/raito-io/cli/base/access_provider"
+	"github.com/raito-io/cli/base/data_object_enricher"
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/base/data_usage"
+	"github.com/raito-io/cli/base/identity_store"
+	"github.com/raito-io/cli/base/resource_provider"
+	"github.com/raito-io/cli/base/tag"
+	"github.com/raito-io/cli/base/util/config"
+	plugin2 "github.com/raito-io/cli/base/util/plugin"
+	"github.com/raito-io/cli/base/util/version"
+	"github.com/raito-io/cli/internal/plugin"
+)
+
+// idempotentMethods can be replayed more often than they were recorded. Once all recorded calls are used, the last one is returned again.
+var idempotentMethods = map[string]struct{}{
+	methodDataSourceVersion:       {},
+	methodDataSourceMetaData:      {},
+	methodEnricherVersion:         {},
+	methodIdentityStoreVersion:    {},
+	methodIdentityStoreMetaData:   {},
+	methodAccessVersion:           {},
+	methodSyncConfig:              {},
+	methodDataUsageVersion:        {},
+	methodResourceProviderVersion: {},
+	methodTagVersion:              {},
+	methodGetInfo:                 {},
+}
+
+// Replayer replays recorded interactions instead of starting the connector plugins.
+// The recorded responses are returned and the recorded output files are restored at the locations requested by the CLI, so they are handled as if the connector produced them.
+type Replayer struct {
+	dir string
+
+	m       sync.Mutex
+	clients map[string]*clientReplay
+}
+
+// NewReplayer creates a replayer for the recording in the given directory.
+func NewReplayer(dir string) (*Replayer, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("opening recording %q: %w", dir, err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("recording %q is not a directory", dir)
+	}
+
+	return &Replayer{dir: dir, clients: map[string]*clientReplay{}}, nil
+}
+
+// Provider returns a plugin client that replays the recorded calls of the client with the given key. It can be passed to plugin.SetTargetClientProvider.
+func (r *Replayer) Provider(key string, _ string, _ string, logger hclog.Logger) (plugin.PluginClient, error) {
+	c, err := r.replay(key)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("Replaying recorded plugin calls of %q", key))
+
+	return &replayClient{replay: c}, nil
+}
+
+// replay returns the recorded calls of the client with the given key. Clients that are created multiple times with the same key continue with the next recorded calls.
+func (r *Replayer) replay(key string) (*clientReplay, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if c, found := r.clients[key]; found {
+		return c, nil
+	}
+
+	c, err := loadClientReplay(key, clientDir(r.dir, key))
+	if err != nil {
+		return nil, err
+	}
+
+	r.clients[key] = c
+
+	return c, nil
+}
+
+// clientReplay holds the recorded calls of a single plugin client, grouped by method.
+type clientReplay struct {
+	key string
+	dir string
+
+	m         sync.Mutex
+	calls     map[string][]*recordedCall
+	positions map[string]int
+}
+
+func loadClientReplay(key string, dir string) (*clientReplay, error) {
+	f, err := os.Open(filepath.Join(dir, callsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no recorded plugin calls found for %q", key)
+	} else if err != nil {
+		return nil, fmt.Errorf("opening recorded plugin calls of %q: %w", key, err)
+	}
+
+	defer f.Close()
+
+	c := &clientReplay{key: key, dir: dir, calls: map[string][]*recordedCall{}, positions: map[string]int{}}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		call := &recordedCall{}
+
+		err = json.Unmarshal(scanner.Bytes(), call)
+		if err != nil {
+			return nil, fmt.Errorf("parsing recorded plugin calls of %q: %w", key, err)
+		}
+
+		c.calls[call.Method] = append(c.calls[call.Method], call)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading recorded plugin calls of %q: %w", key, err)
+	}
+
+	return c, nil
+}
+
+// next returns the next recorded call of the given method.
+func (c *clientReplay) next(method string) (*recordedCall, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	calls := c.calls[method]
+	position := c.positions[method]
+
+	if position < len(calls) {
+		c.positions[method]++
+
+		return calls[position], nil
+	}
+
+	if _, idempotent := idempotentMethods[method]; idempotent && len(calls) > 0 {
+		return calls[len(calls)-1], nil
+	}
+
+	return nil, fmt.Errorf("no more recorded calls of %s for %q (%d recorded)", method, c.key, len(calls))
+}
+
+// restoreFile copies a recorded file to the location the CLI expects it. Files that were not recorded are ignored.
+func (c *clientReplay) restoreFile(call *recordedCall, role string, path string) error {
+	name, found := call.Files[role]
+	if !found || path == "" {
+		return nil
+	}
+
+	err := copyFile(filepath.Join(c.dir, filesDir, name), path, newSecretReplacer(nil))
+	if err != nil {
+		return fmt.Errorf("restoring recorded %s of %s: %w", role, call.Method, err)
+	}
+
+	return nil
+}
+
+// restoreIndexedFiles restores a list of files returned by a connector. The files are restored next to the requested target file,
+// as their original location doesn't exist anymore. The paths are updated in place.
+func (c *clientReplay) restoreIndexedFiles(call *recordedCall, role string, paths []string, targetFile string) error {
+	for i, f := range paths {
+		paths[i] = filepath.Join(filepath.Dir(targetFile), fmt.Sprintf("%d-%s", i, filepath.Base(f)))
+
+		err := c.restoreFile(call, indexedRole(role, i), paths[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replay returns the response of the next recorded call of the method, after restoring its output files.
+func replay[T proto.Message](c *clientReplay, method string, response T, restore func(call *recordedCall, response T) error) (T, error) {
+	var empty T
+
+	call, err := c.next(method)
+	if err != nil {
+		return empty, err
+	}
+
+	if call.Error != "" {
+		return empty, errors.New(call.Error)
+	}
+
+	if len(call.Response) > 0 {
+		err = protojson.Unmarshal(call.Response, response)
+		if err != nil {
+			return empty, fmt.Errorf("parsing recorded response of %s: %w", method, err)
+		}
+	}
+
+	if restore != nil {
+		err = restore(call, response)
+		if err != nil {
+			return empty, err
+		}
+	}
+
+	return response, nil
+}
+
+// replayClient implements all syncers by replaying the recorded calls.
+type replayClient struct {
+	replay *clientReplay
+}
+
+func (c *replayClient) Close() {}
+
+func (c *replayClient) GetDataSourceSyncer() (data_source.DataSourceSyncer, error) {
+	return &replayDataSourceSyncer{replay: c.replay}, nil
+}
+
+func (c *replayClient) GetDataObjectEnricher() (data_object_enricher.DataObjectEnricher, error) {
+	return &replayEnricher{replay: c.replay}, nil
+}
+
+func (c *replayClient) GetIdentityStoreSyncer() (identity_store.IdentityStoreSyncer, error) {
+	return &replayIdentityStoreSyncer{replay: c.replay}, nil
+}
+
+func (c *replayClient) GetAccessSyncer() (access_provider.AccessSyncer, error) {
+	return &replayAccessSyncer{replay: c.replay}, nil
+}
+
+func (c *replayClient) GetDataUsageSyncer() (data_usage.DataUsageSyncer, error) {
+	return &replayDataUsageSyncer{replay: c.replay}, nil
+}
+
+func (c *replayClient) GetResourceProvider() (resource_provider.ResourceProviderSyncer, error) {
+	return &replayResourceProvider{replay: c.replay}, nil
+}
+
+func (c *replayClient) GetTagSyncer() (tag.TagSyncer, error) {
+	return &replayTagSyncer{replay: c.replay}, nil
+}
+
+func (c *replayClient) GetInfo() (plugin2.Info, error) {
+	return &replayInfo{replay: c.replay}, nil
+}
+
+type replayDataSourceSyncer struct {
+	replay *clientReplay
+}
+
+func (s *replayDataSourceSyncer) CliVersionInformation(_ context.Context) (*version.CliBuildInformation, error) {
+	return replay(s.replay, methodDataSourceVersion, &version.CliBuildInformation{}, nil)
+}
+
+func (s *replayDataSourceSyncer) SyncDataSource(_ context.Context, syncConfig *data_source.DataSourceSyncConfig) (*data_source.DataSourceSyncResult, error) {
+	return replay(s.replay, methodSyncDataSource, &data_source.DataSourceSyncResult{}, func(call *recordedCall, result *data_source.DataSourceSyncResult) error {
+		err := s.replay.restoreFile(call, roleTargetFile, syncConfig.TargetFile)
+		if err != nil {
+			return err
+		}
+
+		err = s.replay.restoreFile(call, roleDeletedObjectsFile, syncConfig.DeletedObjectsFile)
+		if err != nil {
+			return err
+		}
+
+		return s.replay.restoreIndexedFiles(call, rolePartFiles, result.PartFiles, syncConfig.TargetFile)
+	})
+}
+
+func (s *replayDataSourceSyncer) GetDataSourceMetaData(_ context.Context, _ *config.ConfigMap) (*data_source.MetaData, error) {
+	return replay(s.replay, methodDataSourceMetaData, &data_source.MetaData{}, nil)
+}
+
+type replayEnricher struct {
+	replay *clientReplay
+}
+
+func (s *replayEnricher) CliVersionInformation(_ context.Context) (*version.CliBuildInformation, error) {
+	return replay(s.replay, methodEnricherVersion, &version.CliBuildInformation{}, nil)
+}
+
+func (s *replayEnricher) Enrich(_ context.Context, enricherConfig *data_object_enricher.DataObjectEnricherConfig) (*data_object_enricher.DataObjectEnricherResult, error) {
+	return replay(s.replay, methodEnrich, &data_object_enricher.DataObjectEnricherResult{}, func(call *recordedCall, _ *data_object_enricher.DataObjectEnricherResult) error {
+		return s.replay.restoreFile(call, roleOutputFile, enricherConfig.OutputFile)
+	})
+}
+
+type replayIdentityStoreSyncer struct {
+	replay *clientReplay
+}
+
+func (s *replayIdentityStoreSyncer) CliVersionInformation(_ context.Context) (*version.CliBuildInformation, error) {
+	return replay(s.replay, methodIdentityStoreVersion, &version.CliBuildInformation{}, nil)
+}
+
+func (s *replayIdentityStoreSyncer) SyncIdentityStore(_ context.Context, syncConfig *identity_store.IdentityStoreSyncConfig) (*identity_store.IdentityStoreSyncResult, error) {
+	return replay(s.replay, methodSyncIdentityStore, &identity_store.IdentityStoreSyncResult{}, func(call *recordedCall, _ *identity_store.IdentityStoreSyncResult) error {
+		err := s.replay.restoreFile(call, roleUserFile, syncConfig.UserFile)
+		if err != nil {
+			return err
+		}
+
+		return s.replay.restoreFile(call, roleGroupFile, syncConfig.GroupFile)
+	})
+}
+
+func (s *replayIdentityStoreSyncer) GetIdentityStoreMetaData(_ context.Context, _ *config.ConfigMap) (*identity_store.MetaData, error) {
+	return replay(s.replay, methodIdentityStoreMetaData, &identity_store.MetaData{}, nil)
+}
+
+type replayAccessSyncer struct {
+	replay *clientReplay
+}
+
+func (s *replayAccessSyncer) CliVersionInformation(_ context.Context) (*version.CliBuildInformation, error) {
+	return replay(s.replay, methodAccessVersion, &version.CliBuildInformation{}, nil)
+}
+
+func (s *replayAccessSyncer) SyncFromTarget(_ context.Context, syncConfig *access_provider.AccessSyncFromTarget) (*access_provider.AccessSyncResult, error) {
+	return replay(s.replay, methodSyncFromTarget, &access_provider.AccessSyncResult{}, func(call *recordedCall, _ *access_provider.AccessSyncResult) error {
+		return s.replay.restoreFile(call, roleTargetFile, syncConfig.TargetFile)
+	})
+}
+
+func (s *replayAccessSyncer) SyncToTarget(_ context.Context, syncConfig *access_provider.AccessSyncToTarget) (*access_provider.AccessSyncResult, error) {
+	return replay(s.replay, methodSyncToTarget, &access_provider.AccessSyncResult{}, func(call *recordedCall, _ *access_provider.AccessSyncResult) error {
+		return s.replay.restoreFile(call, roleFeedbackTargetFile, syncConfig.FeedbackTargetFile)
+	})
+}
+
+func (s *replayAccessSyncer) SyncConfig(_ context.Context) (*access_provider.AccessSyncConfig, error) {
+	return replay(s.replay, methodSyncConfig, &access_provider.AccessSyncConfig{}, nil)
+}
+
+type replayDataUsageSyncer struct {
+	replay *clientReplay
+}
+
+func (s *replayDataUsageSyncer) CliVersionInformation(_ context.Context) (*version.CliBuildInformation, error) {
+	return replay(s.replay, methodDataUsageVersion, &version.CliBuildInformation{}, nil)
+}
+
+func (s *replayDataUsageSyncer) SyncDataUsage(_ context.Context, syncConfig *data_usage.DataUsageSyncConfig) (*data_usage.DataUsageSyncResult, error) {
+	return replay(s.replay, methodSyncDataUsage, &data_usage.DataUsageSyncResult{}, func(call *recordedCall, result *data_usage.DataUsageSyncResult) error {
+		if len(result.PartFiles) > 0 {
+			return s.replay.restoreIndexedFiles(call, rolePartFiles, result.PartFiles, syncConfig.TargetFile)
+		}
+
+		if len(result.TargetFiles) == 0 {
+			return s.replay.restoreFile(call, roleTargetFile, syncConfig.TargetFile)
+		}
+
+		return s.replay.restoreIndexedFiles(call, roleTargetFiles, result.TargetFiles, syncConfig.TargetFile)
+	})
+}
+
+type replayResourceProvider struct {
+	replay *clientReplay
+}
+
+func (s *replayResourceProvider) CliVersionInformation(_ context.Context) (*version.CliBuildInformation, error) {
+	return replay(s.replay, methodResourceProviderVersion, &version.CliBuildInformation{}, nil)
+}
+
+func (s *replayResourceProvider) UpdateResources(_ context.Context, _ *resource_provider.UpdateResourceInput) (*resource_provider.UpdateResourceResult, error) {
+	return replay(s.replay, methodUpdateResources, &resource_provider.UpdateResourceResult{}, nil)
+}
+
+type replayTagSyncer struct {
+	replay *clientReplay
+}
+
+func (s *replayTagSyncer) CliVersionInformation(_ context.Context) (*version.CliBuildInformation, error) {
+	return replay(s.replay, methodTagVersion, &version.CliBuildInformation{}, nil)
+}
+
+func (s *replayTagSyncer) SyncTags(_ context.Context, syncConfig *tag.TagSyncConfig) (*tag.TagSyncResult, error) {
+	return replay(s.replay, methodSyncTags, &tag.TagSyncResult{}, func(call *recordedCall, _ *tag.TagSyncResult) error {
+		return s.replay.restoreFile(call, roleTargetFile, syncConfig.TargetFile)
+	})
+}
+
+type replayInfo struct {
+	replay *clientReplay
+}
+
+func (s *replayInfo) GetInfo(_ context.Context) (*plugin2.PluginInfo, error) {
+	return replay(s.replay, methodGetInfo, &plugin2.PluginInfo{}, nil)
+}
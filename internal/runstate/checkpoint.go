@@ -0,0 +1,64 @@
+package runstate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/raito-io/cli/internal/target/types"
+	"github.com/raito-io/cli/internal/util/file"
+)
+
+const (
+	checkpointsDir       = "checkpoints"
+	checkpointInputsFile = "inputs"
+)
+
+// PrepareCheckpointDir returns the directory in which the connector stores the checkpoints of the given sync type of a target.
+// The checkpoints of a previous attempt are kept when resuming, as long as the configuration of the target didn't change. Otherwise, the directory is emptied.
+func (s *Store) PrepareCheckpointDir(cfg *types.BaseTargetConfig, syncType string, resume bool) (string, error) {
+	dir := s.checkpointDir(cfg.Name, syncType)
+	inputsPath := filepath.Join(dir, checkpointInputsFile)
+	inputs := Fingerprint(cfg, syncType)
+
+	if resume {
+		previousInputs, err := os.ReadFile(inputsPath)
+		if err == nil && string(previousInputs) == inputs {
+			return dir, nil
+		} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("read checkpoint inputs of target %q: %w", cfg.Name, err)
+		}
+	}
+
+	err := os.RemoveAll(dir)
+	if err != nil {
+		return "", fmt.Errorf("clear checkpoints of target %q: %w", cfg.Name, err)
+	}
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return "", fmt.Errorf("create checkpoint directory of target %q: %w", cfg.Name, err)
+	}
+
+	err = os.WriteFile(inputsPath, []byte(inputs), 0600)
+	if err != nil {
+		return "", fmt.Errorf("write checkpoint inputs of target %q: %w", cfg.Name, err)
+	}
+
+	return dir, nil
+}
+
+// ClearCheckpointDir removes the checkpoints of the given sync type of a target, once the sync finished successfully.
+func (s *Store) ClearCheckpointDir(target string, syncType string) error {
+	err := os.RemoveAll(s.checkpointDir(target, syncType))
+	if err != nil {
+		return fmt.Errorf("clear checkpoints of target %q: %w", target, err)
+	}
+
+	return nil
+}
+
+func (s *Store) checkpointDir(target string, syncType string) string {
+	return filepath.Join(s.dir, checkpointsDir, file.GetFileNameFromName(target), syncType)
+}
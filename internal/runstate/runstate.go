@@ -0,0 +1,173 @@
+package runstate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/target/types"
+	"github.com/raito-io/cli/internal/util/file"
+)
+
+const runsDir = "runs"
+
+// Step is a sync step (sync type) of a target that finished successfully.
+type Step struct {
+	FinishedAt time.Time `json:"finishedAt"`
+
+	// Inputs is the fingerprint of the configuration the step was executed with.
+	Inputs string `json:"inputs"`
+}
+
+// TargetState is the state of the last run of a target.
+type TargetState struct {
+	Target    string          `json:"target"`
+	RunType   string          `json:"runType"`
+	JobId     string          `json:"jobId"`
+	StartedAt time.Time       `json:"startedAt"`
+	Completed bool            `json:"completed"`
+	Steps     map[string]Step `json:"steps"`
+}
+
+// ResumableSteps returns the steps of a run that didn't complete, of which the inputs are the same as in the given target configuration.
+func (s *TargetState) ResumableSteps(cfg *types.BaseTargetConfig) map[string]Step {
+	result := make(map[string]Step)
+
+	if s == nil || s.Completed {
+		return result
+	}
+
+	for syncType, step := range s.Steps {
+		if step.Inputs == Fingerprint(cfg, syncType) {
+			result[syncType] = step
+		}
+	}
+
+	return result
+}
+
+// Store keeps the state of the last run of every target in a file per target and run type.
+// Only the full runs store their state for now, as the partial runs (webhooks and manual triggers) are never resumed.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store in the given directory. Returns nil if no directory is given, which disables storing the run state.
+func NewStore(dir string) *Store {
+	if dir == "" {
+		return nil
+	}
+
+	return &Store{dir: dir}
+}
+
+// DefaultDir returns the default directory to store the run state in.
+func DefaultDir() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "raito-run-state")
+	}
+
+	return filepath.Join(homeDir, ".raito", "run-state")
+}
+
+// Load returns the state of the last run of the given type of the given target, or nil if there is none.
+func (s *Store) Load(runType string, target string) (*TargetState, error) {
+	data, err := os.ReadFile(s.path(runType, target))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read run state of target %q: %w", target, err)
+	}
+
+	state := TargetState{}
+
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return nil, fmt.Errorf("parse run state of target %q: %w", target, err)
+	}
+
+	return &state, nil
+}
+
+// Save stores the state of the run of a target. The file is replaced atomically, so a crash never leaves a corrupt state behind.
+func (s *Store) Save(state *TargetState) error {
+	err := writeJSONFile(s.path(state.RunType, state.Target), state)
+	if err != nil {
+		return fmt.Errorf("write run state of target %q: %w", state.Target, err)
+	}
+
+	return nil
+}
+
+func (s *Store) path(runType string, target string) string {
+	return filepath.Join(s.dir, runsDir, file.GetFileNameFromName(runType), file.GetFileNameFromName(target)+".json")
+}
+
+// writeJSONFile serializes the value to the given file. The file is replaced atomically.
+func writeJSONFile(path string, value interface{}) error {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("serialize: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+
+	err = os.WriteFile(tmpPath, data, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// stepInputs contains the configuration that determines the outcome of a step.
+type stepInputs struct {
+	SyncType           string
+	ConnectorName      string
+	ConnectorVersion   string
+	Id                 string
+	Parameters         map[string]string
+	DataObjectParent   *string
+	DataObjectExcludes []string
+	Enrichers          []*types.EnricherConfig
+}
+
+// Fingerprint returns a hash of the configuration that determines the outcome of the given sync type of a target.
+func Fingerprint(cfg *types.BaseTargetConfig, syncType string) string {
+	inputs := stepInputs{
+		SyncType:         syncType,
+		ConnectorName:    cfg.ConnectorName,
+		ConnectorVersion: cfg.ConnectorVersion,
+		Id:               cfg.DataSourceId,
+		Parameters:       cfg.Parameters,
+	}
+
+	switch syncType {
+	case constants.IdentitySync:
+		inputs.Id = cfg.IdentityStoreId
+	case constants.DataSourceSync:
+		inputs.DataObjectParent = cfg.DataObjectParent
+		inputs.DataObjectExcludes = cfg.DataObjectExcludes
+		inputs.Enrichers = cfg.DataObjectEnrichers
+	}
+
+	// Marshalling can't fail for these types. Maps are marshalled with sorted keys, so the result is stable.
+	data, _ := json.Marshal(inputs)
+	hash := sha256.Sum256(data)
+
+	return hex.EncodeToString(hash[:])
+}
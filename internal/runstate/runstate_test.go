@@ -0,0 +1,171 @@
+package runstate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+func testTargetConfig() *types.BaseTargetConfig {
+	cfg := &types.BaseTargetConfig{
+		Name:            "snowflake1",
+		ConnectorName:   "raito-io/cli-plugin-snowflake",
+		DataSourceId:    "SnowflakeDataSource",
+		IdentityStoreId: "SnowflakeIdentityStore",
+	}
+
+	cfg.Parameters = map[string]string{"sf-account": "somewhere.eu-central-1"}
+
+	return cfg
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "run-state"))
+
+	state, err := store.Load("full", "snowflake1")
+	require.NoError(t, err)
+	assert.Nil(t, state)
+
+	expected := &TargetState{
+		Target:    "snowflake1",
+		RunType:   "full",
+		JobId:     "job1",
+		StartedAt: time.Date(2024, 10, 1, 8, 0, 0, 0, time.UTC),
+		Steps: map[string]Step{
+			constants.DataSourceSync: {FinishedAt: time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC), Inputs: "abc"},
+		},
+	}
+
+	require.NoError(t, store.Save(expected))
+
+	state, err = store.Load("full", "snowflake1")
+	require.NoError(t, err)
+	assert.Equal(t, expected, state)
+
+	// A completed run of another type doesn't replace the state of the failed full run
+	require.NoError(t, store.Save(&TargetState{Target: "snowflake1", RunType: "manual", JobId: "job2", Completed: true}))
+
+	state, err = store.Load("full", "snowflake1")
+	require.NoError(t, err)
+	assert.Equal(t, expected, state)
+
+	assert.Nil(t, NewStore(""))
+}
+
+func TestStore_CorruptState(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, runsDir, "full"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, runsDir, "full", "snowflake1.json"), []byte("{"), 0600))
+
+	_, err := store.Load("full", "snowflake1")
+	assert.ErrorContains(t, err, `parse run state of target "snowflake1"`)
+}
+
+func TestTargetState_ResumableSteps(t *testing.T) {
+	cfg := testTargetConfig()
+
+	state := &TargetState{
+		Target: "snowflake1",
+		Steps: map[string]Step{
+			constants.DataSourceSync: {Inputs: Fingerprint(cfg, constants.DataSourceSync)},
+			constants.IdentitySync:   {Inputs: Fingerprint(cfg, constants.IdentitySync)},
+		},
+	}
+
+	assert.Len(t, state.ResumableSteps(cfg), 2)
+
+	// Changing the identity store doesn't influence the data source step
+	changedCfg := testTargetConfig()
+	changedCfg.IdentityStoreId = "OtherIdentityStore"
+
+	resumable := state.ResumableSteps(changedCfg)
+	assert.Len(t, resumable, 1)
+	assert.Contains(t, resumable, constants.DataSourceSync)
+
+	// Changing a parameter influences all steps
+	changedCfg = testTargetConfig()
+	changedCfg.Parameters["sf-account"] = "elsewhere"
+
+	assert.Empty(t, state.ResumableSteps(changedCfg))
+
+	// A completed run is never resumed
+	state.Completed = true
+	assert.Empty(t, state.ResumableSteps(cfg))
+
+	var noState *TargetState
+	assert.Empty(t, noState.ResumableSteps(cfg))
+}
+
+func TestStore_Watermark(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "run-state"))
+
+	watermark, err := store.LoadWatermark("SnowflakeDataSource")
+	require.NoError(t, err)
+	assert.Nil(t, watermark)
+
+	expected := &Watermark{
+		DataSourceId: "SnowflakeDataSource",
+		Cursor:       "2024-10-01T08:00:00Z",
+		LastSync:     time.Date(2024, 10, 1, 8, 0, 0, 0, time.UTC),
+		LastFullSync: time.Date(2024, 9, 30, 8, 0, 0, 0, time.UTC),
+		Inputs:       "abc",
+	}
+
+	require.NoError(t, store.SaveWatermark(expected))
+
+	watermark, err = store.LoadWatermark("SnowflakeDataSource")
+	require.NoError(t, err)
+	assert.Equal(t, expected, watermark)
+
+	// Watermarks don't interfere with the run state of a target with the same name
+	state, err := store.Load("full", "SnowflakeDataSource")
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
+
+func TestStore_CheckpointDir(t *testing.T) {
+	store := NewStore(t.TempDir())
+	cfg := testTargetConfig()
+
+	dir, err := store.PrepareCheckpointDir(cfg, constants.DataUsageSync, false)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "checkpoint.json"), []byte("{}"), 0600))
+
+	// Checkpoints are kept when resuming with the same configuration
+	resumed, err := store.PrepareCheckpointDir(cfg, constants.DataUsageSync, true)
+	require.NoError(t, err)
+	assert.Equal(t, dir, resumed)
+	assert.FileExists(t, filepath.Join(dir, "checkpoint.json"))
+
+	// Other sync types have their own directory
+	other, err := store.PrepareCheckpointDir(cfg, constants.DataSourceSync, true)
+	require.NoError(t, err)
+	assert.NotEqual(t, dir, other)
+	assert.FileExists(t, filepath.Join(dir, "checkpoint.json"))
+
+	// Checkpoints are removed when the configuration changed
+	cfg.Parameters["sf-account"] = "elsewhere"
+
+	_, err = store.PrepareCheckpointDir(cfg, constants.DataUsageSync, true)
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "checkpoint.json"))
+
+	// Checkpoints are removed when not resuming
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "checkpoint.json"), []byte("{}"), 0600))
+
+	_, err = store.PrepareCheckpointDir(cfg, constants.DataUsageSync, false)
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "checkpoint.json"))
+
+	require.NoError(t, store.ClearCheckpointDir(cfg.Name, constants.DataUsageSync))
+	assert.NoDirExists(t, dir)
+}
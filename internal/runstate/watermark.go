@@ -0,0 +1,67 @@
+package runstate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/raito-io/cli/internal/util/file"
+)
+
+const watermarksDir = "watermarks"
+
+// Watermark is the position of the last successful sync of a data source, from which the next incremental sync can continue.
+type Watermark struct {
+	DataSourceId string `json:"dataSourceId"`
+
+	// Cursor is the opaque cursor returned by the connector at the end of the sync.
+	Cursor string `json:"cursor"`
+
+	// LastSync is the start time of the last successful (full or incremental) sync.
+	LastSync time.Time `json:"lastSync"`
+
+	// LastFullSync is the start time of the last successful full sync.
+	LastFullSync time.Time `json:"lastFullSync"`
+
+	// Inputs is the fingerprint of the configuration the data source was synced with.
+	// The watermark is only valid as long as this configuration doesn't change.
+	Inputs string `json:"inputs"`
+}
+
+// LoadWatermark returns the watermark of the last successful sync of the given data source, or nil if there is none.
+func (s *Store) LoadWatermark(dataSourceId string) (*Watermark, error) {
+	data, err := os.ReadFile(s.watermarkPath(dataSourceId))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read watermark of data source %q: %w", dataSourceId, err)
+	}
+
+	watermark := Watermark{}
+
+	err = json.Unmarshal(data, &watermark)
+	if err != nil {
+		return nil, fmt.Errorf("parse watermark of data source %q: %w", dataSourceId, err)
+	}
+
+	return &watermark, nil
+}
+
+// SaveWatermark stores the watermark of a successful sync of a data source.
+func (s *Store) SaveWatermark(watermark *Watermark) error {
+	err := writeJSONFile(s.watermarkPath(watermark.DataSourceId), watermark)
+	if err != nil {
+		return fmt.Errorf("write watermark of data source %q: %w", watermark.DataSourceId, err)
+	}
+
+	return nil
+}
+
+func (s *Store) watermarkPath(dataSourceId string) string {
+	return filepath.Join(s.dir, watermarksDir, file.GetFileNameFromName(dataSourceId)+".json")
+}
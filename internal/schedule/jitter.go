@@ -0,0 +1,120 @@
+package schedule
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// JitterRandom delays every execution by a new random offset within the window.
+	JitterRandom = "random"
+
+	// JitterHostname delays every execution by the same offset within the window, derived from the hostname.
+	// This spreads instances with different hostnames, while the execution time of a single instance stays predictable.
+	JitterHostname = "hostname"
+
+	// JitterTarget delays every execution by the same offset within the window, derived from the names of the targets of the instance.
+	// This spreads instances syncing different targets, even when they run on the same host (e.g. in containers with generated hostnames).
+	JitterTarget = "target"
+
+	// JitterKey delays every execution by the same offset within the window, derived from a key that is configured per instance.
+	JitterKey = "key"
+)
+
+// Jitter delays scheduled executions by an offset within a window, so many instances sharing the same schedule don't all start at the same moment.
+type Jitter struct {
+	window time.Duration
+
+	// offset is the fixed offset in deterministic mode. Nil in random mode.
+	offset *time.Duration
+
+	// last is the scheduled time (without jitter) of the last execution, so a random offset never causes the same execution twice.
+	last time.Time
+}
+
+// NewJitter creates a jitter for the given window and mode. Returns nil if the window is not positive, which disables the jitter.
+// The key is only used by the JitterTarget and JitterKey modes: the names of the targets of the instance or the key configured for the instance.
+func NewJitter(window time.Duration, mode string, key string) (*Jitter, error) {
+	if window <= 0 {
+		return nil, nil
+	}
+
+	switch mode {
+	case "", JitterRandom:
+		return &Jitter{window: window}, nil
+	case JitterHostname:
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine hostname for the schedule jitter: %w", err)
+		}
+
+		return newDeterministicJitter(window, hostname), nil
+	case JitterTarget, JitterKey:
+		if key == "" {
+			return nil, fmt.Errorf("no key to derive the offset of the schedule jitter from in jitter mode %q", mode)
+		}
+
+		return newDeterministicJitter(window, key), nil
+	default:
+		return nil, fmt.Errorf("unknown jitter mode %q. Possible values are %s, %s, %s and %s", mode, JitterRandom, JitterHostname, JitterTarget, JitterKey)
+	}
+}
+
+func newDeterministicJitter(window time.Duration, key string) *Jitter {
+	offset := DeterministicOffset(key, window)
+
+	return &Jitter{window: window, offset: &offset}
+}
+
+// Next returns the first execution of the schedule after the given time, delayed by the jitter.
+// As the jitter delays the executions, an execution of which the scheduled time already passed can still be ahead.
+func (j *Jitter) Next(scheduler cron.Schedule, t time.Time) time.Time {
+	if j == nil {
+		return scheduler.Next(t)
+	}
+
+	offset := j.nextOffset()
+
+	next := scheduler.Next(t.Add(-j.window))
+	for !next.After(j.last) || !next.Add(offset).After(t) {
+		next = scheduler.Next(next)
+	}
+
+	j.last = next
+
+	return next.Add(offset)
+}
+
+func (j *Jitter) nextOffset() time.Duration {
+	if j.offset != nil {
+		return *j.offset
+	}
+
+	return time.Duration(rand.Int63n(int64(j.window))) //nolint:gosec
+}
+
+// DeterministicOffset returns an offset within the window that is always the same for the given key.
+func DeterministicOffset(key string, window time.Duration) time.Duration {
+	if window <= 0 {
+		return 0
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(key))
+
+	return time.Duration(hash.Sum64() % uint64(window))
+}
+
+// StaggerOffset returns the offset of the item with the given index when spreading the given number of items evenly across the window.
+func StaggerOffset(index int, count int, window time.Duration) time.Duration {
+	if count <= 1 || window <= 0 {
+		return 0
+	}
+
+	return window / time.Duration(count) * time.Duration(index)
+}
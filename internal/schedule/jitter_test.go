@@ -0,0 +1,98 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dailyAt2(t *testing.T) cron.Schedule {
+	t.Helper()
+
+	scheduler, err := cron.ParseStandard("0 2 * * *")
+	require.NoError(t, err)
+
+	return scheduler
+}
+
+func TestNewJitter(t *testing.T) {
+	jitter, err := NewJitter(0, JitterRandom, "")
+	require.NoError(t, err)
+	assert.Nil(t, jitter)
+
+	jitter, err = NewJitter(15*time.Minute, JitterHostname, "")
+	require.NoError(t, err)
+	require.NotNil(t, jitter.offset)
+	assert.Less(t, *jitter.offset, 15*time.Minute)
+
+	// Instances with a different key get a different, but stable offset
+	jitter, err = NewJitter(15*time.Minute, JitterKey, "business-unit-1")
+	require.NoError(t, err)
+	require.NotNil(t, jitter.offset)
+	assert.Equal(t, DeterministicOffset("business-unit-1", 15*time.Minute), *jitter.offset)
+
+	otherJitter, err := NewJitter(15*time.Minute, JitterTarget, "snowflake-bu2")
+	require.NoError(t, err)
+	require.NotNil(t, otherJitter.offset)
+	assert.NotEqual(t, *jitter.offset, *otherJitter.offset)
+
+	_, err = NewJitter(15*time.Minute, JitterKey, "")
+	assert.ErrorContains(t, err, "no key")
+
+	_, err = NewJitter(15*time.Minute, "sometimes", "")
+	assert.ErrorContains(t, err, `unknown jitter mode "sometimes"`)
+}
+
+func TestJitter_Next(t *testing.T) {
+	scheduler := dailyAt2(t)
+	now := time.Date(2024, 10, 1, 1, 0, 0, 0, time.UTC)
+
+	var noJitter *Jitter
+	assert.Equal(t, time.Date(2024, 10, 1, 2, 0, 0, 0, time.UTC), noJitter.Next(scheduler, now))
+
+	for range 100 {
+		jitter, err := NewJitter(15*time.Minute, JitterRandom, "")
+		require.NoError(t, err)
+
+		next := jitter.Next(scheduler, now)
+		assert.False(t, next.Before(time.Date(2024, 10, 1, 2, 0, 0, 0, time.UTC)))
+		assert.True(t, next.Before(time.Date(2024, 10, 1, 2, 15, 0, 0, time.UTC)))
+
+		// The execution of the same day is never scheduled twice, even when the new offset is larger
+		next = jitter.Next(scheduler, next.Add(time.Second))
+		assert.False(t, next.Before(time.Date(2024, 10, 2, 2, 0, 0, 0, time.UTC)))
+		assert.True(t, next.Before(time.Date(2024, 10, 2, 2, 15, 0, 0, time.UTC)))
+	}
+}
+
+func TestJitter_Next_ScheduledTimePassed(t *testing.T) {
+	offset := 10 * time.Minute
+	jitter := &Jitter{window: 15 * time.Minute, offset: &offset}
+
+	// The scheduled time passed, but the delayed execution is still ahead
+	next := jitter.Next(dailyAt2(t), time.Date(2024, 10, 1, 2, 5, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 10, 1, 2, 10, 0, 0, time.UTC), next)
+
+	next = jitter.Next(dailyAt2(t), time.Date(2024, 10, 1, 2, 40, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 10, 2, 2, 10, 0, 0, time.UTC), next)
+}
+
+func TestDeterministicOffset(t *testing.T) {
+	window := 15 * time.Minute
+
+	assert.Equal(t, DeterministicOffset("bu-finance", window), DeterministicOffset("bu-finance", window))
+	assert.NotEqual(t, DeterministicOffset("bu-finance", window), DeterministicOffset("bu-marketing", window))
+	assert.Less(t, DeterministicOffset("bu-finance", window), window)
+	assert.Equal(t, time.Duration(0), DeterministicOffset("bu-finance", 0))
+}
+
+func TestStaggerOffset(t *testing.T) {
+	assert.Equal(t, time.Duration(0), StaggerOffset(0, 3, 30*time.Minute))
+	assert.Equal(t, 10*time.Minute, StaggerOffset(1, 3, 30*time.Minute))
+	assert.Equal(t, 20*time.Minute, StaggerOffset(2, 3, 30*time.Minute))
+	assert.Equal(t, time.Duration(0), StaggerOffset(0, 1, 30*time.Minute))
+	assert.Equal(t, time.Duration(0), StaggerOffset(1, 3, 0))
+}
@@ -0,0 +1,53 @@
+package target
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/spf13/viper"
+
+	iconfig "github.com/raito-io/cli/internal/config"
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+// buildIncrementalSyncPolicy builds the incremental sync policy of a target. Values that are not set for the target are taken from the global configuration.
+func buildIncrementalSyncPolicy(target map[string]interface{}) (types.IncrementalSyncPolicy, error) {
+	policy := types.IncrementalSyncPolicy{
+		Enabled:          viper.GetBool(constants.IncrementalDataSourceSyncFlag),
+		FullSyncInterval: viper.GetDuration(constants.FullDataSourceSyncIntervalFlag),
+	}
+
+	if v, found := target[constants.IncrementalDataSourceSyncFlag]; found {
+		enabled, err := iconfig.HandleField(v, reflect.Bool)
+		if err != nil {
+			return policy, err
+		}
+
+		enabledBool, ok := enabled.(bool)
+		if !ok {
+			return policy, fmt.Errorf("%q should be a boolean", constants.IncrementalDataSourceSyncFlag)
+		}
+
+		policy.Enabled = enabledBool
+	}
+
+	if v, found := target[constants.FullDataSourceSyncIntervalFlag]; found {
+		interval, err := iconfig.HandleField(v, reflect.String)
+		if err != nil {
+			return policy, err
+		}
+
+		policy.FullSyncInterval, err = time.ParseDuration(fmt.Sprintf("%v", interval))
+		if err != nil {
+			return policy, fmt.Errorf("invalid %q: %w", constants.FullDataSourceSyncIntervalFlag, err)
+		}
+	}
+
+	if policy.FullSyncInterval < 0 {
+		policy.FullSyncInterval = 0
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,55 @@
+package target
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+func TestBuildIncrementalSyncPolicy(t *testing.T) {
+	clearViper()
+
+	viper.Set(constants.IncrementalDataSourceSyncFlag, true)
+	viper.Set(constants.FullDataSourceSyncIntervalFlag, 24*time.Hour)
+
+	policy, err := buildIncrementalSyncPolicy(nil)
+	require.NoError(t, err)
+	assert.Equal(t, types.IncrementalSyncPolicy{Enabled: true, FullSyncInterval: 24 * time.Hour}, policy)
+
+	policy, err = buildIncrementalSyncPolicy(map[string]interface{}{
+		constants.IncrementalDataSourceSyncFlag:  false,
+		constants.FullDataSourceSyncIntervalFlag: "168h",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, types.IncrementalSyncPolicy{Enabled: false, FullSyncInterval: 168 * time.Hour}, policy)
+
+	policy, err = buildIncrementalSyncPolicy(map[string]interface{}{constants.FullDataSourceSyncIntervalFlag: "-1h"})
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), policy.FullSyncInterval)
+
+	_, err = buildIncrementalSyncPolicy(map[string]interface{}{constants.IncrementalDataSourceSyncFlag: "yes"})
+	assert.ErrorContains(t, err, `"incremental-data-source-sync" should be a boolean`)
+
+	_, err = buildIncrementalSyncPolicy(map[string]interface{}{constants.FullDataSourceSyncIntervalFlag: "daily"})
+	assert.ErrorContains(t, err, `invalid "full-data-source-sync-interval"`)
+
+	clearViper()
+}
+
+func TestIncrementalSyncPolicy_NeedsFullSync(t *testing.T) {
+	now := time.Date(2024, 10, 2, 8, 0, 0, 0, time.UTC)
+	policy := types.IncrementalSyncPolicy{Enabled: true, FullSyncInterval: 24 * time.Hour}
+
+	assert.True(t, policy.NeedsFullSync(time.Time{}, now))
+	assert.True(t, policy.NeedsFullSync(now.Add(-24*time.Hour), now))
+	assert.False(t, policy.NeedsFullSync(now.Add(-23*time.Hour), now))
+
+	policy.FullSyncInterval = 0
+	assert.False(t, policy.NeedsFullSync(now.Add(-1000*time.Hour), now))
+}
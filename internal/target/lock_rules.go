@@ -0,0 +1,164 @@
+package target
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+var knownLocks = map[string]struct{}{
+	"who":         {},
+	"inheritance": {},
+	"what":        {},
+	"name":        {},
+	"delete":      {},
+	"owners":      {},
+	"fully":       {},
+}
+
+// buildLockRules combines the globally defined lock rules with the ones defined for the target.
+func buildLockRules(targetLockRules interface{}) ([]types.LockRule, error) {
+	globalRules, err := parseLockRules(viper.Get(constants.LockRules))
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing the global lock rules: %w", err)
+	}
+
+	targetRules, err := parseLockRules(targetLockRules)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing the lock rules of the target: %w", err)
+	}
+
+	return append(globalRules, targetRules...), nil
+}
+
+func parseLockRules(value interface{}) ([]types.LockRule, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	ruleList, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the lock rules should be defined as a list (%v)", value)
+	}
+
+	rules := make([]types.LockRule, 0, len(ruleList))
+
+	for i, ruleObj := range ruleList {
+		ruleMap, ok := ruleObj.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("the lock rule definition could not be parsed correctly (%v)", ruleObj)
+		}
+
+		rule, err := parseLockRule(ruleMap)
+		if err != nil {
+			name := fmt.Sprintf("%d", i+1)
+			if n, found := ruleMap["name"]; found {
+				name = fmt.Sprintf("%q", n)
+			}
+
+			return nil, fmt.Errorf("lock rule %s: %w", name, err)
+		}
+
+		rules = append(rules, *rule)
+	}
+
+	return rules, nil
+}
+
+func parseLockRule(ruleMap map[string]interface{}) (*types.LockRule, error) {
+	rule := types.LockRule{
+		Name:   stringValue(ruleMap, "name"),
+		Reason: stringValue(ruleMap, "reason"),
+	}
+
+	conditions := []struct {
+		key    string
+		target *[]string
+	}{
+		{key: "names", target: &rule.Names},
+		{key: "types", target: &rule.Types},
+		{key: "tags", target: &rule.Tags},
+		{key: "what-data-objects", target: &rule.WhatDataObjects},
+		{key: "actions", target: &rule.Actions},
+		{key: "owners", target: &rule.Owners},
+	}
+
+	for _, condition := range conditions {
+		patterns, err := stringListValue(ruleMap, condition.key)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pattern := range patterns {
+			if _, err = regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("invalid regular expression in %q: %w", condition.key, err)
+			}
+		}
+
+		*condition.target = patterns
+	}
+
+	if v, found := ruleMap["incomplete-only"]; found {
+		incompleteOnly, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%q should be a boolean", "incomplete-only")
+		}
+
+		rule.IncompleteOnly = incompleteOnly
+	}
+
+	locks, err := stringListValue(ruleMap, "locks")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(locks) == 0 {
+		return nil, fmt.Errorf("at least one lock should be defined in %q", "locks")
+	}
+
+	for _, lock := range locks {
+		if _, found := knownLocks[strings.ToLower(lock)]; !found {
+			return nil, fmt.Errorf("unknown lock %q (expected one of who, inheritance, what, name, delete, owners or fully)", lock)
+		}
+
+		rule.Locks = append(rule.Locks, strings.ToLower(lock))
+	}
+
+	return &rule, nil
+}
+
+func stringValue(m map[string]interface{}, key string) string {
+	if v, found := m[key]; found && v != nil {
+		return strings.TrimSpace(fmt.Sprintf("%v", v))
+	}
+
+	return ""
+}
+
+// stringListValue accepts both a list and a single string value. Unlike the lock flags, single values are not split on commas as a comma is a valid character in a regular expression.
+func stringListValue(m map[string]interface{}, key string) ([]string, error) {
+	v, found := m[key]
+	if !found || v == nil {
+		return nil, nil
+	}
+
+	switch list := v.(type) {
+	case string:
+		return []string{strings.TrimSpace(list)}, nil
+	case []interface{}:
+		result := make([]string, 0, len(list))
+
+		for _, item := range list {
+			result = append(result, strings.TrimSpace(fmt.Sprintf("%v", item)))
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("%q should be a list", key)
+	}
+}
@@ -0,0 +1,80 @@
+package target
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+func TestParseLockRules(t *testing.T) {
+	rules, err := parseLockRules([]interface{}{
+		map[string]interface{}{
+			"name":              "dbt roles",
+			"names":             []interface{}{"DBT_.+"},
+			"types":             "role",
+			"what-data-objects": []interface{}{`ANALYTICS\..+`, "RAW"},
+			"incomplete-only":   true,
+			"locks":             []interface{}{"who", "Delete"},
+			"reason":            "Managed by dbt",
+		},
+		map[string]interface{}{
+			"tags":  "team:(a|b),c",
+			"locks": "fully",
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []types.LockRule{
+		{
+			Name:            "dbt roles",
+			Names:           []string{"DBT_.+"},
+			Types:           []string{"role"},
+			WhatDataObjects: []string{`ANALYTICS\..+`, "RAW"},
+			IncompleteOnly:  true,
+			Locks:           []string{"who", "delete"},
+			Reason:          "Managed by dbt",
+		},
+		{
+			Tags:  []string{"team:(a|b),c"},
+			Locks: []string{"fully"},
+		},
+	}, rules)
+}
+
+func TestParseLockRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		err   string
+	}{
+		{name: "not a list", value: "who", err: "should be defined as a list"},
+		{name: "no locks", value: []interface{}{map[string]interface{}{"name": "x", "names": "a"}}, err: `lock rule "x": at least one lock`},
+		{name: "unknown lock", value: []interface{}{map[string]interface{}{"locks": "everything"}}, err: `lock rule 1: unknown lock "everything"`},
+		{name: "invalid regex", value: []interface{}{map[string]interface{}{"owners": "((", "locks": "who"}}, err: `invalid regular expression in "owners"`},
+		{name: "invalid incomplete-only", value: []interface{}{map[string]interface{}{"incomplete-only": "yes", "locks": "who"}}, err: `"incomplete-only" should be a boolean`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseLockRules(tt.value)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestBuildLockRules(t *testing.T) {
+	viper.Set(constants.LockRules, []interface{}{map[string]interface{}{"name": "global", "locks": "owners"}})
+	defer viper.Set(constants.LockRules, nil)
+
+	rules, err := buildLockRules([]interface{}{map[string]interface{}{"name": "target", "locks": "what"}})
+	require.NoError(t, err)
+
+	require.Len(t, rules, 2)
+	assert.Equal(t, "global", rules[0].Name)
+	assert.Equal(t, "target", rules[1].Name)
+}
@@ -0,0 +1,270 @@
+package target
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/raito-io/cli/base/util/error/grpc_error"
+	iconfig "github.com/raito-io/cli/internal/config"
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/graphql"
+	"github.com/raito-io/cli/internal/target/types"
+	"github.com/raito-io/cli/internal/version_management"
+)
+
+const (
+	defaultRetryBackoff = time.Minute
+	maxRetryBackoff     = time.Hour
+)
+
+// retrySyncTypes maps the sync type names that can be used in the target-retry-on configuration to the internal sync types.
+var retrySyncTypes = map[string]string{
+	"data-source":       constants.DataSourceSync,
+	"identity-store":    constants.IdentitySync,
+	"data-access":       constants.DataAccessSync,
+	"access":            constants.DataAccessSync,
+	"data-usage":        constants.DataUsageSync,
+	"usage":             constants.DataUsageSync,
+	"resource-provider": constants.ResourceProviderSync,
+	"tag":               constants.TagSync,
+}
+
+// SyncTypeError is returned when a specific sync type of a target failed.
+type SyncTypeError struct {
+	SyncType string
+	Err      error
+}
+
+func NewSyncTypeError(syncType string, err error) *SyncTypeError {
+	return &SyncTypeError{SyncType: syncType, Err: err}
+}
+
+func (e *SyncTypeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *SyncTypeError) Unwrap() error {
+	return e.Err
+}
+
+type attemptKey struct{}
+
+// WithAttempt marks the target sync executed with the returned context as the given (zero-based) attempt.
+func WithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// Attempt returns the (zero-based) attempt of the target sync executed with the given context.
+func Attempt(ctx context.Context) int {
+	if attempt, ok := ctx.Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+
+	return 0
+}
+
+// IsRetryableError checks if the error is caused by a (probably) transient problem, like a network issue or an unavailable data source.
+// Permanent errors, like incompatible plugin versions or invalid parameters, are not retryable. Unknown errors are considered permanent as well.
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	incompatibleVersionError := version_management.IncompatiblePluginVersionError{}
+	if errors.As(err, &incompatibleVersionError) {
+		return false
+	}
+
+	var internalPluginStatusError *grpc_error.InternalPluginStatusError
+	if errors.As(err, &internalPluginStatusError) {
+		return isRetryableStatusCode(internalPluginStatusError.StatusCode())
+	}
+
+	// Errors of the connection with the plugin itself (e.g. when the plugin crashed)
+	if s, ok := status.FromError(err); ok {
+		return isRetryableStatusCode(s.Code())
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, graphql.ErrCircuitOpen) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET)
+}
+
+func isRetryableStatusCode(code codes.Code) bool {
+	switch code { //nolint:exhaustive
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// ShouldRetry checks if the given (zero-based) attempt of a target that failed with the given error should be retried.
+func ShouldRetry(policy *types.RetryPolicy, attempt int, err error) bool {
+	if attempt >= policy.Retries || !IsRetryableError(err) {
+		return false
+	}
+
+	if len(policy.RetryOn) == 0 {
+		return true
+	}
+
+	syncTypeError := &SyncTypeError{}
+	if !errors.As(err, &syncTypeError) {
+		return false
+	}
+
+	return policy.RetriesSyncType(syncTypeError.SyncType)
+}
+
+// retryBackoff returns the time to wait before the given (one-based) retry. The backoff doubles with every retry.
+func retryBackoff(policy *types.RetryPolicy, retry int) time.Duration {
+	backoff := policy.Backoff
+
+	for i := 1; i < retry && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, maxRetryBackoff)
+}
+
+// runTargetWithRetries runs the target and retries it according to its retry policy.
+// When the retry is abandoned because the context is done during the backoff, the target runner is notified if it implements RetryAbandoner.
+func runTargetWithRetries(ctx context.Context, tConfig *types.BaseTargetConfig, runTarget TargetRunner) error {
+	for attempt := 0; ; attempt++ {
+		err := runTarget.TargetSync(WithAttempt(ctx, attempt), tConfig)
+		if err == nil || !ShouldRetry(&tConfig.RetryPolicy, attempt, err) {
+			return err
+		}
+
+		backoff := retryBackoff(&tConfig.RetryPolicy, attempt+1)
+
+		tConfig.TargetLogger.Warn(fmt.Sprintf("Target failed with a retryable error. Retrying in %s (retry %d of %d)", backoff, attempt+1, tConfig.RetryPolicy.Retries))
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			tConfig.TargetLogger.Warn("Not retrying the target, as the run is stopped")
+
+			if abandoner, ok := runTarget.(RetryAbandoner); ok {
+				abandoner.AbandonRetry(tConfig, err)
+			}
+
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// buildRetryPolicy builds the retry policy of a target. Values that are not set for the target are taken from the global configuration.
+func buildRetryPolicy(target map[string]interface{}) (types.RetryPolicy, error) {
+	policy := types.RetryPolicy{
+		Retries: viper.GetInt(constants.RetriesFlag),
+		Backoff: viper.GetDuration(constants.RetryBackoffFlag),
+	}
+
+	retryOn, err := parseRetryOn(viper.Get(constants.RetryOnFlag))
+	if err != nil {
+		return policy, err
+	}
+
+	policy.RetryOn = retryOn
+
+	if v, found := target[constants.RetriesFlag]; found {
+		retries, err2 := iconfig.HandleField(v, reflect.Int)
+		if err2 != nil {
+			return policy, err2
+		}
+
+		retriesInt, ok := retries.(int)
+		if !ok {
+			return policy, fmt.Errorf("%q should be a number", constants.RetriesFlag)
+		}
+
+		policy.Retries = retriesInt
+	}
+
+	if v, found := target[constants.RetryBackoffFlag]; found {
+		backoff, err2 := iconfig.HandleField(v, reflect.String)
+		if err2 != nil {
+			return policy, err2
+		}
+
+		policy.Backoff, err2 = time.ParseDuration(fmt.Sprintf("%v", backoff))
+		if err2 != nil {
+			return policy, fmt.Errorf("invalid %q: %w", constants.RetryBackoffFlag, err2)
+		}
+	}
+
+	if v, found := target[constants.RetryOnFlag]; found {
+		policy.RetryOn, err = parseRetryOn(v)
+		if err != nil {
+			return policy, err
+		}
+	}
+
+	if policy.Retries < 0 {
+		return policy, fmt.Errorf("%q should not be negative", constants.RetriesFlag)
+	}
+
+	if policy.Backoff <= 0 {
+		policy.Backoff = defaultRetryBackoff
+	}
+
+	return policy, nil
+}
+
+func parseRetryOn(value interface{}) ([]string, error) {
+	var names []string
+
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		names = strings.Split(v, ",")
+	case []string:
+		names = v
+	case []interface{}:
+		for _, item := range v {
+			names = append(names, fmt.Sprintf("%v", item))
+		}
+	default:
+		return nil, fmt.Errorf("%q should be a list of sync types", constants.RetryOnFlag)
+	}
+
+	var result []string
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		syncType, found := retrySyncTypes[name]
+		if !found {
+			return nil, fmt.Errorf("unknown sync type %q in %q", name, constants.RetryOnFlag)
+		}
+
+		result = append(result, syncType)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,175 @@
+package target
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/raito-io/cli/base/util/error/grpc_error"
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/health_check"
+	"github.com/raito-io/cli/internal/target/types"
+	"github.com/raito-io/cli/internal/version_management"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		retryable bool
+	}{
+		{name: "no error", err: nil, retryable: false},
+		{name: "unavailable plugin status", err: fmt.Errorf("data usage sync: %w", grpc_error.FromStatusError(status.Error(codes.Unavailable, "warehouse down"))), retryable: true},
+		{name: "invalid argument plugin status", err: grpc_error.FromStatusError(status.Error(codes.InvalidArgument, "missing parameter")), retryable: false},
+		{name: "plugin connection", err: status.Error(codes.Unavailable, "connection closed"), retryable: true},
+		{name: "network", err: fmt.Errorf("upload: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), retryable: true},
+		{name: "incompatible plugin version", err: NewSyncTypeError(constants.DataUsageSync, fmt.Errorf("sync: %w", version_management.IncompatiblePluginVersionError{})), retryable: false},
+		{name: "cancelled", err: context.Canceled, retryable: false},
+		{name: "unknown", err: errors.New("something went wrong"), retryable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, IsRetryableError(tt.err))
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	retryableErr := grpc_error.FromStatusError(status.Error(codes.Unavailable, "warehouse down"))
+	policy := &types.RetryPolicy{Retries: 2, RetryOn: []string{constants.DataUsageSync}}
+
+	assert.True(t, ShouldRetry(policy, 0, NewSyncTypeError(constants.DataUsageSync, retryableErr)))
+	assert.True(t, ShouldRetry(policy, 1, fmt.Errorf("data usage sync: %w", NewSyncTypeError(constants.DataUsageSync, retryableErr))))
+	assert.False(t, ShouldRetry(policy, 2, NewSyncTypeError(constants.DataUsageSync, retryableErr)))
+	assert.False(t, ShouldRetry(policy, 0, NewSyncTypeError(constants.DataAccessSync, retryableErr)))
+	assert.False(t, ShouldRetry(policy, 0, retryableErr))
+
+	assert.True(t, ShouldRetry(&types.RetryPolicy{Retries: 1}, 0, retryableErr))
+	assert.False(t, ShouldRetry(&types.RetryPolicy{}, 0, retryableErr))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := &types.RetryPolicy{Backoff: 20 * time.Minute}
+
+	assert.Equal(t, 20*time.Minute, retryBackoff(policy, 1))
+	assert.Equal(t, 40*time.Minute, retryBackoff(policy, 2))
+	assert.Equal(t, time.Hour, retryBackoff(policy, 3))
+	assert.Equal(t, time.Hour, retryBackoff(policy, 30))
+}
+
+func TestBuildRetryPolicy(t *testing.T) {
+	clearViper()
+
+	viper.Set(constants.RetriesFlag, 3)
+	viper.Set(constants.RetryOnFlag, "data-source, access")
+
+	policy, err := buildRetryPolicy(nil)
+	require.NoError(t, err)
+	assert.Equal(t, types.RetryPolicy{Retries: 3, Backoff: time.Minute, RetryOn: []string{constants.DataSourceSync, constants.DataAccessSync}}, policy)
+
+	policy, err = buildRetryPolicy(map[string]interface{}{
+		constants.RetriesFlag:      1,
+		constants.RetryBackoffFlag: "30s",
+		constants.RetryOnFlag:      []interface{}{"data-usage"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, types.RetryPolicy{Retries: 1, Backoff: 30 * time.Second, RetryOn: []string{constants.DataUsageSync}}, policy)
+
+	_, err = buildRetryPolicy(map[string]interface{}{constants.RetryOnFlag: []interface{}{"everything"}})
+	assert.ErrorContains(t, err, `unknown sync type "everything"`)
+
+	_, err = buildRetryPolicy(map[string]interface{}{constants.RetryBackoffFlag: "soon"})
+	assert.ErrorContains(t, err, `invalid "target-retry-backoff"`)
+}
+
+func TestRunFromConfigFile_WithRetries(t *testing.T) {
+	clearViper()
+	viper.AddConfigPath("./testdata")
+	viper.AddConfigPath("./internal/target/testdata")
+	viper.SetConfigType("yaml")
+	viper.SetConfigName("test-raito-with-retries")
+
+	err := viper.ReadInConfig()
+	require.NoError(t, err)
+
+	logger := hclog.L()
+	baseconfig, _ := BuildBaseConfigFromFlags(logger, health_check.NewDummyHealthChecker(logger), []string{})
+
+	unavailableErr := grpc_error.FromStatusError(status.Error(codes.Unavailable, "warehouse down"))
+	attempts := map[string][]int{}
+
+	targetRunner := NewMockTargetRunner(t)
+	targetRunner.EXPECT().RunType().Return("")
+	targetRunner.EXPECT().TargetSync(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, tConfig *types.BaseTargetConfig) error {
+		attempts[tConfig.Name] = append(attempts[tConfig.Name], Attempt(ctx))
+
+		switch tConfig.Name {
+		case "snowflake1":
+			assert.Equal(t, types.RetryPolicy{Retries: 2, Backoff: time.Millisecond, RetryOn: []string{constants.DataUsageSync, constants.IdentitySync}}, tConfig.RetryPolicy)
+
+			if Attempt(ctx) == 0 {
+				return fmt.Errorf("data usage sync: %w", NewSyncTypeError(constants.DataUsageSync, unavailableErr))
+			}
+
+			return nil
+		default:
+			assert.Equal(t, types.RetryPolicy{Retries: 1, Backoff: 2 * time.Millisecond}, tConfig.RetryPolicy)
+
+			return unavailableErr
+		}
+	})
+	targetRunner.EXPECT().Finalize(mock.Anything, baseconfig, mock.Anything).Return(nil)
+
+	err = RunTargets(context.Background(), baseconfig, targetRunner)
+
+	require.ErrorIs(t, err, unavailableErr)
+	assert.Equal(t, map[string][]int{"snowflake1": {0, 1}, "bigquery1": {0, 1}}, attempts)
+}
+
+// abandoningTargetRunner records the retries that are abandoned.
+type abandoningTargetRunner struct {
+	*MockTargetRunner
+
+	abandoned []error
+}
+
+func (r *abandoningTargetRunner) AbandonRetry(_ *types.BaseTargetConfig, err error) {
+	r.abandoned = append(r.abandoned, err)
+}
+
+func TestRunTargetWithRetries_AbandonedWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	unavailableErr := grpc_error.FromStatusError(status.Error(codes.Unavailable, "warehouse down"))
+	tConfig := &types.BaseTargetConfig{
+		Name:         "snowflake1",
+		TargetLogger: hclog.NewNullLogger(),
+		RetryPolicy:  types.RetryPolicy{Retries: 3, Backoff: time.Hour},
+	}
+
+	targetRunner := &abandoningTargetRunner{MockTargetRunner: NewMockTargetRunner(t)}
+	targetRunner.EXPECT().TargetSync(mock.Anything, tConfig).RunAndReturn(func(_ context.Context, _ *types.BaseTargetConfig) error {
+		// The run is stopped while waiting for the backoff of the retry
+		cancel()
+
+		return unavailableErr
+	}).Once()
+
+	err := runTargetWithRetries(ctx, tConfig, targetRunner)
+
+	require.ErrorIs(t, err, unavailableErr)
+	assert.Equal(t, []error{unavailableErr}, targetRunner.abandoned)
+}
@@ -0,0 +1,47 @@
+package target
+
+import (
+	"sort"
+	"sync"
+)
+
+// runningTargets counts the runs per target name, as multiple lanes of the daemon can run (the same) targets concurrently.
+var runningTargets = struct {
+	m      sync.Mutex
+	counts map[string]int
+}{
+	counts: make(map[string]int),
+}
+
+// RunningTargets returns the names of the targets that are currently being run, or an empty list if no target is running.
+func RunningTargets() []string {
+	runningTargets.m.Lock()
+	defer runningTargets.m.Unlock()
+
+	names := make([]string, 0, len(runningTargets.counts))
+	for name := range runningTargets.counts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// startRunningTarget marks the target as running until the returned function is called.
+func startRunningTarget(name string) func() {
+	runningTargets.m.Lock()
+	defer runningTargets.m.Unlock()
+
+	runningTargets.counts[name]++
+
+	return func() {
+		runningTargets.m.Lock()
+		defer runningTargets.m.Unlock()
+
+		runningTargets.counts[name]--
+		if runningTargets.counts[name] <= 0 {
+			delete(runningTargets.counts, name)
+		}
+	}
+}
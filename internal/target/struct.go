@@ -18,7 +18,7 @@ import (
 // This is done by transforming the key from the map to a camel-case to match the field name in the struct (e.g. api-user becomes ApiUser)
 func fillStruct(o interface{}, m map[string]interface{}) error {
 	for k, v := range m {
-		if k != constants.DataObjectEnrichers {
+		if k != constants.DataObjectEnrichers && k != constants.BlackoutWindows && k != constants.LockRules {
 			err := setField(o, k, v)
 			if err != nil {
 				return err
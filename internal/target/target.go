@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-multierror"
@@ -14,11 +15,14 @@ import (
 	"google.golang.org/grpc/codes"
 
 	"github.com/raito-io/cli/base/util/error/grpc_error"
+	"github.com/raito-io/cli/internal/blackout"
 	iconfig "github.com/raito-io/cli/internal/config"
 	"github.com/raito-io/cli/internal/constants"
 	error2 "github.com/raito-io/cli/internal/error"
 	"github.com/raito-io/cli/internal/health_check"
+	"github.com/raito-io/cli/internal/schedule"
 	"github.com/raito-io/cli/internal/target/types"
+	"github.com/raito-io/cli/internal/util/stringops"
 )
 
 //go:generate go run github.com/vektra/mockery/v2 --name=TargetRunner --with-expecter --inpackage
@@ -28,6 +32,12 @@ type TargetRunner interface {
 	RunType() string
 }
 
+// RetryAbandoner is implemented by target runners that keep a failed target open (e.g. its job in progress) when it will be retried.
+// AbandonRetry is called when the retry doesn't happen after all, for example because the run is cancelled while waiting for the backoff.
+type RetryAbandoner interface {
+	AbandonRetry(tConfig *types.BaseTargetConfig, err error)
+}
+
 func GetTargetConfig(targetName string, baseConfig *types.BaseConfig) (*types.BaseTargetConfig, error) {
 	targets := viper.Get(constants.Targets)
 
@@ -52,6 +62,39 @@ func GetTargetConfig(targetName string, baseConfig *types.BaseConfig) (*types.Ba
 	return nil, nil
 }
 
+// ConfiguredTargetNames returns the names of the targets defined in the configuration file or with the command line flags.
+func ConfiguredTargetNames() []string {
+	if connector := viper.GetString(constants.ConnectorNameFlag); connector != "" {
+		if name := viper.GetString(constants.NameFlag); name != "" {
+			return []string{name}
+		}
+
+		return []string{connector}
+	}
+
+	var names []string
+
+	if targetList, ok := viper.Get(constants.Targets).([]interface{}); ok {
+		for _, targetObj := range targetList {
+			target, ok := targetObj.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			name, _ := target[constants.NameFlag].(string)
+			if name == "" {
+				name, _ = target[constants.ConnectorNameFlag].(string)
+			}
+
+			if name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
+
 func RunTargets(ctx context.Context, baseConfig *types.BaseConfig, runTarget TargetRunner, opFns ...func(*Options)) (err error) {
 	options := createOptions(opFns...)
 
@@ -69,7 +112,10 @@ func RunTargets(ctx context.Context, baseConfig *types.BaseConfig, runTarget Tar
 	}()
 
 	if viper.GetString(constants.ConnectorNameFlag) != "" {
-		targetConfig := buildTargetConfigFromFlags(baseConfig)
+		targetConfig, err2 := buildTargetConfigFromFlags(baseConfig)
+		if err2 != nil {
+			return fmt.Errorf("error while parsing the target configuration: %w", err2)
+		}
 
 		if !options.SyncDataSourceId(targetConfig.DataSourceId) {
 			return nil
@@ -79,14 +125,20 @@ func RunTargets(ctx context.Context, baseConfig *types.BaseConfig, runTarget Tar
 			return nil
 		}
 
+		if !options.SyncTargetName(targetConfig.Name) {
+			return nil
+		}
+
 		logTargetConfig(targetConfig)
 
-		err2 := runTarget.TargetSync(ctx, options.TargetOptions(targetConfig))
+		defer startRunningTarget(targetConfig.Name)()
+
+		err2 = runTargetWithRetries(ctx, options.TargetOptions(targetConfig), runTarget)
 		if err2 != nil {
 			return err2
 		}
 	} else {
-		err2 := runMultipleTargets(ctx, baseConfig, runTarget.RunType(), runTarget.TargetSync, &options)
+		err2 := runMultipleTargets(ctx, baseConfig, runTarget, &options)
 		if err2 != nil {
 			return err2
 		}
@@ -112,9 +164,11 @@ func HandleTargetError(err error, config *types.BaseTargetConfig, prefix ...stri
 	config.TargetLogger.Error(fmt.Sprintf("%s%s", prefixString, err.Error()))
 }
 
-func runMultipleTargets(ctx context.Context, baseConfig *types.BaseConfig, runType string, runTarget func(ctx context.Context, tConfig *types.BaseTargetConfig) error, options *Options) error {
+func runMultipleTargets(ctx context.Context, baseConfig *types.BaseConfig, runTarget TargetRunner, options *Options) error {
 	var errorResult error
 
+	runType := runTarget.RunType()
+
 	dataObjectEnricherMap, err := buildDataObjectEnricherMap()
 	if err != nil {
 		errorResult = multierror.Append(errorResult, err)
@@ -130,10 +184,12 @@ func runMultipleTargets(ctx context.Context, baseConfig *types.BaseConfig, runTy
 		}
 	}
 
+	runStart := time.Now()
+
 	if targetList, ok := targets.([]interface{}); ok {
 		hclog.L().Debug(fmt.Sprintf("Found %d targets to run.", len(targetList)))
 
-		for _, targetObj := range targetList {
+		for i, targetObj := range targetList {
 			target, ok := targetObj.(map[string]interface{})
 			if !ok {
 				errorResult = multierror.Append(errorResult, fmt.Errorf("the target definition could not be parsed correctly (%v)", targetObj))
@@ -162,6 +218,10 @@ func runMultipleTargets(ctx context.Context, baseConfig *types.BaseConfig, runTy
 				continue
 			}
 
+			if !options.SyncTargetName(tConfig.Name) {
+				continue
+			}
+
 			tConfig = options.TargetOptions(tConfig)
 
 			if len(onlyTargets) > 0 {
@@ -180,7 +240,19 @@ func runMultipleTargets(ctx context.Context, baseConfig *types.BaseConfig, runTy
 				continue
 			}
 
-			runErr := runTarget(ctx, tConfig)
+			err2 = waitForStaggerOffset(ctx, tConfig, runStart.Add(schedule.StaggerOffset(i, len(targetList), options.Stagger)))
+			if err2 != nil {
+				errorResult = multierror.Append(errorResult, err2)
+
+				break
+			}
+
+			stopRunningTarget := startRunningTarget(tConfig.Name)
+
+			runErr := runTargetWithRetries(ctx, tConfig, runTarget)
+
+			stopRunningTarget()
+
 			if runErr != nil {
 				errorResult = multierror.Append(errorResult, runErr)
 
@@ -287,6 +359,11 @@ func buildTargetConfigFromMapForRun(baseconfig *types.BaseConfig, target map[str
 		return nil, err
 	}
 
+	err = buildTargetPolicies(&tConfig, target)
+	if err != nil {
+		return nil, err
+	}
+
 	return &tConfig, nil
 }
 
@@ -305,7 +382,7 @@ func BuildBaseConfigFromFlags(baseLogger hclog.Logger, healthChecker health_chec
 	return &config, nil
 }
 
-func buildTargetConfigFromFlags(baseConfig *types.BaseConfig) *types.BaseTargetConfig {
+func buildTargetConfigFromFlags(baseConfig *types.BaseConfig) (*types.BaseTargetConfig, error) {
 	connector := viper.GetString(constants.ConnectorNameFlag)
 	version := viper.GetString(constants.ConnectorVersionFlag)
 	name := viper.GetString(constants.NameFlag)
@@ -366,7 +443,75 @@ func buildTargetConfigFromFlags(baseConfig *types.BaseConfig) *types.BaseTargetC
 		ReplaceGroups:   true,
 	}
 
-	return &targetConfig
+	err := buildTargetPolicies(&targetConfig, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &targetConfig, nil
+}
+
+// buildTargetPolicies adds the configuration that can be defined both globally and for the target to the target configuration.
+// The target map is nil when the target is defined by the flags.
+func buildTargetPolicies(targetConfig *types.BaseTargetConfig, target map[string]interface{}) error {
+	var err error
+
+	targetConfig.BlackoutWindows, err = buildBlackoutWindows(target[constants.BlackoutWindows])
+	if err != nil {
+		return err
+	}
+
+	targetConfig.LockRules, err = buildLockRules(target[constants.LockRules])
+	if err != nil {
+		return err
+	}
+
+	targetConfig.RetryPolicy, err = buildRetryPolicy(target)
+	if err != nil {
+		return fmt.Errorf("error while parsing the retry policy: %w", err)
+	}
+
+	targetConfig.IncrementalSync, err = buildIncrementalSyncPolicy(target)
+	if err != nil {
+		return fmt.Errorf("error while parsing the incremental sync configuration: %w", err)
+	}
+
+	return nil
+}
+
+// buildBlackoutWindows combines the globally defined blackout windows with the ones defined for the target.
+func buildBlackoutWindows(targetBlackoutWindows interface{}) ([]blackout.Window, error) {
+	globalWindows, err := blackout.ParseWindows(viper.Get(constants.BlackoutWindows))
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing the global blackout windows: %w", err)
+	}
+
+	targetWindows, err := blackout.ParseWindows(targetBlackoutWindows)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing the blackout windows of the target: %w", err)
+	}
+
+	return append(globalWindows, targetWindows...), nil
+}
+
+// waitForStaggerOffset waits until the start time of the target, when the targets of the run are spread across a window.
+func waitForStaggerOffset(ctx context.Context, tConfig *types.BaseTargetConfig, startAt time.Time) error {
+	wait := time.Until(startAt)
+	if wait <= 0 {
+		return nil
+	}
+
+	tConfig.TargetLogger.Info(fmt.Sprintf("Waiting %s before starting the target, to spread the targets of the run", wait.Round(time.Second)))
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 // logTargetConfig will print out the target configuration in the log (debug level).
@@ -395,8 +540,7 @@ func logTargetConfig(config *types.BaseTargetConfig) {
 	}
 
 	for k := range cc.Parameters {
-		lk := strings.ToLower(k)
-		if strings.Contains(lk, "secret") || strings.Contains(lk, "password") || strings.Contains(lk, "passwd") || strings.Contains(lk, "psswd") {
+		if stringops.IsSensitiveKey(k) {
 			cc.Parameters[k] = "**censured**"
 		}
 	}
@@ -408,7 +552,9 @@ type Options struct {
 	ExternalTrigger  bool
 	DataSourceIds    map[string]struct{}
 	IdentityStoreIds map[string]struct{}
+	TargetNames      map[string]struct{}
 	ConfigOption     func(targetConfig *types.BaseTargetConfig)
+	Stagger          time.Duration
 }
 
 func createOptions(opFns ...func(*Options)) Options {
@@ -440,6 +586,16 @@ func (o *Options) SyncIdentityStoreId(identityStoreId string) bool {
 	return found
 }
 
+func (o *Options) SyncTargetName(name string) bool {
+	if o.TargetNames == nil {
+		return true
+	}
+
+	_, found := o.TargetNames[name]
+
+	return found
+}
+
 func (o *Options) TargetOptions(targetConfig *types.BaseTargetConfig) *types.BaseTargetConfig {
 	if o.ConfigOption != nil {
 		o.ConfigOption(targetConfig)
@@ -480,12 +636,35 @@ func WithIdentityStoreIds(identityStoreIds ...string) func(o *Options) {
 	}
 }
 
+func WithTargetNames(targetNames ...string) func(o *Options) {
+	return func(o *Options) {
+		if len(targetNames) == 0 {
+			return
+		}
+
+		if o.TargetNames == nil {
+			o.TargetNames = map[string]struct{}{}
+		}
+
+		for _, targetName := range targetNames {
+			o.TargetNames[targetName] = struct{}{}
+		}
+	}
+}
+
 func WithConfigOption(fn func(targetConfig *types.BaseTargetConfig)) func(o *Options) {
 	return func(o *Options) {
 		o.ConfigOption = fn
 	}
 }
 
+// WithStagger spreads the start of the targets evenly across the given window.
+func WithStagger(window time.Duration) func(o *Options) {
+	return func(o *Options) {
+		o.Stagger = window
+	}
+}
+
 func WithExternalTrigger() func(o *Options) {
 	return func(o *Options) {
 		o.ExternalTrigger = true
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/jinzhu/copier"
@@ -292,7 +293,8 @@ func TestBuildTargetConfigFromFlags(t *testing.T) {
 
 	logger := hclog.L()
 	baseconfig, _ := BuildBaseConfigFromFlags(logger, health_check.NewDummyHealthChecker(logger), []string{"--custom1", "ok"})
-	config := buildTargetConfigFromFlags(baseconfig)
+	config, err := buildTargetConfigFromFlags(baseconfig)
+	require.NoError(t, err)
 	assert.NotNil(t, config)
 
 	assert.Equal(t, "conn1", config.ConnectorName)
@@ -316,13 +318,50 @@ func TestBuildTargetConfigFromFlagsNoName(t *testing.T) {
 
 	logger := hclog.L()
 	baseconfig, _ := BuildBaseConfigFromFlags(logger, health_check.NewDummyHealthChecker(logger), []string{})
-	config := buildTargetConfigFromFlags(baseconfig)
+	config, err := buildTargetConfigFromFlags(baseconfig)
+	require.NoError(t, err)
 	assert.NotNil(t, config)
 
 	assert.Equal(t, "conn1", config.ConnectorName)
 	assert.Equal(t, "conn1", config.Name)
 }
 
+func TestBuildTargetConfigFromFlagsInvalidBlackoutWindows(t *testing.T) {
+	clearViper()
+
+	viper.Set(constants.ConnectorNameFlag, "conn1")
+	viper.Set(constants.BlackoutWindows, "not-a-list")
+
+	logger := hclog.L()
+	baseconfig, _ := BuildBaseConfigFromFlags(logger, health_check.NewDummyHealthChecker(logger), []string{})
+	_, err := buildTargetConfigFromFlags(baseconfig)
+	require.ErrorContains(t, err, "global blackout windows")
+}
+
+func TestBuildTargetConfigFromFlagsInvalidLockRules(t *testing.T) {
+	clearViper()
+
+	viper.Set(constants.ConnectorNameFlag, "conn1")
+	viper.Set(constants.LockRules, []interface{}{map[string]interface{}{"name": "rule1", "locks": []interface{}{"unknown"}}})
+
+	logger := hclog.L()
+	baseconfig, _ := BuildBaseConfigFromFlags(logger, health_check.NewDummyHealthChecker(logger), []string{})
+	_, err := buildTargetConfigFromFlags(baseconfig)
+	require.ErrorContains(t, err, "global lock rules")
+}
+
+func TestBuildTargetConfigFromFlagsInvalidRetryPolicy(t *testing.T) {
+	clearViper()
+
+	viper.Set(constants.ConnectorNameFlag, "conn1")
+	viper.Set(constants.RetriesFlag, -1)
+
+	logger := hclog.L()
+	baseconfig, _ := BuildBaseConfigFromFlags(logger, health_check.NewDummyHealthChecker(logger), []string{})
+	_, err := buildTargetConfigFromFlags(baseconfig)
+	require.ErrorContains(t, err, "retry policy")
+}
+
 func TestBuildParameterMapFromArguments(t *testing.T) {
 	params := types.BuildParameterMapFromArguments([]string{"--bool-val", "--string-val=blah", "--another-one", "moremoremore"})
 	assert.Equal(t, 3, len(params))
@@ -620,3 +659,84 @@ func TestRunFromConfigFile_WithEnrichers(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 1, runs)
 }
+
+func TestRunFromConfigFile_WithBlackoutWindows(t *testing.T) {
+	clearViper()
+	viper.AddConfigPath("./testdata")
+	viper.AddConfigPath("./internal/target/testdata")
+	viper.SetConfigType("yaml")
+	viper.SetConfigName("test-raito-with-blackout-windows")
+
+	err := viper.ReadInConfig()
+	assert.Nil(t, err)
+
+	runs := 0
+	logger := hclog.L()
+	baseconfig, _ := BuildBaseConfigFromFlags(logger, health_check.NewDummyHealthChecker(logger), []string{})
+
+	targetRunner := NewMockTargetRunner(t)
+	targetRunner.EXPECT().RunType().Return("")
+	targetRunner.EXPECT().TargetSync(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, tConfig *types.BaseTargetConfig) error {
+		require.Len(t, tConfig.BlackoutWindows, 2)
+		assert.Equal(t, "month-end-close", tConfig.BlackoutWindows[0].Name)
+		assert.Equal(t, 72*time.Hour, tConfig.BlackoutWindows[0].Duration)
+		assert.Equal(t, "migration", tConfig.BlackoutWindows[1].Name)
+		assert.Equal(t, time.Date(2024, 12, 27, 8, 0, 0, 0, time.Local), tConfig.BlackoutWindows[1].To)
+
+		assert.NotContains(t, tConfig.Parameters, constants.BlackoutWindows)
+		assert.Equal(t, "somewhere.eu-central-1", tConfig.Parameters["sf-account"])
+
+		runs++
+
+		return nil
+	})
+	targetRunner.EXPECT().Finalize(mock.Anything, baseconfig, mock.Anything).Return(nil)
+
+	err = RunTargets(context.Background(), baseconfig, targetRunner)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, runs)
+}
+
+func TestRunFromConfigFile_WithStagger(t *testing.T) {
+	clearViper()
+	viper.AddConfigPath("./testdata")
+	viper.AddConfigPath("./internal/target/testdata")
+	viper.SetConfigType("yaml")
+	viper.SetConfigName("test-raito-with-retries")
+
+	err := viper.ReadInConfig()
+	require.NoError(t, err)
+
+	logger := hclog.L()
+	baseconfig, _ := BuildBaseConfigFromFlags(logger, health_check.NewDummyHealthChecker(logger), []string{})
+
+	start := time.Now()
+	startOffsets := map[string]time.Duration{}
+
+	targetRunner := NewMockTargetRunner(t)
+	targetRunner.EXPECT().RunType().Return("")
+	targetRunner.EXPECT().TargetSync(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, tConfig *types.BaseTargetConfig) error {
+		startOffsets[tConfig.Name] = time.Since(start)
+
+		return nil
+	})
+	targetRunner.EXPECT().Finalize(mock.Anything, baseconfig, mock.Anything).Return(nil)
+
+	err = RunTargets(context.Background(), baseconfig, targetRunner, WithStagger(200*time.Millisecond))
+
+	require.NoError(t, err)
+	require.Len(t, startOffsets, 2)
+	assert.Less(t, startOffsets["snowflake1"], 100*time.Millisecond)
+	assert.GreaterOrEqual(t, startOffsets["bigquery1"], 100*time.Millisecond)
+}
+
+func TestConfiguredTargetNames(t *testing.T) {
+	viper.Set(constants.Targets, []interface{}{
+		map[string]interface{}{constants.NameFlag: "snowflake-prod", constants.ConnectorNameFlag: "raito-io/cli-plugin-snowflake"},
+		map[string]interface{}{constants.ConnectorNameFlag: "raito-io/cli-plugin-bigquery"},
+	})
+	defer viper.Set(constants.Targets, nil)
+
+	assert.Equal(t, []string{"snowflake-prod", "raito-io/cli-plugin-bigquery"}, ConfiguredTargetNames())
+}
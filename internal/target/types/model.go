@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/raito-io/cli/base/util/config"
+	"github.com/raito-io/cli/internal/blackout"
 	iconfig "github.com/raito-io/cli/internal/config"
 	"github.com/raito-io/cli/internal/constants"
 	"github.com/raito-io/cli/internal/health_check"
@@ -35,6 +36,11 @@ type BaseConfig struct {
 	ApiSecret string
 	Domain    string
 
+	AuthMethod   string
+	OidcTokenUrl string
+	OidcScopes   string
+	TokenCache   bool
+
 	FileBackupLocation      string
 	MaximumBackupsPerTarget int
 
@@ -59,9 +65,23 @@ func (c *BaseConfig) ReloadConfig() error {
 		return err
 	}
 
+	authMethod, err := iconfig.HandleField(viper.GetString(constants.AuthMethodFlag), reflect.String)
+	if err != nil {
+		return err
+	}
+
+	oidcTokenUrl, err := iconfig.HandleField(viper.GetString(constants.OidcTokenUrlFlag), reflect.String)
+	if err != nil {
+		return err
+	}
+
 	c.ApiUser = apiUser.(string)
 	c.ApiSecret = apiSecret.(string)
 	c.Domain = domain.(string)
+	c.AuthMethod = authMethod.(string)
+	c.OidcTokenUrl = oidcTokenUrl.(string)
+	c.OidcScopes = viper.GetString(constants.OidcScopesFlag)
+	c.TokenCache = viper.GetBool(constants.TokenCacheFlag)
 
 	// Only read the parameters the first time as this is read from the command line + otherwise it would override the parameters as read from the
 	if c.Parameters == nil {
@@ -103,6 +123,65 @@ type EnricherConfig struct {
 	Name             string
 }
 
+// RetryPolicy defines how many times and when a failed target is retried within the same run.
+type RetryPolicy struct {
+	Retries int
+	Backoff time.Duration
+
+	// RetryOn contains the internal sync types of which a failure is retried. If empty, failures of all sync types are retried.
+	RetryOn []string
+}
+
+// RetriesSyncType returns true if failures of the given internal sync type can be retried.
+func (p *RetryPolicy) RetriesSyncType(syncType string) bool {
+	if len(p.RetryOn) == 0 {
+		return true
+	}
+
+	for _, st := range p.RetryOn {
+		if st == syncType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LockRule locks (parts of) the access providers that are imported from the data source and match all the conditions of the rule.
+// The conditions are regular expressions; conditions that are not set match all access providers.
+type LockRule struct {
+	Name string
+
+	Names           []string
+	Types           []string
+	Tags            []string
+	WhatDataObjects []string
+	Actions         []string
+	Owners          []string
+	IncompleteOnly  bool
+
+	// Locks contains the kinds of locks to set (who, inheritance, what, name, delete, owners or fully)
+	Locks  []string
+	Reason string
+}
+
+// IncrementalSyncPolicy defines whether the data source of a target is synced incrementally and how often a full sync is done.
+type IncrementalSyncPolicy struct {
+	Enabled bool
+
+	// FullSyncInterval is the maximum time between two full syncs. A value of zero disables the periodic full syncs.
+	FullSyncInterval time.Duration
+}
+
+// NeedsFullSync returns true if the last full sync is older than the full sync interval.
+func (p *IncrementalSyncPolicy) NeedsFullSync(lastFullSync time.Time, now time.Time) bool {
+	if p.FullSyncInterval <= 0 {
+		return false
+	}
+
+	return lastFullSync.IsZero() || now.Sub(lastFullSync) >= p.FullSyncInterval
+}
+
 type BaseTargetConfig struct {
 	BaseConfig
 	ConnectorName    string
@@ -151,6 +230,9 @@ type BaseTargetConfig struct {
 	FullyLockByTag          string
 	FullyLockWhenIncomplete bool
 
+	// LockRules contains the global and target specific lock rules for the imported access providers
+	LockRules []LockRule
+
 	TagOverwriteKeyForAccessProviderName   string
 	TagOverwriteKeyForAccessProviderOwners string
 	TagOverwriteKeyForDataObjectOwners     string
@@ -169,6 +251,15 @@ type BaseTargetConfig struct {
 
 	DataObjectEnrichers []*EnricherConfig
 
+	// BlackoutWindows contains the global and target specific windows in which (some types of) syncs are not allowed to run
+	BlackoutWindows []blackout.Window
+
+	// RetryPolicy defines how the target is retried within the same run when it fails
+	RetryPolicy RetryPolicy
+
+	// IncrementalSync defines whether the data source is synced incrementally
+	IncrementalSync IncrementalSyncPolicy
+
 	TargetLogger hclog.Logger
 
 	fileBackupLocationForRun string
@@ -47,7 +47,8 @@ func isSyncTargetSync(ctx context.Context, targetConfig *types.BaseTargetConfig,
 }
 
 func dataUsageSync(ctx context.Context, targetConfig *types.BaseTargetConfig, jobID string, client plugin.PluginClient) error {
-	dataUsageSyncTask := &data_usage.DataUsageSync{TargetConfig: targetConfig, JobId: jobID}
+	attempt := targetAttemptFromContext(ctx)
+	dataUsageSyncTask := &data_usage.DataUsageSync{TargetConfig: targetConfig, JobId: jobID, RunState: attempt.store, ResumeCheckpoint: attempt.resumeCheckpoints(ctx)}
 
 	err := execute(ctx, targetConfig.DataSourceId, jobID, constants.DataUsageSync, "data usage", targetConfig.SkipDataUsageSync, dataUsageSyncTask, targetConfig, client)
 	if err != nil {
@@ -80,7 +81,8 @@ func identityStoreSync(ctx context.Context, targetConfig *types.BaseTargetConfig
 }
 
 func dataSourceSync(ctx context.Context, targetConfig *types.BaseTargetConfig, jobID string, client plugin.PluginClient) error {
-	dataSourceSyncTask := &data_source.DataSourceSync{TargetConfig: targetConfig, JobId: jobID}
+	attempt := targetAttemptFromContext(ctx)
+	dataSourceSyncTask := &data_source.DataSourceSync{TargetConfig: targetConfig, JobId: jobID, RunState: attempt.store, ResumeCheckpoint: attempt.resumeCheckpoints(ctx)}
 
 	err := execute(ctx, targetConfig.DataSourceId, jobID, constants.DataSourceSync, "data source metadata", targetConfig.SkipDataSourceSync, dataSourceSyncTask, targetConfig, client)
 	if err != nil {
@@ -12,28 +12,64 @@ import (
 
 	"github.com/raito-io/cli/base/util/error/grpc_error"
 	plugin2 "github.com/raito-io/cli/base/util/plugin"
+	"github.com/raito-io/cli/internal/blackout"
 	"github.com/raito-io/cli/internal/constants"
 	error2 "github.com/raito-io/cli/internal/error"
 	gql "github.com/raito-io/cli/internal/graphql"
 	"github.com/raito-io/cli/internal/job"
+	"github.com/raito-io/cli/internal/lane"
 	"github.com/raito-io/cli/internal/logging"
 	"github.com/raito-io/cli/internal/plugin"
+	"github.com/raito-io/cli/internal/runstate"
 	"github.com/raito-io/cli/internal/target"
 	"github.com/raito-io/cli/internal/target/types"
 	"github.com/raito-io/cli/internal/util/array"
 	"github.com/raito-io/cli/internal/version_management"
 )
 
+// targetLocks makes sure only one sync runs per target, handing over to the highest priority lane first.
+// Concurrent syncs of the same target would overwrite each other's run state, watermark and checkpoints.
+var targetLocks = lane.NewPriorityLock()
+
+// accessSyncLocks makes sure only one access sync runs per data source, handing over to the highest priority lane first.
+var accessSyncLocks = lane.NewPriorityLock()
+
 type SyncJob struct {
 	jobIds      []string
 	RunTypeName string
+
+	// RunState stores the progress of the run of every target, so a failed run can be resumed. Nil disables storing the progress.
+	RunState *runstate.Store
+
+	// Resume skips the steps that already finished in the last run of a target if that run failed and the inputs of the steps didn't change.
+	Resume bool
+
+	// attempts keeps the progress of the last attempt per target, so a retry only executes the sync types that didn't finish yet.
+	attempts map[string]*targetAttempt
 }
 
 func (s *SyncJob) RunType() string {
 	return s.RunTypeName
 }
 
+// AbandonRetry marks the job of the target as failed, as it was kept in progress for a retry that won't happen.
+func (s *SyncJob) AbandonRetry(targetConfig *types.BaseTargetConfig, err error) {
+	attempt, found := s.attempts[targetConfig.Name]
+	if !found || attempt.jobId == "" {
+		return
+	}
+
+	job.UpdateJobEvent(targetConfig, attempt.jobId, job.Failed, err)
+}
+
 func (s *SyncJob) TargetSync(ctx context.Context, targetConfig *types.BaseTargetConfig) (syncError error) {
+	unlock, lockErr := targetLocks.Lock(ctx, targetConfig.Name)
+	if lockErr != nil {
+		return fmt.Errorf("waiting for the running sync of target %q: %w", targetConfig.Name, lockErr)
+	}
+
+	defer unlock()
+
 	targetConfig.TargetLogger.Info("Executing target...")
 
 	var jobId string
@@ -48,27 +84,45 @@ func (s *SyncJob) TargetSync(ctx context.Context, targetConfig *types.BaseTarget
 		}
 	}()
 
-	client, err := plugin.NewPluginClient(targetConfig.ConnectorName, targetConfig.ConnectorVersion, targetConfig.TargetLogger)
+	client, err := plugin.NewTargetPluginClient(targetConfig.Name, targetConfig.ConnectorName, targetConfig.ConnectorVersion, targetConfig.TargetLogger)
 	if err != nil {
 		targetConfig.TargetLogger.Error(fmt.Sprintf("Error initializing connector plugin %q: %s", targetConfig.ConnectorName, err.Error()))
 		return err
 	}
 	defer client.Close()
 
-	jobId, err = job.StartJob(ctx, targetConfig)
-	if err != nil {
-		return err
-	}
+	attempt := s.targetAttempt(ctx, targetConfig)
+	ctx = context.WithValue(ctx, targetAttemptKey{}, attempt)
 
-	s.jobIds = append(s.jobIds, jobId)
+	if attempt.jobId != "" {
+		// Retrying within the job of the previous attempt, so the sync types that already finished are not executed again
+		jobId = attempt.jobId
 
-	targetConfig.TargetLogger.Info(fmt.Sprintf("Start job with jobID: '%s'", jobId))
-	job.UpdateJobEvent(targetConfig, jobId, job.InProgress, nil)
+		targetConfig.TargetLogger.Info(fmt.Sprintf("Retrying job with jobID: '%s'", jobId))
+	} else {
+		jobId, err = job.StartJob(ctx, targetConfig)
+		if err != nil {
+			return err
+		}
+
+		attempt.jobId = jobId
+		s.jobIds = append(s.jobIds, jobId)
+
+		targetConfig.TargetLogger.Info(fmt.Sprintf("Start job with jobID: '%s'", jobId))
+		job.UpdateJobEvent(targetConfig, jobId, job.InProgress, nil)
+
+		attempt.startRun(targetConfig, s.RunTypeName, s.Resume)
+	}
 
 	defer func() {
-		if syncError == nil {
+		switch {
+		case syncError == nil:
+			attempt.completeRun()
 			job.UpdateJobEvent(targetConfig, jobId, job.Completed, nil)
-		} else {
+		case target.ShouldRetry(&targetConfig.RetryPolicy, target.Attempt(ctx), syncError):
+			// The job stays in progress, as the failed sync types will be retried. AbandonRetry marks it as failed if the retry doesn't happen.
+			targetConfig.TargetLogger.Debug(fmt.Sprintf("Keeping job '%s' in progress for the retry", jobId))
+		default:
 			job.UpdateJobEvent(targetConfig, jobId, job.Failed, syncError)
 		}
 	}()
@@ -129,6 +183,20 @@ func (s *SyncJob) Finalize(ctx context.Context, baseConfig *types.BaseConfig, op
 }
 
 func execute(ctx context.Context, targetID string, jobID string, syncType string, syncTypeLabel string, skipSync bool, syncTask job.Task, cfg *types.BaseTargetConfig, c plugin.PluginClient) (err error) {
+	attempt := targetAttemptFromContext(ctx)
+
+	if attempt.isFinished(syncType) {
+		cfg.TargetLogger.Info(fmt.Sprintf("Skipping sync of %s as it already finished in a previous attempt", syncTypeLabel))
+
+		return nil
+	}
+
+	defer func() {
+		if err == nil {
+			attempt.finished[syncType] = struct{}{}
+		}
+	}()
+
 	cfg, warningCollector, loggingCleanUp, err := logging.CreateWarningCapturingLogger(cfg)
 	if err != nil {
 		return err
@@ -148,10 +216,32 @@ func execute(ctx context.Context, targetID string, jobID string, syncType string
 		}
 	}()
 
+	blackoutWindow, blackoutUntil := blackout.ActiveWindow(cfg.BlackoutWindows, syncType, time.Now())
+	resumedStep, resumed := attempt.resumedStep(syncType)
+
 	switch {
 	case skipSync:
 		taskEventUpdater.SetStatusToSkipped(ctx)
 		cfg.TargetLogger.Info("Skipping sync of " + syncTypeLabel)
+	case resumed:
+		reason := fmt.Sprintf("Skipping sync of %s as it already finished at %s in the last run, which failed", syncTypeLabel, resumedStep.FinishedAt.Format(time.RFC822))
+
+		taskEventUpdater.SetStatusToSkippedWithReason(ctx, reason)
+		cfg.TargetLogger.Info(reason)
+	case blackoutWindow != nil && blackout.DeferralsEnabled():
+		// The task is reported as skipped, as the sync is executed in a new job once the window ends
+		reason := fmt.Sprintf("Sync of %s deferred until %s because of blackout window %q", syncTypeLabel, blackoutUntil.Format(time.RFC822), blackoutWindow.Name)
+
+		taskEventUpdater.SetStatusToSkippedWithReason(ctx, reason)
+		cfg.TargetLogger.Info(reason)
+
+		blackout.Defer(blackout.Deferral{Target: cfg.Name, SyncType: syncType, Window: blackoutWindow.Name, Until: blackoutUntil})
+	case blackoutWindow != nil:
+		// Outside the daemon, nobody executes the sync once the window ends
+		reason := fmt.Sprintf("Skipping sync of %s because of blackout window %q, which ends at %s", syncTypeLabel, blackoutWindow.Name, blackoutUntil.Format(time.RFC822))
+
+		taskEventUpdater.SetStatusToSkippedWithReason(ctx, reason)
+		cfg.TargetLogger.Warn(reason)
 	case targetID == "":
 		taskEventUpdater.SetStatusToSkipped(ctx)
 
@@ -162,11 +252,25 @@ func execute(ctx context.Context, targetID string, jobID string, syncType string
 
 		cfg.TargetLogger.Warn("No " + idField + " argument found. Skipping syncing of " + syncTypeLabel)
 	default:
+		if syncType == constants.DataAccessSync {
+			// Never running two access syncs for the same data source concurrently (e.g. a scheduled run and an access provider update)
+			unlock, lockErr := accessSyncLocks.Lock(ctx, targetID)
+			if lockErr != nil {
+				return fmt.Errorf("waiting for the running %s sync of data source %q: %w", syncTypeLabel, targetID, lockErr)
+			}
+
+			defer unlock()
+		}
+
+		blackout.Executed(cfg.Name, syncType)
+
 		syncErr := sync(ctx, cfg, syncTypeLabel, taskEventUpdater, syncTask, c, syncType, jobID)
 		if syncErr != nil {
 			// Sync error is already pushed to task error
-			return fmt.Errorf("failed to execute %s sync: %w", syncTypeLabel, syncErr)
+			return target.NewSyncTypeError(syncType, fmt.Errorf("failed to execute %s sync: %w", syncTypeLabel, syncErr))
 		}
+
+		attempt.stepFinished(cfg, syncType)
 	}
 
 	return nil
@@ -277,7 +381,7 @@ func runTaskPartSync(ctx context.Context, cfg *types.BaseTargetConfig, syncTypeL
 	syncResult := taskPart.GetResultObject()
 
 	if syncResult != nil {
-		subtask, err := job.WaitForJobToComplete(ctx, jobID, syncType, subtaskId, syncResult, cfg, status, 5)
+		subtask, err := job.WaitForJobToComplete(ctx, jobID, syncType, subtaskId, syncResult, cfg, status)
 		if err != nil {
 			return err
 		}
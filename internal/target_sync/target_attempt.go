@@ -0,0 +1,146 @@
+package target_sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/raito-io/cli/internal/runstate"
+	"github.com/raito-io/cli/internal/target"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+// targetAttempt keeps the progress of the sync of a target
+type targetAttempt struct {
+	jobId    string
+	finished map[string]struct{}
+
+	// resumed contains the steps that finished in the previous (failed) run of the target and are skipped in this run
+	resumed map[string]runstate.Step
+
+	// resume indicates whether the run resumes the last run of the target
+	resume bool
+
+	store  *runstate.Store
+	state  *runstate.TargetState
+	logger hclog.Logger
+}
+
+type targetAttemptKey struct{}
+
+func (a *targetAttempt) isFinished(syncType string) bool {
+	_, found := a.finished[syncType]
+
+	return found
+}
+
+func (a *targetAttempt) resumedStep(syncType string) (runstate.Step, bool) {
+	step, found := a.resumed[syncType]
+
+	return step, found
+}
+
+// startRun records the start of a new run of the given type of the target in the run state.
+// When resuming, the steps that finished in the last run of the same type are skipped if that run failed and the inputs of the steps didn't change.
+// Skipped steps are only taken over by this run, not by the next one.
+func (a *targetAttempt) startRun(cfg *types.BaseTargetConfig, runType string, resume bool) {
+	a.resume = resume
+
+	if a.store == nil {
+		return
+	}
+
+	if resume {
+		previous, err := a.store.Load(runType, cfg.Name)
+		if err != nil {
+			a.logger.Warn(fmt.Sprintf("Unable to resume the last run: %s", err.Error()))
+		}
+
+		a.resumed = previous.ResumableSteps(cfg)
+
+		if len(a.resumed) > 0 {
+			a.logger.Info(fmt.Sprintf("Resuming the last run (job %q), which failed after %d finished step(s)", previous.JobId, len(a.resumed)))
+		}
+	}
+
+	// Steps taken over from the last run are not stored in the new state, so they are executed again when this run fails as well.
+	// Otherwise a step that keeps failing would cause the steps before it to be skipped forever.
+	a.state = &runstate.TargetState{
+		Target:    cfg.Name,
+		RunType:   runType,
+		JobId:     a.jobId,
+		StartedAt: time.Now(),
+		Steps:     make(map[string]runstate.Step),
+	}
+
+	a.saveState()
+}
+
+// resumeCheckpoints returns whether the syncs can continue from the checkpoints that were stored by a previous attempt.
+// That is the case when retrying the target or when resuming the last run.
+func (a *targetAttempt) resumeCheckpoints(ctx context.Context) bool {
+	return a.resume || target.Attempt(ctx) > 0
+}
+
+// stepFinished records in the run state that the given step finished successfully.
+func (a *targetAttempt) stepFinished(cfg *types.BaseTargetConfig, syncType string) {
+	if a.state == nil {
+		return
+	}
+
+	a.state.Steps[syncType] = runstate.Step{
+		FinishedAt: time.Now(),
+		Inputs:     runstate.Fingerprint(cfg, syncType),
+	}
+
+	a.saveState()
+}
+
+// completeRun records in the run state that all steps finished successfully, so there is nothing to resume.
+func (a *targetAttempt) completeRun() {
+	if a.state == nil {
+		return
+	}
+
+	a.state.Completed = true
+
+	a.saveState()
+}
+
+func (a *targetAttempt) saveState() {
+	err := a.store.Save(a.state)
+	if err != nil {
+		a.logger.Warn(fmt.Sprintf("Unable to store the progress of the run: %s", err.Error()))
+	}
+}
+
+// targetAttemptFromContext returns the attempt of the target sync, or an empty attempt if the sync isn't executed through a SyncJob.
+func targetAttemptFromContext(ctx context.Context) *targetAttempt {
+	if attempt, ok := ctx.Value(targetAttemptKey{}).(*targetAttempt); ok {
+		return attempt
+	}
+
+	return &targetAttempt{finished: map[string]struct{}{}, logger: hclog.NewNullLogger()}
+}
+
+// targetAttempt returns the progress of the previous attempt when retrying the target, or a new attempt otherwise.
+func (s *SyncJob) targetAttempt(ctx context.Context, targetConfig *types.BaseTargetConfig) *targetAttempt {
+	if s.attempts == nil {
+		s.attempts = make(map[string]*targetAttempt)
+	}
+
+	if attempt, found := s.attempts[targetConfig.Name]; found && target.Attempt(ctx) > 0 {
+		return attempt
+	}
+
+	attempt := &targetAttempt{
+		finished: map[string]struct{}{},
+		store:    s.RunState,
+		logger:   targetConfig.TargetLogger,
+	}
+	s.attempts[targetConfig.Name] = attempt
+
+	return attempt
+}
@@ -0,0 +1,40 @@
+package target_sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/internal/constants"
+	"github.com/raito-io/cli/internal/runstate"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+func TestTargetAttempt_StartRun_ResumesOnce(t *testing.T) {
+	store := runstate.NewStore(t.TempDir())
+	cfg := &types.BaseTargetConfig{Name: "snowflake1", ConnectorName: "raito-io/cli-plugin-snowflake", DataSourceId: "ds1"}
+
+	require.NoError(t, store.Save(&runstate.TargetState{
+		Target:  "snowflake1",
+		RunType: "full",
+		JobId:   "job1",
+		Steps: map[string]runstate.Step{
+			constants.DataSourceSync: {FinishedAt: time.Now(), Inputs: runstate.Fingerprint(cfg, constants.DataSourceSync)},
+		},
+	}))
+
+	attempt := &targetAttempt{jobId: "job2", finished: map[string]struct{}{}, store: store, logger: hclog.NewNullLogger()}
+	attempt.startRun(cfg, "full", true)
+
+	_, found := attempt.resumedStep(constants.DataSourceSync)
+	assert.True(t, found)
+
+	// When this run fails as well, the next run executes the skipped step again
+	state, err := store.Load("full", "snowflake1")
+	require.NoError(t, err)
+	assert.Equal(t, "job2", state.JobId)
+	assert.Empty(t, state.ResumableSteps(cfg))
+}
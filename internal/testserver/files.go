@@ -0,0 +1,197 @@
+package testserver
+
+import (
+	"crypto/md5" //nolint:gosec
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type signedURL struct {
+	URL           string              `json:"URL"`
+	Key           string              `json:"Key"`
+	SignedHeaders map[string][]string `json:"signedHeaders,omitempty"`
+}
+
+type multipartUpload struct {
+	Key      string `json:"key"`
+	UploadId string `json:"uploadId"`
+
+	parts map[int][]byte
+}
+
+type completedPart struct {
+	PartNumber int    `json:"partNumber"`
+	ETag       string `json:"etag"`
+}
+
+// handleUpload handles the endpoints the CLI uses to get the URLs to upload files to. The files themselves are stored in the bucket of the server.
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	s.record(Interaction{Type: InteractionHTTP, Name: r.Method + " " + r.URL.Path, Body: string(body)})
+
+	query := r.URL.Query()
+
+	switch {
+	case r.Method == http.MethodGet && (r.URL.Path == "/file/upload/signed-url" || r.URL.Path == "/file/upload/logs/signed-url"):
+		s.m.Lock()
+		key := s.nextId("file")
+		s.m.Unlock()
+
+		writeJSON(w, signedURL{URL: s.bucketURL(key, nil), Key: key})
+	case r.Method == http.MethodPost && r.URL.Path == "/file/upload/multipart/start":
+		s.m.Lock()
+		upload := &multipartUpload{Key: s.nextId("file"), UploadId: s.nextId("upload"), parts: map[int][]byte{}}
+		s.uploads[upload.UploadId] = upload
+		s.m.Unlock()
+
+		writeJSON(w, upload)
+	case r.Method == http.MethodGet && r.URL.Path == "/file/upload/multipart/signed-url":
+		partQuery := url.Values{}
+		partQuery.Set("uploadId", query.Get("uploadId"))
+		partQuery.Set("partNumber", query.Get("partNumber"))
+
+		writeJSON(w, signedURL{URL: s.bucketURL(query.Get("key"), partQuery), Key: query.Get("key")})
+	case r.Method == http.MethodPost && r.URL.Path == "/file/upload/multipart/complete":
+		s.completeMultipartUpload(w, body)
+	case r.Method == http.MethodPost && r.URL.Path == "/file/upload/multipart/abort":
+		upload := multipartUpload{}
+		_ = json.Unmarshal(body, &upload)
+
+		s.m.Lock()
+		delete(s.uploads, upload.UploadId)
+		s.m.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) bucketURL(key string, query url.Values) string {
+	bucketURL := s.url + bucketPath + key
+
+	if len(query) > 0 {
+		bucketURL += "?" + query.Encode()
+	}
+
+	return bucketURL
+}
+
+// handleBucket stores the files (or the parts of a multipart upload) that are uploaded by the CLI.
+func (s *Server) handleBucket(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, bucketPath)
+
+	if r.Method != http.MethodPut {
+		w.Header().Set("Allow", http.MethodPut)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	content, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	s.record(Interaction{Type: InteractionHTTP, Name: r.Method + " " + bucketPath + key})
+
+	uploadId := r.URL.Query().Get("uploadId")
+	if uploadId == "" {
+		s.m.Lock()
+		s.files[key] = content
+		s.m.Unlock()
+
+		return
+	}
+
+	partNumber, err := strconv.Atoi(r.URL.Query().Get("partNumber"))
+	if err != nil {
+		http.Error(w, "invalid part number", http.StatusBadRequest)
+
+		return
+	}
+
+	s.m.Lock()
+	upload, found := s.uploads[uploadId]
+
+	if found {
+		upload.parts[partNumber] = content
+	}
+	s.m.Unlock()
+
+	if !found {
+		http.Error(w, fmt.Sprintf("unknown upload %q", uploadId), http.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set("ETag", etag(content))
+}
+
+func (s *Server) completeMultipartUpload(w http.ResponseWriter, body []byte) {
+	request := struct {
+		Key      string          `json:"key"`
+		UploadId string          `json:"uploadId"`
+		Parts    []completedPart `json:"parts"`
+	}{}
+
+	err := json.Unmarshal(body, &request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %s", err.Error()), http.StatusBadRequest)
+
+		return
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	upload, found := s.uploads[request.UploadId]
+	if !found || upload.Key != request.Key {
+		http.Error(w, fmt.Sprintf("unknown upload %q", request.UploadId), http.StatusNotFound)
+
+		return
+	}
+
+	sort.Slice(request.Parts, func(i, j int) bool {
+		return request.Parts[i].PartNumber < request.Parts[j].PartNumber
+	})
+
+	var content []byte
+
+	for _, part := range request.Parts {
+		partContent, partFound := upload.parts[part.PartNumber]
+		if !partFound || etag(partContent) != part.ETag {
+			http.Error(w, fmt.Sprintf("invalid part %d", part.PartNumber), http.StatusBadRequest)
+
+			return
+		}
+
+		content = append(content, partContent...)
+	}
+
+	s.files[upload.Key] = content
+	delete(s.uploads, request.UploadId)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func etag(content []byte) string {
+	sum := md5.Sum(content) //nolint:gosec
+
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
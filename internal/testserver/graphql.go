@@ -0,0 +1,339 @@
+package testserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/raito-io/cli/internal/job"
+)
+
+// GraphQLRequest is a GraphQL request sent by the CLI.
+type GraphQLRequest struct {
+	OperationName string                 `json:"operationName"`
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// OperationHandler returns the data for the given GraphQL operation (the value of the first field of the query).
+// The data should only contain fields that are requested by the query, as the GraphQL client of the CLI rejects unknown fields.
+type OperationHandler func(request *GraphQLRequest) (interface{}, error)
+
+// HandleOperation overrides how the server answers the GraphQL operation with the given name (e.g. 'createJob').
+func (s *Server) HandleOperation(name string, handler OperationHandler) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.operations[name] = handler
+}
+
+// Argument returns the string value of the argument with the given name.
+// The variables are searched first, after which the arguments that are inlined in the query are searched.
+func (r *GraphQLRequest) Argument(name string) string {
+	if value, found := findVariable(r.Variables, name); found {
+		return value
+	}
+
+	match := regexp.MustCompile(regexp.QuoteMeta(name) + `\s*:\s*"([^"]*)"`).FindStringSubmatch(r.Query)
+	if match != nil {
+		return match[1]
+	}
+
+	return ""
+}
+
+func findVariable(variables map[string]interface{}, name string) (string, bool) {
+	if value, found := variables[name].(string); found {
+		return value, true
+	}
+
+	for _, v := range variables {
+		if nested, ok := v.(map[string]interface{}); ok {
+			if value, found := findVariable(nested, name); found {
+				return value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+var operationKeywords = []string{"query", "mutation", "subscription"}
+
+// operationName returns the name of the first field of the query, which identifies what the CLI is asking for.
+func operationName(query string) string {
+	start := strings.Index(query, "{")
+	if start < 0 {
+		return ""
+	}
+
+	header := strings.TrimSpace(query[:start])
+	if header != "" && !hasKeywordPrefix(header) {
+		return ""
+	}
+
+	body := strings.TrimLeft(query[start+1:], " \t\r\n")
+
+	end := strings.IndexFunc(body, func(r rune) bool {
+		return !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+
+	if end < 0 {
+		return body
+	}
+
+	return body[:end]
+}
+
+func hasKeywordPrefix(header string) bool {
+	for _, keyword := range operationKeywords {
+		if strings.HasPrefix(header, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	request := GraphQLRequest{}
+
+	// Decode only the first JSON value, as some of the queries of the CLI have trailing characters
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid GraphQL request: %s", err.Error()), http.StatusBadRequest)
+
+		return
+	}
+
+	name := operationName(request.Query)
+
+	s.record(Interaction{Type: InteractionGraphQL, Name: name, Query: request.Query, Variables: request.Variables})
+
+	s.m.Lock()
+	handler, found := s.operations[name]
+	s.m.Unlock()
+
+	var data interface{}
+
+	if found {
+		data, err = handler(&request)
+	} else {
+		err = fmt.Errorf("operation %q is not supported by the test server", name)
+	}
+
+	if err != nil {
+		writeJSON(w, map[string]interface{}{
+			"data":   nil,
+			"errors": []map[string]string{{"message": err.Error()}},
+		})
+
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"data": map[string]interface{}{name: data},
+	})
+}
+
+type subtask struct {
+	jobId     string
+	jobType   string
+	subtaskId string
+	operation string
+	fileKey   string
+}
+
+func (s *Server) defaultOperations() map[string]OperationHandler {
+	operations := map[string]OperationHandler{
+		"createJob": func(_ *GraphQLRequest) (interface{}, error) {
+			s.m.Lock()
+			defer s.m.Unlock()
+
+			return map[string]string{"jobId": s.nextId("job")}, nil
+		},
+		"updateJob":                jobIdResponse("id"),
+		"addTaskEvent":             jobIdResponse("jobId"),
+		"addSubtaskEvent":          jobIdResponse("jobId"),
+		"setDataSourceMetaData":    idResponse,
+		"setIdentityStoreMetaData": idResponse,
+		"jobSubtask":               s.jobSubtask,
+		"dataSource":               s.dataSourceUsage,
+		"acquireCliLease":          s.acquireLease,
+		"releaseCliLease":          s.releaseLease,
+		"addLogFileToTask": func(r *GraphQLRequest) (interface{}, error) {
+			return map[string]string{"jobId": r.Argument("jobId"), "jobType": r.Argument("jobType")}, nil
+		},
+		"endOfTargetsSync": func(_ *GraphQLRequest) (interface{}, error) {
+			return map[string]bool{"success": true}, nil
+		},
+		"SupportedCLIVersion": func(_ *GraphQLRequest) (interface{}, error) {
+			return map[string]string{"supportedVersions": ">=0.0.0-0"}, nil
+		},
+		"cliTriggerUrl": func(_ *GraphQLRequest) (interface{}, error) {
+			return map[string]string{"url": s.WebsocketURL()}, nil
+		},
+	}
+
+	for _, operation := range []string{"importDataSourceRequest", "importIdentityRequest", "importAccessProvidersRequest", "importAccessProvidersSyncFeedback", "importDataUsageRequest", "importTagsRequest", "exportAccessProvidersRequest"} {
+		operations[operation] = s.createSubtask(operation)
+	}
+
+	return operations
+}
+
+func jobIdResponse(argument string) OperationHandler {
+	return func(r *GraphQLRequest) (interface{}, error) {
+		return map[string]string{"jobId": r.Argument(argument)}, nil
+	}
+}
+
+func idResponse(r *GraphQLRequest) (interface{}, error) {
+	return map[string]string{"id": r.Argument("id")}, nil
+}
+
+// createSubtask handles the import and export requests, which are queued on the server as a subtask of the job.
+func (s *Server) createSubtask(operation string) OperationHandler {
+	return func(r *GraphQLRequest) (interface{}, error) {
+		s.m.Lock()
+		defer s.m.Unlock()
+
+		st := &subtask{
+			jobId:     r.Argument("jobId"),
+			subtaskId: s.nextId("subtask"),
+			operation: operation,
+		}
+
+		if operation == "exportAccessProvidersRequest" {
+			st.fileKey = s.nextId("export")
+			s.files[st.fileKey] = s.exportContent
+		}
+
+		s.subtasks[st.subtaskId] = st
+
+		return map[string]interface{}{
+			"subtask": map[string]interface{}{
+				"subtaskId": st.subtaskId,
+				"status":    job.Queued,
+			},
+		}, nil
+	}
+}
+
+// subtaskResult returns the configured result for the given subtask. Should be called with the lock held.
+func (s *Server) subtaskResult(st *subtask) SubtaskResult {
+	result, found := s.subtaskResults[st.operation]
+	if !found {
+		result = SubtaskResult{Status: job.Completed}
+	}
+
+	if result.Result == nil {
+		result.Result = map[string]interface{}{}
+
+		if st.fileKey != "" {
+			result.Result = map[string]interface{}{
+				"fileKey":      st.fileKey,
+				"fileLocation": s.url + filesPath + st.fileKey,
+			}
+		}
+	}
+
+	return result
+}
+
+func (s *Server) jobSubtask(r *GraphQLRequest) (interface{}, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	subtaskId := r.Argument("subtaskId")
+
+	st, found := s.subtasks[subtaskId]
+	if !found {
+		return nil, fmt.Errorf("subtask %q not found", subtaskId)
+	}
+
+	result := s.subtaskResult(st)
+
+	return map[string]interface{}{
+		"jobId":      r.Argument("jobId"),
+		"jobType":    r.Argument("jobType"),
+		"subtaskId":  subtaskId,
+		"status":     result.Status,
+		"lastUpdate": time.Now(),
+		"errors":     result.Errors,
+		"result":     result.Result,
+	}, nil
+}
+
+func (s *Server) dataSourceUsage(r *GraphQLRequest) (interface{}, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	response := map[string]string{"id": r.Argument("id"), "usageFirstUsed": "", "usageLastUsed": ""}
+
+	if s.usageFirstUsed != nil {
+		response["usageFirstUsed"] = s.usageFirstUsed.Format(time.RFC3339)
+	}
+
+	if s.usageLastUsed != nil {
+		response["usageLastUsed"] = s.usageLastUsed.Format(time.RFC3339)
+	}
+
+	return response, nil
+}
+
+func (s *Server) acquireLease(r *GraphQLRequest) (interface{}, error) {
+	input, ok := r.Variables["input"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("missing lease input")
+	}
+
+	name, _ := input["name"].(string)
+	holder, _ := input["holder"].(string)
+	ttl, _ := input["ttlSeconds"].(float64)
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	current, found := s.leases[name]
+	if found && current.holder != holder && time.Now().Before(current.expires) {
+		return map[string]bool{"acquired": false}, nil
+	}
+
+	s.leases[name] = lease{holder: holder, expires: time.Now().Add(time.Duration(ttl) * time.Second)}
+
+	return map[string]bool{"acquired": true}, nil
+}
+
+func (s *Server) releaseLease(r *GraphQLRequest) (interface{}, error) {
+	input, ok := r.Variables["input"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("missing lease input")
+	}
+
+	name, _ := input["name"].(string)
+	holder, _ := input["holder"].(string)
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	current, found := s.leases[name]
+	if !found || current.holder != holder {
+		return map[string]bool{"released": false}, nil
+	}
+
+	delete(s.leases, name)
+
+	return map[string]bool{"released": true}, nil
+}
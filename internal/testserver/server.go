@@ -0,0 +1,309 @@
+package testserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/raito-io/cli/internal/job"
+)
+
+const (
+	// AdminPath is the prefix of the endpoints to inspect and steer the test server. They are not part of the Raito Cloud API.
+	AdminPath = "/_testserver/"
+
+	queryPath     = "/query"
+	websocketPath = "/websocket"
+	bucketPath    = "/bucket/"
+	filesPath     = AdminPath + "files/"
+)
+
+const (
+	InteractionGraphQL   = "graphql"
+	InteractionHTTP      = "http"
+	InteractionWebsocket = "websocket"
+)
+
+// Interaction is a single request the CLI sent to the test server.
+type Interaction struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+
+	// Name is the GraphQL operation (the first field of the query), the HTTP method and path or the websocket message type.
+	Name string `json:"name"`
+
+	Query     string                 `json:"query,omitempty"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+	Body      string                 `json:"body,omitempty"`
+}
+
+// SubtaskResult is returned when the CLI fetches a subtask that was created by an import or export request.
+type SubtaskResult struct {
+	Status job.JobStatus `json:"status"`
+	Errors []string      `json:"errors,omitempty"`
+	Result interface{}   `json:"result,omitempty"`
+}
+
+// Server is an in-memory stand-in for Raito Cloud, to test the CLI end-to-end without network access.
+// Point the CLI to it with the 'raito-url-override' flag and skip the authentication with the 'skip-authentication' flag.
+type Server struct {
+	logger hclog.Logger
+
+	httpServer *http.Server
+	url        string
+
+	m              sync.Mutex
+	interactions   []Interaction
+	operations     map[string]OperationHandler
+	counter        int
+	subtasks       map[string]*subtask
+	subtaskResults map[string]SubtaskResult
+	files          map[string][]byte
+	uploads        map[string]*multipartUpload
+	leases         map[string]lease
+	exportContent  []byte
+	usageFirstUsed *time.Time
+	usageLastUsed  *time.Time
+
+	connections map[*websocket.Conn]struct{}
+	connected   chan struct{}
+}
+
+// New creates a test server that isn't listening yet.
+func New(logger hclog.Logger) *Server {
+	s := &Server{
+		logger:         logger,
+		subtasks:       map[string]*subtask{},
+		subtaskResults: map[string]SubtaskResult{},
+		files:          map[string][]byte{},
+		uploads:        map[string]*multipartUpload{},
+		leases:         map[string]lease{},
+		exportContent:  []byte("accessProviders: []\n"),
+		connections:    map[*websocket.Conn]struct{}{},
+		connected:      make(chan struct{}),
+	}
+
+	s.operations = s.defaultOperations()
+
+	return s
+}
+
+// Start starts listening on the given address (e.g. 'localhost:8080' or '127.0.0.1:0' to pick a free port).
+func (s *Server) Start(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("listening on %q: %w", address, err)
+	}
+
+	s.url = "http://" + listener.Addr().String()
+	s.httpServer = &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		serveErr := s.httpServer.Serve(listener)
+		if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			s.logger.Error(fmt.Sprintf("Test server stopped: %s", serveErr.Error()))
+		}
+	}()
+
+	return nil
+}
+
+// Close stops the server and closes all websocket connections.
+func (s *Server) Close() error {
+	s.m.Lock()
+	for conn := range s.connections {
+		conn.Close(websocket.StatusGoingAway, "test server closed") //nolint:errcheck
+	}
+	s.m.Unlock()
+
+	if s.httpServer == nil {
+		return nil
+	}
+
+	return s.httpServer.Close()
+}
+
+// URL returns the base URL of the server, to pass as 'raito-url-override'.
+func (s *Server) URL() string {
+	return s.url
+}
+
+// WebsocketURL returns the URL the CLI gets to connect to for CLI triggers.
+func (s *Server) WebsocketURL() string {
+	return "ws" + strings.TrimPrefix(s.url, "http") + websocketPath
+}
+
+// Interactions returns all requests received so far, in order.
+func (s *Server) Interactions() []Interaction {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	result := make([]Interaction, len(s.interactions))
+	copy(result, s.interactions)
+
+	return result
+}
+
+// InteractionsNamed returns the requests received so far with the given name (e.g. 'createJob' or 'heartbeat').
+func (s *Server) InteractionsNamed(name string) []Interaction {
+	var result []Interaction
+
+	for _, interaction := range s.Interactions() {
+		if interaction.Name == name {
+			result = append(result, interaction)
+		}
+	}
+
+	return result
+}
+
+// ResetInteractions forgets all requests received so far.
+func (s *Server) ResetInteractions() {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.interactions = nil
+}
+
+// File returns the content of an uploaded file by the key that was handed to the CLI.
+func (s *Server) File(key string) ([]byte, bool) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	content, found := s.files[key]
+
+	return content, found
+}
+
+// SetSubtaskResult configures the subtask that is returned for all subtasks created by the given operation (e.g. 'importDataSourceRequest').
+// By default, subtasks are completed successfully with an empty result.
+func (s *Server) SetSubtaskResult(operation string, result SubtaskResult) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.subtaskResults[operation] = result
+}
+
+// SetAccessProviderExport sets the file the CLI downloads when exporting the access providers to sync to the data source.
+func (s *Server) SetAccessProviderExport(content []byte) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.exportContent = content
+}
+
+// SetDataUsageWindow sets the first and last usage dates returned for every data source.
+func (s *Server) SetDataUsageWindow(firstUsed, lastUsed *time.Time) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.usageFirstUsed = firstUsed
+	s.usageLastUsed = lastUsed
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == queryPath:
+		s.handleGraphQL(w, r)
+	case r.URL.Path == websocketPath:
+		s.handleWebsocket(w, r)
+	case strings.HasPrefix(r.URL.Path, bucketPath):
+		s.handleBucket(w, r)
+	case strings.HasPrefix(r.URL.Path, "/file/upload/"):
+		s.handleUpload(w, r)
+	case strings.HasPrefix(r.URL.Path, AdminPath):
+		s.handleAdmin(w, r)
+	default:
+		s.record(Interaction{Type: InteractionHTTP, Name: r.Method + " " + r.URL.Path})
+
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) record(interaction Interaction) {
+	interaction.Time = time.Now()
+
+	s.m.Lock()
+	s.interactions = append(s.interactions, interaction)
+	s.m.Unlock()
+
+	s.logger.Debug(fmt.Sprintf("Test server received %s %q", interaction.Type, interaction.Name))
+}
+
+// nextId returns a unique ID with the given prefix. Should be called with the lock held.
+func (s *Server) nextId(prefix string) string {
+	s.counter++
+
+	return fmt.Sprintf("%s-%d", prefix, s.counter)
+}
+
+func (s *Server) handleAdmin(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, AdminPath)
+
+	switch {
+	case path == "interactions" && r.Method == http.MethodGet:
+		writeJSON(w, s.Interactions())
+	case path == "interactions" && r.Method == http.MethodDelete:
+		s.ResetInteractions()
+		w.WriteHeader(http.StatusNoContent)
+	case path == "trigger" && r.Method == http.MethodPost:
+		body, err := io.ReadAll(r.Body)
+		if err != nil || !json.Valid(body) {
+			http.Error(w, "invalid trigger: expected a JSON body", http.StatusBadRequest)
+
+			return
+		}
+
+		err = s.pushMessage(r.Context(), body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	case strings.HasPrefix(path, "subtask-results/") && r.Method == http.MethodPut:
+		result := SubtaskResult{}
+
+		err := json.NewDecoder(r.Body).Decode(&result)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid subtask result: %s", err.Error()), http.StatusBadRequest)
+
+			return
+		}
+
+		s.SetSubtaskResult(strings.TrimPrefix(path, "subtask-results/"), result)
+		w.WriteHeader(http.StatusNoContent)
+	case strings.HasPrefix(r.URL.Path, filesPath) && r.Method == http.MethodGet:
+		content, found := s.File(strings.TrimPrefix(r.URL.Path, filesPath))
+		if !found {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		_, _ = w.Write(content)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+type lease struct {
+	holder  string
+	expires time.Time
+}
@@ -0,0 +1,259 @@
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/hashicorp/go-hclog"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/raito-io/cli/base/data_source"
+	"github.com/raito-io/cli/internal/clitrigger"
+	"github.com/raito-io/cli/internal/constants"
+	ds "github.com/raito-io/cli/internal/data_source"
+	"github.com/raito-io/cli/internal/graphql"
+	"github.com/raito-io/cli/internal/job"
+	"github.com/raito-io/cli/internal/target/types"
+)
+
+func startTestServer(t *testing.T) (*Server, *types.BaseTargetConfig) {
+	t.Helper()
+
+	server := New(hclog.NewNullLogger())
+	require.NoError(t, server.Start("127.0.0.1:0"))
+
+	viper.Set(constants.URLOverrideFlag, server.URL())
+	viper.Set(constants.SkipAuthentication, true)
+
+	t.Cleanup(func() {
+		viper.Set(constants.URLOverrideFlag, "")
+		viper.Set(constants.SkipAuthentication, false)
+
+		server.Close() //nolint:errcheck
+	})
+
+	return server, &types.BaseTargetConfig{
+		BaseConfig:   types.BaseConfig{BaseLogger: hclog.NewNullLogger()},
+		TargetLogger: hclog.NewNullLogger(),
+		DataSourceId: "ds1",
+	}
+}
+
+func TestOperationName(t *testing.T) {
+	tests := map[string]string{
+		"mutation ImportDataSourceRequest {\n  importDataSourceRequest(input: {}) { subtask { status } } }": "importDataSourceRequest",
+		"mutation ($input:JobInput!){createJob(input: $input){jobId}}":                                      "createJob",
+		"query jobSubtask{\njobSubtask(jobId: \"j1\") { status }}":                                          "jobSubtask",
+		"{SupportedCLIVersion{supportedVersions}}":                                                          "SupportedCLIVersion",
+		"fragment X on Y { id }": "",
+		"no query":               "",
+	}
+
+	for query, expected := range tests {
+		assert.Equal(t, expected, operationName(query), query)
+	}
+}
+
+func TestArgument(t *testing.T) {
+	request := GraphQLRequest{
+		Query:     `jobSubtask(jobId: "j1", jobType: "DS", subtaskId: "s1")`,
+		Variables: map[string]interface{}{"id": "job-1", "input": map[string]interface{}{"jobId": "job-2"}},
+	}
+
+	assert.Equal(t, "job-1", request.Argument("id"))
+	assert.Equal(t, "job-2", request.Argument("jobId"))
+	assert.Equal(t, "DS", request.Argument("jobType"))
+	assert.Equal(t, "", request.Argument("unknown"))
+}
+
+func TestServer_DataSourceImport(t *testing.T) {
+	server, config := startTestServer(t)
+	ctx := context.Background()
+
+	jobId, err := job.StartJob(ctx, config)
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", jobId)
+
+	err = ds.SetMetaData(ctx, config, &data_source.MetaData{Type: "test", DataObjectTypes: []*data_source.DataObjectType{{Name: "datasource", Type: "datasource"}}})
+	require.NoError(t, err)
+
+	targetFile := filepath.Join(t.TempDir(), "data-source.json")
+	require.NoError(t, os.WriteFile(targetFile, []byte(`[{"externalId":"schema1"}]`), 0600))
+
+	updater := job.NewTaskEventUpdater(config, jobId, constants.DataSourceSync, nil)
+	importer := ds.NewDataSourceImporter(&ds.DataSourceImportConfig{BaseTargetConfig: *config, TargetFile: targetFile, DeleteUntouched: true}, updater)
+
+	status, subtaskId, err := importer.TriggerImport(ctx, jobId)
+	require.NoError(t, err)
+	assert.Equal(t, job.Queued, status)
+
+	subtask, err := job.WaitForJobToComplete(ctx, jobId, constants.DataSourceSync, subtaskId, &ds.DataSourceImportResult{}, config, status)
+	require.NoError(t, err)
+	assert.Equal(t, job.Completed, subtask.Status)
+
+	imports := server.InteractionsNamed("importDataSourceRequest")
+	require.Len(t, imports, 1)
+	assert.Contains(t, imports[0].Query, `dataSource: "ds1"`)
+	assert.Contains(t, imports[0].Query, "deleteUntouched: true")
+
+	assert.Contains(t, imports[0].Query, `fileKey: "file-2"`)
+
+	content, found := server.File("file-2")
+	require.True(t, found)
+	assert.JSONEq(t, `[{"externalId":"schema1"}]`, string(content))
+
+	events := server.InteractionsNamed("addTaskEvent")
+	require.Len(t, events, 1)
+	assert.Equal(t, "DATA_UPLOAD", events[0].Variables["input"].(map[string]interface{})["status"])
+}
+
+func TestServer_IncrementalDataSourceImport(t *testing.T) {
+	server, config := startTestServer(t)
+	ctx := context.Background()
+
+	viper.Set(constants.SkipFileUpload, true)
+	defer viper.Set(constants.SkipFileUpload, false)
+
+	importer := ds.NewDataSourceImporter(&ds.DataSourceImportConfig{BaseTargetConfig: *config, TargetFile: "data-source.json", DeletedDataObjects: []string{"db1.schema1", `db1."quoted"`}}, job.NewTaskEventUpdater(config, "job-1", constants.DataSourceSync, nil))
+
+	_, _, err := importer.TriggerImport(ctx, "job-1")
+	require.NoError(t, err)
+
+	imports := server.InteractionsNamed("importDataSourceRequest")
+	require.Len(t, imports, 1)
+	assert.Contains(t, imports[0].Query, "deleteUntouched: false")
+	assert.Contains(t, imports[0].Query, `deletedDataObjects: ["db1.schema1", "db1.\"quoted\""]`)
+}
+
+func TestServer_ConfiguredSubtaskResult(t *testing.T) {
+	server, config := startTestServer(t)
+	ctx := context.Background()
+
+	server.SetSubtaskResult("importDataSourceRequest", SubtaskResult{
+		Status: job.Failed,
+		Errors: []string{"import failed"},
+		Result: map[string]interface{}{"dataObjectsAdded": 5},
+	})
+
+	server.HandleOperation("createJob", func(_ *GraphQLRequest) (interface{}, error) {
+		return map[string]string{"jobId": "my-job"}, nil
+	})
+
+	jobId, err := job.StartJob(ctx, config)
+	require.NoError(t, err)
+	assert.Equal(t, "my-job", jobId)
+
+	importer := ds.NewDataSourceImporter(&ds.DataSourceImportConfig{BaseTargetConfig: *config, TargetFile: "data-source.json"}, job.NewTaskEventUpdater(config, jobId, constants.DataSourceSync, nil))
+
+	viper.Set(constants.SkipFileUpload, true)
+	defer viper.Set(constants.SkipFileUpload, false)
+
+	_, subtaskId, err := importer.TriggerImport(ctx, jobId)
+	require.NoError(t, err)
+
+	result := ds.DataSourceImportResult{}
+
+	subtask, err := job.GetSubtask(ctx, config, jobId, constants.DataSourceSync, subtaskId, &result)
+	require.NoError(t, err)
+	assert.Equal(t, job.Failed, subtask.Status)
+	assert.Equal(t, []string{"import failed"}, subtask.Errors)
+	assert.Equal(t, 5, result.DataObjectsAdded)
+}
+
+func TestServer_UnsupportedOperation(t *testing.T) {
+	server, config := startTestServer(t)
+
+	_, err := graphql.ExecuteGraphQL(`{"query": "query { somethingNew { id } }"}`, &config.BaseConfig, &struct{}{})
+	assert.ErrorContains(t, err, `operation "somethingNew" is not supported by the test server`)
+
+	_, err = job.GetSubtask(context.Background(), config, "job-1", constants.DataSourceSync, "unknown", nil)
+	assert.ErrorContains(t, err, `subtask "unknown" not found`)
+
+	assert.Len(t, server.InteractionsNamed("somethingNew"), 1)
+}
+
+func TestServer_MultipartUpload(t *testing.T) {
+	server, _ := startTestServer(t)
+
+	resp, err := http.Post(server.URL()+"/file/upload/multipart/start", "application/json", bytes.NewBufferString(`{"parts":2}`)) //nolint:noctx
+	require.NoError(t, err)
+
+	upload := multipartUpload{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&upload))
+	resp.Body.Close()
+
+	var parts []completedPart
+
+	for i, content := range []string{"part1-", "part2"} {
+		req, reqErr := http.NewRequest(http.MethodPut, server.bucketURL(upload.Key, map[string][]string{"uploadId": {upload.UploadId}, "partNumber": {strconv.Itoa(i + 1)}}), bytes.NewBufferString(content)) //nolint:noctx
+		require.NoError(t, reqErr)
+
+		partResp, reqErr := http.DefaultClient.Do(req)
+		require.NoError(t, reqErr)
+		partResp.Body.Close()
+
+		parts = append(parts, completedPart{PartNumber: i + 1, ETag: partResp.Header.Get("ETag")})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"key": upload.Key, "uploadId": upload.UploadId, "parts": []completedPart{parts[1], parts[0]}})
+	require.NoError(t, err)
+
+	resp, err = http.Post(server.URL()+"/file/upload/multipart/complete", "application/json", bytes.NewReader(body)) //nolint:noctx
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	content, found := server.File(upload.Key)
+	require.True(t, found)
+	assert.Equal(t, "part1-part2", string(content))
+}
+
+func TestServer_Websocket(t *testing.T) {
+	server, _ := startTestServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(ctx, server.WebsocketURL(), nil) //nolint:bodyclose
+	require.NoError(t, err)
+
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	require.NoError(t, server.WaitForWebsocket(ctx))
+	require.NoError(t, conn.Write(ctx, websocket.MessageText, []byte(`{"message":"heartbeat","datasources":["ds1"]}`)))
+
+	dataSource := "ds1"
+	require.NoError(t, server.PushTrigger(ctx, clitrigger.TriggerEvent{SyncTrigger: &clitrigger.SyncTrigger{DataSource: &dataSource, DataSourceSync: true}}))
+
+	_, msg, err := conn.Read(ctx)
+	require.NoError(t, err)
+
+	event := clitrigger.TriggerEvent{}
+	require.NoError(t, json.Unmarshal(msg, &event))
+	require.NotNil(t, event.SyncTrigger)
+	assert.Equal(t, "ds1", *event.SyncTrigger.DataSource)
+
+	// The admin endpoint pushes the trigger as is
+	resp, err := http.Post(server.URL()+AdminPath+"trigger", "application/json", bytes.NewBufferString(`{"apUpdate":{"dataSourceNames":["ds1"]}}`)) //nolint:noctx
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	_, msg, err = conn.Read(ctx)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"apUpdate":{"dataSourceNames":["ds1"]}}`, string(msg))
+
+	assert.Eventually(t, func() bool {
+		return len(server.InteractionsNamed("heartbeat")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
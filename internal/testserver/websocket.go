@@ -0,0 +1,140 @@
+package testserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/coder/websocket"
+
+	"github.com/raito-io/cli/internal/clitrigger"
+	"github.com/raito-io/cli/internal/job"
+)
+
+// PushTrigger sends the given trigger event to all CLIs that are connected over the websocket.
+func (s *Server) PushTrigger(ctx context.Context, event clitrigger.TriggerEvent) error {
+	msg, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("serializing trigger event: %w", err)
+	}
+
+	return s.pushMessage(ctx, msg)
+}
+
+// WaitForWebsocket blocks until a CLI is connected over the websocket.
+func (s *Server) WaitForWebsocket(ctx context.Context) error {
+	s.m.Lock()
+	connected := s.connected
+	s.m.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-connected:
+		return nil
+	}
+}
+
+func (s *Server) pushMessage(ctx context.Context, msg []byte) error {
+	s.m.Lock()
+	connections := make([]*websocket.Conn, 0, len(s.connections))
+
+	for conn := range s.connections {
+		connections = append(connections, conn)
+	}
+	s.m.Unlock()
+
+	if len(connections) == 0 {
+		return errors.New("no CLI connected over the websocket")
+	}
+
+	for _, conn := range connections {
+		err := conn.Write(ctx, websocket.MessageText, msg)
+		if err != nil {
+			return fmt.Errorf("pushing message over the websocket: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("Unable to accept websocket connection: %s", err.Error()))
+
+		return
+	}
+
+	s.m.Lock()
+	s.connections[conn] = struct{}{}
+
+	if len(s.connections) == 1 {
+		close(s.connected)
+	}
+	s.m.Unlock()
+
+	defer func() {
+		s.m.Lock()
+		delete(s.connections, conn)
+
+		if len(s.connections) == 0 {
+			s.connected = make(chan struct{})
+		}
+		s.m.Unlock()
+
+		conn.Close(websocket.StatusNormalClosure, "") //nolint:errcheck
+	}()
+
+	ctx := r.Context()
+
+	for {
+		_, msg, readErr := conn.Read(ctx)
+		if readErr != nil {
+			return
+		}
+
+		message := struct {
+			Message   string `json:"message"`
+			JobId     string `json:"jobId"`
+			JobType   string `json:"jobType"`
+			SubtaskId string `json:"subtaskId"`
+		}{}
+
+		_ = json.Unmarshal(msg, &message)
+
+		s.record(Interaction{Type: InteractionWebsocket, Name: message.Message, Body: string(msg)})
+
+		if message.Message == "subscribeSubtask" {
+			s.pushSubtaskUpdate(ctx, conn, message.JobId, message.JobType, message.SubtaskId)
+		}
+	}
+}
+
+// pushSubtaskUpdate immediately pushes the status of a subscribed subtask, as the subtasks of the test server don't take any time to process.
+func (s *Server) pushSubtaskUpdate(ctx context.Context, conn *websocket.Conn, jobId, jobType, subtaskId string) {
+	s.m.Lock()
+	st, found := s.subtasks[subtaskId]
+
+	var status job.JobStatus
+	if found {
+		status = s.subtaskResult(st).Status
+	}
+	s.m.Unlock()
+
+	if !found {
+		return
+	}
+
+	msg, err := json.Marshal(clitrigger.TriggerEvent{SubtaskUpdate: &job.SubtaskUpdate{JobId: jobId, JobType: jobType, SubtaskId: subtaskId, Status: status}})
+	if err != nil {
+		return
+	}
+
+	err = conn.Write(ctx, websocket.MessageText, msg)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("Unable to push subtask update: %s", err.Error()))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/raito-io/cli/internal/target/types"
 
 	"github.com/raito-io/cli/internal/auth"
+	"github.com/raito-io/cli/internal/util/httpclient"
 	"github.com/raito-io/cli/internal/util/url"
 	"github.com/raito-io/cli/internal/version"
 )
@@ -27,7 +28,10 @@ func doPost(host, path, body, contentType string, config *types.BaseConfig) (*ht
 		return nil, err
 	}
 
-	client := &http.Client{}
+	client, err := httpclient.NewClient()
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -55,7 +59,11 @@ func doGet(host, path string, config *types.BaseConfig) (*http.Response, error)
 		return nil, err
 	}
 
-	client := &http.Client{}
+	client, err := httpclient.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
 	resp, err := client.Do(req)
 
 	if err != nil {
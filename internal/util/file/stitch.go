@@ -0,0 +1,92 @@
+package file
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// StitchJSONArrays concatenates the JSON arrays in the given files into a single JSON array in the target file.
+// The elements are streamed, so the files don't need to fit in memory.
+func StitchJSONArrays(parts []string, target string) error {
+	out, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("creating %q: %w", target, err)
+	}
+
+	defer out.Close()
+
+	writer := bufio.NewWriter(out)
+
+	_, err = writer.WriteString("[")
+	if err != nil {
+		return fmt.Errorf("writing %q: %w", target, err)
+	}
+
+	count := 0
+
+	for _, part := range parts {
+		count, err = appendJSONArray(writer, part, count)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = writer.WriteString("\n]")
+	if err != nil {
+		return fmt.Errorf("writing %q: %w", target, err)
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return fmt.Errorf("writing %q: %w", target, err)
+	}
+
+	return nil
+}
+
+// appendJSONArray writes the elements of the JSON array in the given file and returns the total number of elements written so far.
+func appendJSONArray(writer io.StringWriter, path string, count int) (int, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return count, fmt.Errorf("opening %q: %w", path, err)
+	}
+
+	defer in.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(in))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return count, fmt.Errorf("reading %q: %w", path, err)
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return count, fmt.Errorf("reading %q: expected a JSON array", path)
+	}
+
+	for decoder.More() {
+		var element json.RawMessage
+
+		err = decoder.Decode(&element)
+		if err != nil {
+			return count, fmt.Errorf("reading %q: %w", path, err)
+		}
+
+		separator := "\n"
+		if count > 0 {
+			separator = ",\n"
+		}
+
+		_, err = writer.WriteString(separator + string(element))
+		if err != nil {
+			return count, fmt.Errorf("writing element of %q: %w", path, err)
+		}
+
+		count++
+	}
+
+	return count, nil
+}
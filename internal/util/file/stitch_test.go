@@ -0,0 +1,46 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStitchJSONArrays(t *testing.T) {
+	dir := t.TempDir()
+
+	var parts []string
+
+	for i, content := range []string{`[{"externalId":"ds"},{"externalId":"a"}]`, `[]`, "[\n{\"externalId\":\"b\"}\n]"} {
+		part := filepath.Join(dir, fmt.Sprintf("part-%d.json", i))
+		require.NoError(t, os.WriteFile(part, []byte(content), 0600))
+
+		parts = append(parts, part)
+	}
+
+	target := filepath.Join(dir, "target.json")
+
+	require.NoError(t, StitchJSONArrays(parts, target))
+
+	content, err := os.ReadFile(target)
+	require.NoError(t, err)
+
+	var result []map[string]string
+	require.NoError(t, json.Unmarshal(content, &result))
+	assert.Equal(t, []map[string]string{{"externalId": "ds"}, {"externalId": "a"}, {"externalId": "b"}}, result)
+
+	// Empty result is still a valid array
+	require.NoError(t, StitchJSONArrays(nil, target))
+
+	content, err = os.ReadFile(target)
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", string(content))
+
+	require.NoError(t, os.WriteFile(parts[0], []byte(`{"externalId":"a"}`), 0600))
+	assert.ErrorContains(t, StitchJSONArrays(parts, target), "expected a JSON array")
+}
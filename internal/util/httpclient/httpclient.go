@@ -0,0 +1,166 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/raito-io/cli/internal/constants"
+)
+
+// Settings contains all the network settings used to construct the HTTP transport.
+type Settings struct {
+	ProxyUrl   string
+	CaBundle   string
+	ClientCert string
+	ClientKey  string
+}
+
+var (
+	mutex             sync.Mutex
+	transport         *http.Transport
+	transportSettings Settings
+)
+
+// SettingsFromConfig reads the network settings from the configuration.
+func SettingsFromConfig() Settings {
+	return Settings{
+		ProxyUrl:   strings.TrimSpace(viper.GetString(constants.ProxyUrlFlag)),
+		CaBundle:   strings.TrimSpace(viper.GetString(constants.CaBundleFlag)),
+		ClientCert: strings.TrimSpace(viper.GetString(constants.ClientCertFlag)),
+		ClientKey:  strings.TrimSpace(viper.GetString(constants.ClientKeyFlag)),
+	}
+}
+
+// NewClient returns a new HTTP client that uses the shared transport.
+// All network traffic of the CLI should go through clients created by this function, so the proxy, CA bundle and client certificate configuration is honoured.
+func NewClient() (*http.Client, error) {
+	t, err := Transport()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{Transport: t}, nil
+}
+
+// Transport returns the shared HTTP transport based on the current configuration.
+// The transport is only rebuilt when the configuration changes, so connections can be reused across clients.
+func Transport() (*http.Transport, error) {
+	settings := SettingsFromConfig()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if transport != nil && settings == transportSettings {
+		return transport, nil
+	}
+
+	t, err := NewTransport(&settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if transport != nil {
+		transport.CloseIdleConnections()
+	}
+
+	transport = t
+	transportSettings = settings
+
+	return transport, nil
+}
+
+// NewTransport creates a new HTTP transport for the given settings.
+// If no proxy URL is set, the standard proxy environment variables (HTTP_PROXY, HTTPS_PROXY and NO_PROXY) are used.
+// If a proxy URL is set, the NO_PROXY environment variable is still honoured.
+func NewTransport(settings *Settings) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	if settings.ProxyUrl != "" {
+		proxyUrl, err := url.Parse(settings.ProxyUrl)
+		if err != nil || proxyUrl.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", settings.ProxyUrl)
+		}
+
+		proxyConfig := httpproxy.Config{
+			HTTPProxy:  settings.ProxyUrl,
+			HTTPSProxy: settings.ProxyUrl,
+			NoProxy:    noProxy(),
+		}
+
+		proxyFunc := proxyConfig.ProxyFunc()
+		t.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	tlsConfig, err := tlsConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig != nil {
+		t.TLSClientConfig = tlsConfig
+	}
+
+	return t, nil
+}
+
+func tlsConfig(settings *Settings) (*tls.Config, error) {
+	if settings.CaBundle == "" && settings.ClientCert == "" && settings.ClientKey == "" {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if settings.CaBundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		caBundle, err := os.ReadFile(settings.CaBundle)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA bundle %q: %w", settings.CaBundle, err)
+		}
+
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("no valid certificates found in CA bundle %q", settings.CaBundle)
+		}
+
+		config.RootCAs = pool
+	}
+
+	if settings.ClientCert != "" || settings.ClientKey != "" {
+		if settings.ClientCert == "" || settings.ClientKey == "" {
+			return nil, fmt.Errorf("both %q and %q should be set to use a client certificate", constants.ClientCertFlag, constants.ClientKeyFlag)
+		}
+
+		cert, err := tls.LoadX509KeyPair(settings.ClientCert, settings.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate %q: %w", settings.ClientCert, err)
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+func noProxy() string {
+	if v := os.Getenv("NO_PROXY"); v != "" {
+		return v
+	}
+
+	return os.Getenv("no_proxy")
+}
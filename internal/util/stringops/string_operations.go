@@ -18,3 +18,16 @@ func TrimSpaceInCommaSeparatedList(list string) string {
 
 	return strings.Join(result, ",")
 }
+
+// IsSensitiveKey returns true if the given (parameter) name indicates that its value is a secret that should not be logged or stored.
+func IsSensitiveKey(key string) bool {
+	lk := strings.ToLower(key)
+
+	for _, keyword := range []string{"secret", "password", "passwd", "psswd", "token"} {
+		if strings.Contains(lk, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
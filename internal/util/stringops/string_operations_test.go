@@ -55,3 +55,20 @@ func TestTrimSpaceInCommaSeparatedList(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSensitiveKey(t *testing.T) {
+	tests := map[string]bool{
+		"sf-password":        true,
+		"DB_PASSWD":          true,
+		"client-secret":      true,
+		"api-token":          true,
+		"sf-user":            false,
+		"data-object-parent": false,
+	}
+
+	for key, want := range tests {
+		if got := IsSensitiveKey(key); got != want {
+			t.Errorf("IsSensitiveKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
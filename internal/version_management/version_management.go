@@ -107,6 +107,29 @@ func isValidToSync(pluginInformation *version2.CliBuildInformation, syncerMinima
 	}
 }
 
+// SupportsFeature checks whether the plugin is built with a version of the CLI that supports a feature that was added in the given version.
+// Plugins built with an older version silently ignore the configuration of the features they don't know about.
+func SupportsFeature(ctx context.Context, plugin version2.CliVersionHandler, featureVersion *semver.Version) (bool, error) {
+	pluginInformation, err := plugin.CliVersionInformation(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return supportsFeature(pluginInformation, featureVersion, version.GetCliVersion), nil
+}
+
+func supportsFeature(pluginInformation *version2.CliBuildInformation, featureVersion *semver.Version, cliInfo func() *semver.Version) bool {
+	if currentCliVersion := cliInfo(); currentCliVersion != nil && currentCliVersion.Equal(version.DevVersion) {
+		return true
+	}
+
+	if pluginInformation.CliBuildVersion == nil {
+		return false
+	}
+
+	return !pluginInformation.CliBuildVersion.ToVersion().LessThan(featureVersion)
+}
+
 type IncompatiblePluginVersionError struct {
 	pluginVersion string
 	cliVersion    string
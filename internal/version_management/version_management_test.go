@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	version2 "github.com/raito-io/cli/base/util/version"
+	"github.com/raito-io/cli/internal/version"
 )
 
 func Test_isValidToSync(t *testing.T) {
@@ -112,3 +113,55 @@ func Test_isValidToSync(t *testing.T) {
 		})
 	}
 }
+
+func Test_supportsFeature(t *testing.T) {
+	featureVersion := semver.New(0, 59, 0, "0", "")
+	cliInfo := func() *semver.Version {
+		return semver.New(0, 60, 0, "", "")
+	}
+
+	tests := []struct {
+		name              string
+		pluginInformation *version2.CliBuildInformation
+		cliInfo           func() *semver.Version
+		want              bool
+	}{
+		{
+			name:              "Plugin built with the feature version",
+			pluginInformation: &version2.CliBuildInformation{CliBuildVersion: &version2.SemVer{Major: 0, Minor: 59, Patch: 0}},
+			cliInfo:           cliInfo,
+			want:              true,
+		},
+		{
+			name:              "Plugin built with a newer version",
+			pluginInformation: &version2.CliBuildInformation{CliBuildVersion: &version2.SemVer{Major: 1, Minor: 0, Patch: 0}},
+			cliInfo:           cliInfo,
+			want:              true,
+		},
+		{
+			name:              "Plugin built with an older version",
+			pluginInformation: &version2.CliBuildInformation{CliBuildVersion: &version2.SemVer{Major: 0, Minor: 58, Patch: 3}},
+			cliInfo:           cliInfo,
+			want:              false,
+		},
+		{
+			name:              "Plugin without build version",
+			pluginInformation: &version2.CliBuildInformation{},
+			cliInfo:           cliInfo,
+			want:              false,
+		},
+		{
+			name:              "Dev mode",
+			pluginInformation: &version2.CliBuildInformation{CliBuildVersion: &version2.SemVer{Major: 0, Minor: 58, Patch: 3}},
+			cliInfo: func() *semver.Version {
+				return version.DevVersion
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, supportsFeature(tt.pluginInformation, featureVersion, tt.cliInfo))
+		})
+	}
+}